@@ -27,10 +27,89 @@ var (
 	WontProvisionTenant        ProvisioningStatus = "won't provision"
 	ThreeScaleAccountReady     ProvisioningStatus = "3scale account ready"
 	ThreeScaleAccountRequested ProvisioningStatus = "3scale account requested"
+	ThreeScaleAccountSuspended ProvisioningStatus = "3scale account suspended"
+)
+
+const (
+	// TenantExportAnnotation requests an export of the 3scale configuration of the tenant to the
+	// archive location in its value, s3://<bucket>/<key>. The key is relative to
+	// <namespace>/<name>/ of the tenant in the bucket. The annotation is removed once the export
+	// finishes. Archives are only kept in S3 compatible buckets, a PVC is not supported as the
+	// operator pod mounts no volume for them
+	TenantExportAnnotation = "integreatly.org/tenant-export"
+	// TenantImportAnnotation requests an import of the archive at the location in its value into
+	// the 3scale account of the tenant. The annotation is removed once the import finishes
+	TenantImportAnnotation = "integreatly.org/tenant-import"
+)
+
+type TenantArchivePhase string
+
+var (
+	TenantArchiveInProgress TenantArchivePhase = "in progress"
+	TenantArchiveCompleted  TenantArchivePhase = "completed"
+	TenantArchiveFailed     TenantArchivePhase = "failed"
 )
 
 // APIManagementTenantSpec defines the desired state of APIManagementTenant
 type APIManagementTenantSpec struct {
+	// RateLimit overrides the limit applied to the requests of the tenant. Tenants without an
+	// override share the limit per tenant of the installation
+	// +optional
+	RateLimit *TenantRateLimit `json:"rateLimit,omitempty"`
+	// Suspended suspends the 3scale account of the tenant and rejects all of its requests. The
+	// account and its data are kept, clearing the field restores the tenant
+	// +optional
+	Suspended bool `json:"suspended,omitempty"`
+	// Quota caps the number of objects in the 3scale account of the tenant. Objects without a cap
+	// in the quota fall back to the tenantQuota of the installation
+	// +optional
+	Quota *TenantQuota `json:"quota,omitempty"`
+	// Owner provisions the tenant for the admin it names instead of the OpenShift user whose
+	// {USERNAME}-dev or {USERNAME}-stage namespace the tenant is in. Tenants with an owner can only
+	// be created in the namespace of the operator and don't need an OpenShift user
+	// +optional
+	Owner *TenantOwner `json:"owner,omitempty"`
+}
+
+// TenantOwner is the admin of a tenant that is not owned by an OpenShift user
+type TenantOwner struct {
+	// Email is the email of the admin of the 3scale account and of its RHSSO user
+	Email string `json:"email"`
+	// OrganizationName is the name of the 3scale account of the tenant, it is unique among the
+	// tenants of the installation
+	// +kubebuilder:validation:MinLength=1
+	OrganizationName string `json:"organizationName"`
+	// IdentityProvider is the alias of the RHSSO identity provider the admin logs in with
+	// +kubebuilder:validation:MinLength=1
+	IdentityProvider string `json:"identityProvider"`
+	// UserID is the ID of the admin in the identity provider, the email is used when it is not set
+	// +optional
+	UserID string `json:"userID,omitempty"`
+}
+
+// TenantQuota caps the number of objects of each kind in the 3scale account of a tenant, kinds
+// without a cap are not limited
+type TenantQuota struct {
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Services *int32 `json:"services,omitempty"`
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Backends *int32 `json:"backends,omitempty"`
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Applications *int32 `json:"applications,omitempty"`
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Users *int32 `json:"users,omitempty"`
+}
+
+// TenantRateLimit is a number of requests allowed for a tenant in each unit of time
+type TenantRateLimit struct {
+	// +kubebuilder:validation:Enum=second;minute;hour;day
+	Unit string `json:"unit"`
+	// +kubebuilder:validation:Minimum=1
+	RequestsPerUnit uint32 `json:"requestsPerUnit"`
 }
 
 // APIManagementTenantStatus defines the observed state of APIManagementTenant
@@ -38,6 +117,68 @@ type APIManagementTenantStatus struct {
 	LastError          string             `json:"lastError"`
 	ProvisioningStatus ProvisioningStatus `json:"provisioningStatus"`
 	TenantUrl          string             `json:"tenantUrl,omitempty"`
+	// TenantAccountID is the ID of the 3scale account provisioned for the tenant
+	TenantAccountID int `json:"tenantAccountID,omitempty"`
+	// RateLimit is the limit applied to the requests of the tenant
+	RateLimit *TenantRateLimitStatus `json:"rateLimit,omitempty"`
+	// RateLimitUsage is the number of requests of the tenant counted in the current window of its
+	// limit, it is read periodically from the rate limit service
+	RateLimitUsage *RateLimitUsage `json:"rateLimitUsage,omitempty"`
+	// Export is the progress of the last export of the 3scale configuration of the tenant
+	Export *TenantArchiveStatus `json:"export,omitempty"`
+	// Import is the progress of the last import into the 3scale account of the tenant
+	Import *TenantArchiveStatus `json:"import,omitempty"`
+	// Quota is the number of objects in the 3scale account of the tenant compared to its quota, it
+	// is only set for tenants with a quota
+	Quota *TenantQuotaStatus `json:"quota,omitempty"`
+}
+
+// TenantQuotaStatus is the number of objects in the 3scale account of a tenant compared to its quota
+type TenantQuotaStatus struct {
+	// Quota is the quota of the tenant, the quota in its spec merged with the tenantQuota of the
+	// installation
+	Quota TenantQuota `json:"quota"`
+	// Usage is the number of objects of each kind in the account, they are counted periodically
+	Usage TenantObjectCounts `json:"usage"`
+	// Exceeded lists the kinds of objects the tenant has more of than its quota allows
+	// +optional
+	Exceeded []string `json:"exceeded,omitempty"`
+	// LastCounted is when the objects of the account were last counted
+	LastCounted metav1.Time `json:"lastCounted"`
+}
+
+// TenantObjectCounts is the number of objects of each kind in the 3scale account of a tenant
+type TenantObjectCounts struct {
+	Services     int32 `json:"services"`
+	Backends     int32 `json:"backends"`
+	Applications int32 `json:"applications"`
+	Users        int32 `json:"users"`
+}
+
+// TenantRateLimitStatus is the limit applied to the requests of a tenant and where it comes from
+type TenantRateLimitStatus struct {
+	TenantRateLimit `json:",inline"`
+	// Override is true when the limit is the override in the tenant spec, otherwise the tenant has
+	// the limit per tenant of the installation
+	Override bool `json:"override"`
+}
+
+// RateLimitUsage is the number of requests counted against a rate limit in its current window
+type RateLimitUsage struct {
+	TenantRateLimit `json:",inline"`
+	// Requests is the number of requests counted in the current window of the limit
+	Requests uint32 `json:"requests"`
+}
+
+// TenantArchiveStatus is the progress of an export or import of the 3scale configuration of a tenant
+type TenantArchiveStatus struct {
+	// Location is the archive the tenant is exported to or imported from
+	Location string             `json:"location"`
+	Phase    TenantArchivePhase `json:"phase"`
+	// Message describes the objects in the archive once the phase is completed, or why it failed
+	Message string `json:"message,omitempty"`
+	// LastTransitionTime is when the phase last changed
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
 }
 
 //+kubebuilder:object:root=true
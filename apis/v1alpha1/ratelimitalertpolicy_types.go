@@ -0,0 +1,122 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type RateLimitAlertType string
+
+var (
+	// RateLimitAlertTypeThreshold alerts when the requests during the period are within a range of
+	// the requests the rate limit allows during the period
+	RateLimitAlertTypeThreshold RateLimitAlertType = "Threshold"
+	// RateLimitAlertTypeSpike alerts when the rate limit was exceeded at least once during the period
+	RateLimitAlertTypeSpike RateLimitAlertType = "Spike"
+	// RateLimitAlertTypeSustained alerts when the requests stayed over a percentage of the rate limit
+	// for a number of minutes
+	RateLimitAlertTypeSustained RateLimitAlertType = "Sustained"
+)
+
+// RateLimitAlertPolicySpec defines the alerts on the usage of the rate limit of the installation
+type RateLimitAlertPolicySpec struct {
+	// +kubebuilder:validation:MinItems=1
+	Alerts []RateLimitAlert `json:"alerts"`
+}
+
+// RateLimitAlert is an alert on the usage of the rate limit, a PrometheusRule named after the alert
+// is generated from it
+type RateLimitAlert struct {
+	// Name of the alert, unique in the namespace of the installation
+	// +kubebuilder:validation:MaxLength=63
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	Name string `json:"name"`
+	// +kubebuilder:validation:Enum=Threshold;Spike;Sustained
+	Type RateLimitAlertType `json:"type"`
+	// RuleName is the name of the alert in the PrometheusRule
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z_][a-zA-Z0-9_]*$`
+	RuleName string `json:"ruleName"`
+	// Level is the severity of the alert
+	// +kubebuilder:validation:Enum=info;warning;critical
+	Level string `json:"level"`
+	// Period the usage is evaluated over, as minutes or hours such as 30m or 4h. It is required by
+	// the Threshold and Spike types
+	// +kubebuilder:validation:Pattern=`^[0-9]+[mh]$`
+	// +optional
+	Period string `json:"period,omitempty"`
+	// Threshold is required by the Threshold type
+	// +optional
+	Threshold *RateLimitAlertThreshold `json:"threshold,omitempty"`
+	// Sustained is required by the Sustained type
+	// +optional
+	Sustained *RateLimitAlertSustained `json:"sustained,omitempty"`
+}
+
+// RateLimitAlertThreshold is a range of the requests the rate limit allows, as percentages
+type RateLimitAlertThreshold struct {
+	// +kubebuilder:validation:Pattern=`^[0-9]+%$`
+	MinRate string `json:"minRate"`
+	// MaxRate is the end of the range, the range has no end if it is not set
+	// +kubebuilder:validation:Pattern=`^[0-9]+%$`
+	// +optional
+	MaxRate *string `json:"maxRate,omitempty"`
+}
+
+// RateLimitAlertSustained is a percentage of the rate limit the requests stay over for a number of
+// minutes
+type RateLimitAlertSustained struct {
+	// +kubebuilder:validation:Pattern=`^[0-9]+%$`
+	MinRate string `json:"minRate"`
+	// +kubebuilder:validation:Minimum=1
+	Minutes int32 `json:"minutes"`
+}
+
+// RateLimitAlertPolicyStatus defines the observed state of RateLimitAlertPolicy
+type RateLimitAlertPolicyStatus struct {
+	// PrometheusRules are the namespaced names of the PrometheusRules generated from the policy
+	PrometheusRules []string `json:"prometheusRules,omitempty"`
+	// Error is why the alerts of the policy were not generated
+	Error string `json:"error,omitempty"`
+	// ObservedGeneration is the generation of the policy the status was reported for
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+
+// RateLimitAlertPolicy is the Schema for the RateLimitAlertPolicies API
+type RateLimitAlertPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   RateLimitAlertPolicySpec   `json:"spec,omitempty"`
+	Status RateLimitAlertPolicyStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// RateLimitAlertPolicyList contains a list of RateLimitAlertPolicy
+type RateLimitAlertPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []RateLimitAlertPolicy `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&RateLimitAlertPolicy{}, &RateLimitAlertPolicyList{})
+}
@@ -0,0 +1,176 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+var (
+	rateLimitAlertNameRegexp     = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+	rateLimitAlertRuleNameRegexp = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+	rateLimitAlertPeriodRegexp   = regexp.MustCompile(`^([0-9]+)[mh]$`)
+	rateLimitAlertRateRegexp     = regexp.MustCompile(`^([0-9]+)%$`)
+
+	rateLimitAlertLevels = map[string]bool{"info": true, "warning": true, "critical": true}
+)
+
+var _ admission.Validator = &RateLimitAlertPolicy{}
+
+// ValidateCreate implements admission.Validator
+func (p *RateLimitAlertPolicy) ValidateCreate() error {
+	return p.Validate()
+}
+
+// ValidateUpdate implements admission.Validator
+func (p *RateLimitAlertPolicy) ValidateUpdate(_ runtime.Object) error {
+	return p.Validate()
+}
+
+// ValidateDelete implements admission.Validator, a policy can always be deleted
+func (p *RateLimitAlertPolicy) ValidateDelete() error {
+	return nil
+}
+
+// Validate checks the alerts of the policy can be turned into PrometheusRules. It is called by the
+// webhook and again before the alerts are generated, as the webhook is not enabled when the
+// operator runs locally
+func (p *RateLimitAlertPolicy) Validate() error {
+	if len(p.Spec.Alerts) == 0 {
+		return fmt.Errorf("policy %s has no alerts", p.Name)
+	}
+
+	names := map[string]bool{}
+	for _, alert := range p.Spec.Alerts {
+		if names[alert.Name] {
+			return fmt.Errorf("alert %s is defined more than once", alert.Name)
+		}
+		names[alert.Name] = true
+
+		if err := alert.validate(); err != nil {
+			return fmt.Errorf("invalid alert %s: %w", alert.Name, err)
+		}
+	}
+
+	return nil
+}
+
+func (a RateLimitAlert) validate() error {
+	if len(a.Name) > 63 || !rateLimitAlertNameRegexp.MatchString(a.Name) {
+		return fmt.Errorf("name must be a lowercase RFC 1123 label of at most 63 characters")
+	}
+	if !rateLimitAlertRuleNameRegexp.MatchString(a.RuleName) {
+		return fmt.Errorf("invalid rule name %q", a.RuleName)
+	}
+	if !rateLimitAlertLevels[a.Level] {
+		return fmt.Errorf("invalid level %q, must be info, warning or critical", a.Level)
+	}
+	if a.Period != "" {
+		if _, err := parseRateLimitAlertPeriod(a.Period); err != nil {
+			return err
+		}
+	}
+
+	switch a.Type {
+	case RateLimitAlertTypeThreshold:
+		if a.Period == "" {
+			return fmt.Errorf("period is required by the %s type", a.Type)
+		}
+		if a.Threshold == nil {
+			return fmt.Errorf("threshold is required by the %s type", a.Type)
+		}
+		if a.Sustained != nil {
+			return fmt.Errorf("sustained can not be set for the %s type", a.Type)
+		}
+		minRate, err := parseRateLimitAlertRate(a.Threshold.MinRate)
+		if err != nil {
+			return err
+		}
+		if a.Threshold.MaxRate != nil {
+			maxRate, err := parseRateLimitAlertRate(*a.Threshold.MaxRate)
+			if err != nil {
+				return err
+			}
+			if minRate > maxRate {
+				return fmt.Errorf("min rate %s must be less than or equal to max rate %s", a.Threshold.MinRate, *a.Threshold.MaxRate)
+			}
+		}
+	case RateLimitAlertTypeSpike:
+		if a.Period == "" {
+			return fmt.Errorf("period is required by the %s type", a.Type)
+		}
+		if a.Threshold != nil || a.Sustained != nil {
+			return fmt.Errorf("threshold and sustained can not be set for the %s type", a.Type)
+		}
+	case RateLimitAlertTypeSustained:
+		if a.Sustained == nil {
+			return fmt.Errorf("sustained is required by the %s type", a.Type)
+		}
+		if a.Threshold != nil {
+			return fmt.Errorf("threshold can not be set for the %s type", a.Type)
+		}
+		if _, err := parseRateLimitAlertRate(a.Sustained.MinRate); err != nil {
+			return err
+		}
+		if a.Sustained.Minutes < 1 {
+			return fmt.Errorf("sustained minutes must be at least 1, got %d", a.Sustained.Minutes)
+		}
+	default:
+		return fmt.Errorf("unsupported type %q, must be %s, %s or %s", a.Type, RateLimitAlertTypeThreshold, RateLimitAlertTypeSpike, RateLimitAlertTypeSustained)
+	}
+
+	return nil
+}
+
+// parseRateLimitAlertPeriod returns the minutes in a period of minutes or hours such as 30m or 4h
+func parseRateLimitAlertPeriod(period string) (int, error) {
+	matches := rateLimitAlertPeriodRegexp.FindStringSubmatch(period)
+	if matches == nil {
+		return 0, fmt.Errorf("invalid period %q, must be a number of minutes or hours such as 30m or 4h", period)
+	}
+	value, err := strconv.Atoi(matches[1])
+	if err != nil {
+		return 0, fmt.Errorf("invalid period %q: %w", period, err)
+	}
+	if value == 0 {
+		return 0, fmt.Errorf("invalid period %q, must be greater than zero", period)
+	}
+	if period[len(period)-1] == 'h' {
+		value *= 60
+	}
+
+	return value, nil
+}
+
+// parseRateLimitAlertRate returns the value of a percentage of the rate limit such as 80%
+func parseRateLimitAlertRate(rate string) (int, error) {
+	matches := rateLimitAlertRateRegexp.FindStringSubmatch(rate)
+	if matches == nil {
+		return 0, fmt.Errorf("invalid rate %q, must be a percentage such as 80%%", rate)
+	}
+	value, err := strconv.Atoi(matches[1])
+	if err != nil || value > 100 {
+		return 0, fmt.Errorf("invalid rate %q, must be between 0%% and 100%%", rate)
+	}
+
+	return value, nil
+}
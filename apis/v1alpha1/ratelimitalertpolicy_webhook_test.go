@@ -0,0 +1,121 @@
+package v1alpha1
+
+import (
+	"testing"
+)
+
+func TestRateLimitAlertPolicy_Validate(t *testing.T) {
+	maxRate := "90%"
+	lowMaxRate := "70%"
+	validAlerts := []RateLimitAlert{
+		{
+			Name:      "api-usage-level1",
+			Type:      RateLimitAlertTypeThreshold,
+			RuleName:  "RHOAMApiUsageLevel1ThresholdExceeded",
+			Level:     "info",
+			Period:    "4h",
+			Threshold: &RateLimitAlertThreshold{MinRate: "80%", MaxRate: &maxRate},
+		},
+		{
+			Name:     "rate-limit-spike",
+			Type:     RateLimitAlertTypeSpike,
+			RuleName: "RHOAMApiUsageOverLimit",
+			Level:    "warning",
+			Period:   "30m",
+		},
+		{
+			Name:      "api-usage-sustained",
+			Type:      RateLimitAlertTypeSustained,
+			RuleName:  "RHOAMApiUsageSustained",
+			Level:     "critical",
+			Sustained: &RateLimitAlertSustained{MinRate: "95%", Minutes: 15},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		alerts  []RateLimitAlert
+		wantErr bool
+	}{
+		{
+			name:   "test alerts of every type are valid",
+			alerts: validAlerts,
+		},
+		{
+			name:    "test policy without alerts is invalid",
+			wantErr: true,
+		},
+		{
+			name:    "test duplicated alert names are invalid",
+			alerts:  append(validAlerts, validAlerts[0]),
+			wantErr: true,
+		},
+		{
+			name: "test threshold without threshold rates is invalid",
+			alerts: []RateLimitAlert{
+				{Name: "level", Type: RateLimitAlertTypeThreshold, RuleName: "Level", Level: "info", Period: "4h"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "test threshold with min rate over max rate is invalid",
+			alerts: []RateLimitAlert{
+				{Name: "level", Type: RateLimitAlertTypeThreshold, RuleName: "Level", Level: "info", Period: "4h",
+					Threshold: &RateLimitAlertThreshold{MinRate: "80%", MaxRate: &lowMaxRate}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "test rate over 100% is invalid",
+			alerts: []RateLimitAlert{
+				{Name: "level", Type: RateLimitAlertTypeThreshold, RuleName: "Level", Level: "info", Period: "4h",
+					Threshold: &RateLimitAlertThreshold{MinRate: "120%"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "test spike without period is invalid",
+			alerts: []RateLimitAlert{
+				{Name: "spike", Type: RateLimitAlertTypeSpike, RuleName: "Spike", Level: "warning"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "test invalid period is invalid",
+			alerts: []RateLimitAlert{
+				{Name: "spike", Type: RateLimitAlertTypeSpike, RuleName: "Spike", Level: "warning", Period: "2d"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "test sustained without minutes is invalid",
+			alerts: []RateLimitAlert{
+				{Name: "sustained", Type: RateLimitAlertTypeSustained, RuleName: "Sustained", Level: "info",
+					Sustained: &RateLimitAlertSustained{MinRate: "80%"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "test unknown type is invalid",
+			alerts: []RateLimitAlert{
+				{Name: "unknown", Type: "Unknown", RuleName: "Unknown", Level: "info", Period: "1h"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "test invalid level is invalid",
+			alerts: []RateLimitAlert{
+				{Name: "spike", Type: RateLimitAlertTypeSpike, RuleName: "Spike", Level: "page", Period: "1h"},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := &RateLimitAlertPolicy{Spec: RateLimitAlertPolicySpec{Alerts: tt.alerts}}
+			if err := policy.ValidateCreate(); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateCreate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -17,6 +17,8 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"time"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -42,18 +44,16 @@ var (
 	InstallationTypeManagedApi            InstallationType = "managed-api"
 	InstallationTypeMultitenantManagedApi InstallationType = "multitenant-managed-api"
 
-	BootstrapStage               StageName = "bootstrap"
-	InstallStage                 StageName = "installation"
-	CloudResourcesStage          StageName = "cloud-resources"
-	MonitoringStage              StageName = "monitoring"
-	ObservabilityStage           StageName = "observability"
-	AuthenticationStage          StageName = "authentication"
-	ProductsStage                StageName = "products"
-	CompleteStage                StageName = "complete"
-	UninstallProductsStage       StageName = "uninstall - products"
-	UninstallObservabilityStage  StageName = "uninstall - observability"
-	UninstallCloudResourcesStage StageName = "uninstall - cloud-resources"
-	UninstallBootstrap           StageName = "uninstall - bootstrap"
+	BootstrapStage         StageName = "bootstrap"
+	InstallStage           StageName = "installation"
+	CloudResourcesStage    StageName = "cloud-resources"
+	MonitoringStage        StageName = "monitoring"
+	ObservabilityStage     StageName = "observability"
+	AuthenticationStage    StageName = "authentication"
+	ProductsStage          StageName = "products"
+	CompleteStage          StageName = "complete"
+	UninstallProductsStage StageName = "uninstall - products"
+	UninstallBootstrap     StageName = "uninstall - bootstrap"
 
 	ProductRHSSO          ProductName = "rhsso"
 	ProductRHSSOUser      ProductName = "rhssouser"
@@ -99,6 +99,8 @@ var (
 	EventInstallationCompleted string = "InstallationCompleted"
 	EventPreflightCheckPassed  string = "PreflightCheckPassed"
 	EventUpgradeApproved       string = "UpgradeApproved"
+	EventQuotaChange           string = "QuotaChange"
+	EventQuotaRollback         string = "QuotaRollback"
 
 	DefaultOriginPullSecretName      = "pull-secret"
 	DefaultOriginPullSecretNamespace = "openshift-config" // #nosec G101 -- This is a false positive
@@ -107,6 +109,44 @@ var (
 	EnvKeyQuota         = "QUOTA"
 )
 
+// DryRunAnnotation is set to "true" on the copy of the RHMI CR that is planned by the --plan flag
+// of the operator, the products make no changes outside of the cluster for it. The installation
+// controller removes it from the RHMI CR
+const DryRunAnnotation = "integreatly.org/dry-run"
+
+// DefaultPausedProductsAlertWindow is how long a product can be paused before an alert is raised
+// when the window is not set in the spec
+const DefaultPausedProductsAlertWindow = 2 * time.Hour
+
+// Condition types reported in the RHMI status and in each product status
+const (
+	ConditionTypeReady           = "Ready"
+	ConditionTypeProgressing     = "Progressing"
+	ConditionTypeDegraded        = "Degraded"
+	ConditionTypeUpgrading       = "Upgrading"
+	ConditionTypePreflightPassed = "PreflightPassed"
+	ConditionTypeUninstalling    = "Uninstalling"
+	ConditionTypePaused          = "Paused"
+)
+
+// Condition reasons used with the condition types above
+const (
+	ConditionReasonInstallComplete      = "InstallComplete"
+	ConditionReasonInstallInProgress    = "InstallInProgress"
+	ConditionReasonReconcileError       = "ReconcileError"
+	ConditionReasonReconcileSucceeded   = "ReconcileSucceeded"
+	ConditionReasonUpgradeInProgress    = "UpgradeInProgress"
+	ConditionReasonNoUpgrade            = "NoUpgrade"
+	ConditionReasonPreflightInProgress  = "PreflightInProgress"
+	ConditionReasonPreflightSucceeded   = "PreflightSucceeded"
+	ConditionReasonPreflightFailed      = "PreflightFailed"
+	ConditionReasonDeletionRequested    = "DeletionRequested"
+	ConditionReasonNotDeleted           = "NotDeleted"
+	ConditionReasonAwaitingDependencies = "AwaitingDependencies"
+	ConditionReasonPausedBySpec         = "PausedBySpec"
+	ConditionReasonNotPaused            = "NotPaused"
+)
+
 // RHMISpec defines the desired state of RHMI
 type RHMISpec struct {
 	// INSERT ADDITIONAL SPEC FIELDS - desired state of cluster
@@ -158,6 +198,33 @@ type RHMISpec struct {
 	//
 	// url
 	DeadMansSnitchSecret string `json:"deadMansSnitchSecret,omitempty"`
+
+	// TrustedCABundle references the PEM encoded CA certificates
+	// the operator trusts when it calls the product APIs, in
+	// addition to the system CAs and the trusted CA bundle
+	// injected by OpenShift. Clusters with a private CA should
+	// trust it here instead of setting SelfSignedCerts
+	// +optional
+	TrustedCABundle *TrustedCABundleSpec `json:"trustedCABundle,omitempty"`
+
+	// PausedProducts are the products the operator stops
+	// reconciling, for example while a product is being fixed
+	// by hand. The status of a paused product is kept as it
+	// was when the product was paused
+	// +optional
+	PausedProducts []ProductName `json:"pausedProducts,omitempty"`
+
+	// PausedProductsAlertWindow is how long a product can be
+	// paused before an alert is raised, defaults to 2h
+	// +optional
+	PausedProductsAlertWindow *metav1.Duration `json:"pausedProductsAlertWindow,omitempty"`
+
+	// TenantQuota is the default quota of the APIManagementTenants
+	// of a multitenant installation. It caps the objects each
+	// tenant can create in its 3scale account, tenants override
+	// it with the quota in their spec
+	// +optional
+	TenantQuota *TenantQuota `json:"tenantQuota,omitempty"`
 }
 
 type PullSecretSpec struct {
@@ -165,6 +232,25 @@ type PullSecretSpec struct {
 	Namespace string `json:"namespace"`
 }
 
+// TrustedCABundleSpec references a config map or secret holding a CA bundle
+type TrustedCABundleSpec struct {
+	// Kind is the kind of the resource holding the bundle, either
+	// ConfigMap or Secret
+	// +kubebuilder:validation:Enum=ConfigMap;Secret
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+	// Namespace defaults to the namespace of the installation
+	Namespace string `json:"namespace,omitempty"`
+	// Key is the key of the bundle in the resource, defaults to
+	// ca-bundle.crt
+	Key string `json:"key,omitempty"`
+}
+
+const (
+	TrustedCABundleKindConfigMap = "ConfigMap"
+	TrustedCABundleKindSecret    = "Secret"
+)
+
 type AlertingEmailAddresses struct {
 	BusinessUnit string `json:"businessUnit"`
 	CSSRE        string `json:"cssre"`
@@ -180,6 +266,13 @@ type CustomDomainStatus struct {
 	Error   string `json:"error,omitempty"`
 }
 
+type CustomQuotaStatus struct {
+	// Quotas are the names of the custom quotas that can be selected
+	Quotas []string `json:"quotas,omitempty"`
+	// Errors are the reasons the invalid custom quotas were rejected
+	Errors []string `json:"errors,omitempty"`
+}
+
 // RHMIStatus defines the observed state of RHMI
 type RHMIStatus struct {
 	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
@@ -197,6 +290,97 @@ type RHMIStatus struct {
 	ToQuota            string                        `json:"toQuota,omitempty"`
 	CustomSmtp         *CustomSmtpStatus             `json:"customSmtp,omitempty"`
 	CustomDomain       *CustomDomainStatus           `json:"customDomain,omitempty"`
+
+	// ProductGraph is the product dependency graph computed for the
+	// installation type. It is only reported to help debugging the
+	// install and uninstall order
+	ProductGraph *ProductGraphStatus `json:"productGraph,omitempty"`
+
+	// RateLimitUsage summarises the usage of the rate limits of the
+	// installation, it is read periodically from the rate limit service
+	RateLimitUsage *RateLimitUsageSummary `json:"rateLimitUsage,omitempty"`
+
+	// QuotaChange reports the change from Quota to ToQuota, which is
+	// applied to one product at a time and rolled back if a product
+	// does not become ready
+	QuotaChange *QuotaChangeStatus `json:"quotaChange,omitempty"`
+
+	// CustomQuotas reports the quotas read from the custom quota config
+	// map in the operator namespace, and why invalid ones were rejected
+	CustomQuotas *CustomQuotaStatus `json:"customQuotas,omitempty"`
+
+	// Conditions are the standard conditions for the installation, they
+	// are kept in sync with the stage and errors on every reconcile
+	// +optional
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+}
+
+type ProductGraphStatus struct {
+	// Dependencies maps each product to the products that must be
+	// completed before it is reconciled
+	Dependencies map[ProductName][]ProductName `json:"dependencies,omitempty"`
+	// InstallOrder groups the products into layers that are installed in order
+	InstallOrder []ProductGraphLayer `json:"installOrder,omitempty"`
+	// UninstallOrder groups the products into layers that are removed in order
+	UninstallOrder []ProductGraphLayer `json:"uninstallOrder,omitempty"`
+}
+
+type RateLimitUsageSummary struct {
+	// Global is the usage of the limit shared by every request to the installation
+	Global *RateLimitUsage `json:"global,omitempty"`
+	// Tenants is the number of tenants with requests counted in the
+	// current window of their limit
+	Tenants int32 `json:"tenants,omitempty"`
+	// TenantsAtLimit is the number of tenants that used every request
+	// allowed in the current window of their limit
+	TenantsAtLimit int32 `json:"tenantsAtLimit,omitempty"`
+}
+
+type QuotaChangePhase string
+
+var (
+	QuotaChangeInProgress  QuotaChangePhase = "InProgress"
+	QuotaChangeRollingBack QuotaChangePhase = "RollingBack"
+	QuotaChangeRolledBack  QuotaChangePhase = "RolledBack"
+)
+
+type QuotaChangeStatus struct {
+	FromQuota string           `json:"fromQuota"`
+	ToQuota   string           `json:"toQuota"`
+	Phase     QuotaChangePhase `json:"phase"`
+	// Changes are the replicas and resources of the components that
+	// are different in the new quota
+	Changes []QuotaComponentChange `json:"changes,omitempty"`
+	// Products are the products with changes, in the order the new
+	// quota is applied to them
+	Products []ProductName `json:"products,omitempty"`
+	// AppliedProducts are the products the new quota was applied to
+	// that became ready
+	AppliedProducts []ProductName `json:"appliedProducts,omitempty"`
+	// CurrentProduct is the product waited on to become ready with the
+	// new quota, it is rolled back if it is not ready before the timeout
+	CurrentProduct          ProductName  `json:"currentProduct,omitempty"`
+	CurrentProductStartTime *metav1.Time `json:"currentProductStartTime,omitempty"`
+	Message                 string       `json:"message,omitempty"`
+}
+
+type QuotaComponentChange struct {
+	Product      ProductName `json:"product"`
+	Component    string      `json:"component"`
+	FromReplicas int32       `json:"fromReplicas"`
+	ToReplicas   int32       `json:"toReplicas"`
+	// FromResources and ToResources are the requests and limits of the
+	// component, for example "requests: cpu=250m,memory=450Mi limits: cpu=300m,memory=500Mi"
+	FromResources string `json:"fromResources,omitempty"`
+	ToResources   string `json:"toResources,omitempty"`
+}
+
+type ProductGraphLayer struct {
+	Products []ProductName `json:"products"`
 }
 
 type RHMIStageStatus struct {
@@ -214,6 +398,37 @@ type RHMIProductStatus struct {
 	Mobile          bool            `json:"mobile,omitempty"`
 	Phase           StatusPhase     `json:"status"`
 	Uninstall       bool            `json:"uninstall,omitempty"`
+	// Paused is set while the product is listed in the
+	// pausedProducts of the spec and is not reconciled
+	Paused bool `json:"paused,omitempty"`
+	// PausedSince is when the product was paused
+	// +optional
+	PausedSince *metav1.Time `json:"pausedSince,omitempty"`
+
+	// ObservedGeneration is the RHMI generation the product was last reconciled for
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Conditions are the standard conditions for the product
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// Errors holds the most recent distinct errors returned by the
+	// product reconciler, oldest first
+	// +optional
+	Errors []ProductError `json:"errors,omitempty"`
+}
+
+// ProductError is an error returned by a product reconciler, repeated
+// occurrences of the same error are counted instead of being added again
+type ProductError struct {
+	Message string `json:"message"`
+	// Reason is a short classification of the error
+	Reason string `json:"reason"`
+	// Phase is the phase the product reconciler returned with the error
+	Phase     StatusPhase `json:"phase"`
+	FirstSeen metav1.Time `json:"firstSeen"`
+	LastSeen  metav1.Time `json:"lastSeen"`
+	Count     int32       `json:"count"`
 }
 
 // +kubebuilder:object:root=true
@@ -239,6 +454,29 @@ func (i *RHMI) GetProductStatusObject(product ProductName) *RHMIProductStatus {
 	}
 }
 
+// IsDryRun returns true when the installation should only be planned, see DryRunAnnotation
+func (i *RHMI) IsDryRun() bool {
+	return i.GetAnnotations()[DryRunAnnotation] == "true"
+}
+
+// IsProductPaused returns true when the product is listed in the paused products of the spec
+func (i *RHMI) IsProductPaused(product ProductName) bool {
+	for _, paused := range i.Spec.PausedProducts {
+		if paused == product {
+			return true
+		}
+	}
+	return false
+}
+
+// GetPausedProductsAlertWindow returns how long a product can be paused before an alert is raised
+func (i *RHMI) GetPausedProductsAlertWindow() time.Duration {
+	if i.Spec.PausedProductsAlertWindow == nil {
+		return DefaultPausedProductsAlertWindow
+	}
+	return i.Spec.PausedProductsAlertWindow.Duration
+}
+
 func (i *RHMI) GetPullSecretSpec() *PullSecretSpec {
 	if i.Spec.PullSecret.Name != "" && i.Spec.PullSecret.Namespace != "" {
 		return &(i.Spec.PullSecret)
@@ -22,6 +22,7 @@ limitations under the License.
 package v1alpha1
 
 import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -30,8 +31,8 @@ func (in *APIManagementTenant) DeepCopyInto(out *APIManagementTenant) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
-	out.Status = in.Status
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIManagementTenant.
@@ -87,6 +88,21 @@ func (in *APIManagementTenantList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *APIManagementTenantSpec) DeepCopyInto(out *APIManagementTenantSpec) {
 	*out = *in
+	if in.RateLimit != nil {
+		in, out := &in.RateLimit, &out.RateLimit
+		*out = new(TenantRateLimit)
+		**out = **in
+	}
+	if in.Quota != nil {
+		in, out := &in.Quota, &out.Quota
+		*out = new(TenantQuota)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Owner != nil {
+		in, out := &in.Owner, &out.Owner
+		*out = new(TenantOwner)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIManagementTenantSpec.
@@ -102,6 +118,31 @@ func (in *APIManagementTenantSpec) DeepCopy() *APIManagementTenantSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *APIManagementTenantStatus) DeepCopyInto(out *APIManagementTenantStatus) {
 	*out = *in
+	if in.RateLimit != nil {
+		in, out := &in.RateLimit, &out.RateLimit
+		*out = new(TenantRateLimitStatus)
+		**out = **in
+	}
+	if in.RateLimitUsage != nil {
+		in, out := &in.RateLimitUsage, &out.RateLimitUsage
+		*out = new(RateLimitUsage)
+		**out = **in
+	}
+	if in.Export != nil {
+		in, out := &in.Export, &out.Export
+		*out = new(TenantArchiveStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Import != nil {
+		in, out := &in.Import, &out.Import
+		*out = new(TenantArchiveStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Quota != nil {
+		in, out := &in.Quota, &out.Quota
+		*out = new(TenantQuotaStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIManagementTenantStatus.
@@ -253,6 +294,31 @@ func (in *CustomDomainStatus) DeepCopy() *CustomDomainStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CustomQuotaStatus) DeepCopyInto(out *CustomQuotaStatus) {
+	*out = *in
+	if in.Quotas != nil {
+		in, out := &in.Quotas, &out.Quotas
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Errors != nil {
+		in, out := &in.Errors, &out.Errors
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CustomQuotaStatus.
+func (in *CustomQuotaStatus) DeepCopy() *CustomQuotaStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CustomQuotaStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CustomSmtpStatus) DeepCopyInto(out *CustomSmtpStatus) {
 	*out = *in
@@ -268,6 +334,87 @@ func (in *CustomSmtpStatus) DeepCopy() *CustomSmtpStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProductError) DeepCopyInto(out *ProductError) {
+	*out = *in
+	in.FirstSeen.DeepCopyInto(&out.FirstSeen)
+	in.LastSeen.DeepCopyInto(&out.LastSeen)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProductError.
+func (in *ProductError) DeepCopy() *ProductError {
+	if in == nil {
+		return nil
+	}
+	out := new(ProductError)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProductGraphLayer) DeepCopyInto(out *ProductGraphLayer) {
+	*out = *in
+	if in.Products != nil {
+		in, out := &in.Products, &out.Products
+		*out = make([]ProductName, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProductGraphLayer.
+func (in *ProductGraphLayer) DeepCopy() *ProductGraphLayer {
+	if in == nil {
+		return nil
+	}
+	out := new(ProductGraphLayer)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProductGraphStatus) DeepCopyInto(out *ProductGraphStatus) {
+	*out = *in
+	if in.Dependencies != nil {
+		in, out := &in.Dependencies, &out.Dependencies
+		*out = make(map[ProductName][]ProductName, len(*in))
+		for key, val := range *in {
+			var outVal []ProductName
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = make([]ProductName, len(*in))
+				copy(*out, *in)
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.InstallOrder != nil {
+		in, out := &in.InstallOrder, &out.InstallOrder
+		*out = make([]ProductGraphLayer, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.UninstallOrder != nil {
+		in, out := &in.UninstallOrder, &out.UninstallOrder
+		*out = make([]ProductGraphLayer, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProductGraphStatus.
+func (in *ProductGraphStatus) DeepCopy() *ProductGraphStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ProductGraphStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PullSecretSpec) DeepCopyInto(out *PullSecretSpec) {
 	*out = *in
@@ -283,12 +430,61 @@ func (in *PullSecretSpec) DeepCopy() *PullSecretSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QuotaChangeStatus) DeepCopyInto(out *QuotaChangeStatus) {
+	*out = *in
+	if in.Changes != nil {
+		in, out := &in.Changes, &out.Changes
+		*out = make([]QuotaComponentChange, len(*in))
+		copy(*out, *in)
+	}
+	if in.Products != nil {
+		in, out := &in.Products, &out.Products
+		*out = make([]ProductName, len(*in))
+		copy(*out, *in)
+	}
+	if in.AppliedProducts != nil {
+		in, out := &in.AppliedProducts, &out.AppliedProducts
+		*out = make([]ProductName, len(*in))
+		copy(*out, *in)
+	}
+	if in.CurrentProductStartTime != nil {
+		in, out := &in.CurrentProductStartTime, &out.CurrentProductStartTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QuotaChangeStatus.
+func (in *QuotaChangeStatus) DeepCopy() *QuotaChangeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(QuotaChangeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QuotaComponentChange) DeepCopyInto(out *QuotaComponentChange) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QuotaComponentChange.
+func (in *QuotaComponentChange) DeepCopy() *QuotaComponentChange {
+	if in == nil {
+		return nil
+	}
+	out := new(QuotaComponentChange)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RHMI) DeepCopyInto(out *RHMI) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 }
 
@@ -402,6 +598,24 @@ func (in *RHMIList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RHMIProductStatus) DeepCopyInto(out *RHMIProductStatus) {
 	*out = *in
+	if in.PausedSince != nil {
+		in, out := &in.PausedSince, &out.PausedSince
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Errors != nil {
+		in, out := &in.Errors, &out.Errors
+		*out = make([]ProductError, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RHMIProductStatus.
@@ -419,6 +633,26 @@ func (in *RHMISpec) DeepCopyInto(out *RHMISpec) {
 	*out = *in
 	out.PullSecret = in.PullSecret
 	out.AlertingEmailAddresses = in.AlertingEmailAddresses
+	if in.TrustedCABundle != nil {
+		in, out := &in.TrustedCABundle, &out.TrustedCABundle
+		*out = new(TrustedCABundleSpec)
+		**out = **in
+	}
+	if in.PausedProducts != nil {
+		in, out := &in.PausedProducts, &out.PausedProducts
+		*out = make([]ProductName, len(*in))
+		copy(*out, *in)
+	}
+	if in.PausedProductsAlertWindow != nil {
+		in, out := &in.PausedProductsAlertWindow, &out.PausedProductsAlertWindow
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.TenantQuota != nil {
+		in, out := &in.TenantQuota, &out.TenantQuota
+		*out = new(TenantQuota)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RHMISpec.
@@ -438,7 +672,7 @@ func (in *RHMIStageStatus) DeepCopyInto(out *RHMIStageStatus) {
 		in, out := &in.Products, &out.Products
 		*out = make(map[ProductName]RHMIProductStatus, len(*in))
 		for key, val := range *in {
-			(*out)[key] = val
+			(*out)[key] = *val.DeepCopy()
 		}
 	}
 }
@@ -473,6 +707,33 @@ func (in *RHMIStatus) DeepCopyInto(out *RHMIStatus) {
 		*out = new(CustomDomainStatus)
 		**out = **in
 	}
+	if in.ProductGraph != nil {
+		in, out := &in.ProductGraph, &out.ProductGraph
+		*out = new(ProductGraphStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RateLimitUsage != nil {
+		in, out := &in.RateLimitUsage, &out.RateLimitUsage
+		*out = new(RateLimitUsageSummary)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.QuotaChange != nil {
+		in, out := &in.QuotaChange, &out.QuotaChange
+		*out = new(QuotaChangeStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CustomQuotas != nil {
+		in, out := &in.CustomQuotas, &out.CustomQuotas
+		*out = new(CustomQuotaStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RHMIStatus.
@@ -484,3 +745,350 @@ func (in *RHMIStatus) DeepCopy() *RHMIStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RateLimitAlert) DeepCopyInto(out *RateLimitAlert) {
+	*out = *in
+	if in.Threshold != nil {
+		in, out := &in.Threshold, &out.Threshold
+		*out = new(RateLimitAlertThreshold)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Sustained != nil {
+		in, out := &in.Sustained, &out.Sustained
+		*out = new(RateLimitAlertSustained)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RateLimitAlert.
+func (in *RateLimitAlert) DeepCopy() *RateLimitAlert {
+	if in == nil {
+		return nil
+	}
+	out := new(RateLimitAlert)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RateLimitAlertPolicy) DeepCopyInto(out *RateLimitAlertPolicy) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RateLimitAlertPolicy.
+func (in *RateLimitAlertPolicy) DeepCopy() *RateLimitAlertPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(RateLimitAlertPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RateLimitAlertPolicy) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RateLimitAlertPolicyList) DeepCopyInto(out *RateLimitAlertPolicyList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]RateLimitAlertPolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RateLimitAlertPolicyList.
+func (in *RateLimitAlertPolicyList) DeepCopy() *RateLimitAlertPolicyList {
+	if in == nil {
+		return nil
+	}
+	out := new(RateLimitAlertPolicyList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RateLimitAlertPolicyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RateLimitAlertPolicySpec) DeepCopyInto(out *RateLimitAlertPolicySpec) {
+	*out = *in
+	if in.Alerts != nil {
+		in, out := &in.Alerts, &out.Alerts
+		*out = make([]RateLimitAlert, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RateLimitAlertPolicySpec.
+func (in *RateLimitAlertPolicySpec) DeepCopy() *RateLimitAlertPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RateLimitAlertPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RateLimitAlertPolicyStatus) DeepCopyInto(out *RateLimitAlertPolicyStatus) {
+	*out = *in
+	if in.PrometheusRules != nil {
+		in, out := &in.PrometheusRules, &out.PrometheusRules
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RateLimitAlertPolicyStatus.
+func (in *RateLimitAlertPolicyStatus) DeepCopy() *RateLimitAlertPolicyStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RateLimitAlertPolicyStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RateLimitAlertSustained) DeepCopyInto(out *RateLimitAlertSustained) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RateLimitAlertSustained.
+func (in *RateLimitAlertSustained) DeepCopy() *RateLimitAlertSustained {
+	if in == nil {
+		return nil
+	}
+	out := new(RateLimitAlertSustained)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RateLimitAlertThreshold) DeepCopyInto(out *RateLimitAlertThreshold) {
+	*out = *in
+	if in.MaxRate != nil {
+		in, out := &in.MaxRate, &out.MaxRate
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RateLimitAlertThreshold.
+func (in *RateLimitAlertThreshold) DeepCopy() *RateLimitAlertThreshold {
+	if in == nil {
+		return nil
+	}
+	out := new(RateLimitAlertThreshold)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RateLimitUsage) DeepCopyInto(out *RateLimitUsage) {
+	*out = *in
+	out.TenantRateLimit = in.TenantRateLimit
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RateLimitUsage.
+func (in *RateLimitUsage) DeepCopy() *RateLimitUsage {
+	if in == nil {
+		return nil
+	}
+	out := new(RateLimitUsage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RateLimitUsageSummary) DeepCopyInto(out *RateLimitUsageSummary) {
+	*out = *in
+	if in.Global != nil {
+		in, out := &in.Global, &out.Global
+		*out = new(RateLimitUsage)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RateLimitUsageSummary.
+func (in *RateLimitUsageSummary) DeepCopy() *RateLimitUsageSummary {
+	if in == nil {
+		return nil
+	}
+	out := new(RateLimitUsageSummary)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TenantArchiveStatus) DeepCopyInto(out *TenantArchiveStatus) {
+	*out = *in
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantArchiveStatus.
+func (in *TenantArchiveStatus) DeepCopy() *TenantArchiveStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TenantArchiveStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TenantObjectCounts) DeepCopyInto(out *TenantObjectCounts) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantObjectCounts.
+func (in *TenantObjectCounts) DeepCopy() *TenantObjectCounts {
+	if in == nil {
+		return nil
+	}
+	out := new(TenantObjectCounts)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TenantOwner) DeepCopyInto(out *TenantOwner) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantOwner.
+func (in *TenantOwner) DeepCopy() *TenantOwner {
+	if in == nil {
+		return nil
+	}
+	out := new(TenantOwner)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TenantQuota) DeepCopyInto(out *TenantQuota) {
+	*out = *in
+	if in.Services != nil {
+		in, out := &in.Services, &out.Services
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Backends != nil {
+		in, out := &in.Backends, &out.Backends
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Applications != nil {
+		in, out := &in.Applications, &out.Applications
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Users != nil {
+		in, out := &in.Users, &out.Users
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantQuota.
+func (in *TenantQuota) DeepCopy() *TenantQuota {
+	if in == nil {
+		return nil
+	}
+	out := new(TenantQuota)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TenantQuotaStatus) DeepCopyInto(out *TenantQuotaStatus) {
+	*out = *in
+	in.Quota.DeepCopyInto(&out.Quota)
+	out.Usage = in.Usage
+	if in.Exceeded != nil {
+		in, out := &in.Exceeded, &out.Exceeded
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.LastCounted.DeepCopyInto(&out.LastCounted)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantQuotaStatus.
+func (in *TenantQuotaStatus) DeepCopy() *TenantQuotaStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TenantQuotaStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TenantRateLimit) DeepCopyInto(out *TenantRateLimit) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantRateLimit.
+func (in *TenantRateLimit) DeepCopy() *TenantRateLimit {
+	if in == nil {
+		return nil
+	}
+	out := new(TenantRateLimit)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TenantRateLimitStatus) DeepCopyInto(out *TenantRateLimitStatus) {
+	*out = *in
+	out.TenantRateLimit = in.TenantRateLimit
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantRateLimitStatus.
+func (in *TenantRateLimitStatus) DeepCopy() *TenantRateLimitStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TenantRateLimitStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TrustedCABundleSpec) DeepCopyInto(out *TrustedCABundleSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrustedCABundleSpec.
+func (in *TrustedCABundleSpec) DeepCopy() *TrustedCABundleSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TrustedCABundleSpec)
+	in.DeepCopyInto(out)
+	return out
+}
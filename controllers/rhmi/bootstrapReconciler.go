@@ -3,7 +3,6 @@ package controllers
 import (
 	"context"
 	"crypto/rand"
-	"encoding/json"
 	"fmt"
 	customDomain "github.com/integr8ly/integreatly-operator/pkg/resources/custom-domain"
 	userHelper "github.com/integr8ly/integreatly-operator/pkg/resources/user"
@@ -11,6 +10,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/meta"
 	"math/big"
 	"os"
+	"sort"
 	"strings"
 
 	"github.com/integr8ly/integreatly-operator/pkg/addon"
@@ -25,6 +25,7 @@ import (
 
 	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/apis/v1alpha1"
 	rhmiv1alpha1 "github.com/integr8ly/integreatly-operator/apis/v1alpha1"
+	integreatlyclient "github.com/integr8ly/integreatly-operator/pkg/client"
 	"github.com/integr8ly/integreatly-operator/pkg/config"
 	"github.com/integr8ly/integreatly-operator/pkg/metrics"
 	"github.com/integr8ly/integreatly-operator/pkg/products/observability"
@@ -55,6 +56,10 @@ import (
 
 var tenantOauthclientSecretsName = "tenant-oauth-client-secrets" // #nosec G101 -- This is a false positive
 
+// rateLimitAlertsMigratedAnnotation is set on the installation once the rate-limit-alerts ConfigMap
+// was migrated to a RateLimitAlertPolicy
+const rateLimitAlertsMigratedAnnotation = "integreatly.org/rate-limit-alerts-migrated"
+
 func NewBootstrapReconciler(configManager config.ConfigReadWriter, installation *integreatlyv1alpha1.RHMI, mpm marketplace.MarketplaceInterface, recorder record.EventRecorder, logger l.Logger) (*Reconciler, error) {
 	return &Reconciler{
 		ConfigManager: configManager,
@@ -162,6 +167,12 @@ func (r *Reconciler) Reconcile(ctx context.Context, installation *integreatlyv1a
 		return phase, errors.Wrap(err, "failed to check cloud resources config settings")
 	}
 
+	phase, err = r.reconcileTrustedCABundle(ctx, serverClient)
+	if err != nil || phase != integreatlyv1alpha1.PhaseCompleted {
+		events.HandleError(r.recorder, installation, phase, "Failed to reconcile trusted CA bundle config map", err)
+		return phase, errors.Wrap(err, "failed to reconcile trusted CA bundle config map")
+	}
+
 	phase, err = r.reconcilePriorityClass(ctx, serverClient)
 	if err != nil || phase != integreatlyv1alpha1.PhaseCompleted {
 		events.HandleError(r.recorder, installation, phase, "Failed to reconcile priority class", err)
@@ -354,82 +365,140 @@ func (r *Reconciler) checkCloudResourcesConfig(ctx context.Context, serverClient
 	return integreatlyv1alpha1.PhaseCompleted, nil
 }
 
-func (r *Reconciler) checkRateLimitAlertsConfig(ctx context.Context, serverClient k8sclient.Client) (integreatlyv1alpha1.StatusPhase, error) {
-	alertsConfig := &corev1.ConfigMap{
+// reconcileTrustedCABundle creates the config map OpenShift injects the trusted CA bundle of the
+// cluster into, the bundle is trusted by the clients of the product APIs
+func (r *Reconciler) reconcileTrustedCABundle(ctx context.Context, serverClient k8sclient.Client) (integreatlyv1alpha1.StatusPhase, error) {
+	trustedCABundle := &corev1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      marin3rconfig.AlertConfigMapName,
+			Name:      integreatlyclient.TrustedCABundleConfigMapName,
 			Namespace: r.installation.Namespace,
 		},
 	}
 
-	if _, err := controllerutil.CreateOrUpdate(ctx, serverClient, alertsConfig, func() error {
-		owner.AddIntegreatlyOwnerAnnotations(alertsConfig, r.installation)
-
-		if alertsConfig.Data == nil {
-			alertsConfig.Data = map[string]string{}
+	if _, err := controllerutil.CreateOrUpdate(ctx, serverClient, trustedCABundle, func() error {
+		if trustedCABundle.Labels == nil {
+			trustedCABundle.Labels = map[string]string{}
 		}
+		trustedCABundle.Labels[integreatlyclient.TrustedCABundleInjectLabel] = "true"
+		return nil
+	}); err != nil {
+		return integreatlyv1alpha1.PhaseInProgress, err
+	}
+	return integreatlyv1alpha1.PhaseCompleted, nil
+}
 
-		if _, ok := alertsConfig.Data["alerts"]; ok {
-			return nil
-		}
+// checkRateLimitAlertsConfig creates the default RateLimitAlertPolicy if there is no policy yet.
+// The alerts configured in the rate-limit-alerts ConfigMap are migrated to the policy instead of
+// the defaults, and the ConfigMap is deleted once the policy exists. The migration only runs once
+// and is recorded in an annotation of the installation, so the policies the cluster admin deletes
+// later are not created again
+func (r *Reconciler) checkRateLimitAlertsConfig(ctx context.Context, serverClient k8sclient.Client) (integreatlyv1alpha1.StatusPhase, error) {
+	if r.installation.GetAnnotations()[rateLimitAlertsMigratedAnnotation] == "true" {
+		return integreatlyv1alpha1.PhaseCompleted, nil
+	}
 
-		maxRate1 := "90%"
-		maxRate2 := "95%"
+	policies, err := marin3rconfig.GetAlertPolicies(ctx, serverClient, r.installation.Namespace)
+	if err != nil {
+		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to list rate limit alert policies: %w", err)
+	}
 
-		defaultConfig := map[string]*marin3rconfig.AlertConfig{
-			"api-usage-alert-level1": {
-				Type:     marin3rconfig.AlertTypeThreshold,
-				RuleName: "RHOAMApiUsageLevel1ThresholdExceeded",
-				Level:    "info",
-				Threshold: &marin3rconfig.AlertThresholdConfig{
-					MinRate: "80%",
-					MaxRate: &maxRate1,
-				},
-				Period: "4h",
-			},
-			"api-usage-alert-level2": {
-				Type:     marin3rconfig.AlertTypeThreshold,
-				RuleName: "RHOAMApiUsageLevel2ThresholdExceeded",
-				Level:    "info",
-				Threshold: &marin3rconfig.AlertThresholdConfig{
-					MinRate: "90%",
-					MaxRate: &maxRate2,
-				},
-				Period: "2h",
-			},
-			"api-usage-alert-level3": {
-				Type:     marin3rconfig.AlertTypeThreshold,
-				RuleName: "RHOAMApiUsageLevel3ThresholdExceeded",
-				Level:    "info",
-				Threshold: &marin3rconfig.AlertThresholdConfig{
-					MinRate: "95%",
-					MaxRate: nil,
-				},
-				Period: "30m",
+	if len(policies) == 0 {
+		alertsConfig, err := marin3rconfig.GetAlertConfig(ctx, serverClient, r.installation.Namespace)
+		if err != nil && !k8serr.IsNotFound(err) {
+			return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to read %s config map to migrate: %w", marin3rconfig.AlertConfigMapName, err)
+		}
+		if len(alertsConfig) == 0 {
+			alertsConfig = getDefaultRateLimitAlertsConfig()
+		}
+
+		policy := &integreatlyv1alpha1.RateLimitAlertPolicy{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      marin3rconfig.AlertPolicyName,
+				Namespace: r.installation.Namespace,
 			},
-			"rate-limit-spike": {
-				Type:     marin3rconfig.AlertTypeSpike,
-				RuleName: "RHOAMApiUsageOverLimit",
-				Level:    "warning",
-				Period:   "30m",
+			Spec: integreatlyv1alpha1.RateLimitAlertPolicySpec{
+				Alerts: marin3rconfig.PolicyAlertsFromConfig(alertsConfig),
 			},
 		}
+		owner.AddIntegreatlyOwnerAnnotations(policy, r.installation)
 
-		defaultConfigJSON, err := json.MarshalIndent(defaultConfig, "", "  ")
-		if err != nil {
-			return err
+		// the config map is kept until it is fixed, so the alerts are not lost
+		if err := policy.Validate(); err != nil {
+			return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to migrate %s config map: %w", marin3rconfig.AlertConfigMapName, err)
+		}
+		if err := serverClient.Create(ctx, policy); err != nil && !k8serr.IsAlreadyExists(err) {
+			return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to create rate limit alert policy: %w", err)
 		}
+		r.log.Infof("Created rate limit alert policy", l.Fields{"policy": policy.Name, "alerts": len(policy.Spec.Alerts)})
+	}
 
-		alertsConfig.Data["alerts"] = string(defaultConfigJSON)
+	alertsConfigMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      marin3rconfig.AlertConfigMapName,
+			Namespace: r.installation.Namespace,
+		},
+	}
+	if err := serverClient.Delete(ctx, alertsConfigMap); err != nil && !k8serr.IsNotFound(err) {
+		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to delete migrated %s config map: %w", marin3rconfig.AlertConfigMapName, err)
+	} else if err == nil {
+		r.log.Infof("Deleted config map migrated to the rate limit alert policy", l.Fields{"configMap": marin3rconfig.AlertConfigMapName})
+	}
 
-		return nil
-	}); err != nil {
-		return integreatlyv1alpha1.PhaseInProgress, err
+	// the annotation is saved with the installation at the end of the reconcile
+	annotations := r.installation.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
 	}
+	annotations[rateLimitAlertsMigratedAnnotation] = "true"
+	r.installation.SetAnnotations(annotations)
 
 	return integreatlyv1alpha1.PhaseCompleted, nil
 }
 
+func getDefaultRateLimitAlertsConfig() map[string]*marin3rconfig.AlertConfig {
+	maxRate1 := "90%"
+	maxRate2 := "95%"
+
+	return map[string]*marin3rconfig.AlertConfig{
+		"api-usage-alert-level1": {
+			Type:     marin3rconfig.AlertTypeThreshold,
+			RuleName: "RHOAMApiUsageLevel1ThresholdExceeded",
+			Level:    "info",
+			Threshold: &marin3rconfig.AlertThresholdConfig{
+				MinRate: "80%",
+				MaxRate: &maxRate1,
+			},
+			Period: "4h",
+		},
+		"api-usage-alert-level2": {
+			Type:     marin3rconfig.AlertTypeThreshold,
+			RuleName: "RHOAMApiUsageLevel2ThresholdExceeded",
+			Level:    "info",
+			Threshold: &marin3rconfig.AlertThresholdConfig{
+				MinRate: "90%",
+				MaxRate: &maxRate2,
+			},
+			Period: "2h",
+		},
+		"api-usage-alert-level3": {
+			Type:     marin3rconfig.AlertTypeThreshold,
+			RuleName: "RHOAMApiUsageLevel3ThresholdExceeded",
+			Level:    "info",
+			Threshold: &marin3rconfig.AlertThresholdConfig{
+				MinRate: "95%",
+				MaxRate: nil,
+			},
+			Period: "30m",
+		},
+		"rate-limit-spike": {
+			Type:     marin3rconfig.AlertTypeSpike,
+			RuleName: "RHOAMApiUsageOverLimit",
+			Level:    "warning",
+			Period:   "30m",
+		},
+	}
+}
+
 func (r *Reconciler) reconcileTenantOauthSecrets(ctx context.Context, serverClient k8sclient.Client) (integreatlyv1alpha1.StatusPhase, error) {
 
 	allTenants, err := userHelper.GetMultiTenantUsers(ctx, serverClient, r.installation)
@@ -704,8 +773,6 @@ func generateSecret(length int) string {
 
 func (r *Reconciler) processQuota(installation *rhmiv1alpha1.RHMI, namespace string,
 	installationQuota *quota.Quota, serverClient k8sclient.Client) error {
-	isQuotaUpdated := false
-
 	quotaParam, err := getSecretQuotaParam(installation, serverClient, namespace)
 	if err != nil {
 		return err
@@ -718,25 +785,67 @@ func (r *Reconciler) processQuota(installation *rhmiv1alpha1.RHMI, namespace str
 		return fmt.Errorf("error getting quota config map %w", err)
 	}
 
+	rejected, err := r.addCustomQuotas(installation, namespace, configMap, serverClient)
+	if err != nil {
+		return err
+	}
+	if err, ok := rejected[quotaParam]; ok {
+		return &quota.CustomQuotaError{Param: quotaParam, Err: err}
+	}
+
 	// Updates the installation quota to the quota param if the quota is updated
 	err = quota.GetQuota(quotaParam, configMap, installationQuota)
 	if err != nil {
 		return err
 	}
 
-	// if both are toQuota and Quota are empty this indicates that it's either
-	// the first reconcile of an installation or it's the first reconcile of an upgrade to 1.6.0
+	// if Quota is empty this indicates that it's either the first reconcile of an installation
+	// or it's the first reconcile of an upgrade to 1.6.0, the quota is applied to every product at once.
 	// if the secretname is not the same as status.Quota this indicates there has been a quota change
-	// to an installation which is already using the Quota functionality.
-	// if either case is true set toQuota in the rhmi cr and update the status object and set isQuotaUpdated to true
-	if (installation.Status.ToQuota == "" && installation.Status.Quota == "") ||
-		installationQuota.GetName() != installation.Status.Quota {
+	// to an installation which is already using the Quota functionality, the change is rolled out
+	// to one product at a time
+	if installation.Status.Quota == "" {
 		installation.Status.ToQuota = installationQuota.GetName()
-		isQuotaUpdated = true
+		installation.Status.QuotaChange = nil
+		installationQuota.SetIsUpdated(true)
+		return nil
 	}
 
-	installationQuota.SetIsUpdated(isQuotaUpdated)
-	return nil
+	return r.reconcileQuotaChange(context.TODO(), installation, configMap, installationQuota, serverClient)
+}
+
+// addCustomQuotas adds the valid quotas of the custom quota config map, if the cluster admin created
+// it, to the quota config. The accepted quotas and why invalid ones were rejected are reported in the
+// status, the errors of the rejected quotas are returned by param
+func (r *Reconciler) addCustomQuotas(installation *rhmiv1alpha1.RHMI, namespace string, configMap *corev1.ConfigMap, serverClient k8sclient.Client) (map[string]error, error) {
+	customConfigMap := &corev1.ConfigMap{}
+	err := serverClient.Get(context.TODO(), types.NamespacedName{Namespace: namespace, Name: quota.CustomConfigMapName}, customConfigMap)
+	if k8serr.IsNotFound(err) {
+		installation.Status.CustomQuotas = nil
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting custom quota config map %w", err)
+	}
+
+	accepted, rejected, err := quota.AddCustomQuotas(configMap, customConfigMap)
+	if err != nil {
+		// the shipped quotas are still applied, the whole custom config is reported as invalid
+		r.log.Warningf("Invalid custom quota config map", l.Fields{"configMap": quota.CustomConfigMapName, "error": err})
+		installation.Status.CustomQuotas = &rhmiv1alpha1.CustomQuotaStatus{Errors: []string{err.Error()}}
+		return nil, nil
+	}
+
+	status := &rhmiv1alpha1.CustomQuotaStatus{Quotas: accepted}
+	for _, err := range rejected {
+		status.Errors = append(status.Errors, err.Error())
+	}
+	sort.Strings(status.Errors)
+	for _, message := range status.Errors {
+		r.log.Warningf("Rejected custom quota", l.Fields{"configMap": quota.CustomConfigMapName, "error": message})
+	}
+	installation.Status.CustomQuotas = status
+	return rejected, nil
 }
 
 func (r *Reconciler) reconcileCustomSMTP(ctx context.Context, serverClient k8sclient.Client) (rhmiv1alpha1.StatusPhase, error) {
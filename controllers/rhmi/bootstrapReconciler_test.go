@@ -5,11 +5,13 @@ import (
 	"errors"
 	"fmt"
 	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/apis/v1alpha1"
+	"github.com/integr8ly/integreatly-operator/pkg/addon"
 	moqclient "github.com/integr8ly/integreatly-operator/pkg/client"
 	"github.com/integr8ly/integreatly-operator/pkg/config"
 	"github.com/integr8ly/integreatly-operator/pkg/resources"
 	l "github.com/integr8ly/integreatly-operator/pkg/resources/logger"
 	"github.com/integr8ly/integreatly-operator/pkg/resources/marketplace"
+	"github.com/integr8ly/integreatly-operator/pkg/resources/quota"
 	userHelper "github.com/integr8ly/integreatly-operator/pkg/resources/user"
 	configv1 "github.com/openshift/api/config/v1"
 	routev1 "github.com/openshift/api/route/v1"
@@ -24,6 +26,7 @@ import (
 	"k8s.io/client-go/tools/record"
 	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"strings"
 	"testing"
 )
 
@@ -1072,3 +1075,146 @@ func TestReconciler_retrieveAPIServerURL(t *testing.T) {
 		})
 	}
 }
+
+func TestReconciler_checkRateLimitAlertsConfig(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.SchemeBuilder.AddToScheme(scheme)
+	_ = integreatlyv1alpha1.SchemeBuilder.AddToScheme(scheme)
+
+	alertsConfigMap := func(alerts string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: v1.ObjectMeta{Name: "rate-limit-alerts", Namespace: rhoamOperatorNs},
+			Data:       map[string]string{"alerts": alerts},
+		}
+	}
+	existingPolicy := &integreatlyv1alpha1.RateLimitAlertPolicy{
+		ObjectMeta: v1.ObjectMeta{Name: "custom", Namespace: rhoamOperatorNs},
+		Spec: integreatlyv1alpha1.RateLimitAlertPolicySpec{
+			Alerts: []integreatlyv1alpha1.RateLimitAlert{
+				{Name: "custom", Type: integreatlyv1alpha1.RateLimitAlertTypeSpike, RuleName: "Custom", Level: "warning", Period: "1h"},
+			},
+		},
+	}
+
+	tests := []struct {
+		name          string
+		annotations   map[string]string
+		initObjs      []runtime.Object
+		wantErr       bool
+		wantAlerts    []string
+		wantNoPolicy  bool
+		wantConfigMap bool
+	}{
+		{
+			name:       "test default policy is created",
+			wantAlerts: []string{"api-usage-alert-level1", "api-usage-alert-level2", "api-usage-alert-level3", "rate-limit-spike"},
+		},
+		{
+			name:       "test alerts of the config map are migrated to the policy",
+			initObjs:   []runtime.Object{alertsConfigMap(`{"sustained": {"type": "Sustained", "ruleName": "Sustained", "level": "info", "sustained": {"minRate": "70%", "minutes": 10}}}`)},
+			wantAlerts: []string{"sustained"},
+		},
+		{
+			name:     "test existing policy is kept and the config map is deleted",
+			initObjs: []runtime.Object{existingPolicy, alertsConfigMap(`{}`)},
+		},
+		{
+			name:          "test nothing is migrated once the migration is recorded",
+			annotations:   map[string]string{rateLimitAlertsMigratedAnnotation: "true"},
+			initObjs:      []runtime.Object{alertsConfigMap(`{}`)},
+			wantNoPolicy:  true,
+			wantConfigMap: true,
+		},
+		{
+			name:          "test invalid config map is not migrated",
+			initObjs:      []runtime.Object{alertsConfigMap(`{"invalid": {"type": "Threshold", "ruleName": "Invalid", "level": "info", "period": "4h", "threshold": {"minRate": "95%", "maxRate": "90%"}}}`)},
+			wantErr:       true,
+			wantConfigMap: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := fake.NewFakeClientWithScheme(scheme, tt.initObjs...)
+			r := &Reconciler{
+				installation: &integreatlyv1alpha1.RHMI{ObjectMeta: v1.ObjectMeta{Name: "rhoam", Namespace: rhoamOperatorNs, Annotations: tt.annotations}},
+				log:          l.NewLogger(),
+			}
+
+			_, err := r.checkRateLimitAlertsConfig(context.TODO(), client)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("checkRateLimitAlertsConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if migrated := r.installation.GetAnnotations()[rateLimitAlertsMigratedAnnotation] == "true"; migrated == tt.wantErr {
+				t.Errorf("expected the migration to be recorded %v, got %v", !tt.wantErr, migrated)
+			}
+
+			err = client.Get(context.TODO(), k8sclient.ObjectKey{Name: "rate-limit-alerts", Namespace: rhoamOperatorNs}, &corev1.ConfigMap{})
+			if tt.wantConfigMap != (err == nil) {
+				t.Errorf("expected config map to exist %v, got %v", tt.wantConfigMap, err)
+			}
+
+			if tt.wantNoPolicy {
+				policies := &integreatlyv1alpha1.RateLimitAlertPolicyList{}
+				if err := client.List(context.TODO(), policies); err != nil || len(policies.Items) != 0 {
+					t.Errorf("expected no policy, got %v, %v", policies.Items, err)
+				}
+			}
+			if tt.wantAlerts == nil {
+				return
+			}
+			policy := &integreatlyv1alpha1.RateLimitAlertPolicy{}
+			if err := client.Get(context.TODO(), k8sclient.ObjectKey{Name: "rate-limit-alerts", Namespace: rhoamOperatorNs}, policy); err != nil {
+				t.Fatal(err)
+			}
+			alerts := []string{}
+			for _, alert := range policy.Spec.Alerts {
+				alerts = append(alerts, alert.Name)
+			}
+			if fmt.Sprint(alerts) != fmt.Sprint(tt.wantAlerts) {
+				t.Errorf("expected policy alerts %v, got %v", tt.wantAlerts, alerts)
+			}
+		})
+	}
+}
+
+func TestReconciler_processQuota(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.SchemeBuilder.AddToScheme(scheme)
+	_ = olmv1alpha1.AddToScheme(scheme)
+	quota.RegisterProductComponents(integreatlyv1alpha1.Product3Scale, []string{quota.ApicastProductionName})
+	quota.RegisterProductComponents(integreatlyv1alpha1.ProductMarin3r, []string{quota.RateLimitName})
+
+	client := fake.NewFakeClientWithScheme(scheme,
+		&corev1.Secret{
+			ObjectMeta: v1.ObjectMeta{Name: addon.DefaultSecretName, Namespace: rhoamOperatorNs},
+			Data:       map[string][]byte{addon.QuotaParamName: []byte("50")},
+		},
+		&corev1.ConfigMap{
+			ObjectMeta: v1.ObjectMeta{Name: quota.ConfigMapName, Namespace: rhoamOperatorNs},
+			Data: map[string]string{
+				quota.ConfigMapData: `[{"name": "small", "param": "1", "rate-limiting": {"unit": "minute", "requests_per_unit": 10}, "resources": {"apicast_production": {"replicas": 1}, "ratelimit": {"replicas": 1}}}]`,
+			},
+		},
+		&corev1.ConfigMap{
+			ObjectMeta: v1.ObjectMeta{Name: quota.CustomConfigMapName, Namespace: rhoamOperatorNs},
+			Data: map[string]string{
+				quota.ConfigMapData: `[{"name": "partial", "param": "50", "rate-limiting": {"unit": "minute", "requests_per_unit": 10}, "resources": {"apicast_production": {"replicas": 2, "resources": {"requests": {"cpu": "100m", "memory": "100Mi"}, "limits": {"cpu": "200m", "memory": "200Mi"}}}}}]`,
+			},
+		},
+	)
+	installation := &integreatlyv1alpha1.RHMI{ObjectMeta: v1.ObjectMeta{Name: "rhoam", Namespace: rhoamOperatorNs}}
+	r := &Reconciler{installation: installation, log: l.NewLogger()}
+
+	err := r.processQuota(installation, rhoamOperatorNs, &quota.Quota{}, client)
+	customQuotaErr := &quota.CustomQuotaError{}
+	if !errors.As(err, &customQuotaErr) || customQuotaErr.Param != "50" {
+		t.Fatalf("expected the partial custom quota to be rejected, got %v", err)
+	}
+	if installation.Status.ToQuota != "" {
+		t.Errorf("expected the partial custom quota not to be applied, got %s", installation.Status.ToQuota)
+	}
+	if installation.Status.CustomQuotas == nil || len(installation.Status.CustomQuotas.Errors) != 1 ||
+		!strings.Contains(installation.Status.CustomQuotas.Errors[0], "the resources of every component are required") {
+		t.Errorf("expected the missing components in the custom quota status, got %+v", installation.Status.CustomQuotas)
+	}
+}
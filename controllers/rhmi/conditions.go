@@ -0,0 +1,148 @@
+package controllers
+
+import (
+	"fmt"
+
+	rhmiv1alpha1 "github.com/integr8ly/integreatly-operator/apis/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// setInstallationConditions derives the standard conditions from the rest of the installation
+// status. It must be called after the stage, errors and versions have been set for this reconcile
+func setInstallationConditions(installation *rhmiv1alpha1.RHMI) {
+	status := &installation.Status
+	generation := installation.Generation
+
+	switch status.PreflightStatus {
+	case rhmiv1alpha1.PreflightSuccess:
+		setCondition(&status.Conditions, generation, rhmiv1alpha1.ConditionTypePreflightPassed, metav1.ConditionTrue,
+			rhmiv1alpha1.ConditionReasonPreflightSucceeded, status.PreflightMessage)
+	case rhmiv1alpha1.PreflightFail:
+		setCondition(&status.Conditions, generation, rhmiv1alpha1.ConditionTypePreflightPassed, metav1.ConditionFalse,
+			rhmiv1alpha1.ConditionReasonPreflightFailed, status.PreflightMessage)
+	default:
+		setCondition(&status.Conditions, generation, rhmiv1alpha1.ConditionTypePreflightPassed, metav1.ConditionUnknown,
+			rhmiv1alpha1.ConditionReasonPreflightInProgress, "preflight checks have not completed")
+	}
+
+	if installation.DeletionTimestamp != nil {
+		setCondition(&status.Conditions, generation, rhmiv1alpha1.ConditionTypeUninstalling, metav1.ConditionTrue,
+			rhmiv1alpha1.ConditionReasonDeletionRequested, "the installation is being removed")
+	} else {
+		setCondition(&status.Conditions, generation, rhmiv1alpha1.ConditionTypeUninstalling, metav1.ConditionFalse,
+			rhmiv1alpha1.ConditionReasonNotDeleted, "")
+	}
+
+	if status.Version != "" && status.ToVersion != "" {
+		setCondition(&status.Conditions, generation, rhmiv1alpha1.ConditionTypeUpgrading, metav1.ConditionTrue,
+			rhmiv1alpha1.ConditionReasonUpgradeInProgress, fmt.Sprintf("upgrading from %s to %s", status.Version, status.ToVersion))
+	} else {
+		setCondition(&status.Conditions, generation, rhmiv1alpha1.ConditionTypeUpgrading, metav1.ConditionFalse,
+			rhmiv1alpha1.ConditionReasonNoUpgrade, "")
+	}
+
+	if degraded := degradedMessage(installation); degraded != "" {
+		setCondition(&status.Conditions, generation, rhmiv1alpha1.ConditionTypeDegraded, metav1.ConditionTrue,
+			rhmiv1alpha1.ConditionReasonReconcileError, degraded)
+	} else {
+		setCondition(&status.Conditions, generation, rhmiv1alpha1.ConditionTypeDegraded, metav1.ConditionFalse,
+			rhmiv1alpha1.ConditionReasonReconcileSucceeded, "")
+	}
+
+	if paused := pausedProducts(installation); len(paused) > 0 {
+		setCondition(&status.Conditions, generation, rhmiv1alpha1.ConditionTypePaused, metav1.ConditionTrue,
+			rhmiv1alpha1.ConditionReasonPausedBySpec, fmt.Sprintf("products are not reconciled while they are paused: %v", paused))
+	} else {
+		setCondition(&status.Conditions, generation, rhmiv1alpha1.ConditionTypePaused, metav1.ConditionFalse,
+			rhmiv1alpha1.ConditionReasonNotPaused, "")
+	}
+
+	if status.Stage == rhmiv1alpha1.CompleteStage && installation.DeletionTimestamp == nil {
+		setCondition(&status.Conditions, generation, rhmiv1alpha1.ConditionTypeProgressing, metav1.ConditionFalse,
+			rhmiv1alpha1.ConditionReasonInstallComplete, "all stages have completed")
+		setCondition(&status.Conditions, generation, rhmiv1alpha1.ConditionTypeReady, metav1.ConditionTrue,
+			rhmiv1alpha1.ConditionReasonInstallComplete, "all stages have completed")
+	} else {
+		reason, message := rhmiv1alpha1.ConditionReasonInstallInProgress, fmt.Sprintf("reconciling stage %s", status.Stage)
+		if installation.DeletionTimestamp != nil {
+			reason, message = rhmiv1alpha1.ConditionReasonDeletionRequested, "the installation is being removed"
+		}
+		setCondition(&status.Conditions, generation, rhmiv1alpha1.ConditionTypeProgressing, metav1.ConditionTrue, reason, message)
+		setCondition(&status.Conditions, generation, rhmiv1alpha1.ConditionTypeReady, metav1.ConditionFalse, reason, message)
+	}
+}
+
+// setProductConditions sets the standard conditions on a product status from the phase and error
+// returned by the product reconciler
+func setProductConditions(productStatus *rhmiv1alpha1.RHMIProductStatus, generation int64, reconcileErr error) {
+	productStatus.ObservedGeneration = generation
+
+	if reconcileErr != nil || productStatus.Phase == rhmiv1alpha1.PhaseFailed {
+		message := fmt.Sprintf("product reconcile is in phase %s", productStatus.Phase)
+		if reconcileErr != nil {
+			message = reconcileErr.Error()
+		}
+		setCondition(&productStatus.Conditions, generation, rhmiv1alpha1.ConditionTypeDegraded, metav1.ConditionTrue,
+			rhmiv1alpha1.ConditionReasonReconcileError, message)
+	} else {
+		setCondition(&productStatus.Conditions, generation, rhmiv1alpha1.ConditionTypeDegraded, metav1.ConditionFalse,
+			rhmiv1alpha1.ConditionReasonReconcileSucceeded, "")
+	}
+
+	if productStatus.Phase == rhmiv1alpha1.PhaseCompleted {
+		setCondition(&productStatus.Conditions, generation, rhmiv1alpha1.ConditionTypeProgressing, metav1.ConditionFalse,
+			rhmiv1alpha1.ConditionReasonInstallComplete, "")
+		setCondition(&productStatus.Conditions, generation, rhmiv1alpha1.ConditionTypeReady, metav1.ConditionTrue,
+			rhmiv1alpha1.ConditionReasonInstallComplete, "")
+	} else {
+		message := fmt.Sprintf("product reconcile is in phase %s", productStatus.Phase)
+		setCondition(&productStatus.Conditions, generation, rhmiv1alpha1.ConditionTypeProgressing, metav1.ConditionTrue,
+			rhmiv1alpha1.ConditionReasonInstallInProgress, message)
+		setCondition(&productStatus.Conditions, generation, rhmiv1alpha1.ConditionTypeReady, metav1.ConditionFalse,
+			rhmiv1alpha1.ConditionReasonInstallInProgress, message)
+	}
+}
+
+// setAwaitingDependenciesConditions marks a product that was not reconciled because the products
+// it depends on have not completed
+func setAwaitingDependenciesConditions(productStatus *rhmiv1alpha1.RHMIProductStatus, generation int64, dependencies []rhmiv1alpha1.ProductName) {
+	message := fmt.Sprintf("waiting for dependencies to complete: %v", dependencies)
+	setCondition(&productStatus.Conditions, generation, rhmiv1alpha1.ConditionTypeProgressing, metav1.ConditionTrue,
+		rhmiv1alpha1.ConditionReasonAwaitingDependencies, message)
+	if productStatus.Phase != rhmiv1alpha1.PhaseCompleted {
+		setCondition(&productStatus.Conditions, generation, rhmiv1alpha1.ConditionTypeReady, metav1.ConditionFalse,
+			rhmiv1alpha1.ConditionReasonAwaitingDependencies, message)
+	}
+}
+
+// degradedMessage returns the reason the installation is degraded, or an empty string if it is not
+func degradedMessage(installation *rhmiv1alpha1.RHMI) string {
+	if installation.Status.LastError != "" {
+		return installation.Status.LastError
+	}
+	var failed []rhmiv1alpha1.ProductName
+	for _, stage := range installation.Status.Stages {
+		for _, product := range stage.Products {
+			if product.Phase == rhmiv1alpha1.PhaseFailed {
+				failed = append(failed, product.Name)
+			}
+		}
+	}
+	if len(failed) > 0 {
+		sortProducts(failed)
+		return fmt.Sprintf("products in phase %s: %v", rhmiv1alpha1.PhaseFailed, failed)
+	}
+	return ""
+}
+
+// setCondition only changes the transition time when the status of the condition changes
+func setCondition(conditions *[]metav1.Condition, generation int64, conditionType string, status metav1.ConditionStatus, reason, message string) {
+	meta.SetStatusCondition(conditions, metav1.Condition{
+		Type:               conditionType,
+		Status:             status,
+		ObservedGeneration: generation,
+		Reason:             reason,
+		Message:            message,
+	})
+}
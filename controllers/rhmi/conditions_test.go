@@ -0,0 +1,146 @@
+package controllers
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	rhmiv1alpha1 "github.com/integr8ly/integreatly-operator/apis/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSetInstallationConditions(t *testing.T) {
+	now := metav1.Now()
+
+	tests := []struct {
+		name         string
+		installation *rhmiv1alpha1.RHMI
+		want         map[string]metav1.ConditionStatus
+	}{
+		{
+			name: "test completed installation is ready",
+			installation: &rhmiv1alpha1.RHMI{
+				ObjectMeta: metav1.ObjectMeta{Generation: 2},
+				Status: rhmiv1alpha1.RHMIStatus{
+					Stage:           rhmiv1alpha1.CompleteStage,
+					PreflightStatus: rhmiv1alpha1.PreflightSuccess,
+					Version:         "1.0.0",
+				},
+			},
+			want: map[string]metav1.ConditionStatus{
+				rhmiv1alpha1.ConditionTypeReady:           metav1.ConditionTrue,
+				rhmiv1alpha1.ConditionTypeProgressing:     metav1.ConditionFalse,
+				rhmiv1alpha1.ConditionTypeDegraded:        metav1.ConditionFalse,
+				rhmiv1alpha1.ConditionTypeUpgrading:       metav1.ConditionFalse,
+				rhmiv1alpha1.ConditionTypePreflightPassed: metav1.ConditionTrue,
+				rhmiv1alpha1.ConditionTypeUninstalling:    metav1.ConditionFalse,
+			},
+		},
+		{
+			name: "test upgrading installation with an error is progressing and degraded",
+			installation: &rhmiv1alpha1.RHMI{
+				Status: rhmiv1alpha1.RHMIStatus{
+					Stage:           rhmiv1alpha1.InstallStage,
+					PreflightStatus: rhmiv1alpha1.PreflightSuccess,
+					Version:         "1.0.0",
+					ToVersion:       "1.1.0",
+					LastError:       "failed installation of 3scale",
+				},
+			},
+			want: map[string]metav1.ConditionStatus{
+				rhmiv1alpha1.ConditionTypeReady:       metav1.ConditionFalse,
+				rhmiv1alpha1.ConditionTypeProgressing: metav1.ConditionTrue,
+				rhmiv1alpha1.ConditionTypeDegraded:    metav1.ConditionTrue,
+				rhmiv1alpha1.ConditionTypeUpgrading:   metav1.ConditionTrue,
+			},
+		},
+		{
+			name: "test failed preflight checks",
+			installation: &rhmiv1alpha1.RHMI{
+				Status: rhmiv1alpha1.RHMIStatus{
+					PreflightStatus:  rhmiv1alpha1.PreflightFail,
+					PreflightMessage: "found conflicting packages",
+				},
+			},
+			want: map[string]metav1.ConditionStatus{
+				rhmiv1alpha1.ConditionTypePreflightPassed: metav1.ConditionFalse,
+				rhmiv1alpha1.ConditionTypeReady:           metav1.ConditionFalse,
+			},
+		},
+		{
+			name: "test deleted installation is uninstalling",
+			installation: &rhmiv1alpha1.RHMI{
+				ObjectMeta: metav1.ObjectMeta{DeletionTimestamp: &now},
+				Status: rhmiv1alpha1.RHMIStatus{
+					Stage:           rhmiv1alpha1.StageName("deletion"),
+					PreflightStatus: rhmiv1alpha1.PreflightSuccess,
+				},
+			},
+			want: map[string]metav1.ConditionStatus{
+				rhmiv1alpha1.ConditionTypeUninstalling: metav1.ConditionTrue,
+				rhmiv1alpha1.ConditionTypeReady:        metav1.ConditionFalse,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setInstallationConditions(tt.installation)
+			for conditionType, status := range tt.want {
+				condition := meta.FindStatusCondition(tt.installation.Status.Conditions, conditionType)
+				if condition == nil {
+					t.Fatalf("expected condition %s to be set", conditionType)
+				}
+				if condition.Status != status {
+					t.Errorf("expected condition %s to be %s but got %s", conditionType, status, condition.Status)
+				}
+				if condition.ObservedGeneration != tt.installation.Generation {
+					t.Errorf("expected condition %s observed generation %d but got %d", conditionType, tt.installation.Generation, condition.ObservedGeneration)
+				}
+			}
+		})
+	}
+}
+
+func TestSetInstallationConditions_KeepsTransitionTime(t *testing.T) {
+	installation := &rhmiv1alpha1.RHMI{
+		Status: rhmiv1alpha1.RHMIStatus{Stage: rhmiv1alpha1.CompleteStage},
+	}
+	setInstallationConditions(installation)
+	ready := meta.FindStatusCondition(installation.Status.Conditions, rhmiv1alpha1.ConditionTypeReady)
+	transitioned := metav1.NewTime(ready.LastTransitionTime.Add(-time.Minute))
+	ready.LastTransitionTime = transitioned
+
+	setInstallationConditions(installation)
+	ready = meta.FindStatusCondition(installation.Status.Conditions, rhmiv1alpha1.ConditionTypeReady)
+	if !ready.LastTransitionTime.Equal(&transitioned) {
+		t.Fatalf("expected the transition time to be kept when the status does not change")
+	}
+}
+
+func TestSetProductConditions(t *testing.T) {
+	productStatus := &rhmiv1alpha1.RHMIProductStatus{
+		Name:  rhmiv1alpha1.Product3Scale,
+		Phase: rhmiv1alpha1.PhaseInProgress,
+	}
+
+	setProductConditions(productStatus, 3, errors.New("apicast not ready"))
+	if productStatus.ObservedGeneration != 3 {
+		t.Fatalf("expected observed generation 3 but got %d", productStatus.ObservedGeneration)
+	}
+	if !meta.IsStatusConditionTrue(productStatus.Conditions, rhmiv1alpha1.ConditionTypeDegraded) {
+		t.Errorf("expected product to be degraded")
+	}
+	if !meta.IsStatusConditionFalse(productStatus.Conditions, rhmiv1alpha1.ConditionTypeReady) {
+		t.Errorf("expected product not to be ready")
+	}
+
+	productStatus.Phase = rhmiv1alpha1.PhaseCompleted
+	setProductConditions(productStatus, 3, nil)
+	if !meta.IsStatusConditionTrue(productStatus.Conditions, rhmiv1alpha1.ConditionTypeReady) {
+		t.Errorf("expected product to be ready")
+	}
+	if !meta.IsStatusConditionFalse(productStatus.Conditions, rhmiv1alpha1.ConditionTypeDegraded) {
+		t.Errorf("expected product not to be degraded")
+	}
+}
@@ -0,0 +1,196 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	rhmiv1alpha1 "github.com/integr8ly/integreatly-operator/apis/v1alpha1"
+	integreatlyclient "github.com/integr8ly/integreatly-operator/pkg/client"
+	"github.com/integr8ly/integreatly-operator/pkg/config"
+	l "github.com/integr8ly/integreatly-operator/pkg/resources/logger"
+	"github.com/integr8ly/integreatly-operator/pkg/resources/marketplace"
+	"github.com/integr8ly/integreatly-operator/pkg/resources/quota"
+	"github.com/integr8ly/integreatly-operator/pkg/resources/rhmi"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// the changes made by the bootstrap stage and the config manager are recorded under these owners,
+// every other change is recorded under the product that made it
+const (
+	planOwnerBootstrap = "bootstrap"
+	planOwnerConfig    = "config"
+)
+
+// installationPlan is the outcome of reconciling the install stages against a recording client
+type installationPlan struct {
+	// Stage is the last stage that was reconciled, later stages are not planned until it completes
+	Stage rhmiv1alpha1.StageName
+	Phase rhmiv1alpha1.StatusPhase
+	// Err is the error returned by the last stage, the changes recorded before it are still valid
+	Err error
+	// Products holds the status each product would have after the reconcile
+	Products map[rhmiv1alpha1.ProductName]rhmiv1alpha1.RHMIProductStatus
+	// Changes are the writes each owner would have made, in the order they were made
+	Changes map[string][]integreatlyclient.Change
+}
+
+// Plan writes to out the changes the install stages would make to the cluster for the RHMI CR in
+// namespace, without making them. It is run by the --plan flag of the operator instead of the
+// manager, so the installation controller keeps reconciling the RHMI CR while it is planned
+func Plan(ctx context.Context, restConfig *rest.Config, scheme *runtime.Scheme, namespace string, out io.Writer) error {
+	serverClient, err := k8sclient.New(restConfig, k8sclient.Options{Scheme: scheme})
+	if err != nil {
+		return fmt.Errorf("could not create server client: %w", err)
+	}
+	installation, err := rhmi.GetRhmiCr(serverClient, ctx, namespace, log)
+	if err != nil {
+		return fmt.Errorf("could not get the RHMI CR: %w", err)
+	}
+	if installation == nil {
+		return fmt.Errorf("no RHMI CR found in the %s namespace", namespace)
+	}
+	// the products check the annotation to avoid changes outside of the cluster, it is only set on
+	// the copy that is planned
+	if installation.Annotations == nil {
+		installation.Annotations = map[string]string{}
+	}
+	installation.Annotations[rhmiv1alpha1.DryRunAnnotation] = "true"
+
+	installType, err := TypeFactory(installation.Spec.Type)
+	if err != nil {
+		return err
+	}
+	r := &RHMIReconciler{
+		Client: serverClient,
+		Scheme: scheme,
+
+		restConfig:      restConfig,
+		customInformers: make(map[string]map[string]*cache.Informer),
+		serverClient:    serverClient,
+		httpClients:     integreatlyclient.NewHTTPClientFactory(),
+
+		productsInstallationLoader: marketplace.NewFSProductInstallationLoader(
+			marketplace.GetProductsInstallationPath(),
+		),
+	}
+	if err := r.getHTTPClientFactory().Configure(ctx, serverClient, installation); err != nil {
+		return fmt.Errorf("could not configure the product API clients: %w", err)
+	}
+
+	request := ctrl.Request{NamespacedName: types.NamespacedName{Name: installation.Name, Namespace: installation.Namespace}}
+	plan, err := r.plan(ctx, installation, installType, serverClient, request)
+	if err != nil {
+		return err
+	}
+	return writePlan(out, plan)
+}
+
+// plan reconciles a copy of the installation and of the install stages against a recording client
+// that wraps serverClient. Nothing is written through serverClient
+func (r *RHMIReconciler) plan(ctx context.Context, installation *rhmiv1alpha1.RHMI, installType *Type, serverClient k8sclient.Client, request ctrl.Request) (*installationPlan, error) {
+	installation = installation.DeepCopy()
+	if installation.Status.Stages == nil {
+		installation.Status.Stages = map[rhmiv1alpha1.StageName]rhmiv1alpha1.RHMIStageStatus{}
+	}
+
+	recorder := integreatlyclient.NewRecordingClient(serverClient, r.Scheme)
+	configManager, err := config.NewManager(ctx, recorder.WithOwner(planOwnerConfig), request.Namespace, getInstallationConfigMapName(installation), installation)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &installationPlan{
+		Products: map[rhmiv1alpha1.ProductName]rhmiv1alpha1.RHMIProductStatus{},
+	}
+	installationQuota := &quota.Quota{}
+	for _, installStage := range installType.GetInstallStages() {
+		// the stages are shared with the installation controller so their product statuses must not
+		// be changed by the plan
+		stage := installStage.copy()
+		stageLog := l.NewLoggerWithContext(l.Fields{l.StageLogContext: stage.Name, "dryRun": true})
+
+		if stage.Name == rhmiv1alpha1.BootstrapStage {
+			plan.Phase, plan.Err = r.bootstrapStage(ctx, installation, configManager, recorder.WithOwner(planOwnerBootstrap), stageLog, installationQuota, request)
+		} else {
+			plan.Phase, plan.Err = r.processStage(ctx, installation, &stage, configManager, recorder, installationQuota, stageLog)
+		}
+		plan.Stage = stage.Name
+		for name, productStatus := range stage.Products {
+			plan.Products[name] = productStatus
+		}
+
+		if plan.Phase != rhmiv1alpha1.PhaseCompleted {
+			break
+		}
+	}
+
+	plan.Changes = map[string][]integreatlyclient.Change{}
+	for _, change := range recorder.Changes() {
+		plan.Changes[change.Owner] = append(plan.Changes[change.Owner], change)
+	}
+
+	return plan, nil
+}
+
+// productClient returns the client passed to the reconciler of a product. When the installation
+// is being planned the changes made through it are recorded under the product
+func productClient(serverClient k8sclient.Client, product rhmiv1alpha1.ProductName) k8sclient.Client {
+	if recorder, ok := serverClient.(*integreatlyclient.RecordingClient); ok {
+		return recorder.WithOwner(string(product))
+	}
+	return serverClient
+}
+
+// writePlan writes the outcome of the plan followed by the changes of each owner, sorted by owner
+func writePlan(out io.Writer, plan *installationPlan) error {
+	w := &planWriter{out: out}
+	w.printf("Planned installation up to stage %s, phase %s\n", plan.Stage, plan.Phase)
+	if plan.Err != nil {
+		w.printf("Error: %v\n", plan.Err)
+	}
+
+	owners := make([]string, 0, len(plan.Changes))
+	for owner := range plan.Changes {
+		owners = append(owners, owner)
+	}
+	sort.Strings(owners)
+
+	for _, owner := range owners {
+		changes := plan.Changes[owner]
+		w.printf("\n%s: %d changes", owner, len(changes))
+		if productStatus, ok := plan.Products[rhmiv1alpha1.ProductName(owner)]; ok {
+			w.printf(", phase %s", productStatus.Phase)
+		}
+		w.printf("\n")
+		for _, change := range changes {
+			w.printf("  %s\n", change)
+			for _, line := range strings.Split(strings.TrimRight(change.Diff, "\n"), "\n") {
+				if line != "" {
+					w.printf("    %s\n", line)
+				}
+			}
+		}
+	}
+	return w.err
+}
+
+// planWriter keeps the first error of the writes to out, the later writes are skipped
+type planWriter struct {
+	out io.Writer
+	err error
+}
+
+func (w *planWriter) printf(format string, args ...interface{}) {
+	if w.err != nil {
+		return
+	}
+	_, w.err = fmt.Fprintf(w.out, format, args...)
+}
@@ -0,0 +1,140 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	rhmiv1alpha1 "github.com/integr8ly/integreatly-operator/apis/v1alpha1"
+	integreatlyclient "github.com/integr8ly/integreatly-operator/pkg/client"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestRHMIReconciler_plan(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := rhmiv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	installation := &rhmiv1alpha1.RHMI{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        FakeName,
+			Namespace:   FakeNamespace,
+			Annotations: map[string]string{rhmiv1alpha1.DryRunAnnotation: "true"},
+		},
+		Spec: rhmiv1alpha1.RHMISpec{
+			Type:            string(rhmiv1alpha1.InstallationTypeManagedApi),
+			NamespacePrefix: "redhat-rhoam-",
+		},
+	}
+	serverClient := fakeclient.NewFakeClientWithScheme(scheme, installation.DeepCopy())
+	r := &RHMIReconciler{
+		Client:       serverClient,
+		Scheme:       scheme,
+		serverClient: serverClient,
+	}
+
+	installType, err := TypeFactory(installation.Spec.Type)
+	if err != nil {
+		t.Fatal(err)
+	}
+	request := ctrl.Request{NamespacedName: types.NamespacedName{Name: FakeName, Namespace: FakeNamespace}}
+	plan, err := r.plan(context.TODO(), installation, installType, serverClient, request)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if plan.Stage != rhmiv1alpha1.BootstrapStage {
+		t.Fatalf("expected the plan to stop at the bootstrap stage but got %s", plan.Stage)
+	}
+	// the bootstrap stage adds the observability finalizer before anything else
+	bootstrapChanges := plan.Changes[planOwnerBootstrap]
+	if len(bootstrapChanges) == 0 {
+		t.Fatalf("expected changes from the bootstrap stage")
+	}
+	if change := bootstrapChanges[0]; change.Action != integreatlyclient.ChangeActionUpdate || change.Kind.Kind != "RHMI" || !strings.Contains(change.Diff, "finalizers") {
+		t.Errorf("expected the installation finalizers to be updated but got %s\n%s", change, change.Diff)
+	}
+
+	// the plan leaves the cluster and the installation type as they were
+	clusterInstallation := &rhmiv1alpha1.RHMI{}
+	if err := serverClient.Get(context.TODO(), request.NamespacedName, clusterInstallation); err != nil {
+		t.Fatal(err)
+	}
+	if len(clusterInstallation.Finalizers) != 0 {
+		t.Errorf("expected the installation not to be changed but got finalizers %v", clusterInstallation.Finalizers)
+	}
+	for _, stage := range installType.GetInstallStages() {
+		for name, productStatus := range stage.Products {
+			if productStatus.Phase != rhmiv1alpha1.PhaseNone {
+				t.Errorf("expected the status of %s in the installation type not to change but got phase %s", name, productStatus.Phase)
+			}
+		}
+	}
+}
+
+func TestProductClient(t *testing.T) {
+	serverClient := fakeclient.NewFakeClientWithScheme(runtime.NewScheme())
+	if got := productClient(serverClient, rhmiv1alpha1.Product3Scale); got != serverClient {
+		t.Fatalf("expected the server client to be used when the installation is not planned")
+	}
+
+	recorder := integreatlyclient.NewRecordingClient(serverClient, runtime.NewScheme())
+	got, ok := productClient(recorder, rhmiv1alpha1.Product3Scale).(*integreatlyclient.RecordingClient)
+	if !ok {
+		t.Fatalf("expected a recording client when the installation is planned")
+	}
+	if got == recorder {
+		t.Fatalf("expected a recording client owned by the product")
+	}
+}
+
+func TestWritePlan(t *testing.T) {
+	plan := &installationPlan{
+		Stage: rhmiv1alpha1.InstallStage,
+		Phase: rhmiv1alpha1.PhaseInProgress,
+		Err:   errors.New("3scale is not ready"),
+		Products: map[rhmiv1alpha1.ProductName]rhmiv1alpha1.RHMIProductStatus{
+			rhmiv1alpha1.Product3Scale: {Name: rhmiv1alpha1.Product3Scale, Phase: rhmiv1alpha1.PhaseInProgress},
+		},
+		Changes: map[string][]integreatlyclient.Change{
+			string(rhmiv1alpha1.Product3Scale): {
+				{Owner: string(rhmiv1alpha1.Product3Scale), Action: integreatlyclient.ChangeActionUpdate, Kind: schema.GroupVersionKind{Kind: "ConfigMap"}, Namespace: "redhat-rhoam-3scale", Name: "system", Diff: "-a: b\n+a: c\n"},
+			},
+			planOwnerBootstrap: {
+				{Owner: planOwnerBootstrap, Action: integreatlyclient.ChangeActionUpdate, Kind: schema.GroupVersionKind{Kind: "RHMI"}, Namespace: FakeNamespace, Name: FakeName},
+			},
+		},
+	}
+
+	out := &bytes.Buffer{}
+	if err := writePlan(out, plan); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got := out.String()
+	for _, want := range []string{
+		"Planned installation up to stage installation, phase in progress\n",
+		"Error: 3scale is not ready\n",
+		"3scale: 1 changes, phase in progress\n  update ConfigMap redhat-rhoam-3scale/system\n    -a: b\n    +a: c\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected the plan to contain %q but got\n%s", want, got)
+		}
+	}
+	// the changes are written sorted by owner
+	if strings.Index(got, "3scale:") > strings.Index(got, planOwnerBootstrap+":") {
+		t.Errorf("expected the owners to be sorted but got\n%s", got)
+	}
+}
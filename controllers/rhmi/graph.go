@@ -0,0 +1,206 @@
+package controllers
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/apis/v1alpha1"
+)
+
+// ProductGraph declares, for every product in an installation type, the products that must report
+// PhaseCompleted before it is reconciled. Products with no dependencies map to an empty list
+type ProductGraph map[integreatlyv1alpha1.ProductName][]integreatlyv1alpha1.ProductName
+
+// Validate returns an error if a product depends on a product missing from the graph, or if the
+// dependencies contain a cycle
+func (g ProductGraph) Validate() error {
+	for _, product := range g.products() {
+		for _, dependency := range g[product] {
+			if _, ok := g[dependency]; !ok {
+				return fmt.Errorf("product %s depends on %s which is not part of the installation type", product, dependency)
+			}
+		}
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := map[integreatlyv1alpha1.ProductName]int{}
+	var path []integreatlyv1alpha1.ProductName
+
+	var visit func(product integreatlyv1alpha1.ProductName) error
+	visit = func(product integreatlyv1alpha1.ProductName) error {
+		switch state[product] {
+		case visiting:
+			return fmt.Errorf("product dependency cycle found: %s", formatCycle(path, product))
+		case visited:
+			return nil
+		}
+		state[product] = visiting
+		path = append(path, product)
+		for _, dependency := range g.dependencies(product) {
+			if err := visit(dependency); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		state[product] = visited
+		return nil
+	}
+
+	for _, product := range g.products() {
+		if err := visit(product); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// InstallOrder groups the products into layers, every product in a layer only depends on products in
+// earlier layers. Products within a layer are sorted by name
+func (g ProductGraph) InstallOrder() ([][]integreatlyv1alpha1.ProductName, error) {
+	if err := g.Validate(); err != nil {
+		return nil, err
+	}
+
+	var layers [][]integreatlyv1alpha1.ProductName
+	placed := map[integreatlyv1alpha1.ProductName]bool{}
+	for len(placed) < len(g) {
+		var layer []integreatlyv1alpha1.ProductName
+		for _, product := range g.products() {
+			if !placed[product] && g.Ready(product, placed) {
+				layer = append(layer, product)
+			}
+		}
+		for _, product := range layer {
+			placed[product] = true
+		}
+		layers = append(layers, layer)
+	}
+	return layers, nil
+}
+
+// UninstallOrder is the reverse of InstallOrder, a product is only removed once every product that
+// depends on it has been removed
+func (g ProductGraph) UninstallOrder() ([][]integreatlyv1alpha1.ProductName, error) {
+	if err := g.Validate(); err != nil {
+		return nil, err
+	}
+
+	var layers [][]integreatlyv1alpha1.ProductName
+	placed := map[integreatlyv1alpha1.ProductName]bool{}
+	for len(placed) < len(g) {
+		var layer []integreatlyv1alpha1.ProductName
+		for _, product := range g.products() {
+			if !placed[product] && g.DependentsRemoved(product, placed) {
+				layer = append(layer, product)
+			}
+		}
+		for _, product := range layer {
+			placed[product] = true
+		}
+		layers = append(layers, layer)
+	}
+	return layers, nil
+}
+
+// Ready returns true if every dependency of the product is in completed
+func (g ProductGraph) Ready(product integreatlyv1alpha1.ProductName, completed map[integreatlyv1alpha1.ProductName]bool) bool {
+	for _, dependency := range g[product] {
+		if !completed[dependency] {
+			return false
+		}
+	}
+	return true
+}
+
+// DependentsRemoved returns true if every product that depends on the product is in removed
+func (g ProductGraph) DependentsRemoved(product integreatlyv1alpha1.ProductName, removed map[integreatlyv1alpha1.ProductName]bool) bool {
+	for _, dependent := range g.Dependents(product) {
+		if !removed[dependent] {
+			return false
+		}
+	}
+	return true
+}
+
+// Dependents returns the products that directly depend on the product, sorted by name
+func (g ProductGraph) Dependents(product integreatlyv1alpha1.ProductName) []integreatlyv1alpha1.ProductName {
+	var dependents []integreatlyv1alpha1.ProductName
+	for _, candidate := range g.products() {
+		for _, dependency := range g[candidate] {
+			if dependency == product {
+				dependents = append(dependents, candidate)
+				break
+			}
+		}
+	}
+	return dependents
+}
+
+// Status converts the graph into the form reported in the RHMI status
+func (g ProductGraph) Status() (*integreatlyv1alpha1.ProductGraphStatus, error) {
+	installOrder, err := g.InstallOrder()
+	if err != nil {
+		return nil, err
+	}
+	uninstallOrder, err := g.UninstallOrder()
+	if err != nil {
+		return nil, err
+	}
+
+	status := &integreatlyv1alpha1.ProductGraphStatus{
+		Dependencies: map[integreatlyv1alpha1.ProductName][]integreatlyv1alpha1.ProductName{},
+	}
+	for _, product := range g.products() {
+		status.Dependencies[product] = g.dependencies(product)
+	}
+	for _, layer := range installOrder {
+		status.InstallOrder = append(status.InstallOrder, integreatlyv1alpha1.ProductGraphLayer{Products: layer})
+	}
+	for _, layer := range uninstallOrder {
+		status.UninstallOrder = append(status.UninstallOrder, integreatlyv1alpha1.ProductGraphLayer{Products: layer})
+	}
+	return status, nil
+}
+
+// products returns every product in the graph sorted by name so the computed order is stable
+func (g ProductGraph) products() []integreatlyv1alpha1.ProductName {
+	products := make([]integreatlyv1alpha1.ProductName, 0, len(g))
+	for product := range g {
+		products = append(products, product)
+	}
+	sortProducts(products)
+	return products
+}
+
+func (g ProductGraph) dependencies(product integreatlyv1alpha1.ProductName) []integreatlyv1alpha1.ProductName {
+	dependencies := append([]integreatlyv1alpha1.ProductName{}, g[product]...)
+	sortProducts(dependencies)
+	return dependencies
+}
+
+func sortProducts(products []integreatlyv1alpha1.ProductName) {
+	sort.Slice(products, func(i, j int) bool {
+		return products[i] < products[j]
+	})
+}
+
+func formatCycle(path []integreatlyv1alpha1.ProductName, product integreatlyv1alpha1.ProductName) string {
+	start := 0
+	for i := range path {
+		if path[i] == product {
+			start = i
+			break
+		}
+	}
+	cycle := make([]string, 0, len(path)-start+1)
+	for _, p := range path[start:] {
+		cycle = append(cycle, string(p))
+	}
+	cycle = append(cycle, string(product))
+	return strings.Join(cycle, " -> ")
+}
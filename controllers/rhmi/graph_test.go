@@ -0,0 +1,163 @@
+package controllers
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	rhmiv1alpha1 "github.com/integr8ly/integreatly-operator/apis/v1alpha1"
+)
+
+func TestProductGraph_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		graph   ProductGraph
+		wantErr string
+	}{
+		{
+			name:  "test managed api graph is valid",
+			graph: newProductGraph(rhmiv1alpha1.InstallationTypeManagedApi),
+		},
+		{
+			name:  "test multitenant managed api graph is valid",
+			graph: newProductGraph(rhmiv1alpha1.InstallationTypeMultitenantManagedApi),
+		},
+		{
+			name: "test unknown dependency is rejected",
+			graph: ProductGraph{
+				rhmiv1alpha1.Product3Scale: {rhmiv1alpha1.ProductRHSSO},
+			},
+			wantErr: "depends on rhsso which is not part of the installation type",
+		},
+		{
+			name: "test dependency cycle is rejected",
+			graph: ProductGraph{
+				rhmiv1alpha1.ProductCloudResources: {},
+				rhmiv1alpha1.Product3Scale:         {rhmiv1alpha1.ProductCloudResources, rhmiv1alpha1.ProductRHSSO},
+				rhmiv1alpha1.ProductRHSSO:          {rhmiv1alpha1.ProductMarin3r},
+				rhmiv1alpha1.ProductMarin3r:        {rhmiv1alpha1.Product3Scale},
+			},
+			wantErr: "cycle found: 3scale -> rhsso -> marin3r -> 3scale",
+		},
+		{
+			name: "test product depending on itself is rejected",
+			graph: ProductGraph{
+				rhmiv1alpha1.ProductGrafana: {rhmiv1alpha1.ProductGrafana},
+			},
+			wantErr: "cycle found: grafana -> grafana",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.graph.Validate()
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("expected error containing %q but got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestProductGraph_InstallAndUninstallOrder(t *testing.T) {
+	graph := ProductGraph{
+		rhmiv1alpha1.ProductObservability:  {},
+		rhmiv1alpha1.ProductCloudResources: {rhmiv1alpha1.ProductObservability},
+		rhmiv1alpha1.ProductGrafana:        {rhmiv1alpha1.ProductObservability},
+		rhmiv1alpha1.ProductRHSSO:          {rhmiv1alpha1.ProductCloudResources},
+		rhmiv1alpha1.Product3Scale:         {rhmiv1alpha1.ProductCloudResources, rhmiv1alpha1.ProductRHSSO},
+	}
+
+	installOrder, err := graph.InstallOrder()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	wantInstallOrder := [][]rhmiv1alpha1.ProductName{
+		{rhmiv1alpha1.ProductObservability},
+		{rhmiv1alpha1.ProductCloudResources, rhmiv1alpha1.ProductGrafana},
+		{rhmiv1alpha1.ProductRHSSO},
+		{rhmiv1alpha1.Product3Scale},
+	}
+	if !reflect.DeepEqual(installOrder, wantInstallOrder) {
+		t.Fatalf("InstallOrder() = %v, want %v", installOrder, wantInstallOrder)
+	}
+
+	uninstallOrder, err := graph.UninstallOrder()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	wantUninstallOrder := [][]rhmiv1alpha1.ProductName{
+		{rhmiv1alpha1.Product3Scale, rhmiv1alpha1.ProductGrafana},
+		{rhmiv1alpha1.ProductRHSSO},
+		{rhmiv1alpha1.ProductCloudResources},
+		{rhmiv1alpha1.ProductObservability},
+	}
+	if !reflect.DeepEqual(uninstallOrder, wantUninstallOrder) {
+		t.Fatalf("UninstallOrder() = %v, want %v", uninstallOrder, wantUninstallOrder)
+	}
+}
+
+func TestProductGraph_Ready(t *testing.T) {
+	completed := map[rhmiv1alpha1.ProductName]bool{
+		rhmiv1alpha1.ProductObservability:  true,
+		rhmiv1alpha1.ProductCloudResources: true,
+	}
+
+	managedApiProducts := newProductGraph(rhmiv1alpha1.InstallationTypeManagedApi)
+	if !managedApiProducts.Ready(rhmiv1alpha1.ProductMarin3r, completed) {
+		t.Errorf("expected marin3r to be ready once cloud-resources and observability completed")
+	}
+	if managedApiProducts.Ready(rhmiv1alpha1.Product3Scale, completed) {
+		t.Errorf("expected 3scale to wait for rhsso")
+	}
+	if !ProductGraph(nil).Ready(rhmiv1alpha1.Product3Scale, nil) {
+		t.Errorf("expected every product to be ready when there is no graph")
+	}
+}
+
+func TestTypeFactory(t *testing.T) {
+	for _, installationType := range []rhmiv1alpha1.InstallationType{
+		rhmiv1alpha1.InstallationTypeManagedApi,
+		rhmiv1alpha1.InstallationTypeMultitenantManagedApi,
+	} {
+		t.Run(string(installationType), func(t *testing.T) {
+			installType, err := TypeFactory(string(installationType))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			// the same stages must be returned so product statuses carry over between reconciles
+			again, err := TypeFactory(string(installationType))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if installType != again {
+				t.Fatalf("expected TypeFactory to return the same type on every call")
+			}
+
+			for _, stage := range installType.GetInstallStages() {
+				if stage.Name != rhmiv1alpha1.InstallStage {
+					continue
+				}
+				for product := range installType.Dependencies {
+					if _, ok := stage.Products[product]; !ok {
+						t.Errorf("expected %s in the install stage", product)
+					}
+				}
+			}
+
+			status, err := installType.Dependencies.Status()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(status.InstallOrder) == 0 || len(status.UninstallOrder) != len(status.InstallOrder) {
+				t.Fatalf("unexpected graph status %+v", status)
+			}
+		})
+	}
+
+	if _, err := TypeFactory("unknown"); err == nil {
+		t.Fatalf("expected an error for an unknown installation type")
+	}
+}
@@ -0,0 +1,50 @@
+package controllers
+
+import (
+	"fmt"
+	"time"
+
+	rhmiv1alpha1 "github.com/integr8ly/integreatly-operator/apis/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// pauseProduct marks a product listed in the paused products of the installation as paused
+// instead of reconciling it. The product keeps the status it had when it was paused, if the
+// operator has restarted since then the status is taken from the installation
+func pauseProduct(installation *rhmiv1alpha1.RHMI, stageName rhmiv1alpha1.StageName, productStatus rhmiv1alpha1.RHMIProductStatus, now metav1.Time) rhmiv1alpha1.RHMIProductStatus {
+	if !productStatus.Paused && productStatus.Phase == rhmiv1alpha1.PhaseNone {
+		if persisted, ok := installation.Status.Stages[stageName].Products[productStatus.Name]; ok {
+			productStatus = *persisted.DeepCopy()
+		}
+	}
+
+	productStatus.Paused = true
+	if productStatus.PausedSince == nil {
+		productStatus.PausedSince = &now
+	}
+	setCondition(&productStatus.Conditions, installation.Generation, rhmiv1alpha1.ConditionTypePaused, metav1.ConditionTrue,
+		rhmiv1alpha1.ConditionReasonPausedBySpec, fmt.Sprintf("the product is paused in the installation since %s", productStatus.PausedSince.UTC().Format(time.RFC3339)))
+	return productStatus
+}
+
+// resumeProduct clears the pause of a product that is reconciled
+func resumeProduct(productStatus *rhmiv1alpha1.RHMIProductStatus, generation int64) {
+	productStatus.Paused = false
+	productStatus.PausedSince = nil
+	setCondition(&productStatus.Conditions, generation, rhmiv1alpha1.ConditionTypePaused, metav1.ConditionFalse,
+		rhmiv1alpha1.ConditionReasonNotPaused, "")
+}
+
+// pausedProducts returns the paused products in the installation status ordered by name
+func pausedProducts(installation *rhmiv1alpha1.RHMI) []rhmiv1alpha1.ProductName {
+	var paused []rhmiv1alpha1.ProductName
+	for _, stage := range installation.Status.Stages {
+		for _, product := range stage.Products {
+			if product.Paused {
+				paused = append(paused, product.Name)
+			}
+		}
+	}
+	sortProducts(paused)
+	return paused
+}
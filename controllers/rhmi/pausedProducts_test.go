@@ -0,0 +1,132 @@
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	rhmiv1alpha1 "github.com/integr8ly/integreatly-operator/apis/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestPauseProduct(t *testing.T) {
+	pausedAt := metav1.NewTime(time.Now().Add(-time.Hour).Truncate(time.Second))
+	now := metav1.NewTime(time.Now().Truncate(time.Second))
+
+	tests := []struct {
+		name          string
+		installation  *rhmiv1alpha1.RHMI
+		productStatus rhmiv1alpha1.RHMIProductStatus
+		wantPhase     rhmiv1alpha1.StatusPhase
+		wantSince     metav1.Time
+	}{
+		{
+			name:         "test product is paused now",
+			installation: &rhmiv1alpha1.RHMI{},
+			productStatus: rhmiv1alpha1.RHMIProductStatus{
+				Name:  rhmiv1alpha1.Product3Scale,
+				Phase: rhmiv1alpha1.PhaseCompleted,
+			},
+			wantPhase: rhmiv1alpha1.PhaseCompleted,
+			wantSince: now,
+		},
+		{
+			name:         "test paused product keeps the time it was paused",
+			installation: &rhmiv1alpha1.RHMI{},
+			productStatus: rhmiv1alpha1.RHMIProductStatus{
+				Name:        rhmiv1alpha1.Product3Scale,
+				Phase:       rhmiv1alpha1.PhaseInProgress,
+				Paused:      true,
+				PausedSince: &pausedAt,
+			},
+			wantPhase: rhmiv1alpha1.PhaseInProgress,
+			wantSince: pausedAt,
+		},
+		{
+			name: "test paused product status is restored from the installation after a restart",
+			installation: &rhmiv1alpha1.RHMI{
+				Status: rhmiv1alpha1.RHMIStatus{
+					Stages: map[rhmiv1alpha1.StageName]rhmiv1alpha1.RHMIStageStatus{
+						rhmiv1alpha1.InstallStage: {
+							Name: rhmiv1alpha1.InstallStage,
+							Products: map[rhmiv1alpha1.ProductName]rhmiv1alpha1.RHMIProductStatus{
+								rhmiv1alpha1.Product3Scale: {
+									Name:        rhmiv1alpha1.Product3Scale,
+									Phase:       rhmiv1alpha1.PhaseCompleted,
+									Paused:      true,
+									PausedSince: &pausedAt,
+								},
+							},
+						},
+					},
+				},
+			},
+			productStatus: rhmiv1alpha1.RHMIProductStatus{Name: rhmiv1alpha1.Product3Scale},
+			wantPhase:     rhmiv1alpha1.PhaseCompleted,
+			wantSince:     pausedAt,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := pauseProduct(tt.installation, rhmiv1alpha1.InstallStage, tt.productStatus, now)
+
+			if !got.Paused || got.PausedSince == nil {
+				t.Fatalf("expected the product to be paused but got %+v", got)
+			}
+			if !got.PausedSince.Equal(&tt.wantSince) {
+				t.Errorf("expected the product to be paused since %v but got %v", tt.wantSince, got.PausedSince)
+			}
+			if got.Phase != tt.wantPhase {
+				t.Errorf("expected phase %s but got %s", tt.wantPhase, got.Phase)
+			}
+			if !meta.IsStatusConditionTrue(got.Conditions, rhmiv1alpha1.ConditionTypePaused) {
+				t.Errorf("expected the %s condition to be true but got %v", rhmiv1alpha1.ConditionTypePaused, got.Conditions)
+			}
+		})
+	}
+}
+
+func TestResumeProduct(t *testing.T) {
+	productStatus := pauseProduct(&rhmiv1alpha1.RHMI{}, rhmiv1alpha1.InstallStage, rhmiv1alpha1.RHMIProductStatus{Name: rhmiv1alpha1.Product3Scale}, metav1.Now())
+	resumeProduct(&productStatus, 1)
+
+	if productStatus.Paused || productStatus.PausedSince != nil {
+		t.Fatalf("expected the pause to be cleared but got %+v", productStatus)
+	}
+	if !meta.IsStatusConditionFalse(productStatus.Conditions, rhmiv1alpha1.ConditionTypePaused) {
+		t.Errorf("expected the %s condition to be false but got %v", rhmiv1alpha1.ConditionTypePaused, productStatus.Conditions)
+	}
+}
+
+func TestSetInstallationConditions_PausedProducts(t *testing.T) {
+	installation := &rhmiv1alpha1.RHMI{
+		Spec: rhmiv1alpha1.RHMISpec{PausedProducts: []rhmiv1alpha1.ProductName{rhmiv1alpha1.Product3Scale}},
+		Status: rhmiv1alpha1.RHMIStatus{
+			Stages: map[rhmiv1alpha1.StageName]rhmiv1alpha1.RHMIStageStatus{
+				rhmiv1alpha1.InstallStage: {
+					Name: rhmiv1alpha1.InstallStage,
+					Products: map[rhmiv1alpha1.ProductName]rhmiv1alpha1.RHMIProductStatus{
+						rhmiv1alpha1.Product3Scale: {Name: rhmiv1alpha1.Product3Scale, Paused: true},
+						rhmiv1alpha1.ProductRHSSO:  {Name: rhmiv1alpha1.ProductRHSSO},
+					},
+				},
+			},
+		},
+	}
+
+	setInstallationConditions(installation)
+	paused := meta.FindStatusCondition(installation.Status.Conditions, rhmiv1alpha1.ConditionTypePaused)
+	if paused == nil || paused.Status != metav1.ConditionTrue || paused.Reason != rhmiv1alpha1.ConditionReasonPausedBySpec {
+		t.Fatalf("expected the installation to be paused but got %v", paused)
+	}
+
+	// the condition is cleared once the product is reconciled again
+	productStatus := installation.Status.Stages[rhmiv1alpha1.InstallStage].Products[rhmiv1alpha1.Product3Scale]
+	resumeProduct(&productStatus, installation.Generation)
+	installation.Status.Stages[rhmiv1alpha1.InstallStage].Products[rhmiv1alpha1.Product3Scale] = productStatus
+
+	setInstallationConditions(installation)
+	if !meta.IsStatusConditionFalse(installation.Status.Conditions, rhmiv1alpha1.ConditionTypePaused) {
+		t.Errorf("expected the installation not to be paused but got %v", installation.Status.Conditions)
+	}
+}
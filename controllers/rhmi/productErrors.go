@@ -0,0 +1,89 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+
+	rhmiv1alpha1 "github.com/integr8ly/integreatly-operator/apis/v1alpha1"
+	"github.com/integr8ly/integreatly-operator/pkg/metrics"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// maxProductErrors is the number of distinct errors kept in each product status
+	maxProductErrors = 10
+
+	productErrorReasonTimeout   = "Timeout"
+	productErrorReasonReconcile = "ReconcileError"
+)
+
+// restoreProductStatus takes the error history and the conditions of the product from the
+// installation when the operator has restarted since the product was last reconciled. The product
+// statuses of the stages are only kept in memory, and they replace the statuses in the installation
+// once the stage is reconciled
+func restoreProductStatus(installation *rhmiv1alpha1.RHMI, stageName rhmiv1alpha1.StageName, productStatus *rhmiv1alpha1.RHMIProductStatus) {
+	persisted, ok := installation.Status.Stages[stageName].Products[productStatus.Name]
+	if !ok {
+		return
+	}
+	if productStatus.Errors == nil {
+		productStatus.Errors = persisted.DeepCopy().Errors
+	}
+	if productStatus.Conditions == nil {
+		productStatus.Conditions = persisted.DeepCopy().Conditions
+	}
+}
+
+// recordProductError adds the error to the error history of the product and counts it in the
+// product error metric. An error already in the history has its count and last seen time updated,
+// otherwise it is added and the least recently seen error is dropped once the history is full
+func recordProductError(productStatus *rhmiv1alpha1.RHMIProductStatus, reconcileErr error, now metav1.Time) {
+	if reconcileErr == nil {
+		return
+	}
+
+	reason := productErrorReason(reconcileErr)
+	metrics.IncProductReconcileErrors(string(productStatus.Name), reason)
+
+	message := reconcileErr.Error()
+	for i := range productStatus.Errors {
+		if productStatus.Errors[i].Message == message {
+			productStatus.Errors[i].Count++
+			productStatus.Errors[i].LastSeen = now
+			productStatus.Errors[i].Phase = productStatus.Phase
+			return
+		}
+	}
+
+	if len(productStatus.Errors) >= maxProductErrors {
+		oldest := 0
+		for i := range productStatus.Errors {
+			if productStatus.Errors[i].LastSeen.Before(&productStatus.Errors[oldest].LastSeen) {
+				oldest = i
+			}
+		}
+		productStatus.Errors = append(productStatus.Errors[:oldest], productStatus.Errors[oldest+1:]...)
+	}
+
+	productStatus.Errors = append(productStatus.Errors, rhmiv1alpha1.ProductError{
+		Message:   message,
+		Reason:    reason,
+		Phase:     productStatus.Phase,
+		FirstSeen: now,
+		LastSeen:  now,
+		Count:     1,
+	})
+}
+
+// productErrorReason classifies the error into a small set of reasons so it can be used as a
+// metric label
+func productErrorReason(err error) string {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return productErrorReasonTimeout
+	}
+	if reason := k8serr.ReasonForError(err); reason != metav1.StatusReasonUnknown {
+		return string(reason)
+	}
+	return productErrorReasonReconcile
+}
@@ -0,0 +1,170 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	rhmiv1alpha1 "github.com/integr8ly/integreatly-operator/apis/v1alpha1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestRecordProductError(t *testing.T) {
+	start := time.Now()
+	at := func(minutes int) metav1.Time {
+		return metav1.NewTime(start.Add(time.Duration(minutes) * time.Minute))
+	}
+
+	tests := []struct {
+		name         string
+		installation *rhmiv1alpha1.RHMI
+		status       *rhmiv1alpha1.RHMIProductStatus
+		record       func(installation *rhmiv1alpha1.RHMI, status *rhmiv1alpha1.RHMIProductStatus)
+		verify       func(t *testing.T, status *rhmiv1alpha1.RHMIProductStatus)
+	}{
+		{
+			name:   "test nil error is not recorded",
+			status: &rhmiv1alpha1.RHMIProductStatus{Name: rhmiv1alpha1.Product3Scale},
+			record: func(installation *rhmiv1alpha1.RHMI, status *rhmiv1alpha1.RHMIProductStatus) {
+				recordProductError(status, nil, at(0))
+			},
+			verify: func(t *testing.T, status *rhmiv1alpha1.RHMIProductStatus) {
+				if len(status.Errors) != 0 {
+					t.Fatalf("expected no errors but got %v", status.Errors)
+				}
+			},
+		},
+		{
+			name:   "test repeated error is counted",
+			status: &rhmiv1alpha1.RHMIProductStatus{Name: rhmiv1alpha1.Product3Scale, Phase: rhmiv1alpha1.PhaseInProgress},
+			record: func(installation *rhmiv1alpha1.RHMI, status *rhmiv1alpha1.RHMIProductStatus) {
+				recordProductError(status, errors.New("apicast not ready"), at(0))
+				status.Phase = rhmiv1alpha1.PhaseFailed
+				recordProductError(status, errors.New("apicast not ready"), at(5))
+			},
+			verify: func(t *testing.T, status *rhmiv1alpha1.RHMIProductStatus) {
+				if len(status.Errors) != 1 {
+					t.Fatalf("expected 1 error but got %d", len(status.Errors))
+				}
+				productError := status.Errors[0]
+				if productError.Count != 2 {
+					t.Errorf("expected count 2 but got %d", productError.Count)
+				}
+				if !productError.FirstSeen.Equal(&metav1.Time{Time: at(0).Time}) || !productError.LastSeen.Equal(&metav1.Time{Time: at(5).Time}) {
+					t.Errorf("unexpected first seen %v and last seen %v", productError.FirstSeen, productError.LastSeen)
+				}
+				if productError.Phase != rhmiv1alpha1.PhaseFailed {
+					t.Errorf("expected phase %s but got %s", rhmiv1alpha1.PhaseFailed, productError.Phase)
+				}
+			},
+		},
+		{
+			name:   "test least recently seen error is dropped when the history is full",
+			status: &rhmiv1alpha1.RHMIProductStatus{Name: rhmiv1alpha1.ProductRHSSO},
+			record: func(installation *rhmiv1alpha1.RHMI, status *rhmiv1alpha1.RHMIProductStatus) {
+				for i := 0; i < maxProductErrors; i++ {
+					recordProductError(status, fmt.Errorf("error %d", i), at(i))
+				}
+				// error 0 is seen again so error 1 becomes the least recently seen
+				recordProductError(status, errors.New("error 0"), at(maxProductErrors))
+				recordProductError(status, errors.New("new error"), at(maxProductErrors+1))
+			},
+			verify: func(t *testing.T, status *rhmiv1alpha1.RHMIProductStatus) {
+				if len(status.Errors) != maxProductErrors {
+					t.Fatalf("expected %d errors but got %d", maxProductErrors, len(status.Errors))
+				}
+				for _, productError := range status.Errors {
+					if productError.Message == "error 1" {
+						t.Fatalf("expected error 1 to be dropped")
+					}
+				}
+				if status.Errors[len(status.Errors)-1].Message != "new error" {
+					t.Fatalf("expected the new error to be last")
+				}
+			},
+		},
+		{
+			name: "test history is taken from the installation after a restart",
+			installation: &rhmiv1alpha1.RHMI{
+				Status: rhmiv1alpha1.RHMIStatus{
+					Stages: map[rhmiv1alpha1.StageName]rhmiv1alpha1.RHMIStageStatus{
+						rhmiv1alpha1.ProductsStage: {
+							Name: rhmiv1alpha1.ProductsStage,
+							Products: map[rhmiv1alpha1.ProductName]rhmiv1alpha1.RHMIProductStatus{
+								rhmiv1alpha1.Product3Scale: {
+									Name: rhmiv1alpha1.Product3Scale,
+									Errors: []rhmiv1alpha1.ProductError{
+										{Message: "apicast not ready", Reason: productErrorReasonReconcile, FirstSeen: at(0), LastSeen: at(0), Count: 3},
+										{Message: "backend not ready", Reason: productErrorReasonReconcile, FirstSeen: at(1), LastSeen: at(1), Count: 1},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			status: &rhmiv1alpha1.RHMIProductStatus{Name: rhmiv1alpha1.Product3Scale, Phase: rhmiv1alpha1.PhaseInProgress},
+			record: func(installation *rhmiv1alpha1.RHMI, status *rhmiv1alpha1.RHMIProductStatus) {
+				restoreProductStatus(installation, rhmiv1alpha1.ProductsStage, status)
+				recordProductError(status, errors.New("apicast not ready"), at(5))
+			},
+			verify: func(t *testing.T, status *rhmiv1alpha1.RHMIProductStatus) {
+				if len(status.Errors) != 2 {
+					t.Fatalf("expected the 2 persisted errors but got %v", status.Errors)
+				}
+				productError := status.Errors[0]
+				if productError.Count != 4 {
+					t.Errorf("expected count 4 but got %d", productError.Count)
+				}
+				if !productError.FirstSeen.Equal(&metav1.Time{Time: at(0).Time}) || !productError.LastSeen.Equal(&metav1.Time{Time: at(5).Time}) {
+					t.Errorf("unexpected first seen %v and last seen %v", productError.FirstSeen, productError.LastSeen)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			installation := tt.installation
+			if installation == nil {
+				installation = &rhmiv1alpha1.RHMI{}
+			}
+			tt.record(installation, tt.status)
+			tt.verify(t, tt.status)
+		})
+	}
+}
+
+func TestProductErrorReason(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{
+			name: "test timeout",
+			err:  fmt.Errorf("reconcile timed out after 5m0s: %w", context.DeadlineExceeded),
+			want: productErrorReasonTimeout,
+		},
+		{
+			name: "test kubernetes api error",
+			err:  fmt.Errorf("failed to get apimanager: %w", k8serr.NewNotFound(schema.GroupResource{Resource: "apimanagers"}, "3scale")),
+			want: string(metav1.StatusReasonNotFound),
+		},
+		{
+			name: "test other error",
+			err:  errors.New("apicast not ready"),
+			want: productErrorReasonReconcile,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := productErrorReason(tt.err); got != tt.want {
+				t.Errorf("productErrorReason() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
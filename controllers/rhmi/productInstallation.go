@@ -0,0 +1,217 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	rhmiv1alpha1 "github.com/integr8ly/integreatly-operator/apis/v1alpha1"
+	"github.com/integr8ly/integreatly-operator/pkg/resources"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// errProductInstallationAbandoned is returned when a product writes its copy of the installation
+// after its reconcile has timed out
+var errProductInstallationAbandoned = errors.New("the reconcile of the product timed out, its changes to the installation are discarded")
+
+// productInstallation is the copy of the installation a product is reconciled with. Products in a
+// stage are reconciled concurrently, so each of them works on its own copy and the changes they
+// make to it are applied to the shared installation one product at a time
+type productInstallation struct {
+	// mu serializes the writes of every product in a stage to the shared installation
+	mu     *sync.Mutex
+	shared *rhmiv1alpha1.RHMI
+
+	installation *rhmiv1alpha1.RHMI
+	// original is the copy when it was made, products can only change its finalizers and status
+	original *rhmiv1alpha1.RHMI
+	// status is the status of the copy when it was last applied to the shared installation
+	status rhmiv1alpha1.RHMIStatus
+	// finalizers are the finalizers of the copy when they were last applied to the shared installation
+	finalizers []string
+	// abandoned is set once the reconcile of the product has timed out, the changes it makes to its
+	// copy after that are no longer applied to the shared installation
+	abandoned bool
+}
+
+// newProductInstallation copies shared, the copy is made while no product is writing to it
+func newProductInstallation(shared *rhmiv1alpha1.RHMI, mu *sync.Mutex) *productInstallation {
+	mu.Lock()
+	defer mu.Unlock()
+
+	installation := shared.DeepCopy()
+	return &productInstallation{
+		mu:           mu,
+		shared:       shared,
+		installation: installation,
+		original:     installation.DeepCopy(),
+		status:       *installation.Status.DeepCopy(),
+		finalizers:   append([]string{}, installation.GetFinalizers()...),
+	}
+}
+
+// client returns serverClient with the writes of the copy of the installation applied to the
+// shared installation instead
+func (p *productInstallation) client(serverClient k8sclient.Client) k8sclient.Client {
+	return &productInstallationClient{Client: serverClient, installation: p}
+}
+
+// update applies the finalizers of the copy to the shared installation and updates it, the copy
+// takes the resource version of the update so later updates of the copy don't conflict. The status
+// of the shared installation is kept, the update would otherwise replace it with the stored status
+// and drop the changes the other stages and products have made to it
+func (p *productInstallation) update(ctx context.Context, serverClient k8sclient.Client, opts ...k8sclient.UpdateOption) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.abandoned {
+		return errProductInstallationAbandoned
+	}
+	if !equality.Semantic.DeepEqual(p.original.Spec, p.installation.Spec) ||
+		!equality.Semantic.DeepEqual(p.original.GetLabels(), p.installation.GetLabels()) ||
+		!equality.Semantic.DeepEqual(p.original.GetAnnotations(), p.installation.GetAnnotations()) {
+		return fmt.Errorf("products can only change the finalizers and the status of the installation")
+	}
+
+	p.mergeFinalizers()
+	status := p.shared.Status.DeepCopy()
+	err := serverClient.Update(ctx, p.shared, opts...)
+	p.shared.Status = *status
+	if err != nil {
+		return err
+	}
+	p.installation.SetResourceVersion(p.shared.GetResourceVersion())
+	return nil
+}
+
+// updateStatus applies the status of the copy to the shared installation, which is written by the
+// installation controller once the stage is reconciled
+func (p *productInstallation) updateStatus() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.abandoned {
+		return errProductInstallationAbandoned
+	}
+	return p.mergeStatus()
+}
+
+// merge applies the finalizers and the status fields the product changed on its copy to the
+// shared installation, it is called once the product is reconciled. An error is returned for the
+// status fields that another product changed to a different value
+func (p *productInstallation) merge() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.mergeFinalizers()
+	return p.mergeStatus()
+}
+
+// abandon stops the changes of the product from being applied to the shared installation
+func (p *productInstallation) abandon() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.abandoned = true
+}
+
+// mergeStatus applies the status fields the product changed on its copy since they were last
+// applied. A field that another product has changed to a different value in the meantime is a
+// conflict, the shared installation keeps the value of the other product
+func (p *productInstallation) mergeStatus() error {
+	status := p.installation.Status.DeepCopy()
+	sharedStatus := reflect.ValueOf(&p.shared.Status).Elem()
+	before := reflect.ValueOf(p.status)
+	after := reflect.ValueOf(*status)
+
+	var conflicts []string
+	for i := 0; i < after.NumField(); i++ {
+		if reflect.DeepEqual(before.Field(i).Interface(), after.Field(i).Interface()) {
+			continue
+		}
+		shared := sharedStatus.Field(i)
+		if !reflect.DeepEqual(before.Field(i).Interface(), shared.Interface()) && !reflect.DeepEqual(after.Field(i).Interface(), shared.Interface()) {
+			conflicts = append(conflicts, after.Type().Field(i).Name)
+			continue
+		}
+		shared.Set(after.Field(i))
+	}
+	p.status = *status.DeepCopy()
+
+	if len(conflicts) > 0 {
+		return fmt.Errorf("the installation status fields %s were also changed by another product", strings.Join(conflicts, ", "))
+	}
+	return nil
+}
+
+// mergeFinalizers adds and removes the finalizers the product added to and removed from its copy
+// since they were last applied, the finalizers of the other products are kept
+func (p *productInstallation) mergeFinalizers() {
+	finalizers := append([]string{}, p.shared.GetFinalizers()...)
+	for _, finalizer := range p.finalizers {
+		if !resources.Contains(p.installation.GetFinalizers(), finalizer) {
+			finalizers = resources.Remove(finalizers, finalizer)
+		}
+	}
+	for _, finalizer := range p.installation.GetFinalizers() {
+		if !resources.Contains(p.finalizers, finalizer) && !resources.Contains(finalizers, finalizer) {
+			finalizers = append(finalizers, finalizer)
+		}
+	}
+	p.shared.SetFinalizers(finalizers)
+	p.finalizers = append([]string{}, p.installation.GetFinalizers()...)
+}
+
+// productInstallationClient applies the writes of the copy of the installation to the shared
+// installation, every other request is made by the wrapped client
+type productInstallationClient struct {
+	k8sclient.Client
+	installation *productInstallation
+}
+
+func (c *productInstallationClient) Update(ctx context.Context, obj runtime.Object, opts ...k8sclient.UpdateOption) error {
+	if obj == c.installation.installation {
+		return c.installation.update(ctx, c.Client, opts...)
+	}
+	return c.Client.Update(ctx, obj, opts...)
+}
+
+// Patch of the copy of the installation updates the shared installation with the finalizers of the
+// copy, the copy already holds the result of the patch
+func (c *productInstallationClient) Patch(ctx context.Context, obj runtime.Object, patch k8sclient.Patch, opts ...k8sclient.PatchOption) error {
+	if obj == c.installation.installation {
+		patchOpts := (&k8sclient.PatchOptions{}).ApplyOptions(opts)
+		return c.installation.update(ctx, c.Client, &k8sclient.UpdateOptions{DryRun: patchOpts.DryRun, FieldManager: patchOpts.FieldManager})
+	}
+	return c.Client.Patch(ctx, obj, patch, opts...)
+}
+
+func (c *productInstallationClient) Status() k8sclient.StatusWriter {
+	return &productInstallationStatusWriter{StatusWriter: c.Client.Status(), installation: c.installation}
+}
+
+// productInstallationStatusWriter applies the status of the copy of the installation to the shared
+// installation, the status of every other object is written by the wrapped writer
+type productInstallationStatusWriter struct {
+	k8sclient.StatusWriter
+	installation *productInstallation
+}
+
+func (w *productInstallationStatusWriter) Update(ctx context.Context, obj runtime.Object, opts ...k8sclient.UpdateOption) error {
+	if obj == w.installation.installation {
+		return w.installation.updateStatus()
+	}
+	return w.StatusWriter.Update(ctx, obj, opts...)
+}
+
+func (w *productInstallationStatusWriter) Patch(ctx context.Context, obj runtime.Object, patch k8sclient.Patch, opts ...k8sclient.PatchOption) error {
+	if obj == w.installation.installation {
+		return w.installation.updateStatus()
+	}
+	return w.StatusWriter.Patch(ctx, obj, patch, opts...)
+}
@@ -0,0 +1,161 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	rhmiv1alpha1 "github.com/integr8ly/integreatly-operator/apis/v1alpha1"
+	"github.com/integr8ly/integreatly-operator/pkg/resources"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func getProductInstallationTestClient(t *testing.T) (client.Client, *rhmiv1alpha1.RHMI) {
+	scheme := runtime.NewScheme()
+	if err := rhmiv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	installation := &rhmiv1alpha1.RHMI{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "test-installation",
+			Namespace:  "test-namespace",
+			Finalizers: []string{"finalizer.existing"},
+		},
+	}
+	serverClient := fakeclient.NewFakeClientWithScheme(scheme, installation.DeepCopy())
+	if err := serverClient.Get(context.TODO(), client.ObjectKey{Name: installation.Name, Namespace: installation.Namespace}, installation); err != nil {
+		t.Fatal(err)
+	}
+	return serverClient, installation
+}
+
+func TestProductInstallation_merge(t *testing.T) {
+	tests := []struct {
+		name    string
+		changeA func(status *rhmiv1alpha1.RHMIStatus)
+		changeB func(status *rhmiv1alpha1.RHMIStatus)
+		wantErr string
+		verify  func(t *testing.T, status rhmiv1alpha1.RHMIStatus)
+	}{
+		{
+			name:    "test changes to different fields are both kept",
+			changeA: func(status *rhmiv1alpha1.RHMIStatus) { status.GitHubOAuthEnabled = true },
+			changeB: func(status *rhmiv1alpha1.RHMIStatus) { status.SMTPEnabled = true },
+			verify: func(t *testing.T, status rhmiv1alpha1.RHMIStatus) {
+				if !status.GitHubOAuthEnabled || !status.SMTPEnabled {
+					t.Errorf("expected both changes but got GitHubOAuthEnabled %t, SMTPEnabled %t", status.GitHubOAuthEnabled, status.SMTPEnabled)
+				}
+			},
+		},
+		{
+			name: "test the same change to a field is not a conflict",
+			changeA: func(status *rhmiv1alpha1.RHMIStatus) {
+				status.CustomSmtp = &rhmiv1alpha1.CustomSmtpStatus{Enabled: true}
+			},
+			changeB: func(status *rhmiv1alpha1.RHMIStatus) {
+				status.CustomSmtp = &rhmiv1alpha1.CustomSmtpStatus{Enabled: true}
+			},
+			verify: func(t *testing.T, status rhmiv1alpha1.RHMIStatus) {
+				if status.CustomSmtp == nil || !status.CustomSmtp.Enabled {
+					t.Errorf("expected the custom smtp status to be set but got %v", status.CustomSmtp)
+				}
+			},
+		},
+		{
+			name: "test different changes to a field are reported",
+			changeA: func(status *rhmiv1alpha1.RHMIStatus) {
+				status.CustomSmtp = &rhmiv1alpha1.CustomSmtpStatus{Enabled: true}
+			},
+			changeB: func(status *rhmiv1alpha1.RHMIStatus) {
+				status.CustomSmtp = &rhmiv1alpha1.CustomSmtpStatus{Error: "invalid"}
+			},
+			wantErr: "CustomSmtp",
+			verify: func(t *testing.T, status rhmiv1alpha1.RHMIStatus) {
+				if status.CustomSmtp == nil || !status.CustomSmtp.Enabled {
+					t.Errorf("expected the change of the first product to be kept but got %v", status.CustomSmtp)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shared := &rhmiv1alpha1.RHMI{}
+			mu := &sync.Mutex{}
+			productA, productB := newProductInstallation(shared, mu), newProductInstallation(shared, mu)
+			tt.changeA(&productA.installation.Status)
+			tt.changeB(&productB.installation.Status)
+
+			if err := productA.merge(); err != nil {
+				t.Fatalf("unexpected error merging the first product: %v", err)
+			}
+			err := productB.merge()
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("unexpected error merging the second product: %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("expected an error about %s but got %v", tt.wantErr, err)
+			}
+			tt.verify(t, shared.Status)
+		})
+	}
+}
+
+func TestProductInstallationClient(t *testing.T) {
+	serverClient, shared := getProductInstallationTestClient(t)
+	mu := &sync.Mutex{}
+	productA, productB := newProductInstallation(shared, mu), newProductInstallation(shared, mu)
+
+	// a patch of the copy of a product keeps the finalizers added by the other products
+	productA.installation.SetFinalizers(append(productA.installation.GetFinalizers(), "finalizer.a"))
+	if err := productA.client(serverClient).Update(context.TODO(), productA.installation); err != nil {
+		t.Fatalf("unexpected error updating the copy: %v", err)
+	}
+	patch := client.MergeFrom(productB.installation.DeepCopy())
+	productB.installation.SetFinalizers(append(productB.installation.GetFinalizers(), "finalizer.b"))
+	if err := productB.client(serverClient).Patch(context.TODO(), productB.installation, patch); err != nil {
+		t.Fatalf("unexpected error patching the copy: %v", err)
+	}
+	stored := &rhmiv1alpha1.RHMI{}
+	if err := serverClient.Get(context.TODO(), client.ObjectKey{Name: shared.Name, Namespace: shared.Namespace}, stored); err != nil {
+		t.Fatal(err)
+	}
+	for _, finalizer := range []string{"finalizer.existing", "finalizer.a", "finalizer.b"} {
+		if !resources.Contains(stored.GetFinalizers(), finalizer) {
+			t.Errorf("expected finalizer %s on the stored installation but got %v", finalizer, stored.GetFinalizers())
+		}
+	}
+
+	// a status update of the copy is applied to the shared installation
+	productA.installation.Status.GitHubOAuthEnabled = true
+	if err := productA.client(serverClient).Status().Update(context.TODO(), productA.installation); err != nil {
+		t.Fatalf("unexpected error updating the status of the copy: %v", err)
+	}
+	if !shared.Status.GitHubOAuthEnabled {
+		t.Errorf("expected the status of the copy to be applied to the shared installation")
+	}
+
+	// only the finalizers and the status of the installation can be changed by a product
+	productA.installation.Spec.SMTPSecret = "smtp"
+	if err := productA.client(serverClient).Update(context.TODO(), productA.installation); err == nil {
+		t.Errorf("expected an error updating the spec of the installation")
+	}
+	productA.installation.Spec.SMTPSecret = ""
+
+	// the writes of an abandoned product are discarded
+	productB.abandon()
+	productB.installation.Status.SMTPEnabled = true
+	if err := productB.client(serverClient).Status().Update(context.TODO(), productB.installation); !errors.Is(err, errProductInstallationAbandoned) {
+		t.Errorf("expected errProductInstallationAbandoned updating the status of an abandoned copy but got %v", err)
+	}
+	if err := productB.client(serverClient).Update(context.TODO(), productB.installation); !errors.Is(err, errProductInstallationAbandoned) {
+		t.Errorf("expected errProductInstallationAbandoned updating an abandoned copy but got %v", err)
+	}
+	if shared.Status.SMTPEnabled {
+		t.Errorf("expected the status of an abandoned copy not to be applied")
+	}
+}
@@ -47,6 +47,40 @@ func (r *RHMIReconciler) newAlertsReconciler(installation *integreatlyv1alpha1.R
 					For:    "150m",
 					Labels: map[string]string{"severity": "critical", "product": installationName, "addon": getAddonName(installation), "namespace": "openshift-monitoring"},
 				},
+				{
+					Alert: fmt.Sprintf("%sProductPausedTooLong", strings.ToUpper(installationName)),
+					Annotations: map[string]string{
+						"sop_url": resources.SopUrlAlertsAndTroubleshooting,
+						"message": fmt.Sprintf("{{ $labels.product }} has been paused in the %s installation for more than %s and is not being reconciled", strings.ToUpper(installationName), installation.GetPausedProductsAlertWindow()),
+					},
+					Expr:   intstr.FromString(fmt.Sprintf(`time() - rhoam_product_paused_since_timestamp_seconds > %d`, int64(installation.GetPausedProductsAlertWindow().Seconds()))),
+					For:    "1m",
+					Labels: map[string]string{"severity": "warning", "addon": getAddonName(installation), "namespace": "openshift-monitoring"},
+				},
+				{
+					Alert: fmt.Sprintf("%sProductInstallStuck", strings.ToUpper(installationName)),
+					Annotations: map[string]string{
+						"sop_url": resources.SopUrlAlertsAndTroubleshooting,
+						"message": fmt.Sprintf("{{ $labels.product }} has not completed a reconcile in the %s installation for more than 1 hour", strings.ToUpper(installationName)),
+					},
+					// a product that has not completed since the operator started is stuck once it
+					// was first reconciled more than an hour ago
+					Expr: intstr.FromString(`((time() - rhoam_product_last_completed_timestamp_seconds > 3600) or ` +
+						`((time() - rhoam_product_first_reconciled_timestamp_seconds > 3600) unless on(product) rhoam_product_last_completed_timestamp_seconds)) ` +
+						`unless on(product) rhoam_product_paused_since_timestamp_seconds`),
+					For:    "5m",
+					Labels: map[string]string{"severity": "warning", "addon": getAddonName(installation), "namespace": "openshift-monitoring"},
+				},
+				{
+					Alert: fmt.Sprintf("%sProductReconcileSlow", strings.ToUpper(installationName)),
+					Annotations: map[string]string{
+						"sop_url": resources.SopUrlAlertsAndTroubleshooting,
+						"message": fmt.Sprintf("95%% of the {{ $labels.product }} reconciles in the %s installation are taking more than 2 minutes", strings.ToUpper(installationName)),
+					},
+					Expr:   intstr.FromString(`histogram_quantile(0.95, sum by(product, le) (rate(rhoam_product_reconcile_duration_seconds_bucket[15m]))) > 120`),
+					For:    "15m",
+					Labels: map[string]string{"severity": "warning", "addon": getAddonName(installation), "namespace": "openshift-monitoring"},
+				},
 			},
 		},
 		{
@@ -0,0 +1,261 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	rhmiv1alpha1 "github.com/integr8ly/integreatly-operator/apis/v1alpha1"
+	"github.com/integr8ly/integreatly-operator/pkg/metrics"
+	l "github.com/integr8ly/integreatly-operator/pkg/resources/logger"
+	"github.com/integr8ly/integreatly-operator/pkg/resources/quota"
+	openshiftappsv1 "github.com/openshift/api/apps/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	quotaChangeTimeoutEnvName = "QUOTA_CHANGE_TIMEOUT"
+	defaultQuotaChangeTimeout = 15 * time.Minute
+	quotaChangeRequeue        = 30 * time.Second
+)
+
+// reconcileQuotaChange rolls a change of quota out to one product at a time. The new quota is
+// applied to the next product once the previous one is ready, the products it is not applied to
+// yet keep the current quota. If a product does not become ready before the timeout the current
+// quota is applied again to every product, and the new quota is not applied until the quota param
+// changes again
+func (r *Reconciler) reconcileQuotaChange(ctx context.Context, installation *rhmiv1alpha1.RHMI, configMap *corev1.ConfigMap, installationQuota *quota.Quota, serverClient k8sclient.Client) error {
+	change := installation.Status.QuotaChange
+
+	// the quota param is set to the current quota, a change that was not rolled back yet is
+	// cancelled and the current quota is applied again to the products it was applied to
+	if installationQuota.GetName() == installation.Status.Quota {
+		if change != nil && change.Phase != rhmiv1alpha1.QuotaChangeRolledBack {
+			installation.Status.ToQuota = installation.Status.Quota
+		}
+		installation.Status.QuotaChange = nil
+		installationQuota.SetIsUpdated(installation.Status.ToQuota != "")
+		return nil
+	}
+
+	currentQuota := &quota.Quota{}
+	if err := quota.GetQuotaByName(installation.Status.Quota, configMap, currentQuota); err != nil {
+		// without the current quota the change can't be rolled out or back, it is applied to
+		// every product at once
+		r.log.Warningf("Current quota not found, applying the new quota to every product", l.Fields{"quota": installation.Status.Quota, "toQuota": installationQuota.GetName(), "error": err})
+		installation.Status.ToQuota = installationQuota.GetName()
+		installation.Status.QuotaChange = nil
+		installationQuota.SetIsUpdated(true)
+		return nil
+	}
+
+	if change == nil || change.FromQuota != installation.Status.Quota || change.ToQuota != installationQuota.GetName() {
+		change = newQuotaChange(currentQuota, installationQuota)
+		installation.Status.QuotaChange = change
+		r.log.Infof("Rolling out quota change", l.Fields{"quota": change.FromQuota, "toQuota": change.ToQuota, "products": change.Products, "changes": change.Changes})
+		r.recorder.Event(installation, "Normal", rhmiv1alpha1.EventQuotaChange, fmt.Sprintf("rolling out quota %s to %d products, changes: %s", change.ToQuota, len(change.Products), formatQuotaChanges(change.Changes)))
+	}
+
+	switch change.Phase {
+	case rhmiv1alpha1.QuotaChangeRolledBack:
+		installation.Status.ToQuota = ""
+		return quota.GetQuotaByName(installation.Status.Quota, configMap, installationQuota)
+	case rhmiv1alpha1.QuotaChangeRollingBack:
+		return r.rollbackQuotaChange(installation, configMap, installationQuota)
+	}
+
+	installation.Status.ToQuota = installationQuota.GetName()
+	if change.CurrentProduct != "" {
+		ready, err := r.isProductReady(ctx, serverClient, installation, change.CurrentProduct)
+		if err != nil {
+			return fmt.Errorf("failed to check if %s is ready with quota %s: %w", change.CurrentProduct, change.ToQuota, err)
+		}
+		timeout := getQuotaChangeTimeout()
+		switch {
+		case ready:
+			r.log.Infof("Quota change applied to product", l.Fields{"product": change.CurrentProduct, "toQuota": change.ToQuota})
+			change.AppliedProducts = append(change.AppliedProducts, change.CurrentProduct)
+			change.CurrentProduct = ""
+			change.CurrentProductStartTime = nil
+		case change.CurrentProductStartTime != nil && time.Since(change.CurrentProductStartTime.Time) > timeout:
+			change.Phase = rhmiv1alpha1.QuotaChangeRollingBack
+			change.Message = fmt.Sprintf("%s was not ready within %s of applying quota %s, rolling back to quota %s", change.CurrentProduct, timeout, change.ToQuota, change.FromQuota)
+			r.log.Warning(change.Message)
+			r.recorder.Event(installation, "Warning", rhmiv1alpha1.EventQuotaRollback, change.Message)
+			if !installation.IsDryRun() {
+				metrics.IncQuotaRollbacks(change.FromQuota, change.ToQuota)
+			}
+			return r.rollbackQuotaChange(installation, configMap, installationQuota)
+		}
+	}
+
+	if change.CurrentProduct == "" {
+		change.CurrentProduct = nextQuotaChangeProduct(change)
+		if change.CurrentProduct == "" {
+			// the new quota was applied to every product, it is completed with the installation
+			installationQuota.SetIsUpdated(true)
+			return nil
+		}
+		now := metav1.Now()
+		change.CurrentProductStartTime = &now
+		r.log.Infof("Applying quota change to product", l.Fields{"product": change.CurrentProduct, "toQuota": change.ToQuota})
+	}
+
+	installationQuota.SetIsUpdated(false)
+	installationQuota.SetRollout(currentQuota, append(append([]rhmiv1alpha1.ProductName{}, change.AppliedProducts...), change.CurrentProduct))
+	return nil
+}
+
+// completeQuotaChange is called when the installation completed with the quota, a change that was
+// applied to every product is removed and a change that was rolling back is rolled back
+func completeQuotaChange(installation *rhmiv1alpha1.RHMI) {
+	change := installation.Status.QuotaChange
+	if change == nil {
+		return
+	}
+	if change.Phase == rhmiv1alpha1.QuotaChangeRollingBack {
+		change.Phase = rhmiv1alpha1.QuotaChangeRolledBack
+		change.CurrentProduct = ""
+		change.CurrentProductStartTime = nil
+		return
+	}
+	if change.Phase == rhmiv1alpha1.QuotaChangeInProgress && change.ToQuota == installation.Status.Quota {
+		installation.Status.QuotaChange = nil
+	}
+}
+
+// rollbackQuotaChange applies the current quota again to every product, the change is rolled back
+// once the installation completes with it
+func (r *Reconciler) rollbackQuotaChange(installation *rhmiv1alpha1.RHMI, configMap *corev1.ConfigMap, installationQuota *quota.Quota) error {
+	if err := quota.GetQuotaByName(installation.Status.Quota, configMap, installationQuota); err != nil {
+		return fmt.Errorf("failed to get quota %s to roll back to: %w", installation.Status.Quota, err)
+	}
+	installation.Status.ToQuota = installation.Status.Quota
+	installationQuota.SetIsUpdated(true)
+	return nil
+}
+
+// isProductReady returns true when the product completed and the deployments, deployment configs
+// and stateful sets in its namespace rolled out every replica
+func (r *Reconciler) isProductReady(ctx context.Context, serverClient k8sclient.Client, installation *rhmiv1alpha1.RHMI, productName rhmiv1alpha1.ProductName) (bool, error) {
+	for _, stage := range installation.Status.Stages {
+		if product, ok := stage.Products[productName]; ok && product.Phase != rhmiv1alpha1.PhaseCompleted {
+			return false, nil
+		}
+	}
+
+	productConfig, err := r.ConfigManager.ReadProduct(productName)
+	if err != nil {
+		return false, fmt.Errorf("failed to read the config of %s: %w", productName, err)
+	}
+	namespace := productConfig.GetNamespace()
+	if namespace == "" {
+		return true, nil
+	}
+
+	deployments := &appsv1.DeploymentList{}
+	if err := serverClient.List(ctx, deployments, k8sclient.InNamespace(namespace)); err != nil {
+		return false, err
+	}
+	for _, deployment := range deployments.Items {
+		replicas := int32(1)
+		if deployment.Spec.Replicas != nil {
+			replicas = *deployment.Spec.Replicas
+		}
+		if deployment.Status.ObservedGeneration < deployment.Generation || !rolledOut(replicas, deployment.Status.UpdatedReplicas, deployment.Status.ReadyReplicas) {
+			return false, nil
+		}
+	}
+
+	statefulSets := &appsv1.StatefulSetList{}
+	if err := serverClient.List(ctx, statefulSets, k8sclient.InNamespace(namespace)); err != nil {
+		return false, err
+	}
+	for _, statefulSet := range statefulSets.Items {
+		replicas := int32(1)
+		if statefulSet.Spec.Replicas != nil {
+			replicas = *statefulSet.Spec.Replicas
+		}
+		if statefulSet.Status.ObservedGeneration < statefulSet.Generation || !rolledOut(replicas, statefulSet.Status.UpdatedReplicas, statefulSet.Status.ReadyReplicas) {
+			return false, nil
+		}
+	}
+
+	deploymentConfigs := &openshiftappsv1.DeploymentConfigList{}
+	if err := serverClient.List(ctx, deploymentConfigs, k8sclient.InNamespace(namespace)); err != nil {
+		return false, err
+	}
+	for _, deploymentConfig := range deploymentConfigs.Items {
+		if deploymentConfig.Status.ObservedGeneration < deploymentConfig.Generation || !rolledOut(deploymentConfig.Spec.Replicas, deploymentConfig.Status.UpdatedReplicas, deploymentConfig.Status.ReadyReplicas) {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+func rolledOut(replicas, updatedReplicas, readyReplicas int32) bool {
+	return updatedReplicas >= replicas && readyReplicas >= replicas
+}
+
+func newQuotaChange(from, to *quota.Quota) *rhmiv1alpha1.QuotaChangeStatus {
+	changes := quota.GetChanges(from, to)
+	return &rhmiv1alpha1.QuotaChangeStatus{
+		FromQuota: from.GetName(),
+		ToQuota:   to.GetName(),
+		Phase:     rhmiv1alpha1.QuotaChangeInProgress,
+		Changes:   changes,
+		Products:  quota.GetChangedProducts(changes),
+	}
+}
+
+// nextQuotaChangeProduct returns the first product of the change the new quota is not applied to,
+// or an empty name if it is applied to every product
+func nextQuotaChangeProduct(change *rhmiv1alpha1.QuotaChangeStatus) rhmiv1alpha1.ProductName {
+	applied := map[rhmiv1alpha1.ProductName]bool{}
+	for _, product := range change.AppliedProducts {
+		applied[product] = true
+	}
+	for _, product := range change.Products {
+		if !applied[product] {
+			return product
+		}
+	}
+	return ""
+}
+
+func formatQuotaChanges(changes []rhmiv1alpha1.QuotaComponentChange) string {
+	if len(changes) == 0 {
+		return "none"
+	}
+	result := ""
+	for i, change := range changes {
+		if i > 0 {
+			result += "; "
+		}
+		result += fmt.Sprintf("%s replicas %d -> %d", change.Component, change.FromReplicas, change.ToReplicas)
+		if change.FromResources != change.ToResources {
+			result += fmt.Sprintf(", resources %q -> %q", change.FromResources, change.ToResources)
+		}
+	}
+	return result
+}
+
+// getQuotaChangeTimeout returns how long a product has to become ready after a new quota is
+// applied to it before the change is rolled back
+func getQuotaChangeTimeout() time.Duration {
+	value, exists := os.LookupEnv(quotaChangeTimeoutEnvName)
+	if !exists {
+		return defaultQuotaChangeTimeout
+	}
+	timeout, err := time.ParseDuration(value)
+	if err != nil || timeout <= 0 {
+		log.Warningf("Invalid quota change timeout, using default", l.Fields{"value": value, "default": defaultQuotaChangeTimeout})
+		return defaultQuotaChangeTimeout
+	}
+	return timeout
+}
@@ -0,0 +1,277 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/apis/v1alpha1"
+	"github.com/integr8ly/integreatly-operator/pkg/config"
+	"github.com/integr8ly/integreatly-operator/pkg/resources"
+	l "github.com/integr8ly/integreatly-operator/pkg/resources/logger"
+	"github.com/integr8ly/integreatly-operator/pkg/resources/quota"
+	openshiftappsv1 "github.com/openshift/api/apps/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestReconciler_reconcileQuotaChange(t *testing.T) {
+	quota.RegisterProductComponents(integreatlyv1alpha1.Product3Scale, []string{quota.ApicastProductionName})
+	quota.RegisterProductComponents(integreatlyv1alpha1.ProductMarin3r, []string{quota.RateLimitName})
+
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := appsv1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := openshiftappsv1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: quota.ConfigMapName},
+		Data: map[string]string{
+			quota.ConfigMapData: `[
+				{"name": "small", "param": "1", "resources": {"apicast_production": {"replicas": 1}, "ratelimit": {"replicas": 1}}},
+				{"name": "large", "param": "2", "resources": {"apicast_production": {"replicas": 2}, "ratelimit": {"replicas": 3}}}
+			]`,
+		},
+	}
+
+	started := func(product integreatlyv1alpha1.ProductName, since time.Duration, applied ...integreatlyv1alpha1.ProductName) *integreatlyv1alpha1.QuotaChangeStatus {
+		startTime := metav1.NewTime(time.Now().Add(-since))
+		return &integreatlyv1alpha1.QuotaChangeStatus{
+			FromQuota:               "small",
+			ToQuota:                 "large",
+			Phase:                   integreatlyv1alpha1.QuotaChangeInProgress,
+			Products:                []integreatlyv1alpha1.ProductName{integreatlyv1alpha1.Product3Scale, integreatlyv1alpha1.ProductMarin3r},
+			AppliedProducts:         applied,
+			CurrentProduct:          product,
+			CurrentProductStartTime: &startTime,
+		}
+	}
+	notReady := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "apicast-production", Namespace: "redhat-rhoam-3scale"},
+		Spec:       appsv1.DeploymentSpec{Replicas: func() *int32 { replicas := int32(2); return &replicas }()},
+		Status:     appsv1.DeploymentStatus{UpdatedReplicas: 2, ReadyReplicas: 1},
+	}
+
+	tests := []struct {
+		name          string
+		quotaParam    string
+		quotaChange   *integreatlyv1alpha1.QuotaChangeStatus
+		toQuota       string
+		objects       []runtime.Object
+		wantPhase     integreatlyv1alpha1.QuotaChangePhase
+		wantCurrent   integreatlyv1alpha1.ProductName
+		wantApplied   int
+		wantToQuota   string
+		wantUpdated   bool
+		wantReplicas  map[integreatlyv1alpha1.ProductName]int32
+		wantNoChange  bool
+		wantEventType string
+	}{
+		{
+			name:          "test a change is published and applied to the first product",
+			quotaParam:    "2",
+			wantPhase:     integreatlyv1alpha1.QuotaChangeInProgress,
+			wantCurrent:   integreatlyv1alpha1.Product3Scale,
+			wantToQuota:   "large",
+			wantReplicas:  map[integreatlyv1alpha1.ProductName]int32{integreatlyv1alpha1.Product3Scale: 2, integreatlyv1alpha1.ProductMarin3r: 1},
+			wantEventType: "Normal",
+		},
+		{
+			name:         "test the change waits for the product to be ready",
+			quotaParam:   "2",
+			quotaChange:  started(integreatlyv1alpha1.Product3Scale, time.Minute),
+			objects:      []runtime.Object{notReady},
+			wantPhase:    integreatlyv1alpha1.QuotaChangeInProgress,
+			wantCurrent:  integreatlyv1alpha1.Product3Scale,
+			wantToQuota:  "large",
+			wantReplicas: map[integreatlyv1alpha1.ProductName]int32{integreatlyv1alpha1.Product3Scale: 2, integreatlyv1alpha1.ProductMarin3r: 1},
+		},
+		{
+			name:         "test the change is applied to the next product once the product is ready",
+			quotaParam:   "2",
+			quotaChange:  started(integreatlyv1alpha1.Product3Scale, time.Minute),
+			wantPhase:    integreatlyv1alpha1.QuotaChangeInProgress,
+			wantCurrent:  integreatlyv1alpha1.ProductMarin3r,
+			wantApplied:  1,
+			wantToQuota:  "large",
+			wantReplicas: map[integreatlyv1alpha1.ProductName]int32{integreatlyv1alpha1.Product3Scale: 2, integreatlyv1alpha1.ProductMarin3r: 3},
+		},
+		{
+			name:         "test the new quota is applied to every product once the last product is ready",
+			quotaParam:   "2",
+			quotaChange:  started(integreatlyv1alpha1.ProductMarin3r, time.Minute, integreatlyv1alpha1.Product3Scale),
+			wantPhase:    integreatlyv1alpha1.QuotaChangeInProgress,
+			wantApplied:  2,
+			wantToQuota:  "large",
+			wantUpdated:  true,
+			wantReplicas: map[integreatlyv1alpha1.ProductName]int32{integreatlyv1alpha1.Product3Scale: 2, integreatlyv1alpha1.ProductMarin3r: 3},
+		},
+		{
+			name:          "test the change is rolled back when the product is not ready before the timeout",
+			quotaParam:    "2",
+			quotaChange:   started(integreatlyv1alpha1.Product3Scale, time.Hour),
+			objects:       []runtime.Object{notReady},
+			wantPhase:     integreatlyv1alpha1.QuotaChangeRollingBack,
+			wantCurrent:   integreatlyv1alpha1.Product3Scale,
+			wantToQuota:   "small",
+			wantUpdated:   true,
+			wantReplicas:  map[integreatlyv1alpha1.ProductName]int32{integreatlyv1alpha1.Product3Scale: 1, integreatlyv1alpha1.ProductMarin3r: 1},
+			wantEventType: "Warning",
+		},
+		{
+			name:       "test the current quota is kept after the change was rolled back",
+			quotaParam: "2",
+			quotaChange: func() *integreatlyv1alpha1.QuotaChangeStatus {
+				change := started("", 0)
+				change.Phase = integreatlyv1alpha1.QuotaChangeRolledBack
+				return change
+			}(),
+			wantPhase:    integreatlyv1alpha1.QuotaChangeRolledBack,
+			wantReplicas: map[integreatlyv1alpha1.ProductName]int32{integreatlyv1alpha1.Product3Scale: 1, integreatlyv1alpha1.ProductMarin3r: 1},
+		},
+		{
+			name:         "test the change is cancelled when the quota param is set back to the current quota",
+			quotaParam:   "1",
+			quotaChange:  started(integreatlyv1alpha1.ProductMarin3r, time.Minute, integreatlyv1alpha1.Product3Scale),
+			toQuota:      "large",
+			wantNoChange: true,
+			wantToQuota:  "small",
+			wantUpdated:  true,
+			wantReplicas: map[integreatlyv1alpha1.ProductName]int32{integreatlyv1alpha1.Product3Scale: 1, integreatlyv1alpha1.ProductMarin3r: 1},
+		},
+		{
+			name:         "test nothing is changed without a quota change",
+			quotaParam:   "1",
+			wantNoChange: true,
+			wantReplicas: map[integreatlyv1alpha1.ProductName]int32{integreatlyv1alpha1.Product3Scale: 1, integreatlyv1alpha1.ProductMarin3r: 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			installation := &integreatlyv1alpha1.RHMI{
+				Status: integreatlyv1alpha1.RHMIStatus{
+					Quota:       "small",
+					ToQuota:     tt.toQuota,
+					QuotaChange: tt.quotaChange,
+				},
+			}
+			recorder := record.NewFakeRecorder(10)
+			r := &Reconciler{
+				ConfigManager: &config.ConfigReadWriterMock{
+					ReadProductFunc: func(product integreatlyv1alpha1.ProductName) (config.ConfigReadable, error) {
+						return config.NewThreeScale(config.ProductConfig{"NAMESPACE": "redhat-rhoam-" + string(product)}), nil
+					},
+				},
+				Reconciler: &resources.Reconciler{},
+				recorder:   recorder,
+				log:        l.NewLoggerWithContext(l.Fields{}),
+			}
+			installationQuota := &quota.Quota{}
+			if err := quota.GetQuota(tt.quotaParam, configMap, installationQuota); err != nil {
+				t.Fatal(err)
+			}
+
+			err := r.reconcileQuotaChange(context.TODO(), installation, configMap, installationQuota, fake.NewFakeClientWithScheme(scheme, tt.objects...))
+			if err != nil {
+				t.Fatalf("reconcileQuotaChange() error = %v", err)
+			}
+
+			change := installation.Status.QuotaChange
+			if tt.wantNoChange {
+				if change != nil {
+					t.Fatalf("expected no quota change but got %+v", change)
+				}
+			} else {
+				if change == nil {
+					t.Fatal("expected a quota change")
+				}
+				if change.Phase != tt.wantPhase {
+					t.Errorf("expected phase %s but got %s", tt.wantPhase, change.Phase)
+				}
+				if change.CurrentProduct != tt.wantCurrent {
+					t.Errorf("expected current product %s but got %s", tt.wantCurrent, change.CurrentProduct)
+				}
+				if len(change.AppliedProducts) != tt.wantApplied {
+					t.Errorf("expected %d applied products but got %v", tt.wantApplied, change.AppliedProducts)
+				}
+				if tt.quotaChange == nil && len(change.Changes) != 2 {
+					t.Errorf("expected the changes of both components but got %+v", change.Changes)
+				}
+			}
+			if installation.Status.ToQuota != tt.wantToQuota {
+				t.Errorf("expected toQuota %q but got %q", tt.wantToQuota, installation.Status.ToQuota)
+			}
+			if installationQuota.IsUpdated() != tt.wantUpdated {
+				t.Errorf("expected the quota updated to be %v", tt.wantUpdated)
+			}
+			components := map[integreatlyv1alpha1.ProductName]string{
+				integreatlyv1alpha1.Product3Scale:  quota.ApicastProductionName,
+				integreatlyv1alpha1.ProductMarin3r: quota.RateLimitName,
+			}
+			for product, want := range tt.wantReplicas {
+				if got := installationQuota.GetProduct(product).GetReplicas(components[product]); got != want {
+					t.Errorf("expected %d replicas for %s but got %d", want, product, got)
+				}
+			}
+
+			select {
+			case event := <-recorder.Events:
+				if tt.wantEventType == "" || event[:len(tt.wantEventType)] != tt.wantEventType {
+					t.Errorf("unexpected event %s", event)
+				}
+			default:
+				if tt.wantEventType != "" {
+					t.Errorf("expected a %s event", tt.wantEventType)
+				}
+			}
+		})
+	}
+}
+
+func TestCompleteQuotaChange(t *testing.T) {
+	tests := []struct {
+		name       string
+		change     *integreatlyv1alpha1.QuotaChangeStatus
+		quota      string
+		wantChange bool
+		wantPhase  integreatlyv1alpha1.QuotaChangePhase
+	}{
+		{
+			name:       "test a change applied to every product is removed",
+			change:     &integreatlyv1alpha1.QuotaChangeStatus{FromQuota: "small", ToQuota: "large", Phase: integreatlyv1alpha1.QuotaChangeInProgress},
+			quota:      "large",
+			wantChange: false,
+		},
+		{
+			name:       "test a change rolling back is rolled back",
+			change:     &integreatlyv1alpha1.QuotaChangeStatus{FromQuota: "small", ToQuota: "large", Phase: integreatlyv1alpha1.QuotaChangeRollingBack, CurrentProduct: integreatlyv1alpha1.Product3Scale},
+			quota:      "small",
+			wantChange: true,
+			wantPhase:  integreatlyv1alpha1.QuotaChangeRolledBack,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			installation := &integreatlyv1alpha1.RHMI{Status: integreatlyv1alpha1.RHMIStatus{Quota: tt.quota, QuotaChange: tt.change}}
+			completeQuotaChange(installation)
+
+			change := installation.Status.QuotaChange
+			if (change != nil) != tt.wantChange {
+				t.Fatalf("expected the change to be kept: %v, got %+v", tt.wantChange, change)
+			}
+			if change != nil && (change.Phase != tt.wantPhase || change.CurrentProduct != "") {
+				t.Errorf("expected phase %s without a current product but got %+v", tt.wantPhase, change)
+			}
+		})
+	}
+}
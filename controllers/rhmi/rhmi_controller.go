@@ -26,12 +26,16 @@ import (
 	"net/http"
 	"os"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
+	integreatlyclient "github.com/integr8ly/integreatly-operator/pkg/client"
 	"github.com/integr8ly/integreatly-operator/pkg/resources/k8s"
 	"github.com/integr8ly/integreatly-operator/pkg/resources/sts"
+	"github.com/integr8ly/integreatly-operator/pkg/tracing"
 	prometheusv1 "github.com/prometheus/client_golang/api/prometheus/v1"
 
 	"github.com/go-openapi/strfmt"
@@ -46,6 +50,7 @@ import (
 
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/trace"
 	corev1 "k8s.io/api/core/v1"
 	k8serr "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -56,6 +61,7 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
@@ -64,6 +70,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	usersv1 "github.com/openshift/api/user/v1"
@@ -91,6 +98,10 @@ const (
 	priorityClassNameEnvName         = "PRIORITY_CLASS_NAME"
 	managedServicePriorityClassName  = "rhoam-pod-priority"
 	routeRequestUrl                  = "/apis/route.openshift.io/v1"
+	productConcurrencyEnvName        = "PRODUCT_RECONCILE_CONCURRENCY"
+	productTimeoutEnvName            = "PRODUCT_RECONCILE_TIMEOUT"
+	defaultProductConcurrency        = 4
+	defaultProductTimeout            = 5 * time.Minute
 )
 
 var (
@@ -110,6 +121,10 @@ type RHMIReconciler struct {
 	controller      controller.Controller
 	restConfig      *rest.Config
 	customInformers map[string]map[string]*cache.Informer
+	serverClient    k8sclient.Client
+	httpClients     *integreatlyclient.HTTPClientFactory
+	// runningProducts are the products whose reconcile has not returned yet
+	runningProducts runningProducts
 
 	productsInstallationLoader marketplace.ProductsInstallationLoader
 }
@@ -124,6 +139,7 @@ func New(mgr ctrl.Manager) *RHMIReconciler {
 		mgr:             mgr,
 		restConfig:      restconfig,
 		customInformers: make(map[string]map[string]*cache.Informer),
+		httpClients:     integreatlyclient.NewHTTPClientFactory(),
 
 		productsInstallationLoader: marketplace.NewFSProductInstallationLoader(
 			marketplace.GetProductsInstallationPath(),
@@ -242,7 +258,13 @@ func New(mgr ctrl.Manager) *RHMIReconciler {
 // +kubebuilder:rbac:groups=operator.openshift.io,resources=cloudcredentials,verbs=get;list;watch
 
 func (r *RHMIReconciler) Reconcile(request ctrl.Request) (ctrl.Result, error) {
-	_ = context.Background()
+	ctx, span := tracing.StartSpan(context.Background(), "RHMIReconciler.Reconcile")
+	result, err := r.reconcile(ctx, request)
+	tracing.EndSpan(span, rhmiv1alpha1.PhaseNone, err)
+	return result, err
+}
+
+func (r *RHMIReconciler) reconcile(ctx context.Context, request ctrl.Request) (ctrl.Result, error) {
 	reconcileDelayedMetric := metrics.InstallationControllerReconcileDelayed
 	reconcileDelayedMetric.Set(0) // reset on every reconcile to prevent alert from firing continuously
 	timer := time.AfterFunc(time.Minute*12, func() {
@@ -259,6 +281,15 @@ func (r *RHMIReconciler) Reconcile(request ctrl.Request) (ctrl.Result, error) {
 		return ctrl.Result{}, err
 	}
 
+	trace.SpanFromContext(ctx).SetAttributes(tracing.InstallationTypeKey.String(installation.Spec.Type))
+
+	// the annotation is only set on the copy of the installation that is planned, see Plan. On the
+	// RHMI CR it would stop the products from reconciling anything outside of the cluster
+	if installation.IsDryRun() {
+		log.Warning("The dry-run annotation is removed from the RHMI CR, run the operator with --plan to plan the installation")
+		delete(installation.Annotations, rhmiv1alpha1.DryRunAnnotation)
+	}
+
 	originalInstallation := installation.DeepCopy()
 
 	retryRequeue := ctrl.Result{
@@ -266,10 +297,7 @@ func (r *RHMIReconciler) Reconcile(request ctrl.Request) (ctrl.Result, error) {
 		RequeueAfter: 10 * time.Second,
 	}
 
-	installationCfgMap := os.Getenv("INSTALLATION_CONFIG_MAP")
-	if installationCfgMap == "" {
-		installationCfgMap = installation.Spec.NamespacePrefix + DefaultInstallationConfigMapName
-	}
+	installationCfgMap := getInstallationConfigMapName(installation)
 
 	cssreAlertingEmailAddress := os.Getenv(alertingEmailAddressEnvName)
 	if installation.Spec.AlertingEmailAddresses.CSSRE == "" && cssreAlertingEmailAddress != "" {
@@ -311,6 +339,11 @@ func (r *RHMIReconciler) Reconcile(request ctrl.Request) (ctrl.Result, error) {
 	if err != nil {
 		return ctrl.Result{}, err
 	}
+	productGraph, err := installType.Dependencies.Status()
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	installation.Status.ProductGraph = productGraph
 	// gets the products from the install type to expose rhmi status metric
 	stages := make([]rhmiv1alpha1.RHMIStageStatus, 0)
 	for _, stage := range installType.GetInstallStages() {
@@ -350,12 +383,12 @@ func (r *RHMIReconciler) Reconcile(request ctrl.Request) (ctrl.Result, error) {
 	// either not checked, or rechecking preflight checks
 	if installation.Status.PreflightStatus == rhmiv1alpha1.PreflightInProgress ||
 		installation.Status.PreflightStatus == rhmiv1alpha1.PreflightFail {
-		return r.preflightChecks(installation, installType, configManager)
+		return r.preflightChecks(ctx, installation, installType, configManager)
 	}
 
 	// If the CR is being deleted, handle uninstall and return
 	if installation.DeletionTimestamp != nil {
-		return r.handleUninstall(installation, installType, request)
+		return r.handleUninstall(ctx, installation, installType, request)
 	}
 
 	clusterVersionCR, err := resources.GetClusterVersionCR(context.TODO(), r.Client)
@@ -420,6 +453,14 @@ func (r *RHMIReconciler) Reconcile(request ctrl.Request) (ctrl.Result, error) {
 	}
 	metrics.SetRhoamState(state)
 
+	serverClient, err := r.getServerClient()
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("could not create server client: %w", err)
+	}
+	if err := r.getHTTPClientFactory().Configure(context.TODO(), serverClient, installation); err != nil {
+		return ctrl.Result{}, fmt.Errorf("could not configure the product API clients: %w", err)
+	}
+
 	installationQuota := &quota.Quota{}
 	installStages := installType.GetInstallStages()
 	for i := range installStages {
@@ -428,11 +469,14 @@ func (r *RHMIReconciler) Reconcile(request ctrl.Request) (ctrl.Result, error) {
 		var stagePhase rhmiv1alpha1.StatusPhase
 		var stageLog = l.NewLoggerWithContext(l.Fields{l.StageLogContext: stage.Name})
 
+		stageCtx, stageSpan := tracing.StartSpan(ctx, "RHMIReconciler.reconcileStage",
+			tracing.InstallationTypeKey.String(installation.Spec.Type), tracing.StageKey.String(string(stage.Name)))
 		if stage.Name == rhmiv1alpha1.BootstrapStage {
-			stagePhase, err = r.bootstrapStage(installation, configManager, stageLog, installationQuota, request)
+			stagePhase, err = r.bootstrapStage(stageCtx, installation, configManager, serverClient, stageLog, installationQuota, request)
 		} else {
-			stagePhase, err = r.processStage(installation, &stage, configManager, installationQuota, stageLog)
+			stagePhase, err = r.processStage(stageCtx, installation, &stage, configManager, serverClient, installationQuota, stageLog)
 		}
+		tracing.EndSpan(stageSpan, stagePhase, err)
 
 		if installation.Status.Stages == nil {
 			installation.Status.Stages = make(map[rhmiv1alpha1.StageName]rhmiv1alpha1.RHMIStageStatus)
@@ -462,8 +506,11 @@ func (r *RHMIReconciler) Reconcile(request ctrl.Request) (ctrl.Result, error) {
 		installation.Status.Version = version.GetVersionByType(installation.Spec.Type)
 		installation.Status.ToVersion = ""
 		metrics.SetVersions(string(installation.Status.Stage), installation.Status.Version, installation.Status.ToVersion, string(externalClusterId), installation.CreationTimestamp.Unix())
-		installation.Status.Quota = installationQuota.GetName()
-		installation.Status.ToQuota = ""
+		// a quota change rolled out to one product at a time is completed once it is applied to every product
+		if installation.Status.QuotaChange == nil {
+			installation.Status.Quota = installationQuota.GetName()
+			installation.Status.ToQuota = ""
+		}
 
 		log.Info("installation completed successfully")
 	}
@@ -480,15 +527,22 @@ func (r *RHMIReconciler) Reconcile(request ctrl.Request) (ctrl.Result, error) {
 			installation.Status.Quota = installationQuota.GetName()
 			installation.Status.ToQuota = ""
 			metrics.SetQuota(installation.Status.Quota, installation.Status.ToQuota)
+			completeQuotaChange(installation)
+		}
+		// a quota change rolled out to one product at a time is checked more often
+		if change := installation.Status.QuotaChange; change != nil && change.Phase != rhmiv1alpha1.QuotaChangeRolledBack {
+			retryRequeue.RequeueAfter = quotaChangeRequeue
 		}
 	}
 	metrics.SetStatus(installation)
+	metrics.SetPausedProducts(installation)
 
 	if _, ok := installation.Status.Stages[rhmiv1alpha1.MonitoringStage]; ok {
 		log.Info("delete Monitoring stage from installation.Status")
 		delete(installation.Status.Stages, rhmiv1alpha1.MonitoringStage)
 	}
 
+	setInstallationConditions(installation)
 	err = r.updateStatusAndObject(originalInstallation, installation)
 	return retryRequeue, err
 }
@@ -581,8 +635,7 @@ func (r *RHMIReconciler) silenceAlert(namespace string, route string, rc *rest.C
 	req.Header.Add("Authorization", bearer)
 	req.Header.Add("Content-Type", "application/json")
 
-	client := &http.Client{}
-	client.Timeout = time.Second * 10
+	client := r.alertmanagerClient()
 
 	resp, err := client.Do(req)
 	if err != nil {
@@ -616,8 +669,7 @@ func (r *RHMIReconciler) silenceExists(namespace string, route string, rc *rest.
 
 	req.Header.Add("Authorization", bearer)
 
-	client := &http.Client{}
-	client.Timeout = time.Second * 10
+	client := r.alertmanagerClient()
 
 	resp, err := client.Do(req)
 	if err != nil {
@@ -690,6 +742,13 @@ func (r *RHMIReconciler) reconcilePodDistribution(installation *rhmiv1alpha1.RHM
 	}
 }
 
+// updateStatusWithConditions brings the installation conditions in line with the rest of the status
+// before updating it
+func (r *RHMIReconciler) updateStatusWithConditions(installation *rhmiv1alpha1.RHMI) error {
+	setInstallationConditions(installation)
+	return r.Status().Update(context.TODO(), installation)
+}
+
 func (r *RHMIReconciler) updateStatusAndObject(original, installation *rhmiv1alpha1.RHMI) error {
 	if !reflect.DeepEqual(original.Status, installation.Status) {
 		log.Info("updating status")
@@ -709,15 +768,12 @@ func (r *RHMIReconciler) updateStatusAndObject(original, installation *rhmiv1alp
 	return nil
 }
 
-func (r *RHMIReconciler) handleUninstall(installation *rhmiv1alpha1.RHMI, installationType *Type, request ctrl.Request) (ctrl.Result, error) {
+func (r *RHMIReconciler) handleUninstall(ctx context.Context, installation *rhmiv1alpha1.RHMI, installationType *Type, request ctrl.Request) (ctrl.Result, error) {
 	retryRequeue := ctrl.Result{
 		Requeue:      true,
 		RequeueAfter: 10 * time.Second,
 	}
-	installationCfgMap := os.Getenv("INSTALLATION_CONFIG_MAP")
-	if installationCfgMap == "" {
-		installationCfgMap = installation.Spec.NamespacePrefix + DefaultInstallationConfigMapName
-	}
+	installationCfgMap := getInstallationConfigMapName(installation)
 	configManager, err := config.NewManager(context.TODO(), r.Client, installation.Namespace, installationCfgMap, installation)
 	if err != nil {
 		return ctrl.Result{}, err
@@ -772,10 +828,22 @@ func (r *RHMIReconciler) handleUninstall(installation *rhmiv1alpha1.RHMI, instal
 		if stage.Name == rhmiv1alpha1.BootstrapStage {
 			pendingUninstalls = r.handleUninstallBootstrap(installation, finalizers, stage, configManager, merr, request)
 		} else {
-			for product := range stage.Products {
-				productPending := r.handleUninstallProduct(installation, product, stage, finalizers, configManager, merr)
-				if productPending && !pendingUninstalls {
+			uninstallOrder, err := stage.uninstallOrder()
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+			// a product is only removed once every product that depends on it has been removed
+			removed := map[rhmiv1alpha1.ProductName]bool{}
+			for _, product := range uninstallOrder {
+				if !stage.Dependencies.DependentsRemoved(product, removed) {
 					pendingUninstalls = true
+					continue
+				}
+				productPending := r.handleUninstallProduct(ctx, installation, product, stage, finalizers, configManager, merr)
+				if productPending {
+					pendingUninstalls = true
+				} else {
+					removed[product] = true
 				}
 			}
 		}
@@ -784,10 +852,10 @@ func (r *RHMIReconciler) handleUninstall(installation *rhmiv1alpha1.RHMI, instal
 		if pendingUninstalls {
 			if len(merr.Errors) > 0 {
 				installation.Status.LastError = merr.Error()
-				err = r.Client.Status().Update(context.TODO(), installation)
-				if err != nil {
-					merr.Add(err)
-				}
+			}
+			err = r.updateStatusWithConditions(installation)
+			if err != nil {
+				merr.Add(err)
 			}
 			err = r.Client.Update(context.TODO(), installation)
 			if err != nil {
@@ -844,7 +912,7 @@ func (r *RHMIReconciler) handleUninstall(installation *rhmiv1alpha1.RHMI, instal
 	return retryRequeue, err
 }
 
-func (r *RHMIReconciler) handleUninstallProduct(installation *rhmiv1alpha1.RHMI, product rhmiv1alpha1.ProductName, stage Stage, finalizers []string, configManager *config.Manager, merr *resources.MultiErr) bool {
+func (r *RHMIReconciler) handleUninstallProduct(ctx context.Context, installation *rhmiv1alpha1.RHMI, product rhmiv1alpha1.ProductName, stage Stage, finalizers []string, configManager *config.Manager, merr *resources.MultiErr) bool {
 	productName := string(product)
 	log.Infof("Uninstalling ", l.Fields{"product": productName, "stage": stage.Name})
 	productStatus := installation.GetProductStatusObject(product)
@@ -853,7 +921,7 @@ func (r *RHMIReconciler) handleUninstallProduct(installation *rhmiv1alpha1.RHMI,
 		if !strings.Contains(productFinalizer, productName) {
 			continue
 		}
-		reconciler, err := products.NewReconciler(product, r.restConfig, configManager, installation, r.mgr, log, r.productsInstallationLoader)
+		reconciler, err := products.NewReconciler(ctx, product, r.restConfig, configManager, installation, r.mgr, log, r.productsInstallationLoader, r.getHTTPClientFactory())
 		if err != nil {
 			merr.Add(fmt.Errorf("Failed to build reconciler for product %s: %w", productName, err))
 		}
@@ -867,7 +935,7 @@ func (r *RHMIReconciler) handleUninstallProduct(installation *rhmiv1alpha1.RHMI,
 		if productStatus.Uninstall || installation.DeletionTimestamp != nil {
 			uninstall = true
 		}
-		phase, err := reconciler.Reconcile(context.TODO(), installation, productStatus, serverClient, quota.QuotaProductConfig{}, uninstall)
+		phase, err := reconciler.Reconcile(ctx, installation, productStatus, serverClient, quota.QuotaProductConfig{}, uninstall)
 		if err != nil {
 			merr.Add(fmt.Errorf("Failed to reconcile product %s: %w", productName, err))
 		}
@@ -923,7 +991,7 @@ func upgradeFirstReconcile(installation *rhmiv1alpha1.RHMI) bool {
 	return status.Version != "" && status.ToVersion == "" && status.Version != version.GetVersionByType(installation.Spec.Type)
 }
 
-func (r *RHMIReconciler) preflightChecks(installation *rhmiv1alpha1.RHMI, installationType *Type, configManager *config.Manager) (ctrl.Result, error) {
+func (r *RHMIReconciler) preflightChecks(ctx context.Context, installation *rhmiv1alpha1.RHMI, installationType *Type, configManager *config.Manager) (ctrl.Result, error) {
 	log.Info("Running preflight checks..")
 	installation.Status.Stage = rhmiv1alpha1.StageName("Preflight Checks")
 	result := ctrl.Result{
@@ -952,7 +1020,7 @@ func (r *RHMIReconciler) preflightChecks(installation *rhmiv1alpha1.RHMI, instal
 	if strings.ToLower(installation.Spec.UseClusterStorage) != "true" && strings.ToLower(installation.Spec.UseClusterStorage) != "false" {
 		installation.Status.PreflightStatus = rhmiv1alpha1.PreflightFail
 		installation.Status.PreflightMessage = "Spec.useClusterStorage must be set to either 'true' or 'false' to continue"
-		_ = r.Status().Update(context.TODO(), installation)
+		_ = r.updateStatusWithConditions(installation)
 		log.Warning("preflight checks failed on useClusterStorage value")
 		return result, nil
 	}
@@ -975,7 +1043,7 @@ func (r *RHMIReconciler) preflightChecks(installation *rhmiv1alpha1.RHMI, instal
 
 			installation.Status.PreflightStatus = rhmiv1alpha1.PreflightFail
 			installation.Status.PreflightMessage = preflightMessage
-			_ = r.Status().Update(context.TODO(), installation)
+			_ = r.updateStatusWithConditions(installation)
 
 			return ctrl.Result{}, err
 		}
@@ -1032,7 +1100,7 @@ func (r *RHMIReconciler) preflightChecks(installation *rhmiv1alpha1.RHMI, instal
 
 			installation.Status.PreflightStatus = rhmiv1alpha1.PreflightFail
 			installation.Status.PreflightMessage = preflightMessage
-			_ = r.Status().Update(context.TODO(), installation)
+			_ = r.updateStatusWithConditions(installation)
 
 			return result, nil
 		}
@@ -1048,7 +1116,7 @@ func (r *RHMIReconciler) preflightChecks(installation *rhmiv1alpha1.RHMI, instal
 	}
 
 	for _, ns := range namespaces.Items {
-		products, err := r.checkNamespaceForProducts(ns, installation, installationType, configManager)
+		products, err := r.checkNamespaceForProducts(ctx, ns, installation, installationType, configManager)
 		if err != nil {
 			// error searching for existing products, keep trying
 			log.Info("error looking for existing deployments, will retry")
@@ -1059,7 +1127,7 @@ func (r *RHMIReconciler) preflightChecks(installation *rhmiv1alpha1.RHMI, instal
 			installation.Status.PreflightStatus = rhmiv1alpha1.PreflightFail
 			installation.Status.PreflightMessage = "found conflicting packages: " + strings.Join(products, ", ") + ", in namespace: " + ns.GetName()
 			log.Info("found conflicting packages: " + strings.Join(products, ", ") + ", in namespace: " + ns.GetName())
-			_ = r.Status().Update(context.TODO(), installation)
+			_ = r.updateStatusWithConditions(installation)
 			return result, err
 		}
 	}
@@ -1076,14 +1144,14 @@ func (r *RHMIReconciler) preflightChecks(installation *rhmiv1alpha1.RHMI, instal
 
 	installation.Status.PreflightStatus = rhmiv1alpha1.PreflightSuccess
 	installation.Status.PreflightMessage = "preflight checks passed"
-	err = r.Status().Update(context.TODO(), installation)
+	err = r.updateStatusWithConditions(installation)
 	if err != nil {
 		log.Infof("error updating status", l.Fields{"error": err.Error()})
 	}
 	return result, nil
 }
 
-func (r *RHMIReconciler) checkNamespaceForProducts(ns corev1.Namespace, installation *rhmiv1alpha1.RHMI, installationType *Type, configManager *config.Manager) ([]string, error) {
+func (r *RHMIReconciler) checkNamespaceForProducts(ctx context.Context, ns corev1.Namespace, installation *rhmiv1alpha1.RHMI, installationType *Type, configManager *config.Manager) ([]string, error) {
 	foundProducts := []string{}
 	if strings.HasPrefix(ns.Name, "openshift-") {
 		return foundProducts, nil
@@ -1097,7 +1165,7 @@ func (r *RHMIReconciler) checkNamespaceForProducts(ns corev1.Namespace, installa
 	})
 	for _, stage := range installationType.InstallStages {
 		for _, product := range stage.Products {
-			reconciler, err := products.NewReconciler(product.Name, r.restConfig, configManager, installation, r.mgr, log, r.productsInstallationLoader)
+			reconciler, err := products.NewReconciler(ctx, product.Name, r.restConfig, configManager, installation, r.mgr, log, r.productsInstallationLoader, r.getHTTPClientFactory())
 			if err != nil {
 				return foundProducts, err
 			}
@@ -1117,22 +1185,20 @@ func (r *RHMIReconciler) checkNamespaceForProducts(ns corev1.Namespace, installa
 	return foundProducts, nil
 }
 
-func (r *RHMIReconciler) bootstrapStage(installation *rhmiv1alpha1.RHMI, configManager config.ConfigReadWriter, log l.Logger, quota *quota.Quota, request ctrl.Request) (rhmiv1alpha1.StatusPhase, error) {
+func (r *RHMIReconciler) bootstrapStage(ctx context.Context, installation *rhmiv1alpha1.RHMI, configManager config.ConfigReadWriter, serverClient k8sclient.Client, log l.Logger, quota *quota.Quota, request ctrl.Request) (rhmiv1alpha1.StatusPhase, error) {
 	installation.Status.Stage = rhmiv1alpha1.BootstrapStage
 	mpm := marketplace.NewManager()
 
-	reconciler, err := NewBootstrapReconciler(configManager, installation, mpm, r.mgr.GetEventRecorderFor(string(rhmiv1alpha1.BootstrapStage)), log)
-	if err != nil {
-		return rhmiv1alpha1.PhaseFailed, fmt.Errorf("failed to build a reconciler for Bootstrap: %w", err)
+	var recorder record.EventRecorder = &record.FakeRecorder{}
+	if !installation.IsDryRun() {
+		recorder = r.mgr.GetEventRecorderFor(string(rhmiv1alpha1.BootstrapStage))
 	}
-	serverClient, err := k8sclient.New(r.restConfig, k8sclient.Options{
-		Scheme: r.mgr.GetScheme(),
-	})
+	reconciler, err := NewBootstrapReconciler(configManager, installation, mpm, recorder, log)
 	if err != nil {
-		return rhmiv1alpha1.PhaseFailed, fmt.Errorf("could not create server client: %w", err)
+		return rhmiv1alpha1.PhaseFailed, fmt.Errorf("failed to build a reconciler for Bootstrap: %w", err)
 	}
 
-	phase, err := reconciler.Reconcile(context.TODO(), installation, serverClient, quota, request)
+	phase, err := reconciler.Reconcile(ctx, installation, serverClient, quota, request)
 	if err != nil || phase == rhmiv1alpha1.PhaseFailed {
 		return rhmiv1alpha1.PhaseFailed, fmt.Errorf("Bootstrap stage reconcile failed: %w", err)
 	}
@@ -1140,77 +1206,119 @@ func (r *RHMIReconciler) bootstrapStage(installation *rhmiv1alpha1.RHMI, configM
 	return phase, nil
 }
 
-func (r *RHMIReconciler) processStage(installation *rhmiv1alpha1.RHMI, stage *Stage,
-	configManager config.ConfigReadWriter, quotaconfig *quota.Quota, _ l.Logger) (rhmiv1alpha1.StatusPhase, error) {
+func (r *RHMIReconciler) processStage(ctx context.Context, installation *rhmiv1alpha1.RHMI, stage *Stage,
+	configManager config.ConfigReadWriter, serverClient k8sclient.Client, quotaconfig *quota.Quota, stageLog l.Logger) (rhmiv1alpha1.StatusPhase, error) {
 	incompleteStage := false
 	productVersionMismatchFound = false
 
 	var mErr error
 	installation.Status.Stage = stage.Name
 
+	productNames := make([]rhmiv1alpha1.ProductName, 0, len(stage.Products))
 	for productName := range stage.Products {
-		productStatus := stage.Products[productName]
-		productLog := l.NewLoggerWithContext(l.Fields{l.ProductLogContext: productStatus.Name})
+		productNames = append(productNames, productName)
+	}
+	// sort the products so they are started and their errors are reported in the same order
+	sort.Slice(productNames, func(i, j int) bool {
+		return productNames[i] < productNames[j]
+	})
 
-		reconciler, err := products.NewReconciler(productStatus.Name, r.restConfig, configManager, installation, r.mgr, productLog, r.productsInstallationLoader)
+	concurrency, timeout := getProductReconcileConcurrency(), getProductReconcileTimeout()
+	stageLog.Infof("Reconciling products", l.Fields{"products": len(productNames), "concurrency": concurrency, "timeout": timeout})
 
-		if err != nil {
-			return rhmiv1alpha1.PhaseFailed, fmt.Errorf("failed to build a reconciler for %s: %w", productStatus.Name, err)
-		}
+	for _, productName := range productNames {
+		productStatus := stage.Products[productName]
+		restoreProductStatus(installation, stage.Name, &productStatus)
+		stage.Products[productName] = productStatus
+	}
 
-		if !reconciler.VerifyVersion(installation) {
-			productVersionMismatchFound = true
+	// paused products are left as they are, a paused product that had completed still allows the
+	// products that depend on it to be reconciled
+	completed := map[rhmiv1alpha1.ProductName]bool{}
+	pending := make([]rhmiv1alpha1.ProductName, 0, len(productNames))
+	for _, productName := range productNames {
+		if !installation.IsProductPaused(productName) {
+			pending = append(pending, productName)
+			continue
 		}
-
-		serverClient, err := k8sclient.New(r.restConfig, k8sclient.Options{
-			Scheme: r.mgr.GetScheme(),
-		})
-		if err != nil {
-			return rhmiv1alpha1.PhaseFailed, fmt.Errorf("could not create server client: %w", err)
+		stageLog.Warningf("Product is paused, skipping reconcile", l.Fields{"product": productName})
+		productStatus := pauseProduct(installation, stage.Name, stage.Products[productName], metav1.Now())
+		if productStatus.Phase == rhmiv1alpha1.PhaseCompleted {
+			completed[productName] = true
+		} else {
+			incompleteStage = true
 		}
+		stage.Products[productName] = productStatus
+	}
 
-		uninstall := false
-		if productStatus.Uninstall || installation.DeletionTimestamp != nil {
-			uninstall = true
+	// each product is reconciled with its own copy of the installation, the changes it makes to the
+	// copy are merged into the installation as soon as it is reconciled. A product starts as soon as
+	// its dependencies have completed, so the products that depend on it see its changes
+	mu := &sync.Mutex{}
+	// the http client factory is created lazily, create it before the products share it
+	r.getHTTPClientFactory()
+	productInstallations := make(map[rhmiv1alpha1.ProductName]*productInstallation, len(pending))
+	productErrs := map[rhmiv1alpha1.ProductName]error{}
+	startProduct := func(productName rhmiv1alpha1.ProductName) func(ctx context.Context) productReconcileResult {
+		productInstallation := newProductInstallation(installation, mu)
+		productInstallations[productName] = productInstallation
+		productStatus := stage.Products[productName]
+		return func(ctx context.Context) productReconcileResult {
+			return r.reconcileProduct(ctx, productInstallation.installation, productStatus, configManager,
+				productInstallation.client(productClient(serverClient, productName)), quotaconfig.GetProduct(productName))
 		}
-		productStatus.Phase, err = reconciler.Reconcile(context.TODO(), installation, &productStatus, serverClient, quotaconfig.GetProduct(productName), uninstall)
-
-		if err != nil {
-			if mErr == nil {
-				mErr = &resources.MultiErr{}
+	}
+	completeProduct := func(productName rhmiv1alpha1.ProductName, result productReconcileResult) (bool, error) {
+		if result.abandoned {
+			productInstallations[productName].abandon()
+		} else if err := productInstallations[productName].merge(); err != nil {
+			if result.err != nil {
+				err = fmt.Errorf("%w, %v", result.err, err)
 			}
-			mErr.(*resources.MultiErr).Add(fmt.Errorf("failed installation of %s: %w", productStatus.Name, err))
+			result.err = err
 		}
 
-		// Verify that watches for this productStatus CRDs have been created
-		productConfig, err := configManager.ReadProduct(productStatus.Name)
+		// the products that are still reconciling write to the installation while holding the lock
+		mu.Lock()
+		productStatus, err := r.completeProduct(installation, stage, productName, configManager, result)
+		mu.Unlock()
 		if err != nil {
-			return rhmiv1alpha1.PhaseFailed, fmt.Errorf("failed to read productStatus config for %s: %v", string(productStatus.Name), err)
+			return false, err
 		}
-
-		if productStatus.Phase == rhmiv1alpha1.PhaseCompleted {
-			for _, crd := range productConfig.GetWatchableCRDs() {
-				namespace := productConfig.GetNamespace()
-				gvk := crd.GetObjectKind().GroupVersionKind().String()
-				if r.customInformers[gvk] == nil {
-					r.customInformers[gvk] = make(map[string]*cache.Informer)
-				}
-				if r.customInformers[gvk][productConfig.GetNamespace()] == nil {
-					err = r.addCustomInformer(crd, namespace)
-					if err != nil {
-						return rhmiv1alpha1.PhaseFailed, fmt.Errorf("failed to create a %s CRD watch for %s: %v", gvk, string(productStatus.Name), err)
-					}
-				} else if !(*r.customInformers[gvk][productConfig.GetNamespace()]).HasSynced() {
-					return rhmiv1alpha1.PhaseFailed, fmt.Errorf("A %s CRD Informer for %s has not synced", gvk, string(productStatus.Name))
-				}
-			}
+		if result.err != nil {
+			productErrs[productName] = fmt.Errorf("failed installation of %s: %w", productStatus.Name, result.err)
 		}
 
 		//found an incomplete productStatus
 		if productStatus.Phase != rhmiv1alpha1.PhaseCompleted {
 			incompleteStage = true
+			return false, nil
 		}
+		return true, nil
+	}
+	pending, err := reconcileProductsConcurrently(ctx, pending, stage.Dependencies, completed, concurrency, timeout, &r.runningProducts, startProduct, completeProduct)
+	if err != nil {
+		return rhmiv1alpha1.PhaseFailed, err
+	}
+
+	// the errors are added in the order of the products so they don't depend on which product
+	// finished first
+	for _, productName := range productNames {
+		if err, ok := productErrs[productName]; ok {
+			if mErr == nil {
+				mErr = &resources.MultiErr{}
+			}
+			mErr.(*resources.MultiErr).Add(err)
+		}
+	}
+
+	//products whose dependencies have not completed are left as they are
+	for _, productName := range pending {
+		stageLog.Infof("Waiting for product dependencies", l.Fields{"product": productName, "dependencies": stage.Dependencies[productName]})
+		productStatus := stage.Products[productName]
+		setAwaitingDependenciesConditions(&productStatus, installation.Generation, stage.Dependencies[productName])
 		stage.Products[productName] = productStatus
+		incompleteStage = true
 	}
 
 	//some products in this stage have not installed successfully yet
@@ -1220,6 +1328,272 @@ func (r *RHMIReconciler) processStage(installation *rhmiv1alpha1.RHMI, stage *St
 	return rhmiv1alpha1.PhaseCompleted, mErr
 }
 
+// productReconcileResult holds the outcome of reconciling a single product in a stage, it is
+// written back to the stage on the goroutine that reconciles the stage
+type productReconcileResult struct {
+	status          rhmiv1alpha1.RHMIProductStatus
+	versionMismatch bool
+	// buildErr is set when the product reconciler could not be created, this fails the whole stage
+	buildErr error
+	// err is the error returned by the product reconciler
+	err error
+	// duration is how long the product reconciler took
+	duration time.Duration
+	// abandoned is set when the reconcile did not return before the timeout, or did not start
+	// because the previous reconcile of the product had not returned yet. The status is not set
+	abandoned bool
+}
+
+// completeProduct applies the result of the reconcile of a product to the stage and returns the
+// new status of the product, an error is returned when the whole stage has failed
+func (r *RHMIReconciler) completeProduct(installation *rhmiv1alpha1.RHMI, stage *Stage, productName rhmiv1alpha1.ProductName,
+	configManager config.ConfigReadWriter, result productReconcileResult) (rhmiv1alpha1.RHMIProductStatus, error) {
+	productStatus := result.status
+	if result.abandoned {
+		// the product is failed until its reconcile returns within the timeout
+		productStatus = stage.Products[productName]
+		productStatus.Phase = rhmiv1alpha1.PhaseFailed
+	}
+
+	if result.buildErr != nil {
+		return productStatus, result.buildErr
+	}
+
+	if result.versionMismatch {
+		productVersionMismatchFound = true
+	}
+
+	// Verify that watches for this productStatus CRDs have been created
+	productConfig, err := configManager.ReadProduct(productStatus.Name)
+	if err != nil {
+		return productStatus, fmt.Errorf("failed to read productStatus config for %s: %v", string(productStatus.Name), err)
+	}
+
+	if productStatus.Phase == rhmiv1alpha1.PhaseCompleted && !installation.IsDryRun() {
+		registration, _ := products.Lookup(productStatus.Name)
+		for _, crd := range registration.WatchableCRDs {
+			namespace := productConfig.GetNamespace()
+			gvk := crd.GetObjectKind().GroupVersionKind().String()
+			if r.customInformers[gvk] == nil {
+				r.customInformers[gvk] = make(map[string]*cache.Informer)
+			}
+			if r.customInformers[gvk][productConfig.GetNamespace()] == nil {
+				err = r.addCustomInformer(crd, namespace)
+				if err != nil {
+					return productStatus, fmt.Errorf("failed to create a %s CRD watch for %s: %v", gvk, string(productStatus.Name), err)
+				}
+			} else if !(*r.customInformers[gvk][productConfig.GetNamespace()]).HasSynced() {
+				return productStatus, fmt.Errorf("A %s CRD Informer for %s has not synced", gvk, string(productStatus.Name))
+			}
+		}
+	}
+
+	if !installation.IsDryRun() {
+		recordProductError(&productStatus, result.err, metav1.Now())
+		metrics.ObserveProductReconcile(string(productName), stage.Products[productName].Phase, productStatus.Phase, result.duration, result.err, time.Now())
+	}
+	resumeProduct(&productStatus, installation.Generation)
+	setProductConditions(&productStatus, installation.Generation, result.err)
+	stage.Products[productName] = productStatus
+
+	return productStatus, nil
+}
+
+// reconcileProduct builds the reconciler for a single product and runs it. It is called concurrently
+// for every product in a stage, so it must not write to the stage or the reconciler
+func (r *RHMIReconciler) reconcileProduct(ctx context.Context, installation *rhmiv1alpha1.RHMI, productStatus rhmiv1alpha1.RHMIProductStatus,
+	configManager config.ConfigReadWriter, serverClient k8sclient.Client, productConfig quota.ProductConfig) (result productReconcileResult) {
+	productLog := l.NewLoggerWithContext(l.Fields{l.ProductLogContext: productStatus.Name})
+
+	ctx, span := tracing.StartSpan(ctx, "product.Reconcile",
+		tracing.InstallationTypeKey.String(installation.Spec.Type), tracing.ProductKey.String(string(productStatus.Name)))
+	defer func() {
+		err := result.err
+		if result.buildErr != nil {
+			err = result.buildErr
+		}
+		tracing.EndSpan(span, result.status.Phase, err)
+	}()
+
+	reconciler, err := products.NewReconciler(ctx, productStatus.Name, r.restConfig, configManager, installation, r.mgr, productLog, r.productsInstallationLoader, r.getHTTPClientFactory())
+	if err != nil {
+		return productReconcileResult{
+			status:   productStatus,
+			buildErr: fmt.Errorf("failed to build a reconciler for %s: %w", productStatus.Name, err),
+		}
+	}
+
+	result = productReconcileResult{
+		versionMismatch: !reconciler.VerifyVersion(installation),
+	}
+
+	uninstall := false
+	if productStatus.Uninstall || installation.DeletionTimestamp != nil {
+		uninstall = true
+	}
+	start := time.Now()
+	productStatus.Phase, result.err = reconciler.Reconcile(ctx, installation, &productStatus, serverClient, productConfig, uninstall)
+	result.duration = time.Since(start)
+	result.status = productStatus
+
+	return result
+}
+
+// reconcileProductsConcurrently reconciles each product as soon as all of its dependencies have
+// completed, running at most concurrency reconciles at the same time. start is called when a
+// product is started and returns its reconcile, which is given a context that expires after
+// timeout, see reconcileProductWithTimeout. complete is called with the result of each product as
+// soon as it is reconciled and returns true if the product completed. start and complete are called
+// on the calling goroutine. Once complete returns an error no more products are started, the
+// products that were not started are returned
+func reconcileProductsConcurrently(ctx context.Context, productNames []rhmiv1alpha1.ProductName, dependencies ProductGraph, completed map[rhmiv1alpha1.ProductName]bool,
+	concurrency int, timeout time.Duration, running *runningProducts,
+	start func(productName rhmiv1alpha1.ProductName) func(ctx context.Context) productReconcileResult,
+	complete func(productName rhmiv1alpha1.ProductName, result productReconcileResult) (bool, error)) ([]rhmiv1alpha1.ProductName, error) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	type productResult struct {
+		productName rhmiv1alpha1.ProductName
+		result      productReconcileResult
+	}
+	results := make(chan productResult)
+	pending := append([]rhmiv1alpha1.ProductName{}, productNames...)
+	reconciling := 0
+	var completeErr error
+
+	for {
+		// start the products whose dependencies have completed while there is a free slot
+		var blocked []rhmiv1alpha1.ProductName
+		for _, productName := range pending {
+			if completeErr != nil || reconciling >= concurrency || !dependencies.Ready(productName, completed) {
+				blocked = append(blocked, productName)
+				continue
+			}
+			reconcile := start(productName)
+			reconciling++
+			go func(productName rhmiv1alpha1.ProductName) {
+				results <- productResult{productName: productName, result: reconcileProductWithTimeout(ctx, productName, timeout, running, reconcile)}
+			}(productName)
+		}
+		pending = blocked
+		if reconciling == 0 {
+			return pending, completeErr
+		}
+
+		productResult := <-results
+		reconciling--
+		isCompleted, err := complete(productResult.productName, productResult.result)
+		if err != nil && completeErr == nil {
+			completeErr = err
+		}
+		if isCompleted {
+			completed[productResult.productName] = true
+		}
+	}
+}
+
+// reconcileProductWithTimeout calls reconcile for the product and waits at most timeout for it to
+// return. A reconcile that doesn't return in time is abandoned: it keeps running in the background
+// but its result is discarded, and the product is not reconciled again until it returns
+func reconcileProductWithTimeout(ctx context.Context, productName rhmiv1alpha1.ProductName, timeout time.Duration, running *runningProducts,
+	reconcile func(ctx context.Context) productReconcileResult) productReconcileResult {
+	if !running.start(productName) {
+		return productReconcileResult{
+			abandoned: true,
+			err:       fmt.Errorf("the previous reconcile of %s timed out and has not returned yet", productName),
+		}
+	}
+
+	productCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	done := make(chan productReconcileResult, 1)
+	go func() {
+		result := reconcile(productCtx)
+		running.done(productName)
+		done <- result
+	}()
+
+	select {
+	case result := <-done:
+		if result.err != nil && errors.Is(productCtx.Err(), context.DeadlineExceeded) {
+			result.err = fmt.Errorf("reconcile timed out after %s: %w", timeout, result.err)
+		}
+		return result
+	case <-productCtx.Done():
+		return productReconcileResult{
+			abandoned: true,
+			err:       fmt.Errorf("reconcile timed out after %s: %w", timeout, productCtx.Err()),
+			duration:  timeout,
+		}
+	}
+}
+
+// runningProducts tracks the products whose reconcile has not returned yet, so a product whose
+// reconcile was abandoned is not reconciled a second time alongside it
+type runningProducts struct {
+	mu      sync.Mutex
+	running map[rhmiv1alpha1.ProductName]bool
+}
+
+// start marks the product as running, it returns false when the product is already running
+func (r *runningProducts) start(productName rhmiv1alpha1.ProductName) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.running[productName] {
+		return false
+	}
+	if r.running == nil {
+		r.running = map[rhmiv1alpha1.ProductName]bool{}
+	}
+	r.running[productName] = true
+	return true
+}
+
+// done marks the reconcile of the product as returned
+func (r *runningProducts) done(productName rhmiv1alpha1.ProductName) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.running, productName)
+}
+
+// getServerClient returns the client used by the stage reconcilers, it is created once and then
+// shared by every product and every reconcile
+func (r *RHMIReconciler) getServerClient() (k8sclient.Client, error) {
+	if r.serverClient != nil {
+		return r.serverClient, nil
+	}
+
+	serverClient, err := k8sclient.New(r.restConfig, k8sclient.Options{
+		Scheme: r.mgr.GetScheme(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	r.serverClient = serverClient
+
+	return r.serverClient, nil
+}
+
+// getHTTPClientFactory returns the factory of the clients for the product APIs, the factory is
+// shared by every reconcile so the clients share a connection pool
+func (r *RHMIReconciler) getHTTPClientFactory() *integreatlyclient.HTTPClientFactory {
+	if r.httpClients == nil {
+		r.httpClients = integreatlyclient.NewHTTPClientFactory()
+	}
+	return r.httpClients
+}
+
+// alertmanagerClient returns the client for the alertmanager API, it verifies the certificate of
+// alertmanager even when the installation uses self signed certificates for the products
+func (r *RHMIReconciler) alertmanagerClient() *http.Client {
+	return r.getHTTPClientFactory().VerifiedClient(time.Second * 10)
+}
+
 // handle the deletion of CRO config map
 func (r *RHMIReconciler) handleCROConfigDeletion(rhmi rhmiv1alpha1.RHMI) error {
 	// get cloud resource config map
@@ -1307,6 +1681,7 @@ func (r *RHMIReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		Watches(&source.Kind{Type: &corev1.Secret{}}, enqueueAllInstallations).
 		Watches(&source.Kind{Type: &usersv1.Group{}}, enqueueAllInstallations).
 		Watches(&source.Kind{Type: &corev1.ConfigMap{}}, enqueueAllInstallations, builder.WithPredicates(newObjectPredicate(isName(marin3rconfig.RateLimitConfigMapName)))).
+		Watches(&source.Kind{Type: &rhmiv1alpha1.RateLimitAlertPolicy{}}, enqueueAllInstallations, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
 		Build(r)
 
 	if err != nil {
@@ -1454,8 +1829,7 @@ func (r *RHMIReconciler) getCurrentAlerts(route string, namespace string) ([]pro
 	var bearer = "Bearer " + r.restConfig.BearerToken
 	req.Header.Add("Authorization", bearer)
 
-	client := &http.Client{}
-	client.Timeout = time.Second * 10
+	client := r.alertmanagerClient()
 
 	resp, err := client.Do(req)
 	if err != nil {
@@ -1548,6 +1922,14 @@ func reconcileQuotaConfig(ctx context.Context, serverClient k8sclient.Client, in
 	return err
 }
 
+func getInstallationConfigMapName(installation *rhmiv1alpha1.RHMI) string {
+	installationCfgMap := os.Getenv("INSTALLATION_CONFIG_MAP")
+	if installationCfgMap == "" {
+		installationCfgMap = installation.Spec.NamespacePrefix + DefaultInstallationConfigMapName
+	}
+	return installationCfgMap
+}
+
 func getRebalancePods() bool {
 	rebalance, exists := os.LookupEnv("REBALANCE_PODS")
 	if !exists || rebalance == "true" {
@@ -1556,6 +1938,36 @@ func getRebalancePods() bool {
 	return false
 }
 
+// getProductReconcileConcurrency returns the maximum number of products in a stage that are
+// reconciled at the same time
+func getProductReconcileConcurrency() int {
+	value, exists := os.LookupEnv(productConcurrencyEnvName)
+	if !exists {
+		return defaultProductConcurrency
+	}
+	concurrency, err := strconv.Atoi(value)
+	if err != nil || concurrency < 1 {
+		log.Warningf("Invalid product reconcile concurrency, using default", l.Fields{"value": value, "default": defaultProductConcurrency})
+		return defaultProductConcurrency
+	}
+	return concurrency
+}
+
+// getProductReconcileTimeout returns how long a single product reconcile may run before its
+// context is cancelled
+func getProductReconcileTimeout() time.Duration {
+	value, exists := os.LookupEnv(productTimeoutEnvName)
+	if !exists {
+		return defaultProductTimeout
+	}
+	timeout, err := time.ParseDuration(value)
+	if err != nil || timeout <= 0 {
+		log.Warningf("Invalid product reconcile timeout, using default", l.Fields{"value": value, "default": defaultProductTimeout})
+		return defaultProductTimeout
+	}
+	return timeout
+}
+
 func (r *RHMIReconciler) addCustomInformer(crd runtime.Object, namespace string) error {
 	gvk := crd.GetObjectKind().GroupVersionKind().String()
 	mapper, err := apiutil.NewDynamicRESTMapper(r.restConfig, apiutil.WithLazyDiscovery)
@@ -2,25 +2,38 @@ package controllers
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	rhmiv1alpha1 "github.com/integr8ly/integreatly-operator/apis/v1alpha1"
 	"github.com/integr8ly/integreatly-operator/pkg/config"
+	"github.com/integr8ly/integreatly-operator/pkg/products"
 	"github.com/integr8ly/integreatly-operator/pkg/resources"
+	l "github.com/integr8ly/integreatly-operator/pkg/resources/logger"
 	"github.com/integr8ly/integreatly-operator/pkg/resources/marketplace"
+	"github.com/integr8ly/integreatly-operator/pkg/resources/quota"
 	routev1 "github.com/openshift/api/route/v1"
 	prometheusv1 "github.com/prometheus/client_golang/api/prometheus/v1"
 	"github.com/prometheus/common/model"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
 	controllerruntime "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
 )
 
 const (
@@ -352,6 +365,504 @@ func TestUpgradeFirstReconcile(t *testing.T) {
 	}
 }
 
+// reconcileTestProducts reconciles the products with reconcileProductsConcurrently and returns the
+// result of each product, the products in the order they were reconciled and the products that were
+// not started. A product completes when its reconcile completes without an error
+func reconcileTestProducts(productNames []rhmiv1alpha1.ProductName, dependencies ProductGraph, concurrency int, timeout time.Duration, running *runningProducts,
+	reconcile func(ctx context.Context, productName rhmiv1alpha1.ProductName) productReconcileResult) (map[rhmiv1alpha1.ProductName]productReconcileResult, []rhmiv1alpha1.ProductName, []rhmiv1alpha1.ProductName) {
+	results := map[rhmiv1alpha1.ProductName]productReconcileResult{}
+	var order []rhmiv1alpha1.ProductName
+	pending, _ := reconcileProductsConcurrently(context.TODO(), productNames, dependencies, map[rhmiv1alpha1.ProductName]bool{}, concurrency, timeout, running,
+		func(productName rhmiv1alpha1.ProductName) func(ctx context.Context) productReconcileResult {
+			return func(ctx context.Context) productReconcileResult {
+				return reconcile(ctx, productName)
+			}
+		},
+		func(productName rhmiv1alpha1.ProductName, result productReconcileResult) (bool, error) {
+			results[productName] = result
+			order = append(order, productName)
+			return result.err == nil && result.status.Phase == rhmiv1alpha1.PhaseCompleted, nil
+		})
+	return results, order, pending
+}
+
+func TestReconcileProductsConcurrently(t *testing.T) {
+	productNames := []rhmiv1alpha1.ProductName{
+		rhmiv1alpha1.Product3Scale,
+		rhmiv1alpha1.ProductCloudResources,
+		rhmiv1alpha1.ProductGrafana,
+		rhmiv1alpha1.ProductMarin3r,
+		rhmiv1alpha1.ProductRHSSO,
+	}
+
+	tests := []struct {
+		name        string
+		concurrency int
+		timeout     time.Duration
+		reconcile   func(ctx context.Context, productName rhmiv1alpha1.ProductName) productReconcileResult
+		verify      func(t *testing.T, results map[rhmiv1alpha1.ProductName]productReconcileResult, maxRunning int32)
+	}{
+		{
+			name:        "test every product is reconciled",
+			concurrency: 5,
+			timeout:     time.Minute,
+			reconcile: func(ctx context.Context, productName rhmiv1alpha1.ProductName) productReconcileResult {
+				// finish the products in the reverse order to which they were started
+				time.Sleep(time.Duration(len(productName)) * time.Millisecond)
+				return productReconcileResult{status: rhmiv1alpha1.RHMIProductStatus{Name: productName, Phase: rhmiv1alpha1.PhaseCompleted}}
+			},
+			verify: func(t *testing.T, results map[rhmiv1alpha1.ProductName]productReconcileResult, _ int32) {
+				for _, productName := range productNames {
+					if results[productName].status.Name != productName {
+						t.Fatalf("expected the result of %s but got %v", productName, results[productName])
+					}
+				}
+			},
+		},
+		{
+			name:        "test concurrency limit is honoured",
+			concurrency: 2,
+			timeout:     time.Minute,
+			reconcile: func(ctx context.Context, productName rhmiv1alpha1.ProductName) productReconcileResult {
+				time.Sleep(10 * time.Millisecond)
+				return productReconcileResult{status: rhmiv1alpha1.RHMIProductStatus{Name: productName}}
+			},
+			verify: func(t *testing.T, _ map[rhmiv1alpha1.ProductName]productReconcileResult, maxRunning int32) {
+				if maxRunning > 2 {
+					t.Fatalf("expected at most 2 concurrent reconciles but got %d", maxRunning)
+				}
+			},
+		},
+		{
+			name:        "test product reconcile is cancelled after the timeout",
+			concurrency: 5,
+			timeout:     10 * time.Millisecond,
+			reconcile: func(ctx context.Context, productName rhmiv1alpha1.ProductName) productReconcileResult {
+				if productName != rhmiv1alpha1.Product3Scale {
+					return productReconcileResult{status: rhmiv1alpha1.RHMIProductStatus{Name: productName, Phase: rhmiv1alpha1.PhaseCompleted}}
+				}
+				<-ctx.Done()
+				return productReconcileResult{status: rhmiv1alpha1.RHMIProductStatus{Name: productName, Phase: rhmiv1alpha1.PhaseFailed}, err: ctx.Err()}
+			},
+			verify: func(t *testing.T, results map[rhmiv1alpha1.ProductName]productReconcileResult, _ int32) {
+				if err := results[rhmiv1alpha1.Product3Scale].err; err == nil || !strings.Contains(err.Error(), "timed out") {
+					t.Fatalf("expected a timeout error for 3scale but got %v", err)
+				}
+				for _, productName := range productNames[1:] {
+					if result := results[productName]; result.err != nil || result.status.Phase != rhmiv1alpha1.PhaseCompleted {
+						t.Fatalf("expected %s to complete but got phase %s, error %v", productName, result.status.Phase, result.err)
+					}
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var running, maxRunning int32
+			results, _, pending := reconcileTestProducts(productNames, nil, tt.concurrency, tt.timeout, &runningProducts{}, func(ctx context.Context, productName rhmiv1alpha1.ProductName) productReconcileResult {
+				current := atomic.AddInt32(&running, 1)
+				defer atomic.AddInt32(&running, -1)
+				for {
+					observed := atomic.LoadInt32(&maxRunning)
+					if current <= observed || atomic.CompareAndSwapInt32(&maxRunning, observed, current) {
+						break
+					}
+				}
+				return tt.reconcile(ctx, productName)
+			})
+			if len(results) != len(productNames) || len(pending) != 0 {
+				t.Fatalf("expected %d results but got %d, %v were not started", len(productNames), len(results), pending)
+			}
+			tt.verify(t, results, maxRunning)
+		})
+	}
+}
+
+func TestReconcileProductsConcurrently_Dependencies(t *testing.T) {
+	productNames := []rhmiv1alpha1.ProductName{
+		rhmiv1alpha1.Product3Scale,
+		rhmiv1alpha1.ProductCloudResources,
+		rhmiv1alpha1.ProductGrafana,
+		rhmiv1alpha1.ProductMarin3r,
+	}
+	dependencies := ProductGraph{
+		rhmiv1alpha1.Product3Scale:         {rhmiv1alpha1.ProductCloudResources},
+		rhmiv1alpha1.ProductCloudResources: {},
+		rhmiv1alpha1.ProductGrafana:        {},
+		rhmiv1alpha1.ProductMarin3r:        {rhmiv1alpha1.ProductGrafana},
+	}
+
+	// grafana only completes once 3scale has started, so 3scale must start as soon as cloud
+	// resources completes rather than once every product it was started with has completed
+	threeScaleStarted := make(chan struct{})
+	results, order, pending := reconcileTestProducts(productNames, dependencies, 3, time.Minute, &runningProducts{}, func(ctx context.Context, productName rhmiv1alpha1.ProductName) productReconcileResult {
+		switch productName {
+		case rhmiv1alpha1.Product3Scale:
+			close(threeScaleStarted)
+		case rhmiv1alpha1.ProductGrafana:
+			select {
+			case <-threeScaleStarted:
+			case <-time.After(10 * time.Second):
+				return productReconcileResult{status: rhmiv1alpha1.RHMIProductStatus{Name: productName, Phase: rhmiv1alpha1.PhaseFailed}, err: errors.New("3scale was not started")}
+			}
+		}
+		return productReconcileResult{status: rhmiv1alpha1.RHMIProductStatus{Name: productName, Phase: rhmiv1alpha1.PhaseCompleted}}
+	})
+	if len(pending) != 0 {
+		t.Fatalf("expected every product to be started but %v were not", pending)
+	}
+	for _, productName := range productNames {
+		if result := results[productName]; result.err != nil || result.status.Phase != rhmiv1alpha1.PhaseCompleted {
+			t.Fatalf("expected %s to complete but got phase %s, error %v", productName, result.status.Phase, result.err)
+		}
+	}
+	position := map[rhmiv1alpha1.ProductName]int{}
+	for i, productName := range order {
+		position[productName] = i
+	}
+	for product, productDependencies := range dependencies {
+		for _, dependency := range productDependencies {
+			if position[dependency] > position[product] {
+				t.Errorf("expected %s to be reconciled after %s but got %v", product, dependency, order)
+			}
+		}
+	}
+
+	// the products that depend on a product that did not complete are not started
+	results, _, pending = reconcileTestProducts(productNames, dependencies, 3, time.Minute, &runningProducts{}, func(ctx context.Context, productName rhmiv1alpha1.ProductName) productReconcileResult {
+		if productName == rhmiv1alpha1.ProductGrafana {
+			return productReconcileResult{status: rhmiv1alpha1.RHMIProductStatus{Name: productName, Phase: rhmiv1alpha1.PhaseInProgress}}
+		}
+		return productReconcileResult{status: rhmiv1alpha1.RHMIProductStatus{Name: productName, Phase: rhmiv1alpha1.PhaseCompleted}}
+	})
+	if !reflect.DeepEqual(pending, []rhmiv1alpha1.ProductName{rhmiv1alpha1.ProductMarin3r}) {
+		t.Errorf("expected only marin3r not to be started but got %v", pending)
+	}
+	if _, ok := results[rhmiv1alpha1.Product3Scale]; !ok {
+		t.Errorf("expected 3scale to be reconciled")
+	}
+}
+
+func TestReconcileProductsConcurrently_AbandonsReconcilesThatIgnoreTheTimeout(t *testing.T) {
+	productNames := []rhmiv1alpha1.ProductName{rhmiv1alpha1.Product3Scale, rhmiv1alpha1.ProductRHSSO}
+	running := &runningProducts{}
+	release := make(chan struct{})
+	reconcile := func(ctx context.Context, productName rhmiv1alpha1.ProductName) productReconcileResult {
+		if productName == rhmiv1alpha1.Product3Scale {
+			// the 3scale reconcile ignores its context until it is released
+			<-release
+		}
+		return productReconcileResult{status: rhmiv1alpha1.RHMIProductStatus{Name: productName, Phase: rhmiv1alpha1.PhaseCompleted}}
+	}
+
+	// with a single slot rhsso is only reconciled once the 3scale reconcile gives up its slot
+	results, _, _ := reconcileTestProducts(productNames, nil, 1, 10*time.Millisecond, running, reconcile)
+	if result := results[rhmiv1alpha1.Product3Scale]; !result.abandoned || !errors.Is(result.err, context.DeadlineExceeded) {
+		t.Fatalf("expected the 3scale reconcile to be abandoned after the timeout but got %+v", result)
+	}
+	if result := results[rhmiv1alpha1.ProductRHSSO]; result.abandoned || result.status.Phase != rhmiv1alpha1.PhaseCompleted {
+		t.Fatalf("expected rhsso to complete but got %+v", result)
+	}
+
+	results, _, _ = reconcileTestProducts(productNames, nil, 1, time.Minute, running, reconcile)
+	if result := results[rhmiv1alpha1.Product3Scale]; !result.abandoned || result.err == nil || !strings.Contains(result.err.Error(), "has not returned yet") {
+		t.Fatalf("expected 3scale not to be reconciled while its previous reconcile is running but got %+v", result)
+	}
+
+	close(release)
+	err := wait.PollImmediate(time.Millisecond, 10*time.Second, func() (bool, error) {
+		running.mu.Lock()
+		defer running.mu.Unlock()
+		return !running.running[rhmiv1alpha1.Product3Scale], nil
+	})
+	if err != nil {
+		t.Fatalf("the 3scale reconcile did not return: %v", err)
+	}
+	results, _, _ = reconcileTestProducts(productNames, nil, 1, time.Minute, running, reconcile)
+	for _, productName := range productNames {
+		if result := results[productName]; result.abandoned || result.status.Phase != rhmiv1alpha1.PhaseCompleted {
+			t.Fatalf("expected %s to complete once its previous reconcile returned but got %+v", productName, result)
+		}
+	}
+}
+
+// parallelProducts are registered for TestProcessStage, each of them waits for the other to start
+// reconciling so the stage only completes when they are reconciled at the same time
+var (
+	parallelProductA          rhmiv1alpha1.ProductName = "test-parallel-a"
+	parallelProductB          rhmiv1alpha1.ProductName = "test-parallel-b"
+	registerParallelProducts  sync.Once
+	parallelProductsStarted   *sync.WaitGroup
+	parallelProductsReconcile = map[rhmiv1alpha1.ProductName]func(installation *rhmiv1alpha1.RHMI){
+		parallelProductA: func(installation *rhmiv1alpha1.RHMI) {
+			installation.Status.GitHubOAuthEnabled = true
+		},
+		parallelProductB: func(installation *rhmiv1alpha1.RHMI) {
+			installation.Status.SMTPEnabled = true
+		},
+	}
+)
+
+func setupParallelProducts(started *sync.WaitGroup) {
+	registerParallelProducts.Do(func() {
+		for productName, setStatus := range parallelProductsReconcile {
+			productName, setStatus := productName, setStatus
+			products.Register(products.Registration{
+				Name: productName,
+				NewReconciler: func(opts products.ReconcilerOptions) (products.Interface, error) {
+					return &products.InterfaceMock{
+						VerifyVersionFunc: func(installation *rhmiv1alpha1.RHMI) bool { return true },
+						ReconcileFunc: func(ctx context.Context, installation *rhmiv1alpha1.RHMI, product *rhmiv1alpha1.RHMIProductStatus, serverClient client.Client, productConfig quota.ProductConfig, uninstall bool) (rhmiv1alpha1.StatusPhase, error) {
+							parallelProductsStarted.Done()
+							waitCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+							defer cancel()
+							if err := waitForGroup(waitCtx, parallelProductsStarted); err != nil {
+								return rhmiv1alpha1.PhaseFailed, fmt.Errorf("%s was not reconciled with the other products: %w", productName, err)
+							}
+							if err := resources.AddFinalizer(ctx, installation, serverClient, "finalizer."+string(productName), opts.Log); err != nil {
+								return rhmiv1alpha1.PhaseFailed, err
+							}
+							setStatus(installation)
+							return rhmiv1alpha1.PhaseCompleted, nil
+						},
+					}, nil
+				},
+			})
+		}
+	})
+	parallelProductsStarted = started
+}
+
+func waitForGroup(ctx context.Context, group *sync.WaitGroup) error {
+	done := make(chan struct{})
+	go func() {
+		group.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+type fakeManager struct {
+	manager.Manager
+}
+
+func (m *fakeManager) GetEventRecorderFor(name string) record.EventRecorder {
+	return &record.FakeRecorder{}
+}
+
+type fakeProductsInstallationLoader struct{}
+
+func (l *fakeProductsInstallationLoader) GetProductsInstallation() (*marketplace.ProductsInstallation, error) {
+	return &marketplace.ProductsInstallation{}, nil
+}
+
+func TestProcessStage(t *testing.T) {
+	started := &sync.WaitGroup{}
+	started.Add(2)
+	setupParallelProducts(started)
+	t.Setenv(productConcurrencyEnvName, "2")
+
+	scheme := runtime.NewScheme()
+	if err := rhmiv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	installation := &rhmiv1alpha1.RHMI{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "test-installation",
+			Namespace:  "test-namespace",
+			Finalizers: []string{"finalizer.existing"},
+		},
+	}
+	serverClient := fakeclient.NewFakeClientWithScheme(scheme, installation.DeepCopy())
+	if err := serverClient.Get(context.TODO(), client.ObjectKey{Name: installation.Name, Namespace: installation.Namespace}, installation); err != nil {
+		t.Fatal(err)
+	}
+
+	r := &RHMIReconciler{
+		mgr:                        &fakeManager{},
+		customInformers:            map[string]map[string]*cache.Informer{},
+		productsInstallationLoader: &fakeProductsInstallationLoader{},
+	}
+	stage := &Stage{
+		Name: rhmiv1alpha1.ProductsStage,
+		Products: map[rhmiv1alpha1.ProductName]rhmiv1alpha1.RHMIProductStatus{
+			parallelProductA: {Name: parallelProductA},
+			parallelProductB: {Name: parallelProductB},
+		},
+	}
+	configManager := &config.ConfigReadWriterMock{
+		ReadProductFunc: func(product rhmiv1alpha1.ProductName) (config.ConfigReadable, error) {
+			return config.NewThreeScale(config.ProductConfig{}), nil
+		},
+	}
+
+	phase, err := r.processStage(context.TODO(), installation, stage, configManager, serverClient, &quota.Quota{}, l.NewLogger())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if phase != rhmiv1alpha1.PhaseCompleted {
+		t.Fatalf("expected the stage to complete but got phase %s", phase)
+	}
+	for productName, productStatus := range stage.Products {
+		if productStatus.Phase != rhmiv1alpha1.PhaseCompleted {
+			t.Errorf("expected %s to complete but got phase %s", productName, productStatus.Phase)
+		}
+	}
+
+	expectedFinalizers := []string{"finalizer.existing", "finalizer." + string(parallelProductA), "finalizer." + string(parallelProductB)}
+	stored := &rhmiv1alpha1.RHMI{}
+	if err := serverClient.Get(context.TODO(), client.ObjectKey{Name: installation.Name, Namespace: installation.Namespace}, stored); err != nil {
+		t.Fatal(err)
+	}
+	for _, finalizer := range expectedFinalizers {
+		if !resources.Contains(installation.GetFinalizers(), finalizer) {
+			t.Errorf("expected finalizer %s on the installation but got %v", finalizer, installation.GetFinalizers())
+		}
+		if !resources.Contains(stored.GetFinalizers(), finalizer) {
+			t.Errorf("expected finalizer %s on the stored installation but got %v", finalizer, stored.GetFinalizers())
+		}
+	}
+	if !installation.Status.GitHubOAuthEnabled || !installation.Status.SMTPEnabled {
+		t.Errorf("expected the status set by both products but got GitHubOAuthEnabled %t, SMTPEnabled %t", installation.Status.GitHubOAuthEnabled, installation.Status.SMTPEnabled)
+	}
+	if installation.Status.Stage != rhmiv1alpha1.ProductsStage {
+		t.Errorf("expected stage %s but got %s", rhmiv1alpha1.ProductsStage, installation.Status.Stage)
+	}
+}
+
+// restartProduct is registered for TestProcessStage_RestoresProductStatusAfterRestart, it always
+// completes
+var (
+	restartProduct         rhmiv1alpha1.ProductName = "test-restart"
+	registerRestartProduct sync.Once
+)
+
+func TestProcessStage_RestoresProductStatusAfterRestart(t *testing.T) {
+	registerRestartProduct.Do(func() {
+		products.Register(products.Registration{
+			Name: restartProduct,
+			NewReconciler: func(opts products.ReconcilerOptions) (products.Interface, error) {
+				return &products.InterfaceMock{
+					VerifyVersionFunc: func(installation *rhmiv1alpha1.RHMI) bool { return true },
+					ReconcileFunc: func(ctx context.Context, installation *rhmiv1alpha1.RHMI, product *rhmiv1alpha1.RHMIProductStatus, serverClient client.Client, productConfig quota.ProductConfig, uninstall bool) (rhmiv1alpha1.StatusPhase, error) {
+						return rhmiv1alpha1.PhaseCompleted, nil
+					},
+				}, nil
+			},
+		})
+	})
+
+	scheme := runtime.NewScheme()
+	if err := rhmiv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	failedAt := metav1.NewTime(time.Now().Add(-time.Hour).Truncate(time.Second))
+	persistedErrors := []rhmiv1alpha1.ProductError{
+		{Message: "apicast not ready", Reason: productErrorReasonReconcile, Phase: rhmiv1alpha1.PhaseInProgress, FirstSeen: failedAt, LastSeen: failedAt, Count: 3},
+	}
+	installation := &rhmiv1alpha1.RHMI{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-installation", Namespace: "test-namespace"},
+		Status: rhmiv1alpha1.RHMIStatus{
+			Stages: map[rhmiv1alpha1.StageName]rhmiv1alpha1.RHMIStageStatus{
+				rhmiv1alpha1.ProductsStage: {
+					Name: rhmiv1alpha1.ProductsStage,
+					Products: map[rhmiv1alpha1.ProductName]rhmiv1alpha1.RHMIProductStatus{
+						restartProduct: {
+							Name:   restartProduct,
+							Phase:  rhmiv1alpha1.PhaseInProgress,
+							Errors: persistedErrors,
+							Conditions: []metav1.Condition{
+								{Type: rhmiv1alpha1.ConditionTypeDegraded, Status: metav1.ConditionFalse, Reason: rhmiv1alpha1.ConditionReasonReconcileSucceeded, LastTransitionTime: failedAt},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	serverClient := fakeclient.NewFakeClientWithScheme(scheme, installation.DeepCopy())
+
+	r := &RHMIReconciler{
+		mgr:                        &fakeManager{},
+		customInformers:            map[string]map[string]*cache.Informer{},
+		productsInstallationLoader: &fakeProductsInstallationLoader{},
+	}
+	// the stages only keep the product statuses in memory, after a restart they are empty
+	stage := &Stage{
+		Name:     rhmiv1alpha1.ProductsStage,
+		Products: map[rhmiv1alpha1.ProductName]rhmiv1alpha1.RHMIProductStatus{restartProduct: {Name: restartProduct}},
+	}
+	configManager := &config.ConfigReadWriterMock{
+		ReadProductFunc: func(product rhmiv1alpha1.ProductName) (config.ConfigReadable, error) {
+			return config.NewThreeScale(config.ProductConfig{}), nil
+		},
+	}
+
+	phase, err := r.processStage(context.TODO(), installation, stage, configManager, serverClient, &quota.Quota{}, l.NewLogger())
+	if err != nil || phase != rhmiv1alpha1.PhaseCompleted {
+		t.Fatalf("expected the stage to complete but got phase %s, error %v", phase, err)
+	}
+	// the installation controller writes the product statuses of the stage to the installation
+	installation.Status.Stages[stage.Name] = rhmiv1alpha1.RHMIStageStatus{Name: stage.Name, Phase: phase, Products: stage.Products}
+
+	productStatus := installation.Status.Stages[rhmiv1alpha1.ProductsStage].Products[restartProduct]
+	if !reflect.DeepEqual(productStatus.Errors, persistedErrors) {
+		t.Errorf("expected the error history to be kept after a successful reconcile but got %v", productStatus.Errors)
+	}
+	degraded := meta.FindStatusCondition(productStatus.Conditions, rhmiv1alpha1.ConditionTypeDegraded)
+	if degraded == nil || degraded.Status != metav1.ConditionFalse {
+		t.Fatalf("expected the product not to be degraded but got %v", productStatus.Conditions)
+	}
+	if !degraded.LastTransitionTime.Equal(&failedAt) {
+		t.Errorf("expected the degraded condition to keep its transition time %v but got %v", failedAt, degraded.LastTransitionTime)
+	}
+}
+
+func TestGetProductReconcileConcurrency(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  int
+	}{
+		{name: "test valid concurrency is used", value: "2", want: 2},
+		{name: "test invalid concurrency falls back to default", value: "none", want: defaultProductConcurrency},
+		{name: "test zero concurrency falls back to default", value: "0", want: defaultProductConcurrency},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(productConcurrencyEnvName, tt.value)
+			if got := getProductReconcileConcurrency(); got != tt.want {
+				t.Errorf("getProductReconcileConcurrency() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetProductReconcileTimeout(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  time.Duration
+	}{
+		{name: "test valid timeout is used", value: "90s", want: 90 * time.Second},
+		{name: "test invalid timeout falls back to default", value: "soon", want: defaultProductTimeout},
+		{name: "test negative timeout falls back to default", value: "-1m", want: defaultProductTimeout},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(productTimeoutEnvName, tt.value)
+			if got := getProductReconcileTimeout(); got != tt.want {
+				t.Errorf("getProductReconcileTimeout() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func getBuildScheme() (*runtime.Scheme, error) {
 	scheme := runtime.NewScheme()
 	if err := corev1.SchemeBuilder.AddToScheme(scheme); err != nil {
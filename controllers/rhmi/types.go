@@ -2,119 +2,82 @@ package controllers
 
 import (
 	"errors"
+	"fmt"
+	"sync"
 
 	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/apis/v1alpha1"
+	"github.com/integr8ly/integreatly-operator/pkg/products"
+	// registers the products installed by the installation types
+	_ "github.com/integr8ly/integreatly-operator/pkg/products/builtin"
 )
 
 type Stage struct {
 	Products map[integreatlyv1alpha1.ProductName]integreatlyv1alpha1.RHMIProductStatus
 	Name     integreatlyv1alpha1.StageName
+	// Dependencies orders the products within the stage, a product is only reconciled once all of
+	// its dependencies have completed. A nil graph reconciles every product in the stage together
+	Dependencies ProductGraph
 }
 
-var (
-	allMultitenantManagedApiStages = &Type{
-		[]Stage{
-			{
-				Name: integreatlyv1alpha1.BootstrapStage,
-			},
-			{
-				Name: integreatlyv1alpha1.InstallStage,
-				Products: map[integreatlyv1alpha1.ProductName]integreatlyv1alpha1.RHMIProductStatus{
-					integreatlyv1alpha1.ProductCloudResources: {Name: integreatlyv1alpha1.ProductCloudResources},
-					integreatlyv1alpha1.ProductObservability:  {Name: integreatlyv1alpha1.ProductObservability},
-					integreatlyv1alpha1.ProductRHSSO:          {Name: integreatlyv1alpha1.ProductRHSSO},
-					integreatlyv1alpha1.Product3Scale:         {Name: integreatlyv1alpha1.Product3Scale},
-					integreatlyv1alpha1.ProductMarin3r:        {Name: integreatlyv1alpha1.ProductMarin3r},
-					integreatlyv1alpha1.ProductGrafana:        {Name: integreatlyv1alpha1.ProductGrafana},
-				},
-			},
-		},
-		[]Stage{
-			{
-				Name: integreatlyv1alpha1.UninstallProductsStage,
-				Products: map[integreatlyv1alpha1.ProductName]integreatlyv1alpha1.RHMIProductStatus{
-					integreatlyv1alpha1.ProductRHSSO:   {Name: integreatlyv1alpha1.ProductRHSSO},
-					integreatlyv1alpha1.Product3Scale:  {Name: integreatlyv1alpha1.Product3Scale},
-					integreatlyv1alpha1.ProductMarin3r: {Name: integreatlyv1alpha1.ProductMarin3r},
-					integreatlyv1alpha1.ProductGrafana: {Name: integreatlyv1alpha1.ProductGrafana},
-				},
-			},
-			{
-				Name: integreatlyv1alpha1.UninstallCloudResourcesStage,
-				Products: map[integreatlyv1alpha1.ProductName]integreatlyv1alpha1.RHMIProductStatus{
-					integreatlyv1alpha1.ProductCloudResources: {Name: integreatlyv1alpha1.ProductCloudResources},
-				},
-			},
-			{
-				Name: integreatlyv1alpha1.UninstallObservabilityStage,
-				Products: map[integreatlyv1alpha1.ProductName]integreatlyv1alpha1.RHMIProductStatus{
-					integreatlyv1alpha1.ProductObservability: {Name: integreatlyv1alpha1.ProductObservability},
-				},
-			},
-			{
-				Name: integreatlyv1alpha1.UninstallBootstrap,
-			},
-		},
+// copy returns a stage whose product statuses can be changed without changing the statuses of
+// this stage
+func (s Stage) copy() Stage {
+	if s.Products == nil {
+		return s
 	}
-	allManagedApiStages = &Type{
-		[]Stage{
-			{
-				Name: integreatlyv1alpha1.BootstrapStage,
-			},
-			{
-				Name: integreatlyv1alpha1.InstallStage,
-				Products: map[integreatlyv1alpha1.ProductName]integreatlyv1alpha1.RHMIProductStatus{
-					integreatlyv1alpha1.ProductCloudResources: {Name: integreatlyv1alpha1.ProductCloudResources},
-					integreatlyv1alpha1.ProductObservability:  {Name: integreatlyv1alpha1.ProductObservability},
-					integreatlyv1alpha1.ProductRHSSO:          {Name: integreatlyv1alpha1.ProductRHSSO},
-					integreatlyv1alpha1.Product3Scale:         {Name: integreatlyv1alpha1.Product3Scale},
-					integreatlyv1alpha1.ProductRHSSOUser:      {Name: integreatlyv1alpha1.ProductRHSSOUser},
-					integreatlyv1alpha1.ProductMarin3r:        {Name: integreatlyv1alpha1.ProductMarin3r},
-					integreatlyv1alpha1.ProductGrafana:        {Name: integreatlyv1alpha1.ProductGrafana},
-				},
-			},
-		},
-		[]Stage{
-			{
-				Name: integreatlyv1alpha1.UninstallProductsStage,
-				Products: map[integreatlyv1alpha1.ProductName]integreatlyv1alpha1.RHMIProductStatus{
-					integreatlyv1alpha1.ProductRHSSO:     {Name: integreatlyv1alpha1.ProductRHSSO},
-					integreatlyv1alpha1.Product3Scale:    {Name: integreatlyv1alpha1.Product3Scale},
-					integreatlyv1alpha1.ProductRHSSOUser: {Name: integreatlyv1alpha1.ProductRHSSOUser},
-					integreatlyv1alpha1.ProductMarin3r:   {Name: integreatlyv1alpha1.ProductMarin3r},
-					integreatlyv1alpha1.ProductGrafana:   {Name: integreatlyv1alpha1.ProductGrafana},
-				},
-			},
-			{
-				Name: integreatlyv1alpha1.UninstallCloudResourcesStage,
-				Products: map[integreatlyv1alpha1.ProductName]integreatlyv1alpha1.RHMIProductStatus{
-					integreatlyv1alpha1.ProductCloudResources: {Name: integreatlyv1alpha1.ProductCloudResources},
-				},
-			},
-			{
-				Name: integreatlyv1alpha1.UninstallObservabilityStage,
-				Products: map[integreatlyv1alpha1.ProductName]integreatlyv1alpha1.RHMIProductStatus{
-					integreatlyv1alpha1.ProductObservability: {Name: integreatlyv1alpha1.ProductObservability},
-				},
-			},
-			{
-				Name: integreatlyv1alpha1.UninstallBootstrap,
-			},
-		},
+	products := make(map[integreatlyv1alpha1.ProductName]integreatlyv1alpha1.RHMIProductStatus, len(s.Products))
+	for name, status := range s.Products {
+		products[name] = *status.DeepCopy()
 	}
+	s.Products = products
+	return s
+}
+
+// uninstallOrder returns the products in the stage ordered so that each product comes after every
+// product that depends on it
+func (s Stage) uninstallOrder() ([]integreatlyv1alpha1.ProductName, error) {
+	if s.Dependencies == nil {
+		products := make([]integreatlyv1alpha1.ProductName, 0, len(s.Products))
+		for product := range s.Products {
+			products = append(products, product)
+		}
+		sortProducts(products)
+		return products, nil
+	}
+
+	layers, err := s.Dependencies.UninstallOrder()
+	if err != nil {
+		return nil, err
+	}
+	var products []integreatlyv1alpha1.ProductName
+	for _, layer := range layers {
+		for _, product := range layer {
+			if _, ok := s.Products[product]; ok {
+				products = append(products, product)
+			}
+		}
+	}
+	return products, nil
+}
+
+var (
+	installationTypes     = map[string]*Type{}
+	installationTypesLock sync.Mutex
 )
 
 type Type struct {
 	InstallStages   []Stage
 	UninstallStages []Stage
+	Dependencies    ProductGraph
 }
 
 func (t *Type) HasProduct(product string) bool {
-	return false
+	_, ok := t.Dependencies[integreatlyv1alpha1.ProductName(product)]
+	return ok
 }
 
-//GetInstallStages returns indexed arrays of products names this is worked through starting at 0
-//the install will not move to the next index until all installs in the current index have completed successfully
+// GetInstallStages returns indexed arrays of products names this is worked through starting at 0
+// the install will not move to the next index until all installs in the current index have completed successfully
 func (t *Type) GetInstallStages() []Stage {
 	return t.InstallStages
 }
@@ -123,22 +86,80 @@ func (t *Type) GetUninstallStages() []Stage {
 	return t.UninstallStages
 }
 
+// TypeFactory returns the stages for the installation type. The stages are only built once for each
+// type, the product statuses in them are carried over from one reconcile to the next
 func TypeFactory(installationType string) (*Type, error) {
+	installationTypesLock.Lock()
+	defer installationTypesLock.Unlock()
+
+	if t, ok := installationTypes[installationType]; ok {
+		return t, nil
+	}
+
+	var t *Type
+	var err error
 	//TODO: export this logic to a configmap for each installation type
 	switch installationType {
-	case string(integreatlyv1alpha1.InstallationTypeManagedApi):
-		return newManagedApiType(), nil
-	case string(integreatlyv1alpha1.InstallationTypeMultitenantManagedApi):
-		return newMultitenantManagedApiType(), nil
+	case string(integreatlyv1alpha1.InstallationTypeManagedApi), string(integreatlyv1alpha1.InstallationTypeMultitenantManagedApi):
+		t, err = newType(newProductGraph(integreatlyv1alpha1.InstallationType(installationType)))
 	default:
 		return nil, errors.New("unknown installation type: " + installationType)
 	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid product dependencies for installation type %s: %w", installationType, err)
+	}
+
+	installationTypes[installationType] = t
+	return t, nil
+}
+
+// newProductGraph declares the products installed by the installation type and the products each
+// of them depends on, from the products that have been registered
+func newProductGraph(installationType integreatlyv1alpha1.InstallationType) ProductGraph {
+	graph := ProductGraph{}
+	for _, registration := range products.ForInstallationType(installationType) {
+		graph[registration.Name] = registration.Dependencies
+	}
+	return graph
 }
 
-func newManagedApiType() *Type {
-	return allManagedApiStages
+// newType builds the install and uninstall stages for the products in the graph. Every product is
+// placed in a single stage and ordered within it by its dependencies, so a product starts as soon
+// as the products it depends on have completed
+func newType(products ProductGraph) (*Type, error) {
+	if err := products.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &Type{
+		InstallStages: []Stage{
+			{
+				Name: integreatlyv1alpha1.BootstrapStage,
+			},
+			{
+				Name:         integreatlyv1alpha1.InstallStage,
+				Products:     newStageProducts(products),
+				Dependencies: products,
+			},
+		},
+		UninstallStages: []Stage{
+			{
+				Name:         integreatlyv1alpha1.UninstallProductsStage,
+				Products:     newStageProducts(products),
+				Dependencies: products,
+			},
+			{
+				Name: integreatlyv1alpha1.UninstallBootstrap,
+			},
+		},
+		Dependencies: products,
+	}, nil
 }
 
-func newMultitenantManagedApiType() *Type {
-	return allMultitenantManagedApiStages
+func newStageProducts(products ProductGraph) map[integreatlyv1alpha1.ProductName]integreatlyv1alpha1.RHMIProductStatus {
+	stageProducts := make(map[integreatlyv1alpha1.ProductName]integreatlyv1alpha1.RHMIProductStatus, len(products))
+	for product := range products {
+		stageProducts[product] = integreatlyv1alpha1.RHMIProductStatus{Name: product}
+	}
+	return stageProducts
 }
@@ -2,11 +2,23 @@ package controllers
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/mail"
+	"os"
+
 	"github.com/integr8ly/integreatly-operator/apis/v1alpha1"
+	integreatlyclient "github.com/integr8ly/integreatly-operator/pkg/client"
+	"github.com/integr8ly/integreatly-operator/pkg/config"
+	"github.com/integr8ly/integreatly-operator/pkg/metrics"
+	"github.com/integr8ly/integreatly-operator/pkg/products/threescale"
+	"github.com/integr8ly/integreatly-operator/pkg/resources/k8s"
 	l "github.com/integr8ly/integreatly-operator/pkg/resources/logger"
+	"github.com/integr8ly/integreatly-operator/pkg/resources/rhmi"
+	userHelper "github.com/integr8ly/integreatly-operator/pkg/resources/user"
 	routev1 "github.com/openshift/api/route/v1"
 	usersv1 "github.com/openshift/api/user/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	k8serr "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
@@ -24,7 +36,22 @@ import (
 
 var log = l.NewLoggerWithContext(l.Fields{l.ControllerLogContext: "tenant_controller"})
 
-// +kubebuilder:rbac:groups=integreatly.org,resources=apimanagementtenant,verbs=get;list;watch
+// errTenantUserNotFound is returned when the OpenShift user of the namespace of a tenant no longer exists
+var errTenantUserNotFound = errors.New("the user extracted by the APIManagementTenant's namespace does not exist")
+
+const (
+	// tenantFinalizer removes the 3scale account of the tenant when its APIManagementTenant is deleted
+	tenantFinalizer = "integreatly.org/3scale-tenant-account"
+
+	defaultInstallationConfigMapName = "installation-config"
+
+	// tenantRequeueInterval is how often a tenant whose 3scale account is not ready is reconciled
+	tenantRequeueInterval = time.Second * 10
+	// tenantResyncInterval is how often the 3scale account of a ready tenant is checked
+	tenantResyncInterval = time.Minute * 10
+)
+
+// +kubebuilder:rbac:groups=integreatly.org,resources=apimanagementtenant,verbs=get;list;watch;update
 // +kubebuilder:rbac:groups=integreatly.org,resources=apimanagementtenant/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=user.openshift.io,resources=users,verbs=watch;get;list;update
 
@@ -39,23 +66,33 @@ func New(mgr manager.Manager) (*TenantReconciler, error) {
 		return nil, err
 	}
 
+	watchNamespace, err := k8s.GetWatchNamespace()
+	if err != nil {
+		return nil, err
+	}
+
 	return &TenantReconciler{
-		Client: client,
-		Scheme: mgr.GetScheme(),
-		mgr:    mgr,
-		log:    l.Logger{},
+		Client:         client,
+		Scheme:         mgr.GetScheme(),
+		mgr:            mgr,
+		log:            l.Logger{},
+		watchNamespace: watchNamespace,
+		httpClients:    integreatlyclient.NewHTTPClientFactory(),
 	}, nil
 }
 
 type TenantReconciler struct {
 	k8sclient.Client
-	Scheme *runtime.Scheme
-	mgr    manager.Manager
-	log    l.Logger
+	Scheme         *runtime.Scheme
+	mgr            manager.Manager
+	log            l.Logger
+	watchNamespace string
+	httpClients    *integreatlyclient.HTTPClientFactory
+	archives       tenantArchives
 }
 
 func (r *TenantReconciler) Reconcile(request ctrl.Request) (ctrl.Result, error) {
-	_ = context.Background()
+	ctx := context.Background()
 
 	log.Info(fmt.Sprintf("TenantReconciler request: %s", request))
 
@@ -68,6 +105,10 @@ func (r *TenantReconciler) Reconcile(request ctrl.Request) (ctrl.Result, error)
 		return ctrl.Result{}, err
 	}
 
+	if !tenant.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, r.finalizeTenant(ctx, tenant)
+	}
+
 	isTenantVerified, rejectionReason, err := r.verifyAPIManagementTenant(tenant)
 	if err != nil {
 		log.Error("error verifying the APIManagementTenant CR", err)
@@ -89,7 +130,33 @@ func (r *TenantReconciler) Reconcile(request ctrl.Request) (ctrl.Result, error)
 		return ctrl.Result{}, nil
 	}
 
+	if !controllerutil.ContainsFinalizer(tenant, tenantFinalizer) {
+		controllerutil.AddFinalizer(tenant, tenantFinalizer)
+		if err := r.Client.Update(ctx, tenant); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to add finalizer to tenant %s: %v", tenant.Name, err)
+		}
+	}
+
+	// Tenants with an owner have no user to annotate, so the requested status reserves their 3scale
+	// tenant name until the account is created
+	if tenant.Status.ProvisioningStatus == "" && tenant.Spec.Owner != nil {
+		if err := r.updateProvisioningStatus(tenant, v1alpha1.ThreeScaleAccountRequested); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
 	err = r.addAnnotationToUser(tenant)
+	if errors.Is(err, errTenantUserNotFound) {
+		return ctrl.Result{}, r.rejectTenantWithoutUser(ctx, tenant, nil)
+	}
+	if err != nil {
+		if err1 := r.updateLastError(tenant, err.Error()); err1 != nil {
+			return ctrl.Result{}, err1
+		}
+		return ctrl.Result{}, err
+	}
+
+	accounts, err := r.getTenantAccountReconciler(ctx)
 	if err != nil {
 		if err1 := r.updateLastError(tenant, err.Error()); err1 != nil {
 			return ctrl.Result{}, err1
@@ -97,9 +164,38 @@ func (r *TenantReconciler) Reconcile(request ctrl.Request) (ctrl.Result, error)
 		return ctrl.Result{}, err
 	}
 
+	account, err := r.reconcileTenantAccount(ctx, tenant, accounts)
+	if errors.Is(err, errTenantUserNotFound) {
+		return ctrl.Result{}, r.rejectTenantWithoutUser(ctx, tenant, accounts)
+	}
+	if err != nil {
+		if err1 := r.updateLastError(tenant, err.Error()); err1 != nil {
+			return ctrl.Result{}, err1
+		}
+		return ctrl.Result{}, err
+	}
+	if account.Suspended {
+		if err := r.updateProvisioningStatus(tenant, v1alpha1.ThreeScaleAccountSuspended); err != nil {
+			return ctrl.Result{}, err
+		}
+		if err := r.updateLastError(tenant, ""); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: tenantResyncInterval}, nil
+	}
+	if !account.Ready {
+		if err := r.updateProvisioningStatus(tenant, v1alpha1.ThreeScaleAccountRequested); err != nil {
+			return ctrl.Result{}, err
+		}
+		if err := r.updateLastError(tenant, account.Message); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: tenantRequeueInterval}, nil
+	}
+
 	wasTenantUrlReconciled, err := r.reconcileTenantUrl(tenant)
 	if err == nil && !wasTenantUrlReconciled {
-		return ctrl.Result{Requeue: true}, nil
+		return ctrl.Result{RequeueAfter: tenantRequeueInterval}, nil
 	}
 	if err != nil {
 		if err1 := r.updateLastError(tenant, err.Error()); err1 != nil {
@@ -108,15 +204,35 @@ func (r *TenantReconciler) Reconcile(request ctrl.Request) (ctrl.Result, error)
 		return ctrl.Result{}, err
 	}
 
+	if err := r.reconcileTenantArchives(ctx, tenant, accounts, account); err != nil {
+		if err1 := r.updateLastError(tenant, err.Error()); err1 != nil {
+			return ctrl.Result{}, err1
+		}
+		return ctrl.Result{}, err
+	}
+
+	if err := r.reconcileTenantQuota(ctx, tenant, accounts, account); err != nil {
+		if err1 := r.updateLastError(tenant, err.Error()); err1 != nil {
+			return ctrl.Result{}, err1
+		}
+		return ctrl.Result{}, err
+	}
+
 	// Clear out LastError since reconcile finished successfully.
 	if err1 := r.updateLastError(tenant, ""); err1 != nil {
 		return ctrl.Result{}, err1
 	}
 
-	return ctrl.Result{}, nil
+	return ctrl.Result{RequeueAfter: tenantResyncInterval}, nil
 }
 
 func (r *TenantReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if err := mgr.Add(manager.RunnableFunc(r.reportTenantRateLimitUsage)); err != nil {
+		return err
+	}
+	if err := mgr.Add(manager.RunnableFunc(r.archives.run)); err != nil {
+		return err
+	}
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&v1alpha1.APIManagementTenant{}).
 		Watches(&source.Kind{Type: &v1alpha1.APIManagementTenant{}}, &handler.EnqueueRequestForObject{}).
@@ -143,7 +259,11 @@ func (r *TenantReconciler) getAPIManagementTenant(crName string, crNamespace str
 
 func (r *TenantReconciler) verifyAPIManagementTenant(tenant *v1alpha1.APIManagementTenant) (bool, string, error) {
 	// Skip verification if the tenant is already reconciled
-	if tenant.Status.ProvisioningStatus != v1alpha1.ThreeScaleAccountReady {
+	if !isTenantProvisioned(tenant) {
+		// Tenants with an owner are verified without an OpenShift user
+		if tenant.Spec.Owner != nil {
+			return r.verifyTenantOwner(tenant)
+		}
 		// Fails if APIManagementTenant isn't from a namespace ending in -dev or -stage
 		if !strings.HasSuffix(tenant.Namespace, "-dev") && !strings.HasSuffix(tenant.Namespace, "-stage") {
 			return false, "tenant not created in a namespace ending in {USERNAME}-dev or {USERNAME}-stage", nil
@@ -170,21 +290,95 @@ func (r *TenantReconciler) verifyAPIManagementTenant(tenant *v1alpha1.APIManagem
 			if err != nil {
 				return false, "an error occurred while trying to check if another reconciled APIManagementTenant CR already exists", err
 			}
-			for _, t := range tenants.Items {
-				if t.Status.ProvisioningStatus == v1alpha1.ThreeScaleAccountReady {
+			for i := range tenants.Items {
+				if isTenantProvisioned(&tenants.Items[i]) {
 					return false, "a reconciled APIManagementTenant CR already exists", nil
 				}
 			}
 		}
+		// Check if a tenant with an owner already has the 3scale account of the user
+		isTenantNameTaken, err := r.isTenantNameTaken(tenant)
+		if err != nil {
+			return false, "an error occurred while trying to check if another reconciled APIManagementTenant CR already exists", err
+		}
+		if isTenantNameTaken {
+			return false, "a reconciled APIManagementTenant CR already exists", nil
+		}
 	}
 
 	return true, "", nil
 }
 
+// verifyTenantOwner checks the owner in the spec of tenant, which replaces the OpenShift user of the
+// namespace of the tenant. Only the admins of the operator namespace can create tenants with an
+// owner, and the organization of the owner can only have one reconciled tenant
+func (r *TenantReconciler) verifyTenantOwner(tenant *v1alpha1.APIManagementTenant) (bool, string, error) {
+	owner := tenant.Spec.Owner
+	if tenant.Namespace != r.watchNamespace {
+		return false, fmt.Sprintf("tenants with an owner can only be created in the %s namespace", r.watchNamespace), nil
+	}
+	if _, err := mail.ParseAddress(owner.Email); err != nil {
+		return false, fmt.Sprintf("the email %q of the tenant owner is not valid", owner.Email), nil
+	}
+	if userHelper.GetTenantName(tenant) == "" || owner.IdentityProvider == "" {
+		return false, "the tenant owner has no organization name or identity provider", nil
+	}
+
+	isTenantNameTaken, err := r.isTenantNameTaken(tenant)
+	if err != nil {
+		return false, "an error occurred while trying to check if another reconciled APIManagementTenant CR already exists", err
+	}
+	if isTenantNameTaken {
+		return false, fmt.Sprintf("a reconciled APIManagementTenant CR already exists for the organization %s", owner.OrganizationName), nil
+	}
+	return true, "", nil
+}
+
+// isTenantNameTaken returns true when another tenant in any namespace holds the same 3scale tenant
+// name as tenant. The name is reserved by the first tenant that passes the verification, so two
+// tenants that are verified before either has a 3scale account can't both take it
+func (r *TenantReconciler) isTenantNameTaken(tenant *v1alpha1.APIManagementTenant) (bool, error) {
+	tenants := &v1alpha1.APIManagementTenantList{}
+	if err := r.Client.List(context.TODO(), tenants); err != nil {
+		return false, err
+	}
+	tenantName := userHelper.GetTenantName(tenant)
+	for i := range tenants.Items {
+		other := &tenants.Items[i]
+		if other.UID == tenant.UID || !other.DeletionTimestamp.IsZero() {
+			continue
+		}
+		if userHelper.GetTenantName(other) == tenantName && holdsTenantName(other, tenant) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// holdsTenantName returns true when other, which has the same 3scale tenant name as tenant, keeps
+// the name from tenant. Rejected tenants hold no name, verified tenants hold it from the tenants
+// not verified yet, and otherwise the oldest tenant holds it
+func holdsTenantName(other, tenant *v1alpha1.APIManagementTenant) bool {
+	if other.Status.ProvisioningStatus == v1alpha1.WontProvisionTenant {
+		return false
+	}
+	if isOtherVerified, isVerified := isTenantVerified(other), isTenantVerified(tenant); isOtherVerified != isVerified {
+		return isOtherVerified
+	}
+	if !other.CreationTimestamp.Equal(&tenant.CreationTimestamp) {
+		return other.CreationTimestamp.Before(&tenant.CreationTimestamp)
+	}
+	return other.UID < tenant.UID
+}
+
 func (r *TenantReconciler) addAnnotationToUser(tenant *v1alpha1.APIManagementTenant) error {
 	// Only add the annotation to the User if its APIManagementTenant's ProvisioningStatus hasn't been set to a value yet.
-	if tenant.Status.ProvisioningStatus == "" {
+	// Tenants with an owner are provisioned without an OpenShift user.
+	if tenant.Status.ProvisioningStatus == "" && tenant.Spec.Owner == nil {
 		user, err := r.getUserByTenantNamespace(tenant.Namespace)
+		if k8serr.IsNotFound(err) {
+			return errTenantUserNotFound
+		}
 		if err != nil {
 			return fmt.Errorf("error getting user for tenant %s: %v", tenant.Name, err)
 		}
@@ -231,11 +425,13 @@ func (r *TenantReconciler) reconcileTenantUrl(tenant *v1alpha1.APIManagementTena
 			return tenantUrlReconciled, fmt.Errorf("failed to find any system-developer routes in namespace %s", opts.Namespace)
 		}
 
+		// the admin portal of the 3scale account is served at <tenant name>-admin.<domain>, any
+		// other route only shares a part of its name
 		var foundRoute *routev1.Route
-		user, err := r.getUserByTenantNamespace(tenant.Namespace)
+		adminHostPrefix := userHelper.GetTenantName(tenant) + "-admin."
 		for i := range routes.Items {
 			rt := routes.Items[i]
-			if strings.Contains(rt.Spec.Host, user.Name) {
+			if strings.HasPrefix(rt.Spec.Host, adminHostPrefix) {
 				foundRoute = &rt
 				break
 			}
@@ -252,21 +448,7 @@ func (r *TenantReconciler) reconcileTenantUrl(tenant *v1alpha1.APIManagementTena
 			return tenantUrlReconciled, nil
 		}
 
-		// Get the value of the `ssoReady` annotation if it exists
-		ssoReady := user.Annotations["ssoReady"]
-		if ssoReady != "yes" {
-			// If the user's SSO is not ready, then the tenantUrl isn't ready
-			// Set the provisioningStatus to ThreeScaleAccountRequested
-			err := r.updateProvisioningStatus(tenant, v1alpha1.ThreeScaleAccountRequested)
-			if err != nil {
-				return tenantUrlReconciled, err
-			}
-			// Update the value of lastError to reflect the user's SSO not being ready
-			err = r.updateLastError(tenant, fmt.Sprintf("waiting for SSO for user %s to be ready", user.Name))
-			return tenantUrlReconciled, nil
-		}
-
-		// Since the user's SSO is ready, update the tenant's tenantUrl and provisioningStatus
+		// The 3scale account is ready, update the tenant's tenantUrl and provisioningStatus
 		err = r.updateTenantUrl(tenant, foundRoute.Spec.Host)
 		if err != nil {
 			return tenantUrlReconciled, err
@@ -280,6 +462,201 @@ func (r *TenantReconciler) reconcileTenantUrl(tenant *v1alpha1.APIManagementTena
 	return tenantUrlReconciled, nil
 }
 
+// reconcileTenantAccount provisions the 3scale account of the user of tenant and records the ID
+// of the account in the tenant status. The account of a tenant with an owner is provisioned for
+// the owner in its spec, together with the RHSSO user the owner logs in with
+func (r *TenantReconciler) reconcileTenantAccount(ctx context.Context, tenant *v1alpha1.APIManagementTenant, accounts *threescale.TenantAccountReconciler) (threescale.TenantAccountStatus, error) {
+	var mtUser userHelper.MultiTenantUser
+	if tenant.Spec.Owner != nil {
+		mtUser = userHelper.GetTenantOwnerUser(tenant)
+		if err := accounts.ReconcileTenantOwner(ctx, r.Client, mtUser, tenant.Spec.Owner); err != nil {
+			return threescale.TenantAccountStatus{}, err
+		}
+	} else {
+		user, err := r.getUserByTenantNamespace(tenant.Namespace)
+		if k8serr.IsNotFound(err) {
+			return threescale.TenantAccountStatus{}, errTenantUserNotFound
+		}
+		if err != nil {
+			return threescale.TenantAccountStatus{}, fmt.Errorf("error getting user for tenant %s: %v", tenant.Name, err)
+		}
+		mtUser, err = userHelper.GetMultiTenantUser(ctx, r.Client, user)
+		if err != nil {
+			return threescale.TenantAccountStatus{}, err
+		}
+	}
+
+	account, err := accounts.ReconcileTenantAccount(ctx, r.Client, mtUser, tenant.Status.TenantAccountID, tenant.Spec.Suspended)
+	// Errors returned before the account is found have no account ID, the recorded ID is kept
+	if account.ID != tenant.Status.TenantAccountID && (err == nil || account.ID != 0) {
+		tenant.Status.TenantAccountID = account.ID
+		if err1 := r.Client.Status().Update(ctx, tenant); err1 != nil {
+			return account, fmt.Errorf("error updating the tenantAccountID to %d for tenant %s: %v", account.ID, tenant.Name, err1)
+		}
+	}
+	if err != nil {
+		return account, err
+	}
+
+	if account.Ready || account.Suspended {
+		metrics.DeleteNoActivated3ScaleTenantAccount(mtUser.Username)
+	} else {
+		metrics.SetNoActivated3ScaleTenantAccount(mtUser.Username)
+	}
+	return account, nil
+}
+
+// rejectTenantWithoutUser stops the reconcile of tenant once the OpenShift user of its namespace is
+// gone. The 3scale account of the user is deleted when accounts is set, and the tenant is only
+// verified again when it changes
+func (r *TenantReconciler) rejectTenantWithoutUser(ctx context.Context, tenant *v1alpha1.APIManagementTenant, accounts *threescale.TenantAccountReconciler) error {
+	if accounts != nil {
+		tenantName := userHelper.GetTenantName(tenant)
+		if err := accounts.DeleteTenantAccount(ctx, r.Client, tenantName, tenant.Status.TenantAccountID); err != nil {
+			if err1 := r.updateLastError(tenant, err.Error()); err1 != nil {
+				return err1
+			}
+			return err
+		}
+		metrics.DeleteNoActivated3ScaleTenantAccount(getTenantUsername(tenant))
+		setTenantQuotaMetrics(tenantName, nil)
+	}
+
+	tenant.Status.TenantAccountID = 0
+	tenant.Status.TenantUrl = ""
+	tenant.Status.Quota = nil
+	tenant.Status.ProvisioningStatus = v1alpha1.WontProvisionTenant
+	tenant.Status.LastError = errTenantUserNotFound.Error()
+	if err := r.Client.Status().Update(ctx, tenant); err != nil {
+		return fmt.Errorf("error updating the provisioningStatus to %s for tenant %s: %v", v1alpha1.WontProvisionTenant, tenant.Name, err)
+	}
+
+	log.Warning(fmt.Sprintf("tenant %s in namespace %s will not be reconciled because %s", tenant.Name, tenant.Namespace, errTenantUserNotFound))
+	return nil
+}
+
+// finalizeTenant aborts the export or import of tenant, deletes its 3scale account and removes the
+// tenant annotation from its user, or the RHSSO user of its owner, before the finalizer is removed
+func (r *TenantReconciler) finalizeTenant(ctx context.Context, tenant *v1alpha1.APIManagementTenant) error {
+	if !controllerutil.ContainsFinalizer(tenant, tenantFinalizer) {
+		return nil
+	}
+
+	if err := r.archives.abort(tenant.UID, tenantArchiveAbortTimeout); err != nil {
+		return fmt.Errorf("error finalizing tenant %s: %v", tenant.Name, err)
+	}
+
+	accounts, err := r.getTenantAccountReconciler(ctx)
+	if err != nil {
+		return err
+	}
+	username := getTenantUsername(tenant)
+	tenantName := userHelper.GetTenantName(tenant)
+	if err := accounts.DeleteTenantAccount(ctx, r.Client, tenantName, tenant.Status.TenantAccountID); err != nil {
+		return err
+	}
+	if tenant.Spec.Owner != nil {
+		if err := accounts.DeleteTenantOwner(ctx, r.Client, tenantName); err != nil {
+			return err
+		}
+	} else if err := r.removeAnnotationFromUser(tenant); err != nil {
+		return err
+	}
+	metrics.DeleteNoActivated3ScaleTenantAccount(username)
+	setTenantQuotaMetrics(tenantName, nil)
+
+	controllerutil.RemoveFinalizer(tenant, tenantFinalizer)
+	if err := r.Client.Update(ctx, tenant); err != nil {
+		return fmt.Errorf("failed to remove finalizer from tenant %s: %v", tenant.Name, err)
+	}
+	log.Info(fmt.Sprintf("deleted 3scale account of tenant %s in namespace %s", tenant.Name, tenant.Namespace))
+	return nil
+}
+
+func (r *TenantReconciler) removeAnnotationFromUser(tenant *v1alpha1.APIManagementTenant) error {
+	user, err := r.getUserByTenantNamespace(tenant.Namespace)
+	if err != nil {
+		if k8serr.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("error getting user for tenant %s: %v", tenant.Name, err)
+	}
+	if _, ok := user.Annotations["tenant"]; !ok {
+		return nil
+	}
+	delete(user.Annotations, "tenant")
+	if err := r.Client.Update(context.TODO(), user); err != nil {
+		return fmt.Errorf("failed to remove tenant annotation from user %s: %v", user.Name, err)
+	}
+	return nil
+}
+
+// reconcileTenantQuota counts the objects in the 3scale account of tenant at most once every
+// tenantResyncInterval and records how they compare to the quota of the tenant in its status and
+// metrics
+func (r *TenantReconciler) reconcileTenantQuota(ctx context.Context, tenant *v1alpha1.APIManagementTenant, accounts *threescale.TenantAccountReconciler, account threescale.TenantAccountStatus) error {
+	tenantName := userHelper.GetTenantName(tenant)
+	tsClient := threescale.NewTenantThreeScaleClient(r.httpClients.Client(time.Second*10), account.AdminBaseURL)
+
+	quota, err := accounts.ReconcileTenantQuota(ctx, r.Client, tsClient, tenantName, tenant.Spec.Quota, tenant.Status.Quota, tenantResyncInterval)
+	if err != nil {
+		return err
+	}
+	setTenantQuotaMetrics(tenantName, quota)
+
+	if equality.Semantic.DeepEqual(quota, tenant.Status.Quota) {
+		return nil
+	}
+	tenant.Status.Quota = quota
+	if err := r.Client.Status().Update(ctx, tenant); err != nil {
+		return fmt.Errorf("error updating the quota status of tenant %s: %v", tenant.Name, err)
+	}
+	return nil
+}
+
+// setTenantQuotaMetrics reports quota in the metrics of tenantName, the metrics are removed when
+// quota is nil
+func setTenantQuotaMetrics(tenantName string, quota *v1alpha1.TenantQuotaStatus) {
+	for _, kind := range threescale.TenantQuotaKinds {
+		if quota == nil {
+			metrics.DeleteTenantQuota(tenantName, kind)
+			continue
+		}
+		limit, count := threescale.GetTenantQuotaLimit(quota.Quota, quota.Usage, kind)
+		metrics.SetTenantQuota(tenantName, kind, limit, count)
+	}
+}
+
+// getTenantAccountReconciler returns the reconciler of the 3scale tenant accounts of the installation
+func (r *TenantReconciler) getTenantAccountReconciler(ctx context.Context) (*threescale.TenantAccountReconciler, error) {
+	installation, err := rhmi.GetRhmiCr(r.Client, ctx, r.watchNamespace, log)
+	if err != nil {
+		return nil, fmt.Errorf("error getting RHMI CR: %v", err)
+	}
+	if installation == nil {
+		return nil, fmt.Errorf("no RHMI CR found in namespace %s", r.watchNamespace)
+	}
+
+	configManager, err := config.NewManager(ctx, r.Client, installation.Namespace, getInstallationConfigMapName(installation), installation)
+	if err != nil {
+		return nil, fmt.Errorf("error reading installation config: %v", err)
+	}
+	if err := r.httpClients.Configure(ctx, r.Client, installation); err != nil {
+		return nil, fmt.Errorf("error configuring http client: %v", err)
+	}
+	tsClient := threescale.NewThreeScaleClient(r.httpClients.Client(time.Second*10), installation.Spec.RoutingSubdomain)
+
+	return threescale.NewTenantAccountReconciler(configManager, installation, tsClient, log)
+}
+
+func getInstallationConfigMapName(installation *v1alpha1.RHMI) string {
+	installationCfgMap := os.Getenv("INSTALLATION_CONFIG_MAP")
+	if installationCfgMap == "" {
+		installationCfgMap = installation.Spec.NamespacePrefix + defaultInstallationConfigMapName
+	}
+	return installationCfgMap
+}
+
 func (r *TenantReconciler) updateLastError(tenant *v1alpha1.APIManagementTenant, message string) error {
 	tenant.Status.LastError = message
 	err := r.Client.Status().Update(context.TODO(), tenant)
@@ -310,10 +687,7 @@ func (r *TenantReconciler) updateTenantUrl(tenant *v1alpha1.APIManagementTenant,
 }
 
 func (r *TenantReconciler) getUserByTenantNamespace(ns string) (*usersv1.User, error) {
-	// Extract name from namespace
-	username := ns
-	username = strings.TrimSuffix(username, "-dev")
-	username = strings.TrimSuffix(username, "-stage")
+	username := getUsernameFromTenantNamespace(ns)
 
 	user := &usersv1.User{
 		ObjectMeta: metav1.ObjectMeta{
@@ -331,3 +705,30 @@ func (r *TenantReconciler) getUserByTenantNamespace(ns string) (*usersv1.User, e
 
 	return user, nil
 }
+
+// getTenantUsername returns the username of the admin of the 3scale account of tenant, the
+// organization name of its owner or the user whose namespace it is in
+func getTenantUsername(tenant *v1alpha1.APIManagementTenant) string {
+	if tenant.Spec.Owner != nil {
+		return userHelper.GetTenantName(tenant)
+	}
+	return getUsernameFromTenantNamespace(tenant.Namespace)
+}
+
+// getUsernameFromTenantNamespace extracts the username from a {USERNAME}-dev or {USERNAME}-stage namespace
+func getUsernameFromTenantNamespace(ns string) string {
+	username := strings.TrimSuffix(ns, "-dev")
+	return strings.TrimSuffix(username, "-stage")
+}
+
+// isTenantVerified returns true when tenant passed its verification and holds its 3scale tenant name
+func isTenantVerified(tenant *v1alpha1.APIManagementTenant) bool {
+	return tenant.Status.ProvisioningStatus != "" && tenant.Status.ProvisioningStatus != v1alpha1.WontProvisionTenant
+}
+
+// isTenantProvisioned returns true when the 3scale account of tenant was provisioned, suspended
+// tenants keep their account
+func isTenantProvisioned(tenant *v1alpha1.APIManagementTenant) bool {
+	return tenant.Status.ProvisioningStatus == v1alpha1.ThreeScaleAccountReady ||
+		tenant.Status.ProvisioningStatus == v1alpha1.ThreeScaleAccountSuspended
+}
@@ -0,0 +1,505 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/integr8ly/integreatly-operator/apis/v1alpha1"
+	"github.com/integr8ly/integreatly-operator/pkg/config"
+	"github.com/integr8ly/integreatly-operator/pkg/products/threescale"
+	"github.com/integr8ly/integreatly-operator/pkg/resources/archive"
+	consolev1 "github.com/openshift/api/console/v1"
+	routev1 "github.com/openshift/api/route/v1"
+	usersv1 "github.com/openshift/api/user/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	tenantTestNamespace         = "tenant-dev"
+	tenantOperatorTestNamespace = "redhat-rhoam-operator"
+	threescaleTestNamespace     = "redhat-rhoam-3scale"
+)
+
+func getTenantTestScheme(t *testing.T) *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	if err := v1alpha1.SchemeBuilder.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := usersv1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := consolev1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	return scheme
+}
+
+func getTestTenant(status v1alpha1.ProvisioningStatus, accountID int) *v1alpha1.APIManagementTenant {
+	return &v1alpha1.APIManagementTenant{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "example",
+			Namespace:  tenantTestNamespace,
+			UID:        types.UID("tenant"),
+			Finalizers: []string{tenantFinalizer},
+		},
+		Status: v1alpha1.APIManagementTenantStatus{
+			ProvisioningStatus: status,
+			TenantAccountID:    accountID,
+			TenantUrl:          "tenant-admin.example.com",
+		},
+	}
+}
+
+func getTestTenantAccountReconciler(t *testing.T, tsClient threescale.ThreeScaleInterface) *threescale.TenantAccountReconciler {
+	configManager := &config.ConfigReadWriterMock{
+		ReadThreeScaleFunc: func() (*config.ThreeScale, error) {
+			return config.NewThreeScale(config.ProductConfig{"NAMESPACE": threescaleTestNamespace}), nil
+		},
+	}
+	accounts, err := threescale.NewTenantAccountReconciler(configManager, &v1alpha1.RHMI{}, tsClient, log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return accounts
+}
+
+func TestTenantReconciler_Reconcile(t *testing.T) {
+	scheme := getTenantTestScheme(t)
+	tenant := getTestTenant("", 0)
+	tenant.Finalizers = nil
+	serverClient := fake.NewFakeClientWithScheme(scheme, tenant)
+	r := &TenantReconciler{Client: serverClient, Scheme: scheme}
+
+	result, err := r.Reconcile(ctrl.Request{NamespacedName: types.NamespacedName{Name: tenant.Name, Namespace: tenant.Namespace}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Requeue || result.RequeueAfter != 0 {
+		t.Errorf("expected a tenant without a user not to be requeued but got %v", result)
+	}
+
+	got := &v1alpha1.APIManagementTenant{}
+	if err := serverClient.Get(context.TODO(), k8sclient.ObjectKey{Name: tenant.Name, Namespace: tenant.Namespace}, got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Status.ProvisioningStatus != v1alpha1.WontProvisionTenant || got.Status.LastError == "" {
+		t.Errorf("expected the tenant to be rejected but got %v", got.Status)
+	}
+	if controllerutil.ContainsFinalizer(got, tenantFinalizer) {
+		t.Errorf("expected no finalizer on a rejected tenant")
+	}
+}
+
+func TestTenantReconciler_reconcileTenantAccount(t *testing.T) {
+	scheme := getTenantTestScheme(t)
+	tenant := getTestTenant(v1alpha1.ThreeScaleAccountReady, 4)
+	serverClient := fake.NewFakeClientWithScheme(scheme, tenant)
+	r := &TenantReconciler{Client: serverClient, Scheme: scheme}
+	tsClient := &threescale.ThreeScaleInterfaceMock{}
+
+	_, err := r.reconcileTenantAccount(context.TODO(), tenant, getTestTenantAccountReconciler(t, tsClient))
+	if !errors.Is(err, errTenantUserNotFound) {
+		t.Errorf("expected errTenantUserNotFound but got %v", err)
+	}
+}
+
+func TestTenantReconciler_rejectTenantWithoutUser(t *testing.T) {
+	scheme := getTenantTestScheme(t)
+
+	tests := []struct {
+		name             string
+		tenant           *v1alpha1.APIManagementTenant
+		withAccounts     bool
+		tsClient         *threescale.ThreeScaleInterfaceMock
+		wantErr          bool
+		wantDeletedID    int
+		wantStatus       v1alpha1.ProvisioningStatus
+		wantAccountID    int
+		wantAccessTokens int
+	}{
+		{
+			name:             "the account of a provisioned tenant is deleted",
+			tenant:           getTestTenant(v1alpha1.ThreeScaleAccountReady, 4),
+			withAccounts:     true,
+			tsClient:         &threescale.ThreeScaleInterfaceMock{DeleteTenantFunc: func(accessToken string, id int) error { return nil }},
+			wantDeletedID:    4,
+			wantStatus:       v1alpha1.WontProvisionTenant,
+			wantAccountID:    0,
+			wantAccessTokens: 1,
+		},
+		{
+			name:             "a tenant whose user was never annotated has no account to delete",
+			tenant:           getTestTenant("", 0),
+			tsClient:         &threescale.ThreeScaleInterfaceMock{},
+			wantStatus:       v1alpha1.WontProvisionTenant,
+			wantAccountID:    0,
+			wantAccessTokens: 2,
+		},
+		{
+			name:         "the tenant is kept when its account can't be deleted",
+			tenant:       getTestTenant(v1alpha1.ThreeScaleAccountReady, 4),
+			withAccounts: true,
+			tsClient: &threescale.ThreeScaleInterfaceMock{DeleteTenantFunc: func(accessToken string, id int) error {
+				return errors.New("unavailable")
+			}},
+			wantErr:          true,
+			wantDeletedID:    4,
+			wantStatus:       v1alpha1.ThreeScaleAccountReady,
+			wantAccountID:    4,
+			wantAccessTokens: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			serverClient := fake.NewFakeClientWithScheme(scheme, tt.tenant,
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Name: "system-seed", Namespace: threescaleTestNamespace},
+					Data:       map[string][]byte{"MASTER_ACCESS_TOKEN": []byte("master-token")},
+				},
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Name: "mt-signupaccount-3scale-access-token", Namespace: threescaleTestNamespace},
+					Data:       map[string][]byte{"tenant": []byte("token"), "other-tenant": []byte("other-token")},
+				},
+			)
+			r := &TenantReconciler{Client: serverClient, Scheme: scheme}
+			var accounts *threescale.TenantAccountReconciler
+			if tt.withAccounts {
+				accounts = getTestTenantAccountReconciler(t, tt.tsClient)
+			}
+
+			err := r.rejectTenantWithoutUser(context.TODO(), tt.tenant, accounts)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("rejectTenantWithoutUser() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if calls := tt.tsClient.DeleteTenantCalls(); tt.wantDeletedID == 0 && len(calls) != 0 ||
+				tt.wantDeletedID != 0 && (len(calls) != 1 || calls[0].ID != tt.wantDeletedID) {
+				t.Errorf("expected account %d to be deleted but got %v", tt.wantDeletedID, calls)
+			}
+
+			got := &v1alpha1.APIManagementTenant{}
+			if err := serverClient.Get(context.TODO(), k8sclient.ObjectKey{Name: tt.tenant.Name, Namespace: tt.tenant.Namespace}, got); err != nil {
+				t.Fatal(err)
+			}
+			if got.Status.ProvisioningStatus != tt.wantStatus || got.Status.TenantAccountID != tt.wantAccountID {
+				t.Errorf("expected status %q with account %d but got %q with account %d", tt.wantStatus, tt.wantAccountID, got.Status.ProvisioningStatus, got.Status.TenantAccountID)
+			}
+			if got.Status.LastError == "" {
+				t.Errorf("expected the last error of the tenant to be set")
+			}
+			if !tt.wantErr && got.Status.TenantUrl != "" {
+				t.Errorf("expected the tenant url to be cleared but got %s", got.Status.TenantUrl)
+			}
+
+			accessTokens := &corev1.Secret{}
+			if err := serverClient.Get(context.TODO(), k8sclient.ObjectKey{Name: "mt-signupaccount-3scale-access-token", Namespace: threescaleTestNamespace}, accessTokens); err != nil {
+				t.Fatal(err)
+			}
+			if len(accessTokens.Data) != tt.wantAccessTokens {
+				t.Errorf("expected %d access tokens but got %v", tt.wantAccessTokens, accessTokens.Data)
+			}
+		})
+	}
+}
+
+func TestTenantReconciler_verifyTenantOwner(t *testing.T) {
+	scheme := getTenantTestScheme(t)
+	now := metav1.Now()
+	earlier := metav1.NewTime(now.Add(-time.Hour))
+	getOwnerTenant := func(name, namespace string, status v1alpha1.ProvisioningStatus, created metav1.Time) *v1alpha1.APIManagementTenant {
+		tenant := getTestTenant(status, 0)
+		tenant.Name = name
+		tenant.Namespace = namespace
+		tenant.UID = types.UID(namespace + "/" + name)
+		tenant.CreationTimestamp = created
+		tenant.Spec.Owner = &v1alpha1.TenantOwner{
+			Email:            "admin@example.com",
+			OrganizationName: "example",
+			IdentityProvider: "example-idp",
+		}
+		return tenant
+	}
+	userTenant := getTestTenant(v1alpha1.UserAnnotated, 0)
+	userTenant.Namespace = "example-dev"
+
+	tests := []struct {
+		name         string
+		tenant       *v1alpha1.APIManagementTenant
+		others       []runtime.Object
+		wantVerified bool
+	}{
+		{
+			name:         "a tenant with an owner in the operator namespace is verified",
+			tenant:       getOwnerTenant("example", tenantOperatorTestNamespace, "", now),
+			wantVerified: true,
+		},
+		{
+			name:   "a tenant with an owner outside of the operator namespace is rejected",
+			tenant: getOwnerTenant("example", tenantTestNamespace, "", now),
+		},
+		{
+			name:   "the name is held by a verified tenant that has no account yet",
+			tenant: getOwnerTenant("example", tenantOperatorTestNamespace, "", earlier),
+			others: []runtime.Object{getOwnerTenant("other", tenantOperatorTestNamespace, v1alpha1.ThreeScaleAccountRequested, now)},
+		},
+		{
+			name:   "the name is held by the annotated user of a tenant without an owner",
+			tenant: getOwnerTenant("example", tenantOperatorTestNamespace, "", now),
+			others: []runtime.Object{userTenant},
+		},
+		{
+			name:   "the name is held by an older tenant that is not verified yet",
+			tenant: getOwnerTenant("example", tenantOperatorTestNamespace, "", now),
+			others: []runtime.Object{getOwnerTenant("other", tenantOperatorTestNamespace, "", earlier)},
+		},
+		{
+			name:         "the name is not held by a newer tenant that is not verified yet",
+			tenant:       getOwnerTenant("example", tenantOperatorTestNamespace, "", earlier),
+			others:       []runtime.Object{getOwnerTenant("other", tenantOperatorTestNamespace, "", now)},
+			wantVerified: true,
+		},
+		{
+			name:         "the name is not held by a rejected tenant",
+			tenant:       getOwnerTenant("example", tenantOperatorTestNamespace, "", now),
+			others:       []runtime.Object{getOwnerTenant("other", tenantTestNamespace, v1alpha1.WontProvisionTenant, earlier)},
+			wantVerified: true,
+		},
+		{
+			name:         "a verified tenant keeps the name from an older tenant that is not verified yet",
+			tenant:       getOwnerTenant("example", tenantOperatorTestNamespace, v1alpha1.ThreeScaleAccountRequested, now),
+			others:       []runtime.Object{getOwnerTenant("other", tenantOperatorTestNamespace, "", earlier)},
+			wantVerified: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			serverClient := fake.NewFakeClientWithScheme(scheme, append(tt.others, tt.tenant)...)
+			r := &TenantReconciler{Client: serverClient, Scheme: scheme, watchNamespace: tenantOperatorTestNamespace}
+
+			verified, reason, err := r.verifyTenantOwner(tt.tenant)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if verified != tt.wantVerified {
+				t.Errorf("expected verified to be %v but got %v: %s", tt.wantVerified, verified, reason)
+			}
+			if !verified && reason == "" {
+				t.Errorf("expected a reason for the rejection of the tenant")
+			}
+		})
+	}
+}
+
+func TestTenantReconciler_reconcileTenantUrl(t *testing.T) {
+	scheme := getTenantTestScheme(t)
+	if err := routev1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	route := func(host string) runtime.Object {
+		return &routev1.Route{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      host,
+				Namespace: "sandbox-rhoam-3scale",
+				Labels:    map[string]string{"zync.3scale.net/route-to": "system-provider"},
+			},
+			Spec: routev1.RouteSpec{Host: host},
+		}
+	}
+
+	tests := []struct {
+		name           string
+		routes         []runtime.Object
+		wantReconciled bool
+		wantStatus     v1alpha1.ProvisioningStatus
+		wantUrl        string
+	}{
+		{
+			name:           "the admin route of the tenant is found by its host prefix",
+			routes:         []runtime.Object{route("acme-corp-admin.apps.example.com"), route("notacme-admin.apps.example.com"), route("acme-admin.apps.example.com")},
+			wantReconciled: true,
+			wantStatus:     v1alpha1.ThreeScaleAccountReady,
+			wantUrl:        "acme-admin.apps.example.com",
+		},
+		{
+			name:       "routes of tenants whose names contain the tenant name are not matched",
+			routes:     []runtime.Object{route("acme-corp-admin.apps.example.com"), route("notacme-admin.apps.example.com")},
+			wantStatus: v1alpha1.ThreeScaleAccountRequested,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tenant := getTestTenant(v1alpha1.UserAnnotated, 4)
+			tenant.Status.TenantUrl = ""
+			tenant.Spec.Owner = &v1alpha1.TenantOwner{Email: "admin@acme.com", OrganizationName: "acme", IdentityProvider: "acme-sso"}
+			serverClient := fake.NewFakeClientWithScheme(scheme, append(tt.routes, tenant)...)
+			r := &TenantReconciler{Client: serverClient, Scheme: scheme, watchNamespace: tenantOperatorTestNamespace}
+
+			reconciled, err := r.reconcileTenantUrl(tenant)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if reconciled != tt.wantReconciled {
+				t.Errorf("expected reconciled %v but got %v", tt.wantReconciled, reconciled)
+			}
+			got := &v1alpha1.APIManagementTenant{}
+			if err := serverClient.Get(context.TODO(), k8sclient.ObjectKey{Name: tenant.Name, Namespace: tenant.Namespace}, got); err != nil {
+				t.Fatal(err)
+			}
+			if got.Status.ProvisioningStatus != tt.wantStatus || got.Status.TenantUrl != tt.wantUrl {
+				t.Errorf("expected status %q with url %q but got %q with url %q", tt.wantStatus, tt.wantUrl, got.Status.ProvisioningStatus, got.Status.TenantUrl)
+			}
+		})
+	}
+}
+
+func TestTenantArchives(t *testing.T) {
+	archives := tenantArchives{}
+
+	if _, ok := archives.start("tenant"); !ok {
+		t.Fatalf("expected the archive of the tenant to start")
+	}
+	if _, ok := archives.start("tenant"); ok {
+		t.Errorf("expected a second archive of the tenant not to start while the first is running")
+	}
+	if _, ok := archives.start("other-tenant"); !ok {
+		t.Errorf("expected the archive of another tenant to start")
+	}
+	archives.done("tenant")
+	if _, ok := archives.start("tenant"); !ok {
+		t.Errorf("expected the archive of the tenant to start once the first is done")
+	}
+}
+
+func TestTenantArchives_abort(t *testing.T) {
+	archives := tenantArchives{}
+
+	if err := archives.abort("idle-tenant", time.Millisecond); err != nil {
+		t.Errorf("unexpected error aborting a tenant without an archive: %v", err)
+	}
+
+	// an archive returns once its context is cancelled
+	ctx, _ := archives.start("tenant")
+	go func() {
+		<-ctx.Done()
+		archives.done("tenant")
+	}()
+	if err := archives.abort("tenant", time.Second); err != nil {
+		t.Fatalf("unexpected error aborting the archive of the tenant: %v", err)
+	}
+	if _, ok := archives.start("tenant"); !ok {
+		t.Errorf("expected the archive of the tenant to start once the first is aborted")
+	}
+
+	// an archive that doesn't return keeps the tenant from being finalized
+	archives.start("stuck-tenant")
+	if err := archives.abort("stuck-tenant", time.Millisecond); err == nil {
+		t.Errorf("expected an error aborting an archive that does not return")
+	}
+}
+
+func TestTenantArchives_run(t *testing.T) {
+	archives := tenantArchives{}
+	ctx, _ := archives.start("tenant")
+
+	stop := make(chan struct{})
+	close(stop)
+	if err := archives.run(stop); err != nil {
+		t.Fatal(err)
+	}
+	if ctx.Err() == nil {
+		t.Errorf("expected the archives to be cancelled when the manager stops")
+	}
+	if _, ok := archives.start("other-tenant"); ok {
+		t.Errorf("expected no archive to start once the manager has stopped")
+	}
+}
+
+func TestTenantReconciler_runTenantArchive(t *testing.T) {
+	scheme := getTenantTestScheme(t)
+	location := "s3://bucket/tenant.json.gz"
+
+	tests := []struct {
+		name           string
+		annotation     string
+		location       string
+		runErr         error
+		wantCompleted  bool
+		wantPhase      v1alpha1.TenantArchivePhase
+		wantMessage    string
+		wantAnnotation bool
+	}{
+		{
+			name:          "a completed export is recorded and its annotation removed",
+			annotation:    location,
+			location:      location,
+			wantCompleted: true,
+			wantPhase:     v1alpha1.TenantArchiveCompleted,
+			wantMessage:   "exported",
+		},
+		{
+			name:        "a failed export is recorded and its annotation removed",
+			annotation:  location,
+			location:    location,
+			runErr:      errors.New("unavailable"),
+			wantPhase:   v1alpha1.TenantArchiveFailed,
+			wantMessage: "unavailable",
+		},
+		{
+			name:        "an unsupported location fails",
+			annotation:  "pvc://tenant.json.gz",
+			location:    "pvc://tenant.json.gz",
+			wantPhase:   v1alpha1.TenantArchiveFailed,
+			wantMessage: "unsupported archive location pvc://tenant.json.gz, expected s3://<bucket>/<key>",
+		},
+		{
+			name:           "an annotation changed during the export is kept",
+			annotation:     "s3://bucket/other.json.gz",
+			location:       location,
+			wantCompleted:  true,
+			wantPhase:      v1alpha1.TenantArchiveCompleted,
+			wantMessage:    "exported",
+			wantAnnotation: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tenant := getTestTenant(v1alpha1.ThreeScaleAccountReady, 4)
+			tenant.Annotations = map[string]string{v1alpha1.TenantExportAnnotation: tt.annotation}
+			serverClient := fake.NewFakeClientWithScheme(scheme, tenant, &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: archive.S3CredentialsSecretName, Namespace: tenantOperatorTestNamespace},
+				Data:       map[string][]byte{"AWS_ACCESS_KEY_ID": []byte("key-id"), "AWS_SECRET_ACCESS_KEY": []byte("secret")},
+			})
+			r := &TenantReconciler{Client: serverClient, Scheme: scheme, watchNamespace: tenantOperatorTestNamespace}
+			key := k8sclient.ObjectKey{Name: tenant.Name, Namespace: tenant.Namespace}
+
+			completed := r.runTenantArchive(context.TODO(), key, v1alpha1.TenantExportAnnotation, tt.location, func(store archive.Store) (string, error) {
+				return "exported", tt.runErr
+			})
+			if completed != tt.wantCompleted {
+				t.Errorf("expected completed %v but got %v", tt.wantCompleted, completed)
+			}
+
+			got := &v1alpha1.APIManagementTenant{}
+			if err := serverClient.Get(context.TODO(), key, got); err != nil {
+				t.Fatal(err)
+			}
+			if got.Status.Export == nil || got.Status.Export.Phase != tt.wantPhase || got.Status.Export.Message != tt.wantMessage || got.Status.Export.Location != tt.location {
+				t.Errorf("expected export %s %q of %s but got %+v", tt.wantPhase, tt.wantMessage, tt.location, got.Status.Export)
+			}
+			if _, ok := got.Annotations[v1alpha1.TenantExportAnnotation]; ok != tt.wantAnnotation {
+				t.Errorf("expected the export annotation to be kept %v but got %v", tt.wantAnnotation, got.Annotations)
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/integr8ly/integreatly-operator/pkg/config"
+	"github.com/integr8ly/integreatly-operator/pkg/products/marin3r"
+	"github.com/integr8ly/integreatly-operator/pkg/resources/rhmi"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// rateLimitUsageInterval is how often the rate limit and the rate limit usage of the tenants are
+// reported
+const rateLimitUsageInterval = time.Minute
+
+// reportTenantRateLimitUsage reports the rate limit and the rate limit usage of every tenant every
+// rateLimitUsageInterval until stop is closed. The limits and the counters of all the tenants are
+// read from limitador at once, so they are reported outside of the reconcile of each tenant
+func (r *TenantReconciler) reportTenantRateLimitUsage(stop <-chan struct{}) error {
+	wait.Until(func() {
+		if err := r.reconcileTenantRateLimitUsage(context.Background()); err != nil {
+			log.Warning("Failed to report the rate limit usage of the tenants: " + err.Error())
+		}
+	}, rateLimitUsageInterval, stop)
+	return nil
+}
+
+func (r *TenantReconciler) reconcileTenantRateLimitUsage(ctx context.Context) error {
+	installation, err := rhmi.GetRhmiCr(r.Client, ctx, r.watchNamespace, log)
+	if err != nil {
+		return fmt.Errorf("error getting RHMI CR: %v", err)
+	}
+	if installation == nil {
+		return nil
+	}
+
+	configManager, err := config.NewManager(ctx, r.Client, installation.Namespace, getInstallationConfigMapName(installation), installation)
+	if err != nil {
+		return fmt.Errorf("error reading installation config: %v", err)
+	}
+	marin3rConfig, err := configManager.ReadMarin3r()
+	if err != nil {
+		return fmt.Errorf("error reading marin3r config: %v", err)
+	}
+	if marin3rConfig.GetNamespace() == "" {
+		return nil
+	}
+
+	limitadorClient := marin3r.NewLimitadorClient(r.httpClients.Client(time.Second*10), marin3r.GetLimitadorServiceURL(marin3rConfig.GetNamespace()))
+	return marin3r.ReconcileTenantRateLimitUsage(ctx, r.Client, limitadorClient)
+}
@@ -0,0 +1,266 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/integr8ly/integreatly-operator/apis/v1alpha1"
+	"github.com/integr8ly/integreatly-operator/pkg/products/threescale"
+	"github.com/integr8ly/integreatly-operator/pkg/resources/archive"
+	userHelper "github.com/integr8ly/integreatly-operator/pkg/resources/user"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// tenantArchiveAbortTimeout is how long the finalizer of a tenant waits for its export or import to
+// return once it is aborted
+const tenantArchiveAbortTimeout = 30 * time.Second
+
+// tenantArchives tracks the tenants with an export or import running in the background, a tenant
+// has at most one of them running at a time. They run with a context that is cancelled when the
+// manager stops or when their tenant is deleted
+type tenantArchives struct {
+	mu      sync.Mutex
+	ctx     context.Context
+	cancel  context.CancelFunc
+	running map[types.UID]*runningTenantArchive
+}
+
+type runningTenantArchive struct {
+	cancel context.CancelFunc
+	// done is closed once the export or import has returned
+	done chan struct{}
+}
+
+// start returns the context of an export or import of the tenant uid, false is returned when the
+// tenant already has one running or the manager has stopped
+func (a *tenantArchives) start(uid types.UID) (context.Context, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.ctx == nil {
+		a.ctx, a.cancel = context.WithCancel(context.Background())
+	}
+	if _, ok := a.running[uid]; ok || a.ctx.Err() != nil {
+		return nil, false
+	}
+	if a.running == nil {
+		a.running = map[types.UID]*runningTenantArchive{}
+	}
+	ctx, cancel := context.WithCancel(a.ctx)
+	a.running[uid] = &runningTenantArchive{cancel: cancel, done: make(chan struct{})}
+	return ctx, true
+}
+
+func (a *tenantArchives) done(uid types.UID) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if archive, ok := a.running[uid]; ok {
+		archive.cancel()
+		close(archive.done)
+		delete(a.running, uid)
+	}
+}
+
+// abort cancels the export or import of the tenant uid and waits up to timeout for it to return, an
+// error is returned when it is still running
+func (a *tenantArchives) abort(uid types.UID, timeout time.Duration) error {
+	a.mu.Lock()
+	archive, ok := a.running[uid]
+	a.mu.Unlock()
+	if !ok {
+		return nil
+	}
+
+	archive.cancel()
+	select {
+	case <-archive.done:
+		return nil
+	case <-time.After(timeout):
+		return fmt.Errorf("the aborted export or import of the tenant has not returned after %s", timeout)
+	}
+}
+
+// run is added to the manager, the exports and imports are cancelled when it stops
+func (a *tenantArchives) run(stop <-chan struct{}) error {
+	<-stop
+
+	a.mu.Lock()
+	if a.ctx == nil {
+		a.ctx, a.cancel = context.WithCancel(context.Background())
+	}
+	a.cancel()
+	a.mu.Unlock()
+	return nil
+}
+
+// reconcileTenantArchives starts the export and import requested by the annotations of tenant in
+// the background and records them as in progress in its status. When both are requested the export
+// runs first, so the configuration of the tenant is kept before the import changes it
+func (r *TenantReconciler) reconcileTenantArchives(ctx context.Context, tenant *v1alpha1.APIManagementTenant, accounts *threescale.TenantAccountReconciler, account threescale.TenantAccountStatus) error {
+	exportLocation, exportRequested := tenant.Annotations[v1alpha1.TenantExportAnnotation]
+	importLocation, importRequested := tenant.Annotations[v1alpha1.TenantImportAnnotation]
+	if !exportRequested && !importRequested {
+		return nil
+	}
+	uid := tenant.UID
+	archiveCtx, ok := r.archives.start(uid)
+	if !ok {
+		return nil
+	}
+
+	tenantName := userHelper.GetTenantName(tenant)
+	accessToken, err := accounts.GetTenantAccessToken(ctx, r.Client, tenantName)
+	if err != nil {
+		r.archives.done(uid)
+		return err
+	}
+	// the requests to 3scale are aborted with the export or import
+	httpClient := r.httpClients.Client(time.Second * 10)
+	httpClient.Transport = &contextTransport{ctx: archiveCtx, base: httpClient.Transport}
+	tsClient := threescale.NewTenantThreeScaleClient(httpClient, account.AdminBaseURL)
+
+	if exportRequested {
+		setTenantArchiveStatus(tenant, v1alpha1.TenantExportAnnotation, exportLocation, v1alpha1.TenantArchiveInProgress, "")
+	}
+	if importRequested {
+		setTenantArchiveStatus(tenant, v1alpha1.TenantImportAnnotation, importLocation, v1alpha1.TenantArchiveInProgress, "")
+	}
+	if err := r.Client.Status().Update(ctx, tenant); err != nil {
+		r.archives.done(uid)
+		return fmt.Errorf("error updating the archive status of tenant %s: %v", tenant.Name, err)
+	}
+
+	key := k8sclient.ObjectKey{Name: tenant.Name, Namespace: tenant.Namespace}
+	go func() {
+		defer r.archives.done(uid)
+		ctx := archiveCtx
+
+		exported := true
+		if exportRequested {
+			exported = r.runTenantArchive(ctx, key, v1alpha1.TenantExportAnnotation, exportLocation, func(store archive.Store) (string, error) {
+				tenantArchive, err := threescale.ExportTenant(tsClient, accessToken)
+				if err != nil {
+					return "", err
+				}
+				data, err := tenantArchive.Marshal()
+				if err != nil {
+					return "", err
+				}
+				if err := store.Write(ctx, data); err != nil {
+					return "", err
+				}
+				return fmt.Sprintf("exported %s", tenantArchive.Summary()), nil
+			})
+		}
+
+		if !importRequested {
+			return
+		}
+		if !exported {
+			r.finishTenantArchive(ctx, key, v1alpha1.TenantImportAnnotation, importLocation, v1alpha1.TenantArchiveFailed, "the import did not run because the export of the tenant failed")
+			return
+		}
+		r.runTenantArchive(ctx, key, v1alpha1.TenantImportAnnotation, importLocation, func(store archive.Store) (string, error) {
+			data, err := store.Read(ctx)
+			if err != nil {
+				return "", err
+			}
+			tenantArchive, err := threescale.UnmarshalTenantArchive(data)
+			if err != nil {
+				return "", err
+			}
+			if err := threescale.ImportTenant(tsClient, accessToken, tenantArchive); err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("imported %s", tenantArchive.Summary()), nil
+		})
+	}()
+	return nil
+}
+
+// runTenantArchive runs the export or import requested by annotation with the archive at location
+// of the tenant key and returns true when it completed. A failed run is reported in the status and
+// is not retried until the annotation is added again
+func (r *TenantReconciler) runTenantArchive(ctx context.Context, key k8sclient.ObjectKey, annotation, location string, run func(store archive.Store) (string, error)) bool {
+	log.Info(fmt.Sprintf("running %s of tenant %s with archive %s", annotation, key.Name, location))
+
+	phase := v1alpha1.TenantArchiveCompleted
+	store, err := archive.NewStore(ctx, r.Client, r.watchNamespace, path.Join(key.Namespace, key.Name), location)
+	message := ""
+	if err == nil {
+		message, err = run(store)
+	}
+	if err != nil {
+		log.Error(fmt.Sprintf("%s of tenant %s failed", annotation, key.Name), err)
+		phase = v1alpha1.TenantArchiveFailed
+		message = err.Error()
+	}
+	r.finishTenantArchive(ctx, key, annotation, location, phase, message)
+	return err == nil
+}
+
+// finishTenantArchive records the phase a finished export or import of the tenant key ended in
+// and removes its annotation, unless the annotation was changed to another location meanwhile
+func (r *TenantReconciler) finishTenantArchive(ctx context.Context, key k8sclient.ObjectKey, annotation, location string, phase v1alpha1.TenantArchivePhase, message string) {
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		tenant := &v1alpha1.APIManagementTenant{}
+		if err := r.Client.Get(ctx, key, tenant); err != nil {
+			return err
+		}
+		setTenantArchiveStatus(tenant, annotation, location, phase, message)
+		return r.Client.Status().Update(ctx, tenant)
+	})
+	if err != nil {
+		log.Error(fmt.Sprintf("error updating the %s status to %s for tenant %s", annotation, phase, key.Name), err)
+		return
+	}
+
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		tenant := &v1alpha1.APIManagementTenant{}
+		if err := r.Client.Get(ctx, key, tenant); err != nil {
+			return err
+		}
+		if tenant.Annotations[annotation] != location {
+			return nil
+		}
+		delete(tenant.Annotations, annotation)
+		return r.Client.Update(ctx, tenant)
+	})
+	if err != nil {
+		log.Error(fmt.Sprintf("failed to remove annotation %s from tenant %s", annotation, key.Name), err)
+	}
+}
+
+func setTenantArchiveStatus(tenant *v1alpha1.APIManagementTenant, annotation, location string, phase v1alpha1.TenantArchivePhase, message string) {
+	status := &v1alpha1.TenantArchiveStatus{
+		Location:           location,
+		Phase:              phase,
+		Message:            message,
+		LastTransitionTime: metav1.Now(),
+	}
+	if annotation == v1alpha1.TenantExportAnnotation {
+		tenant.Status.Export = status
+	} else {
+		tenant.Status.Import = status
+	}
+}
+
+// contextTransport sends the requests with ctx, so they are aborted once it is cancelled
+type contextTransport struct {
+	ctx  context.Context
+	base http.RoundTripper
+}
+
+func (t *contextTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return base.RoundTrip(req.WithContext(t.ctx))
+}
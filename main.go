@@ -17,6 +17,7 @@ limitations under the License.
 package main
 
 import (
+	"context"
 	"flag"
 	"github.com/integr8ly/integreatly-operator/pkg/resources/k8s"
 	"os"
@@ -41,6 +42,7 @@ import (
 	tenantcontroller "github.com/integr8ly/integreatly-operator/controllers/tenant"
 	usercontroller "github.com/integr8ly/integreatly-operator/controllers/user"
 	"github.com/integr8ly/integreatly-operator/pkg/addon"
+	"github.com/integr8ly/integreatly-operator/pkg/tracing"
 	"github.com/integr8ly/integreatly-operator/pkg/webhooks"
 	"github.com/openshift/api/apps/v1"
 	"github.com/sirupsen/logrus"
@@ -63,9 +65,23 @@ func init() {
 	customMetrics.Registry.MustRegister(integreatlymetrics.RHOAMCluster)
 	customMetrics.Registry.MustRegister(integreatlymetrics.ThreeScaleUserAction)
 	customMetrics.Registry.MustRegister(integreatlymetrics.Quota)
+	customMetrics.Registry.MustRegister(integreatlymetrics.QuotaRollbacks)
 	customMetrics.Registry.MustRegister(integreatlymetrics.TenantsSummary)
 	customMetrics.Registry.MustRegister(integreatlymetrics.NoActivated3ScaleTenantAccount)
 	customMetrics.Registry.MustRegister(integreatlymetrics.InstallationControllerReconcileDelayed)
+	customMetrics.Registry.MustRegister(integreatlymetrics.ProductReconcileErrors)
+	customMetrics.Registry.MustRegister(integreatlymetrics.ProductPausedSince)
+	customMetrics.Registry.MustRegister(integreatlymetrics.ProductReconcileDuration)
+	customMetrics.Registry.MustRegister(integreatlymetrics.ProductPhaseTransitions)
+	customMetrics.Registry.MustRegister(integreatlymetrics.ProductPhaseDuration)
+	customMetrics.Registry.MustRegister(integreatlymetrics.ProductLastCompleted)
+	customMetrics.Registry.MustRegister(integreatlymetrics.ProductFirstReconciled)
+	customMetrics.Registry.MustRegister(integreatlymetrics.RateLimitUsage)
+	customMetrics.Registry.MustRegister(integreatlymetrics.RateLimitUsageLimit)
+	customMetrics.Registry.MustRegister(integreatlymetrics.RateLimitExemptRequests)
+	customMetrics.Registry.MustRegister(integreatlymetrics.TenantQuotaUsage)
+	customMetrics.Registry.MustRegister(integreatlymetrics.TenantQuotaLimit)
+	customMetrics.Registry.MustRegister(integreatlymetrics.TenantOverQuota)
 	customMetrics.Registry.MustRegister(integreatlymetrics.CustomDomain)
 	customMetrics.Registry.MustRegister(integreatlymetrics.ThreeScalePortals)
 	customMetrics.Registry.MustRegister(integreatlymetrics.RhoamStateMetric)
@@ -88,11 +104,14 @@ func main() {
 	var metricsAddr string
 	var enableLeaderElection bool
 	var probeAddr string
+	var plan bool
 	flag.StringVar(&metricsAddr, "metrics-addr", ":8383", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
 	flag.BoolVar(&enableLeaderElection, "enable-leader-election", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+	flag.BoolVar(&plan, "plan", false, "Print the changes the installation would make to the cluster and exit, "+
+		"without making them or starting the manager.")
 	flag.Parse()
 
 	ctrl.SetLogger(zap.New(zap.UseDevMode(true)))
@@ -102,12 +121,26 @@ func main() {
 		QuoteEmptyFields: false,
 	})
 
+	shutdownTracing, err := tracing.Setup(context.Background(), os.Getenv(tracing.ExporterEnvName))
+	if err != nil {
+		setupLog.Error(err, "unable to set up tracing")
+		os.Exit(1)
+	}
+
 	watchNamespace, err := k8s.GetWatchNamespace()
 	if err != nil {
 		setupLog.Error(err, "unable to get WatchNamespace, "+
 			"the manager will watch and manage resources in all namespaces")
 	}
 
+	if plan {
+		if err := rhmicontroller.Plan(context.Background(), ctrl.GetConfigOrDie(), scheme, watchNamespace, os.Stdout); err != nil {
+			setupLog.Error(err, "unable to plan the installation")
+			os.Exit(1)
+		}
+		return
+	}
+
 	var mgr ctrl.Manager
 	if strings.Contains(watchNamespace, "sandbox") || watchNamespace == "" {
 		mgr, err = ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
@@ -190,7 +223,12 @@ func main() {
 	}
 
 	setupLog.Info("starting manager")
-	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
+	err = mgr.Start(ctrl.SetupSignalHandler())
+	// spans are flushed before exiting, os.Exit does not run deferred functions
+	if err := shutdownTracing(context.Background()); err != nil {
+		setupLog.Error(err, "unable to flush traces")
+	}
+	if err != nil {
 		setupLog.Error(err, "problem running manager")
 		os.Exit(1)
 	}
@@ -215,6 +253,22 @@ func setupWebhooks(mgr ctrl.Manager) error {
 		},
 	})
 
+	// Validating webhook for the RateLimitAlertPolicy CR, rejects alerts that
+	// can't be turned into PrometheusRules
+	rateLimitAlertPolicyRegister, err := webhooks.WebhookRegisterFor(&rhmiv1alpha1.RateLimitAlertPolicy{})
+	if err != nil {
+		return err
+	}
+	webhooks.Config.AddWebhook(webhooks.IntegreatlyWebhook{
+		Name: "ratelimitalertpolicy",
+		Rule: webhooks.NewRule().
+			OneResource("integreatly.org", "v1alpha1", "ratelimitalertpolicies").
+			ForCreate().
+			ForUpdate().
+			NamespacedScope(),
+		Register: rateLimitAlertPolicyRegister,
+	})
+
 	// The webhooks feature can't work when the operator runs locally, as it
 	// needs to be accessible by kubernetes and depends on the TLS certificates
 	// being mounted
@@ -0,0 +1,196 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/apis/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// TrustedCABundleConfigMapName is the config map in the installation namespace that OpenShift
+	// injects the trusted CA bundle of the cluster into
+	TrustedCABundleConfigMapName = "trusted-ca-bundle"
+	// TrustedCABundleInjectLabel asks OpenShift to inject the trusted CA bundle into a config map
+	TrustedCABundleInjectLabel = "config.openshift.io/inject-trusted-cabundle"
+	// DefaultCABundleKey is the key OpenShift injects the trusted CA bundle under, it is also the
+	// default key of the bundle referenced by the installation
+	DefaultCABundleKey = "ca-bundle.crt"
+	// ServiceAccountCAFile is the CA of the cluster API server, it is mounted into every pod
+	ServiceAccountCAFile = "/var/run/secrets/kubernetes.io/serviceaccount/ca.crt"
+)
+
+// HTTPClientFactory builds the HTTP clients used to call the product and cluster APIs. The clients
+// share one transport so connections are pooled, and trust the system CAs and the CA of the cluster
+// API server together with the CA bundles configured for the installation
+type HTTPClientFactory struct {
+	lock      sync.RWMutex
+	transport *http.Transport
+	// verified is the transport of the clients that verify certificates even when the installation
+	// uses self signed certificates
+	verified *http.Transport
+	caBundle []byte
+	insecure bool
+}
+
+func NewHTTPClientFactory() *HTTPClientFactory {
+	transport, _ := newTransport(nil, false)
+	verified, _ := newTransport(nil, false)
+	return &HTTPClientFactory{transport: transport, verified: verified}
+}
+
+// Configure loads the CA bundles trusted by the clients of the installation. The transport is only
+// replaced when the bundles or the TLS settings change, so pooled connections are kept otherwise
+func (f *HTTPClientFactory) Configure(ctx context.Context, reader k8sclient.Reader, installation *integreatlyv1alpha1.RHMI) error {
+	caBundle, err := loadCABundles(ctx, reader, installation)
+	if err != nil {
+		return err
+	}
+	insecure := installation.Spec.SelfSignedCerts
+
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if insecure == f.insecure && bytes.Equal(caBundle, f.caBundle) {
+		return nil
+	}
+	transport, err := newTransport(caBundle, insecure)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(caBundle, f.caBundle) {
+		verified, err := newTransport(caBundle, false)
+		if err != nil {
+			return err
+		}
+		f.verified.CloseIdleConnections()
+		f.verified = verified
+	}
+	f.transport.CloseIdleConnections()
+	f.transport, f.caBundle, f.insecure = transport, caBundle, insecure
+	return nil
+}
+
+// Client returns a client using the shared transport that gives up on requests after timeout
+func (f *HTTPClientFactory) Client(timeout time.Duration) *http.Client {
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: f.Transport(),
+	}
+}
+
+// VerifiedClient returns a client that always verifies the certificates of the servers it calls,
+// for calls that must not skip verification when the installation uses self signed certificates
+func (f *HTTPClientFactory) VerifiedClient(timeout time.Duration) *http.Client {
+	f.lock.RLock()
+	defer f.lock.RUnlock()
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: f.verified,
+	}
+}
+
+// Transport returns the shared transport, for clients that need to wrap it
+func (f *HTTPClientFactory) Transport() http.RoundTripper {
+	f.lock.RLock()
+	defer f.lock.RUnlock()
+	return f.transport
+}
+
+// loadCABundles returns the CA of the cluster API server, when the operator runs in a pod, and the
+// trusted CA bundle injected by OpenShift, when it has been injected, followed by the bundle
+// referenced by the installation
+func loadCABundles(ctx context.Context, reader k8sclient.Reader, installation *integreatlyv1alpha1.RHMI) ([]byte, error) {
+	caBundle, err := ioutil.ReadFile(ServiceAccountCAFile)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read the cluster CA: %w", err)
+	}
+
+	injected := &corev1.ConfigMap{}
+	err = reader.Get(ctx, k8sclient.ObjectKey{Name: TrustedCABundleConfigMapName, Namespace: installation.Namespace}, injected)
+	if err != nil && !k8serr.IsNotFound(err) {
+		return nil, fmt.Errorf("failed to get the injected trusted CA bundle: %w", err)
+	}
+	if len(caBundle) > 0 && caBundle[len(caBundle)-1] != '\n' {
+		caBundle = append(caBundle, '\n')
+	}
+	caBundle = append(caBundle, injected.Data[DefaultCABundleKey]...)
+
+	spec := installation.Spec.TrustedCABundle
+	if spec == nil {
+		return caBundle, nil
+	}
+	namespace, key := spec.Namespace, spec.Key
+	if namespace == "" {
+		namespace = installation.Namespace
+	}
+	if key == "" {
+		key = DefaultCABundleKey
+	}
+
+	var data []byte
+	switch spec.Kind {
+	case integreatlyv1alpha1.TrustedCABundleKindConfigMap:
+		configMap := &corev1.ConfigMap{}
+		if err := reader.Get(ctx, k8sclient.ObjectKey{Name: spec.Name, Namespace: namespace}, configMap); err != nil {
+			return nil, fmt.Errorf("failed to get trusted CA bundle config map %s/%s: %w", namespace, spec.Name, err)
+		}
+		data = []byte(configMap.Data[key])
+	case integreatlyv1alpha1.TrustedCABundleKindSecret:
+		secret := &corev1.Secret{}
+		if err := reader.Get(ctx, k8sclient.ObjectKey{Name: spec.Name, Namespace: namespace}, secret); err != nil {
+			return nil, fmt.Errorf("failed to get trusted CA bundle secret %s/%s: %w", namespace, spec.Name, err)
+		}
+		data = secret.Data[key]
+	default:
+		return nil, fmt.Errorf("unsupported trusted CA bundle kind %q", spec.Kind)
+	}
+	if len(data) == 0 {
+		return nil, fmt.Errorf("trusted CA bundle %s %s/%s has no %s key", spec.Kind, namespace, spec.Name, key)
+	}
+	if len(caBundle) > 0 && caBundle[len(caBundle)-1] != '\n' {
+		caBundle = append(caBundle, '\n')
+	}
+	return append(caBundle, data...), nil
+}
+
+func newTransport(caBundle []byte, insecure bool) (*http.Transport, error) {
+	rootCAs, err := x509.SystemCertPool()
+	if err != nil {
+		rootCAs = x509.NewCertPool()
+	}
+	if len(caBundle) > 0 && !rootCAs.AppendCertsFromPEM(caBundle) {
+		return nil, errors.New("no certificates found in the trusted CA bundle")
+	}
+
+	return &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   10 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		MaxIdleConns:          100,
+		MaxIdleConnsPerHost:   10,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: time.Second,
+		TLSClientConfig: &tls.Config{
+			RootCAs:            rootCAs,
+			MinVersion:         tls.VersionTLS12,
+			InsecureSkipVerify: insecure, // #nosec G402 -- value is read from CR config
+		},
+	}, nil
+}
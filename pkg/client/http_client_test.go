@@ -0,0 +1,197 @@
+package client
+
+import (
+	"context"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/apis/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestHTTPClientFactory(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	serverCA := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}))
+
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name            string
+		objects         []runtime.Object
+		spec            integreatlyv1alpha1.RHMISpec
+		wantConfigErr   bool
+		wantRequestPass bool
+	}{
+		{
+			name:            "test server is not trusted without a CA bundle",
+			wantRequestPass: false,
+		},
+		{
+			name: "test injected trusted CA bundle is trusted",
+			objects: []runtime.Object{
+				&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Name: TrustedCABundleConfigMapName, Namespace: "rhmi"},
+					Data:       map[string]string{DefaultCABundleKey: serverCA},
+				},
+			},
+			wantRequestPass: true,
+		},
+		{
+			name: "test CA bundle from a config map is trusted",
+			objects: []runtime.Object{
+				&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Name: "private-ca", Namespace: "rhmi"},
+					Data:       map[string]string{DefaultCABundleKey: serverCA},
+				},
+			},
+			spec: integreatlyv1alpha1.RHMISpec{
+				TrustedCABundle: &integreatlyv1alpha1.TrustedCABundleSpec{Kind: integreatlyv1alpha1.TrustedCABundleKindConfigMap, Name: "private-ca"},
+			},
+			wantRequestPass: true,
+		},
+		{
+			name: "test CA bundle from a secret is trusted",
+			objects: []runtime.Object{
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Name: "private-ca", Namespace: "ca"},
+					Data:       map[string][]byte{"ca.crt": []byte(serverCA)},
+				},
+			},
+			spec: integreatlyv1alpha1.RHMISpec{
+				TrustedCABundle: &integreatlyv1alpha1.TrustedCABundleSpec{Kind: integreatlyv1alpha1.TrustedCABundleKindSecret, Name: "private-ca", Namespace: "ca", Key: "ca.crt"},
+			},
+			wantRequestPass: true,
+		},
+		{
+			name: "test self signed certs skip verification",
+			spec: integreatlyv1alpha1.RHMISpec{
+				SelfSignedCerts: true,
+			},
+			wantRequestPass: true,
+		},
+		{
+			name: "test missing CA bundle is an error",
+			spec: integreatlyv1alpha1.RHMISpec{
+				TrustedCABundle: &integreatlyv1alpha1.TrustedCABundleSpec{Kind: integreatlyv1alpha1.TrustedCABundleKindConfigMap, Name: "private-ca"},
+			},
+			wantConfigErr: true,
+		},
+		{
+			name: "test CA bundle without certificates is an error",
+			objects: []runtime.Object{
+				&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Name: "private-ca", Namespace: "rhmi"},
+					Data:       map[string]string{DefaultCABundleKey: "not a certificate"},
+				},
+			},
+			spec: integreatlyv1alpha1.RHMISpec{
+				TrustedCABundle: &integreatlyv1alpha1.TrustedCABundleSpec{Kind: integreatlyv1alpha1.TrustedCABundleKindConfigMap, Name: "private-ca"},
+			},
+			wantConfigErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			installation := &integreatlyv1alpha1.RHMI{
+				ObjectMeta: metav1.ObjectMeta{Name: "rhmi", Namespace: "rhmi"},
+				Spec:       tt.spec,
+			}
+			factory := NewHTTPClientFactory()
+			err := factory.Configure(context.TODO(), fake.NewFakeClientWithScheme(scheme, tt.objects...), installation)
+			if (err != nil) != tt.wantConfigErr {
+				t.Fatalf("Configure() error = %v, wantConfigErr %v", err, tt.wantConfigErr)
+			}
+			if tt.wantConfigErr {
+				return
+			}
+
+			resp, err := factory.Client(time.Second * 10).Get(server.URL)
+			if err == nil {
+				resp.Body.Close()
+			}
+			if (err == nil) != tt.wantRequestPass {
+				t.Errorf("expected the request to pass %v but got error %v", tt.wantRequestPass, err)
+			}
+		})
+	}
+}
+
+func TestHTTPClientFactory_KeepsTransport(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	serverClient := fake.NewFakeClientWithScheme(scheme)
+	installation := &integreatlyv1alpha1.RHMI{ObjectMeta: metav1.ObjectMeta{Name: "rhmi", Namespace: "rhmi"}}
+
+	factory := NewHTTPClientFactory()
+	if err := factory.Configure(context.TODO(), serverClient, installation); err != nil {
+		t.Fatal(err)
+	}
+	transport := factory.Transport()
+	if err := factory.Configure(context.TODO(), serverClient, installation); err != nil {
+		t.Fatal(err)
+	}
+	if factory.Transport() != transport {
+		t.Errorf("expected the transport to be kept when the configuration has not changed")
+	}
+
+	installation.Spec.SelfSignedCerts = true
+	if err := factory.Configure(context.TODO(), serverClient, installation); err != nil {
+		t.Fatal(err)
+	}
+	if factory.Transport() == transport {
+		t.Errorf("expected the transport to be replaced when the configuration has changed")
+	}
+}
+
+func TestHTTPClientFactory_VerifiedClient(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	serverCA := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}))
+
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	installation := &integreatlyv1alpha1.RHMI{
+		ObjectMeta: metav1.ObjectMeta{Name: "rhmi", Namespace: "rhmi"},
+		Spec:       integreatlyv1alpha1.RHMISpec{SelfSignedCerts: true},
+	}
+
+	factory := NewHTTPClientFactory()
+	if err := factory.Configure(context.TODO(), fake.NewFakeClientWithScheme(scheme), installation); err != nil {
+		t.Fatal(err)
+	}
+	if resp, err := factory.VerifiedClient(time.Second * 10).Get(server.URL); err == nil {
+		resp.Body.Close()
+		t.Fatalf("expected the verified client to refuse an untrusted server when self signed certs are enabled")
+	}
+
+	trustedCA := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: TrustedCABundleConfigMapName, Namespace: "rhmi"},
+		Data:       map[string]string{DefaultCABundleKey: serverCA},
+	}
+	if err := factory.Configure(context.TODO(), fake.NewFakeClientWithScheme(scheme, trustedCA), installation); err != nil {
+		t.Fatal(err)
+	}
+	resp, err := factory.VerifiedClient(time.Second * 10).Get(server.URL)
+	if err != nil {
+		t.Fatalf("expected the verified client to trust the CA bundle but got %v", err)
+	}
+	resp.Body.Close()
+}
@@ -0,0 +1,37 @@
+package client
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	keycloakCommon "github.com/integr8ly/keycloak-client/pkg/common"
+	keycloak "github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
+)
+
+type keycloakClientFactory struct {
+	factory     keycloakCommon.KeycloakClientFactory
+	httpClients *HTTPClientFactory
+}
+
+// NewKeycloakClientFactory checks the certificate of the keycloak server with a client from
+// httpClients before factory builds a client for it. The keycloak client builds its own transport
+// that does not verify certificates, so the server is only called once it is trusted by the
+// installation
+func NewKeycloakClientFactory(factory keycloakCommon.KeycloakClientFactory, httpClients *HTTPClientFactory) keycloakCommon.KeycloakClientFactory {
+	return &keycloakClientFactory{factory: factory, httpClients: httpClients}
+}
+
+func (f *keycloakClientFactory) AuthenticatedClient(kc keycloak.Keycloak) (keycloakCommon.KeycloakInterface, error) {
+	req, err := http.NewRequest(http.MethodHead, kc.Status.ExternalURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid keycloak URL %q: %w", kc.Status.ExternalURL, err)
+	}
+	resp, err := f.httpClients.Client(time.Second * 10).Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify keycloak server %s: %w", kc.Status.ExternalURL, err)
+	}
+	resp.Body.Close()
+
+	return f.factory.AuthenticatedClient(kc)
+}
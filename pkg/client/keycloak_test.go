@@ -0,0 +1,82 @@
+package client
+
+import (
+	"context"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/apis/v1alpha1"
+	keycloakCommon "github.com/integr8ly/keycloak-client/pkg/common"
+	keycloak "github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+type keycloakClientFactoryStub struct {
+	calls int
+}
+
+func (f *keycloakClientFactoryStub) AuthenticatedClient(_ keycloak.Keycloak) (keycloakCommon.KeycloakInterface, error) {
+	f.calls++
+	return &keycloakCommon.KeycloakInterfaceMock{}, nil
+}
+
+func TestKeycloakClientFactory(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	serverCA := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}))
+
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		objects   []runtime.Object
+		wantErr   bool
+		wantCalls int
+	}{
+		{
+			name:      "test untrusted keycloak server is refused",
+			wantErr:   true,
+			wantCalls: 0,
+		},
+		{
+			name: "test trusted keycloak server gets a client",
+			objects: []runtime.Object{
+				&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Name: TrustedCABundleConfigMapName, Namespace: "rhmi"},
+					Data:       map[string]string{DefaultCABundleKey: serverCA},
+				},
+			},
+			wantCalls: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpClients := NewHTTPClientFactory()
+			installation := &integreatlyv1alpha1.RHMI{ObjectMeta: metav1.ObjectMeta{Name: "rhmi", Namespace: "rhmi"}}
+			if err := httpClients.Configure(context.TODO(), fake.NewFakeClientWithScheme(scheme, tt.objects...), installation); err != nil {
+				t.Fatal(err)
+			}
+			stub := &keycloakClientFactoryStub{}
+
+			_, err := NewKeycloakClientFactory(stub, httpClients).AuthenticatedClient(keycloak.Keycloak{
+				Status: keycloak.KeycloakStatus{ExternalURL: server.URL},
+			})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("AuthenticatedClient() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if stub.calls != tt.wantCalls {
+				t.Errorf("expected %d clients to be built but got %d", tt.wantCalls, stub.calls)
+			}
+		})
+	}
+}
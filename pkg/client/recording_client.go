@@ -0,0 +1,380 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sync"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	"github.com/google/go-cmp/cmp"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"k8s.io/client-go/rest"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+type ChangeAction string
+
+const (
+	ChangeActionCreate       ChangeAction = "create"
+	ChangeActionUpdate       ChangeAction = "update"
+	ChangeActionPatch        ChangeAction = "patch"
+	ChangeActionDelete       ChangeAction = "delete"
+	ChangeActionDeleteAllOf  ChangeAction = "delete all of"
+	ChangeActionStatusUpdate ChangeAction = "status update"
+	ChangeActionStatusPatch  ChangeAction = "status patch"
+)
+
+// Change is a write that a RecordingClient received instead of sending it to the cluster
+type Change struct {
+	// Owner is the name given to the client that received the change, e.g. a product name
+	Owner     string
+	Action    ChangeAction
+	Kind      schema.GroupVersionKind
+	Namespace string
+	Name      string
+	// Diff is the difference between the object in the cluster and the object that would have been
+	// written. It is empty for deletes of a whole collection
+	Diff string
+}
+
+func (c Change) String() string {
+	return fmt.Sprintf("%s %s %s/%s", c.Action, c.Kind.Kind, c.Namespace, c.Name)
+}
+
+// RecordingClient reads from the wrapped client but records every write instead of sending it.
+// Objects that were written are returned by later calls to Get, so a reconciler that creates an
+// object and reads it back sees the same object it would on the cluster. List is not affected by
+// the recorded writes
+type RecordingClient struct {
+	reader k8sclient.Client
+	scheme *runtime.Scheme
+	owner  string
+	state  *recordingState
+}
+
+type recordingState struct {
+	lock    sync.Mutex
+	changes []Change
+	objects map[recordingKey]*recordedObject
+}
+
+type recordingKey struct {
+	gvk       schema.GroupVersionKind
+	namespace string
+	name      string
+}
+
+type recordedObject struct {
+	data    []byte
+	deleted bool
+}
+
+var _ k8sclient.Client = &RecordingClient{}
+
+func NewRecordingClient(reader k8sclient.Client, scheme *runtime.Scheme) *RecordingClient {
+	return &RecordingClient{
+		reader: reader,
+		scheme: scheme,
+		state: &recordingState{
+			objects: map[recordingKey]*recordedObject{},
+		},
+	}
+}
+
+// WithOwner returns a client that shares the recorded objects and changes of this client, changes
+// made through it are recorded with the given owner
+func (c *RecordingClient) WithOwner(owner string) *RecordingClient {
+	return &RecordingClient{
+		reader: c.reader,
+		scheme: c.scheme,
+		owner:  owner,
+		state:  c.state,
+	}
+}
+
+// Changes returns the changes recorded by this client and every client created from it with
+// WithOwner, in the order they were made
+func (c *RecordingClient) Changes() []Change {
+	c.state.lock.Lock()
+	defer c.state.lock.Unlock()
+
+	changes := make([]Change, len(c.state.changes))
+	copy(changes, c.state.changes)
+	return changes
+}
+
+func (c *RecordingClient) Get(ctx context.Context, key k8sclient.ObjectKey, obj runtime.Object) error {
+	recordKey, err := c.key(obj, key.Namespace, key.Name)
+	if err != nil {
+		return err
+	}
+
+	c.state.lock.Lock()
+	recorded, ok := c.state.objects[recordKey]
+	c.state.lock.Unlock()
+	if !ok {
+		return c.reader.Get(ctx, key, obj)
+	}
+	if recorded.deleted {
+		return k8serr.NewNotFound(schema.GroupResource{Group: recordKey.gvk.Group, Resource: recordKey.gvk.Kind}, key.Name)
+	}
+	return json.Unmarshal(recorded.data, obj)
+}
+
+func (c *RecordingClient) List(ctx context.Context, list runtime.Object, opts ...k8sclient.ListOption) error {
+	return c.reader.List(ctx, list, opts...)
+}
+
+func (c *RecordingClient) Create(ctx context.Context, obj runtime.Object, _ ...k8sclient.CreateOption) error {
+	recordKey, current, err := c.current(ctx, obj)
+	if err != nil && !k8serr.IsNotFound(err) {
+		return err
+	}
+	if current != nil {
+		return k8serr.NewAlreadyExists(schema.GroupResource{Group: recordKey.gvk.Group, Resource: recordKey.gvk.Kind}, recordKey.name)
+	}
+	return c.record(recordKey, ChangeActionCreate, nil, obj, false)
+}
+
+func (c *RecordingClient) Update(ctx context.Context, obj runtime.Object, _ ...k8sclient.UpdateOption) error {
+	return c.write(ctx, obj, ChangeActionUpdate)
+}
+
+func (c *RecordingClient) Patch(ctx context.Context, obj runtime.Object, patch k8sclient.Patch, _ ...k8sclient.PatchOption) error {
+	return c.patch(ctx, obj, patch, ChangeActionPatch)
+}
+
+func (c *RecordingClient) Delete(ctx context.Context, obj runtime.Object, _ ...k8sclient.DeleteOption) error {
+	recordKey, current, err := c.current(ctx, obj)
+	if err != nil {
+		return err
+	}
+	return c.record(recordKey, ChangeActionDelete, current, nil, true)
+}
+
+func (c *RecordingClient) DeleteAllOf(_ context.Context, obj runtime.Object, opts ...k8sclient.DeleteAllOfOption) error {
+	deleteOpts := &k8sclient.DeleteAllOfOptions{}
+	deleteOpts.ApplyOptions(opts)
+
+	recordKey, err := c.key(obj, deleteOpts.Namespace, "")
+	if err != nil {
+		return err
+	}
+
+	c.state.lock.Lock()
+	defer c.state.lock.Unlock()
+	c.state.changes = append(c.state.changes, Change{
+		Owner:     c.owner,
+		Action:    ChangeActionDeleteAllOf,
+		Kind:      recordKey.gvk,
+		Namespace: recordKey.namespace,
+	})
+	return nil
+}
+
+func (c *RecordingClient) Status() k8sclient.StatusWriter {
+	return &recordingStatusWriter{client: c}
+}
+
+type recordingStatusWriter struct {
+	client *RecordingClient
+}
+
+func (w *recordingStatusWriter) Update(ctx context.Context, obj runtime.Object, _ ...k8sclient.UpdateOption) error {
+	return w.client.write(ctx, obj, ChangeActionStatusUpdate)
+}
+
+func (w *recordingStatusWriter) Patch(ctx context.Context, obj runtime.Object, patch k8sclient.Patch, _ ...k8sclient.PatchOption) error {
+	return w.client.patch(ctx, obj, patch, ChangeActionStatusPatch)
+}
+
+// write records a change to an object that must already exist. Writes that would not change the
+// object are not recorded
+func (c *RecordingClient) write(ctx context.Context, obj runtime.Object, action ChangeAction) error {
+	recordKey, current, err := c.current(ctx, obj)
+	if err != nil {
+		return err
+	}
+	return c.record(recordKey, action, current, obj, false)
+}
+
+// patch records a patch of an object that must already exist. The patch is applied to the object
+// as the reconciler would see it and obj is set to the result, as it is by the API server
+func (c *RecordingClient) patch(ctx context.Context, obj runtime.Object, patch k8sclient.Patch, action ChangeAction) error {
+	recordKey, current, err := c.current(ctx, obj)
+	if err != nil {
+		return err
+	}
+	if err := c.applyPatch(recordKey, current, obj, patch); err != nil {
+		return err
+	}
+	return c.record(recordKey, action, current, obj, false)
+}
+
+// applyPatch sets obj to the result of applying the patch to current. Apply patches need the field
+// management of the API server, so obj is taken to hold the result of those
+func (c *RecordingClient) applyPatch(recordKey recordingKey, current map[string]interface{}, obj runtime.Object, patch k8sclient.Patch) error {
+	data, err := patch.Data(obj)
+	if err != nil {
+		return err
+	}
+	currentJSON, err := json.Marshal(current)
+	if err != nil {
+		return err
+	}
+
+	var patched []byte
+	switch patch.Type() {
+	case types.JSONPatchType:
+		jsonPatch, err := jsonpatch.DecodePatch(data)
+		if err != nil {
+			return k8serr.NewBadRequest(fmt.Sprintf("invalid json patch: %v", err))
+		}
+		if patched, err = jsonPatch.Apply(currentJSON); err != nil {
+			return k8serr.NewBadRequest(fmt.Sprintf("failed to apply json patch: %v", err))
+		}
+	case types.MergePatchType:
+		if patched, err = jsonpatch.MergePatch(currentJSON, data); err != nil {
+			return k8serr.NewBadRequest(fmt.Sprintf("failed to apply merge patch: %v", err))
+		}
+	case types.StrategicMergePatchType:
+		// only the built in types have a strategy, the API server does not accept strategic merge
+		// patches of custom resources so they are applied as merge patches
+		dataStruct, err := c.scheme.New(recordKey.gvk)
+		if err != nil {
+			if patched, err = jsonpatch.MergePatch(currentJSON, data); err != nil {
+				return k8serr.NewBadRequest(fmt.Sprintf("failed to apply merge patch: %v", err))
+			}
+			break
+		}
+		if patched, err = strategicpatch.StrategicMergePatch(currentJSON, data, dataStruct); err != nil {
+			return k8serr.NewBadRequest(fmt.Sprintf("failed to apply strategic merge patch: %v", err))
+		}
+	default:
+		return nil
+	}
+
+	// the patched object replaces obj, fields removed by the patch must not be left in it
+	value := reflect.ValueOf(obj).Elem()
+	value.Set(reflect.Zero(value.Type()))
+	return json.Unmarshal(patched, obj)
+}
+
+// current returns the object as the reconciler would see it, either the last recorded write or the
+// object in the cluster
+func (c *RecordingClient) current(ctx context.Context, obj runtime.Object) (recordingKey, map[string]interface{}, error) {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return recordingKey{}, nil, err
+	}
+	recordKey, err := c.key(obj, accessor.GetNamespace(), accessor.GetName())
+	if err != nil {
+		return recordingKey{}, nil, err
+	}
+
+	current, err := c.scheme.New(recordKey.gvk)
+	if err != nil {
+		current = obj.DeepCopyObject()
+	}
+	if err := c.Get(ctx, k8sclient.ObjectKey{Namespace: recordKey.namespace, Name: recordKey.name}, current); err != nil {
+		return recordKey, nil, err
+	}
+	currentContent, err := runtime.DefaultUnstructuredConverter.ToUnstructured(current)
+	if err != nil {
+		return recordKey, nil, err
+	}
+	return recordKey, currentContent, nil
+}
+
+func (c *RecordingClient) record(recordKey recordingKey, action ChangeAction, current map[string]interface{}, desired runtime.Object, deleted bool) error {
+	recorded := &recordedObject{deleted: deleted}
+	var desiredContent map[string]interface{}
+	if desired != nil {
+		var err error
+		if desiredContent, err = runtime.DefaultUnstructuredConverter.ToUnstructured(desired); err != nil {
+			return err
+		}
+		if recorded.data, err = json.Marshal(desired); err != nil {
+			return err
+		}
+	}
+
+	diff := cmp.Diff(withoutServerFields(current, action), withoutServerFields(desiredContent, action))
+	if diff == "" {
+		return nil
+	}
+
+	c.state.lock.Lock()
+	defer c.state.lock.Unlock()
+	c.state.objects[recordKey] = recorded
+	c.state.changes = append(c.state.changes, Change{
+		Owner:     c.owner,
+		Action:    action,
+		Kind:      recordKey.gvk,
+		Namespace: recordKey.namespace,
+		Name:      recordKey.name,
+		Diff:      diff,
+	})
+	return nil
+}
+
+func (c *RecordingClient) key(obj runtime.Object, namespace, name string) (recordingKey, error) {
+	gvk, err := apiutil.GVKForObject(obj, c.scheme)
+	if err != nil {
+		return recordingKey{}, err
+	}
+	return recordingKey{gvk: gvk, namespace: namespace, name: name}, nil
+}
+
+// withoutServerFields removes the fields set by the API server so they do not show up in diffs.
+// Only status writes can change the status of an object
+func withoutServerFields(content map[string]interface{}, action ChangeAction) map[string]interface{} {
+	if content == nil {
+		return nil
+	}
+	content = runtime.DeepCopyJSON(content)
+	if metadata, ok := content["metadata"].(map[string]interface{}); ok {
+		for _, field := range []string{"resourceVersion", "uid", "generation", "creationTimestamp", "selfLink", "managedFields"} {
+			delete(metadata, field)
+		}
+	}
+	if action != ChangeActionStatusUpdate && action != ChangeActionStatusPatch {
+		delete(content, "status")
+	}
+	return content
+}
+
+// NewReadOnlyConfig returns a copy of the rest config whose clients fail any request that could
+// change the cluster
+func NewReadOnlyConfig(rc *rest.Config) *rest.Config {
+	readOnly := rest.CopyConfig(rc)
+	readOnly.Wrap(NewReadOnlyRoundTripper)
+	return readOnly
+}
+
+// NewReadOnlyRoundTripper returns a round tripper that only lets GET, HEAD and OPTIONS requests
+// through
+func NewReadOnlyRoundTripper(rt http.RoundTripper) http.RoundTripper {
+	return readOnlyRoundTripper{next: rt}
+}
+
+type readOnlyRoundTripper struct {
+	next http.RoundTripper
+}
+
+func (rt readOnlyRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return rt.next.RoundTrip(req)
+	}
+	return nil, fmt.Errorf("%s %s is not allowed by a read only client", req.Method, req.URL)
+}
@@ -0,0 +1,235 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+func TestRecordingClient(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	existing := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "existing", Namespace: "ns"},
+		Data:       map[string]string{"key": "old"},
+	}
+	removed := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "removed", Namespace: "ns"},
+	}
+	fakeClient := fake.NewFakeClientWithScheme(scheme, existing.DeepCopy(), removed.DeepCopy())
+	recorder := NewRecordingClient(fakeClient, scheme)
+	ctx := context.TODO()
+
+	// update through the usual create or update helper
+	cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "existing", Namespace: "ns"}}
+	if _, err := controllerutil.CreateOrUpdate(ctx, recorder.WithOwner("3scale"), cm, func() error {
+		cm.Data = map[string]string{"key": "new"}
+		return nil
+	}); err != nil {
+		t.Fatalf("unexpected error updating config map: %v", err)
+	}
+
+	// create, the created object is returned by get
+	created := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "created", Namespace: "ns"},
+		Data:       map[string]string{"key": "value"},
+	}
+	if err := recorder.WithOwner("rhsso").Create(ctx, created); err != nil {
+		t.Fatalf("unexpected error creating config map: %v", err)
+	}
+	got := &corev1.ConfigMap{}
+	if err := recorder.Get(ctx, k8sclient.ObjectKey{Name: "created", Namespace: "ns"}, got); err != nil {
+		t.Fatalf("expected the created config map to be returned: %v", err)
+	}
+	if got.Data["key"] != "value" {
+		t.Fatalf("unexpected data in created config map: %v", got.Data)
+	}
+	if err := recorder.Create(ctx, created.DeepCopy()); !k8serr.IsAlreadyExists(err) {
+		t.Fatalf("expected an already exists error but got %v", err)
+	}
+
+	// update that does not change the object is not recorded
+	if err := recorder.Update(ctx, got); err != nil {
+		t.Fatalf("unexpected error updating config map: %v", err)
+	}
+
+	// delete, the deleted object is no longer returned by get
+	if err := recorder.WithOwner("rhsso").Delete(ctx, removed.DeepCopy()); err != nil {
+		t.Fatalf("unexpected error deleting secret: %v", err)
+	}
+	if err := recorder.Get(ctx, k8sclient.ObjectKey{Name: "removed", Namespace: "ns"}, &corev1.Secret{}); !k8serr.IsNotFound(err) {
+		t.Fatalf("expected a not found error for the deleted secret but got %v", err)
+	}
+	if err := recorder.Delete(ctx, removed.DeepCopy()); !k8serr.IsNotFound(err) {
+		t.Fatalf("expected a not found error deleting the secret again but got %v", err)
+	}
+
+	changes := recorder.Changes()
+	wantChanges := []struct {
+		owner  string
+		action ChangeAction
+		name   string
+		diff   string
+	}{
+		{owner: "3scale", action: ChangeActionUpdate, name: "existing", diff: `"new"`},
+		{owner: "rhsso", action: ChangeActionCreate, name: "created", diff: `"value"`},
+		{owner: "rhsso", action: ChangeActionDelete, name: "removed"},
+	}
+	if len(changes) != len(wantChanges) {
+		t.Fatalf("expected %d changes but got %d: %v", len(wantChanges), len(changes), changes)
+	}
+	for i, want := range wantChanges {
+		change := changes[i]
+		if change.Owner != want.owner || change.Action != want.action || change.Name != want.name {
+			t.Errorf("unexpected change %d: %s by %s", i, change, change.Owner)
+		}
+		if !strings.Contains(change.Diff, want.diff) {
+			t.Errorf("expected the diff of change %d to contain %s but got %s", i, want.diff, change.Diff)
+		}
+		if resourceVersion := `"resourceVersion"`; strings.Contains(change.Diff, resourceVersion) {
+			t.Errorf("expected server fields to be left out of the diff of change %d", i)
+		}
+	}
+
+	// nothing is written to the cluster
+	cluster := &corev1.ConfigMap{}
+	if err := fakeClient.Get(ctx, k8sclient.ObjectKey{Name: "existing", Namespace: "ns"}, cluster); err != nil {
+		t.Fatal(err)
+	}
+	if cluster.Data["key"] != "old" {
+		t.Errorf("expected the config map in the cluster not to change but got %v", cluster.Data)
+	}
+	if err := fakeClient.Get(ctx, k8sclient.ObjectKey{Name: "created", Namespace: "ns"}, &corev1.ConfigMap{}); !k8serr.IsNotFound(err) {
+		t.Errorf("expected the config map not to be created in the cluster but got %v", err)
+	}
+	if err := fakeClient.Get(ctx, k8sclient.ObjectKey{Name: "removed", Namespace: "ns"}, &corev1.Secret{}); err != nil {
+		t.Errorf("expected the secret to remain in the cluster but got %v", err)
+	}
+}
+
+func TestRecordingClientPatch(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		patch     func(cm *corev1.ConfigMap) k8sclient.Patch
+		wantData  map[string]string
+		wantDiff  string
+		unchanged string
+	}{
+		{
+			name: "test json patch is applied to the current object",
+			patch: func(_ *corev1.ConfigMap) k8sclient.Patch {
+				return k8sclient.RawPatch(types.JSONPatchType, []byte(`[{"op":"add","path":"/data/added","value":"patched"}]`))
+			},
+			wantData:  map[string]string{"key": "old", "other": "old", "added": "patched"},
+			wantDiff:  `"patched"`,
+			unchanged: `"other"`,
+		},
+		{
+			name: "test strategic merge patch is applied to the current object",
+			patch: func(_ *corev1.ConfigMap) k8sclient.Patch {
+				return k8sclient.RawPatch(types.StrategicMergePatchType, []byte(`{"data":{"key":"patched"}}`))
+			},
+			wantData:  map[string]string{"key": "patched", "other": "old"},
+			wantDiff:  `"patched"`,
+			unchanged: `"other"`,
+		},
+		{
+			name: "test merge patch from a stale object only changes the patched fields",
+			patch: func(cm *corev1.ConfigMap) k8sclient.Patch {
+				// the reconciler read the config map before other was added to it
+				base := cm.DeepCopy()
+				base.Data = map[string]string{"key": "old"}
+				cm.Data = map[string]string{"key": "patched"}
+				return k8sclient.MergeFrom(base)
+			},
+			wantData:  map[string]string{"key": "patched", "other": "old"},
+			wantDiff:  `"patched"`,
+			unchanged: `"other"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			existing := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "existing", Namespace: "ns"},
+				Data:       map[string]string{"key": "old", "other": "old"},
+			}
+			recorder := NewRecordingClient(fake.NewFakeClientWithScheme(scheme, existing.DeepCopy()), scheme)
+			ctx := context.TODO()
+
+			cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "existing", Namespace: "ns"}}
+			if err := recorder.Patch(ctx, cm, tt.patch(cm)); err != nil {
+				t.Fatalf("unexpected error patching config map: %v", err)
+			}
+			if !reflect.DeepEqual(cm.Data, tt.wantData) {
+				t.Errorf("expected the patched object to have data %v but got %v", tt.wantData, cm.Data)
+			}
+
+			got := &corev1.ConfigMap{}
+			if err := recorder.Get(ctx, k8sclient.ObjectKey{Name: "existing", Namespace: "ns"}, got); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got.Data, tt.wantData) {
+				t.Errorf("expected the recorded object to have data %v but got %v", tt.wantData, got.Data)
+			}
+
+			changes := recorder.Changes()
+			if len(changes) != 1 || changes[0].Action != ChangeActionPatch {
+				t.Fatalf("expected a single patch change but got %v", changes)
+			}
+			if !strings.Contains(changes[0].Diff, tt.wantDiff) {
+				t.Errorf("expected the diff to contain %s but got %s", tt.wantDiff, changes[0].Diff)
+			}
+			for _, line := range strings.Split(changes[0].Diff, "\n") {
+				if strings.Contains(line, tt.unchanged) && (strings.HasPrefix(strings.TrimSpace(line), "-") || strings.HasPrefix(strings.TrimSpace(line), "+")) {
+					t.Errorf("expected %s to be left out of the diff but got %s", tt.unchanged, changes[0].Diff)
+				}
+			}
+		})
+	}
+}
+
+func TestReadOnlyRoundTripper(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	httpClient := &http.Client{Transport: NewReadOnlyRoundTripper(http.DefaultTransport)}
+
+	resp, err := httpClient.Get(server.URL)
+	if err != nil {
+		t.Fatalf("expected get requests to be allowed: %v", err)
+	}
+	resp.Body.Close()
+
+	for _, method := range []string{http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete} {
+		req, err := http.NewRequest(method, server.URL, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp, err := httpClient.Do(req); err == nil {
+			resp.Body.Close()
+			t.Errorf("expected %s requests to be refused", method)
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"sync"
 
 	"k8s.io/apimachinery/pkg/runtime"
 
@@ -79,37 +80,43 @@ type ConfigReadable interface {
 	GetNamespace() string
 }
 
+// Manager is safe for concurrent use, products in the same stage are reconciled in parallel and
+// share a single Manager
 type Manager struct {
 	Client       k8sclient.Client
 	Namespace    string
 	cfgmap       *corev1.ConfigMap
 	context      context.Context
 	installation *integreatlyv1alpha1.RHMI
+
+	lock sync.RWMutex
+}
+
+var (
+	productConfigs     = map[integreatlyv1alpha1.ProductName]func(ProductConfig) ConfigReadable{}
+	productConfigsLock sync.RWMutex
+)
+
+// RegisterProductConfig sets the config type returned by ReadProduct for the product
+func RegisterProductConfig(product integreatlyv1alpha1.ProductName, newConfig func(ProductConfig) ConfigReadable) {
+	productConfigsLock.Lock()
+	defer productConfigsLock.Unlock()
+	productConfigs[product] = newConfig
 }
 
 func (m *Manager) ReadProduct(product integreatlyv1alpha1.ProductName) (ConfigReadable, error) {
-	switch product {
-	case integreatlyv1alpha1.Product3Scale:
-		return m.ReadThreeScale()
-	case integreatlyv1alpha1.ProductRHSSO:
-		return m.ReadRHSSO()
-	case integreatlyv1alpha1.ProductRHSSOUser:
-		return m.ReadRHSSOUser()
-	case integreatlyv1alpha1.ProductCloudResources:
-		return m.ReadCloudResources()
-	case integreatlyv1alpha1.ProductMonitoring:
-		return m.ReadMonitoring()
-	case integreatlyv1alpha1.ProductMonitoringSpec:
-		return m.ReadMonitoringSpec()
-	case integreatlyv1alpha1.ProductMarin3r:
-		return m.ReadMarin3r()
-	case integreatlyv1alpha1.ProductGrafana:
-		return m.ReadGrafana()
-	case integreatlyv1alpha1.ProductObservability:
-		return m.ReadObservability()
-	}
-
-	return nil, fmt.Errorf("no config found for product %v", product)
+	productConfigsLock.RLock()
+	newConfig, ok := productConfigs[product]
+	productConfigsLock.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("no config found for product %v", product)
+	}
+
+	config, err := m.readConfigForProduct(product)
+	if err != nil {
+		return nil, err
+	}
+	return newConfig(config), nil
 }
 
 func (m *Manager) GetOperatorNamespace() string {
@@ -203,6 +210,13 @@ func (m *Manager) ReadObservability() (*Observability, error) {
 
 func (m *Manager) WriteConfig(config ConfigReadable) error {
 	stringConfig, err := yaml.Marshal(config.Read())
+	if err != nil {
+		return err
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
 	err = m.Client.Get(m.context, k8sclient.ObjectKey{Name: m.cfgmap.Name, Namespace: m.Namespace}, m.cfgmap)
 	if errors.IsNotFound(err) {
 		m.cfgmap.Data = map[string]string{string(config.GetProductName()): string(stringConfig)}
@@ -216,7 +230,10 @@ func (m *Manager) WriteConfig(config ConfigReadable) error {
 }
 
 func (m *Manager) readConfigForProduct(product integreatlyv1alpha1.ProductName) (ProductConfig, error) {
+	m.lock.RLock()
 	config := m.cfgmap.Data[string(product)]
+	m.lock.RUnlock()
+
 	decoder := yaml.NewDecoder(strings.NewReader(config))
 	retConfig := ProductConfig{}
 	if config == "" {
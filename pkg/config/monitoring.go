@@ -33,6 +33,7 @@ var rhmiTemplateList = []string{
 	"critical-slo-rhmi-alerts",
 	"cro-resources",
 	"rhoam-rhsso-availability-slo",
+	"product-reconcile",
 }
 
 var managedAPITemplateList = []string{
@@ -45,6 +46,7 @@ var managedAPITemplateList = []string{
 	"critical-slo-managed-api-alerts",
 	"cro-resources",
 	"rhoam-rhsso-availability-slo",
+	"product-reconcile",
 }
 
 var multitenantManagedAPITemplateList = []string{
@@ -58,6 +60,7 @@ var multitenantManagedAPITemplateList = []string{
 	"cro-resources",
 	"rhoam-rhsso-availability-slo",
 	"multitenancy-detailed",
+	"product-reconcile",
 }
 
 func NewMonitoring(config ProductConfig) *Monitoring {
@@ -11,6 +11,8 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
 	"strconv"
+	"sync"
+	"time"
 )
 
 // Custom metrics
@@ -137,6 +139,17 @@ var (
 		},
 	)
 
+	QuotaRollbacks = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "rhoam_quota_rollbacks_total",
+			Help: "Count of the quota changes rolled back because a product did not become ready, by the quota changed from and to",
+		},
+		[]string{
+			"quota",
+			"toQuota",
+		},
+	)
+
 	CustomDomain = prometheus.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Name: "rhoam_custom_domain",
@@ -169,6 +182,7 @@ var (
 			"tenantNamespace",
 			"provisioningStatus",
 			"lastError",
+			"suspended",
 		},
 	)
 
@@ -182,6 +196,148 @@ var (
 		},
 	)
 
+	ProductReconcileErrors = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "rhoam_product_reconcile_errors_total",
+			Help: "Count of errors returned by each product reconciler, by product and reason",
+		},
+		[]string{
+			"product",
+			"reason",
+		},
+	)
+
+	ProductPausedSince = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "rhoam_product_paused_since_timestamp_seconds",
+			Help: "Unix time each paused product was paused at, only paused products are reported",
+		},
+		[]string{
+			"product",
+		},
+	)
+
+	ProductReconcileDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "rhoam_product_reconcile_duration_seconds",
+			Help:    "Duration of each product reconcile, by product and result. The result is the phase returned by the reconcile, or error when it returned an error",
+			Buckets: []float64{0.1, 0.5, 1, 2.5, 5, 10, 30, 60, 120, 300},
+		},
+		[]string{
+			"product",
+			"result",
+		},
+	)
+
+	ProductPhaseTransitions = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "rhoam_product_phase_transitions_total",
+			Help: "Count of the phase changes of each product, by product and the phases changed from and to",
+		},
+		[]string{
+			"product",
+			"from_phase",
+			"to_phase",
+		},
+	)
+
+	ProductPhaseDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "rhoam_product_phase_duration_seconds",
+			Help:    "Time each product spent in a phase before changing to another phase, by product and phase",
+			Buckets: []float64{10, 30, 60, 300, 600, 1800, 3600, 7200, 14400},
+		},
+		[]string{
+			"product",
+			"phase",
+		},
+	)
+
+	ProductLastCompleted = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "rhoam_product_last_completed_timestamp_seconds",
+			Help: "Unix time of the last reconcile of each product that returned the completed phase",
+		},
+		[]string{
+			"product",
+		},
+	)
+
+	ProductFirstReconciled = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "rhoam_product_first_reconciled_timestamp_seconds",
+			Help: "Unix time of the first reconcile of each product since the operator started",
+		},
+		[]string{
+			"product",
+		},
+	)
+
+	RateLimitUsage = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "rhoam_rate_limit_usage_requests",
+			Help: "Requests counted in the current window of each rate limit, by scope and tenant. The scope is global for the limit shared by every request, or tenant for the limit of each tenant",
+		},
+		[]string{
+			"scope",
+			"tenant",
+		},
+	)
+
+	RateLimitUsageLimit = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "rhoam_rate_limit_usage_limit_requests",
+			Help: "Requests allowed in each window of each rate limit, by scope and tenant",
+		},
+		[]string{
+			"scope",
+			"tenant",
+		},
+	)
+
+	RateLimitExemptRequests = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "rhoam_rate_limit_exempt_requests",
+			Help: "Requests exempt from the rate limit counted in the current window of the global limit, by exemption",
+		},
+		[]string{
+			"exemption",
+		},
+	)
+
+	TenantQuotaUsage = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "rhoam_tenant_quota_usage",
+			Help: "Objects in the 3scale account of each tenant with a quota, by kind of object",
+		},
+		[]string{
+			"tenant",
+			"kind",
+		},
+	)
+
+	TenantQuotaLimit = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "rhoam_tenant_quota_limit",
+			Help: "Objects allowed in the 3scale account of each tenant by its quota, by kind of object. Kinds without a cap are not reported",
+		},
+		[]string{
+			"tenant",
+			"kind",
+		},
+	)
+
+	TenantOverQuota = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "rhoam_tenant_over_quota",
+			Help: "1 when the 3scale account of a tenant has more objects of a kind than its quota allows, otherwise 0",
+		},
+		[]string{
+			"tenant",
+			"kind",
+		},
+	)
+
 	InstallationControllerReconcileDelayed = prometheus.NewGauge(
 		prometheus.GaugeOpts{
 			Name: "installation_controller_reconcile_delayed",
@@ -190,6 +346,19 @@ var (
 	)
 )
 
+// productPhases holds the phase of each product and the time it changed to it, and the products
+// that have been reconciled. Products are reconciled concurrently so it is guarded by a lock
+var productPhases = struct {
+	sync.Mutex
+	since      map[string]time.Time
+	reconciled map[string]bool
+}{since: map[string]time.Time{}, reconciled: map[string]bool{}}
+
+const (
+	reconcileResultError = "error"
+	phaseNone            = "none"
+)
+
 const (
 	LabelActive          = "active"
 	LabelSystemMaster    = "system_master"
@@ -262,18 +431,120 @@ func SetTenantsSummary(tenants *integreatlyv1alpha1.APIManagementTenantList) {
 			"tenantNamespace":    tenant.Namespace,
 			"provisioningStatus": string(tenant.Status.ProvisioningStatus),
 			"lastError":          tenant.Status.LastError,
+			"suspended":          strconv.FormatBool(tenant.Spec.Suspended),
 		}).Set(float64(tenant.CreationTimestamp.Unix()))
 	}
 }
 
-func ResetNoActivated3ScaleTenantAccount() {
-	NoActivated3ScaleTenantAccount.Reset()
+func DeleteNoActivated3ScaleTenantAccount(username string) {
+	NoActivated3ScaleTenantAccount.DeleteLabelValues(username)
 }
 
 func SetNoActivated3ScaleTenantAccount(username string) {
 	NoActivated3ScaleTenantAccount.WithLabelValues(username).Set(float64(1))
 }
 
+// SetTenantQuota reports the number of objects of kind in the 3scale account of tenant and its cap,
+// limit is nil when kind is not capped
+func SetTenantQuota(tenant, kind string, limit *int32, count int32) {
+	TenantQuotaUsage.WithLabelValues(tenant, kind).Set(float64(count))
+	if limit == nil {
+		TenantQuotaLimit.DeleteLabelValues(tenant, kind)
+		TenantOverQuota.DeleteLabelValues(tenant, kind)
+		return
+	}
+	TenantQuotaLimit.WithLabelValues(tenant, kind).Set(float64(*limit))
+	overQuota := 0
+	if count > *limit {
+		overQuota = 1
+	}
+	TenantOverQuota.WithLabelValues(tenant, kind).Set(float64(overQuota))
+}
+
+func DeleteTenantQuota(tenant, kind string) {
+	TenantQuotaUsage.DeleteLabelValues(tenant, kind)
+	TenantQuotaLimit.DeleteLabelValues(tenant, kind)
+	TenantOverQuota.DeleteLabelValues(tenant, kind)
+}
+
+func IncProductReconcileErrors(product string, reason string) {
+	ProductReconcileErrors.WithLabelValues(product, reason).Inc()
+}
+
+// ObserveProductReconcile records the duration and the result of a product reconcile, and the
+// phase change it made. The time spent in the previous phase is only recorded when the change to
+// it was observed, it is not known for the first change seen after the operator starts
+func ObserveProductReconcile(product string, from, to integreatlyv1alpha1.StatusPhase, duration time.Duration, reconcileErr error, now time.Time) {
+	result := phaseLabel(to)
+	if reconcileErr != nil {
+		result = reconcileResultError
+	}
+	ProductReconcileDuration.WithLabelValues(product, result).Observe(duration.Seconds())
+
+	if to == integreatlyv1alpha1.PhaseCompleted {
+		ProductLastCompleted.WithLabelValues(product).Set(float64(now.Unix()))
+	}
+
+	productPhases.Lock()
+	defer productPhases.Unlock()
+	if !productPhases.reconciled[product] {
+		productPhases.reconciled[product] = true
+		ProductFirstReconciled.WithLabelValues(product).Set(float64(now.Unix()))
+	}
+	if from == to {
+		return
+	}
+	ProductPhaseTransitions.WithLabelValues(product, phaseLabel(from), phaseLabel(to)).Inc()
+	if since, ok := productPhases.since[product]; ok {
+		ProductPhaseDuration.WithLabelValues(product, phaseLabel(from)).Observe(now.Sub(since).Seconds())
+	}
+	productPhases.since[product] = now
+}
+
+func phaseLabel(phase integreatlyv1alpha1.StatusPhase) string {
+	if phase == integreatlyv1alpha1.PhaseNone {
+		return phaseNone
+	}
+	return string(phase)
+}
+
+// SetPausedProducts exposes the time each paused product in the installation status was paused at
+func SetPausedProducts(installation *integreatlyv1alpha1.RHMI) {
+	ProductPausedSince.Reset()
+	for _, stage := range installation.Status.Stages {
+		for _, product := range stage.Products {
+			if product.Paused && product.PausedSince != nil {
+				ProductPausedSince.WithLabelValues(string(product.Name)).Set(float64(product.PausedSince.Unix()))
+			}
+		}
+	}
+}
+
+// SetRateLimitUsage replaces the reported usage of the rate limits, tenants that are not in tenants
+// are no longer reported
+func SetRateLimitUsage(global *integreatlyv1alpha1.RateLimitUsage, tenants map[string]integreatlyv1alpha1.RateLimitUsage) {
+	RateLimitUsage.Reset()
+	RateLimitUsageLimit.Reset()
+
+	if global != nil {
+		RateLimitUsage.WithLabelValues("global", "").Set(float64(global.Requests))
+		RateLimitUsageLimit.WithLabelValues("global", "").Set(float64(global.RequestsPerUnit))
+	}
+	for tenant, usage := range tenants {
+		RateLimitUsage.WithLabelValues("tenant", tenant).Set(float64(usage.Requests))
+		RateLimitUsageLimit.WithLabelValues("tenant", tenant).Set(float64(usage.RequestsPerUnit))
+	}
+}
+
+// SetRateLimitExemptRequests replaces the reported requests of the exemptions from the rate limit
+func SetRateLimitExemptRequests(exemptions map[string]uint32) {
+	RateLimitExemptRequests.Reset()
+
+	for exemption, requests := range exemptions {
+		RateLimitExemptRequests.WithLabelValues(exemption).Set(float64(requests))
+	}
+}
+
 func SetQuota(quota string, toQuota string) {
 	Quota.Reset()
 	Quota.WithLabelValues(quota, toQuota).Set(float64(1))
@@ -292,6 +563,10 @@ func GetContainerCPUMetric(ctx context.Context, serverClient k8sclient.Client, l
 	}
 }
 
+func IncQuotaRollbacks(quota string, toQuota string) {
+	QuotaRollbacks.WithLabelValues(quota, toQuota).Inc()
+}
+
 func SetCustomDomain(active bool, value float64) {
 	labels := prometheus.Labels{LabelActive: strconv.FormatBool(active)}
 	CustomDomain.Reset()
@@ -2,16 +2,19 @@ package metrics
 
 import (
 	"context"
+	"errors"
 	"github.com/integr8ly/integreatly-operator/apis/v1alpha1"
 	l "github.com/integr8ly/integreatly-operator/pkg/resources/logger"
 	configv1 "github.com/openshift/api/config/v1"
 	userv1 "github.com/openshift/api/user/v1"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"reflect"
 	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"testing"
+	"time"
 )
 
 func TestGetContainerCPUMetric(t *testing.T) {
@@ -205,3 +208,120 @@ func Test_GetStats(t *testing.T) {
 		})
 	}
 }
+
+func TestObserveProductReconcile(t *testing.T) {
+	ProductReconcileDuration.Reset()
+	ProductPhaseTransitions.Reset()
+	ProductPhaseDuration.Reset()
+	ProductLastCompleted.Reset()
+	ProductFirstReconciled.Reset()
+	productPhases.since = map[string]time.Time{}
+	productPhases.reconciled = map[string]bool{}
+
+	product := string(v1alpha1.Product3Scale)
+	start := time.Unix(1600000000, 0)
+
+	ObserveProductReconcile(product, v1alpha1.PhaseNone, v1alpha1.PhaseInProgress, time.Second, nil, start)
+	ObserveProductReconcile(product, v1alpha1.PhaseInProgress, v1alpha1.PhaseInProgress, time.Second, errors.New("apicast not ready"), start.Add(time.Minute))
+	ObserveProductReconcile(product, v1alpha1.PhaseInProgress, v1alpha1.PhaseCompleted, time.Second, nil, start.Add(5*time.Minute))
+
+	if got := testutil.ToFloat64(ProductPhaseTransitions.WithLabelValues(product, "none", string(v1alpha1.PhaseInProgress))); got != 1 {
+		t.Errorf("expected 1 transition from none to in progress but got %v", got)
+	}
+	if got := testutil.ToFloat64(ProductPhaseTransitions.WithLabelValues(product, string(v1alpha1.PhaseInProgress), string(v1alpha1.PhaseCompleted))); got != 1 {
+		t.Errorf("expected 1 transition from in progress to completed but got %v", got)
+	}
+	if got := testutil.CollectAndCount(ProductPhaseTransitions); got != 2 {
+		t.Errorf("expected only the phase changes to be counted but got %d series", got)
+	}
+	if got := testutil.ToFloat64(ProductLastCompleted.WithLabelValues(product)); got != float64(start.Add(5*time.Minute).Unix()) {
+		t.Errorf("expected the last completed time to be set but got %v", got)
+	}
+	if got := testutil.ToFloat64(ProductFirstReconciled.WithLabelValues(product)); got != float64(start.Unix()) {
+		t.Errorf("expected the first reconciled time to be kept but got %v", got)
+	}
+	// the time spent in none is not known, only the time spent in progress is observed
+	if got := testutil.CollectAndCount(ProductPhaseDuration); got != 1 {
+		t.Errorf("expected 1 phase duration series but got %d", got)
+	}
+	if got := testutil.CollectAndCount(ProductReconcileDuration); got != 3 {
+		t.Errorf("expected a reconcile duration series per result but got %d", got)
+	}
+}
+
+func TestSetRateLimitUsage(t *testing.T) {
+	usage := func(requestsPerUnit, requests uint32) v1alpha1.RateLimitUsage {
+		return v1alpha1.RateLimitUsage{
+			TenantRateLimit: v1alpha1.TenantRateLimit{Unit: "minute", RequestsPerUnit: requestsPerUnit},
+			Requests:        requests,
+		}
+	}
+	global := usage(100, 25)
+
+	SetRateLimitUsage(&global, map[string]v1alpha1.RateLimitUsage{"busy": usage(10, 10), "removed": usage(10, 1)})
+	SetRateLimitUsage(&global, map[string]v1alpha1.RateLimitUsage{"busy": usage(10, 10)})
+
+	if got := testutil.ToFloat64(RateLimitUsage.WithLabelValues("global", "")); got != 25 {
+		t.Errorf("expected 25 global requests but got %v", got)
+	}
+	if got := testutil.ToFloat64(RateLimitUsageLimit.WithLabelValues("tenant", "busy")); got != 10 {
+		t.Errorf("expected a limit of 10 requests for tenant busy but got %v", got)
+	}
+	if got := testutil.CollectAndCount(RateLimitUsage); got != 2 {
+		t.Errorf("expected the usage of tenants no longer reported to be removed but got %d series", got)
+	}
+}
+
+func TestSetTenantsSummary(t *testing.T) {
+	tenant := func(name string, suspended bool, status v1alpha1.ProvisioningStatus) v1alpha1.APIManagementTenant {
+		return v1alpha1.APIManagementTenant{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: name + "-dev", CreationTimestamp: metav1.Unix(1000, 0)},
+			Spec:       v1alpha1.APIManagementTenantSpec{Suspended: suspended},
+			Status:     v1alpha1.APIManagementTenantStatus{ProvisioningStatus: status},
+		}
+	}
+
+	SetTenantsSummary(&v1alpha1.APIManagementTenantList{Items: []v1alpha1.APIManagementTenant{
+		tenant("active", false, v1alpha1.ThreeScaleAccountReady),
+		tenant("suspended", true, v1alpha1.ThreeScaleAccountSuspended),
+	}})
+
+	if got := testutil.CollectAndCount(TenantsSummary); got != 2 {
+		t.Errorf("expected a series per tenant but got %d", got)
+	}
+	suspended := TenantsSummary.With(map[string]string{
+		"tenantName":         "suspended",
+		"tenantNamespace":    "suspended-dev",
+		"provisioningStatus": string(v1alpha1.ThreeScaleAccountSuspended),
+		"lastError":          "",
+		"suspended":          "true",
+	})
+	if got := testutil.ToFloat64(suspended); got != 1000 {
+		t.Errorf("expected the suspended tenant to be reported by its creation time but got %v", got)
+	}
+}
+
+func TestSetTenantQuota(t *testing.T) {
+	limit := int32(2)
+	SetTenantQuota("tenant", "services", &limit, 3)
+	SetTenantQuota("tenant", "users", nil, 5)
+
+	if got := testutil.ToFloat64(TenantOverQuota.WithLabelValues("tenant", "services")); got != 1 {
+		t.Errorf("expected the tenant to be over its services quota but got %v", got)
+	}
+	if got := testutil.ToFloat64(TenantQuotaLimit.WithLabelValues("tenant", "services")); got != 2 {
+		t.Errorf("expected a services limit of 2 but got %v", got)
+	}
+	if got := testutil.CollectAndCount(TenantQuotaLimit); got != 1 {
+		t.Errorf("expected only capped kinds to have a limit but got %d series", got)
+	}
+	if got := testutil.CollectAndCount(TenantQuotaUsage); got != 2 {
+		t.Errorf("expected the usage of every kind but got %d series", got)
+	}
+
+	DeleteTenantQuota("tenant", "services")
+	DeleteTenantQuota("tenant", "users")
+	if got := testutil.CollectAndCount(TenantQuotaUsage) + testutil.CollectAndCount(TenantQuotaLimit) + testutil.CollectAndCount(TenantOverQuota); got != 0 {
+		t.Errorf("expected the quota metrics of the tenant to be deleted but got %d series", got)
+	}
+}
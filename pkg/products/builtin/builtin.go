@@ -0,0 +1,14 @@
+// Package builtin registers every product shipped with the operator. Importing it for its side
+// effects makes the products available to products.NewReconciler and the installation types
+package builtin
+
+import (
+	_ "github.com/integr8ly/integreatly-operator/pkg/products/cloudresources"
+	_ "github.com/integr8ly/integreatly-operator/pkg/products/grafana"
+	_ "github.com/integr8ly/integreatly-operator/pkg/products/marin3r"
+	_ "github.com/integr8ly/integreatly-operator/pkg/products/monitoringspec"
+	_ "github.com/integr8ly/integreatly-operator/pkg/products/observability"
+	_ "github.com/integr8ly/integreatly-operator/pkg/products/rhsso"
+	_ "github.com/integr8ly/integreatly-operator/pkg/products/rhssouser"
+	_ "github.com/integr8ly/integreatly-operator/pkg/products/threescale"
+)
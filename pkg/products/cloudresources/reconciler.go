@@ -22,8 +22,8 @@ import (
 	"github.com/integr8ly/integreatly-operator/pkg/resources/backup"
 	"github.com/integr8ly/integreatly-operator/pkg/resources/events"
 
+	"github.com/integr8ly/integreatly-operator/pkg/products"
 	"github.com/integr8ly/integreatly-operator/pkg/resources/constants"
-	"github.com/integr8ly/integreatly-operator/version"
 
 	"github.com/aws/aws-sdk-go/service/elasticache"
 	"github.com/aws/aws-sdk-go/service/rds"
@@ -108,12 +108,7 @@ func (r *Reconciler) GetPreflightObject(_ string) runtime.Object {
 }
 
 func (r *Reconciler) VerifyVersion(installation *integreatlyv1alpha1.RHMI) bool {
-	product := installation.Status.Stages[integreatlyv1alpha1.InstallStage].Products[integreatlyv1alpha1.ProductCloudResources]
-	return version.VerifyProductAndOperatorVersion(
-		product,
-		string(integreatlyv1alpha1.VersionCloudResources),
-		string(integreatlyv1alpha1.OperatorVersionCloudResources),
-	)
+	return products.VerifyVersion(installation, integreatlyv1alpha1.InstallStage, integreatlyv1alpha1.ProductCloudResources)
 }
 
 func (r *Reconciler) Reconcile(ctx context.Context, installation *integreatlyv1alpha1.RHMI, productStatus *integreatlyv1alpha1.RHMIProductStatus, client k8sclient.Client, _ quota.ProductConfig, uninstall bool) (integreatlyv1alpha1.StatusPhase, error) {
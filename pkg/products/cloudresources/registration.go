@@ -0,0 +1,29 @@
+package cloudresources
+
+import (
+	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/apis/v1alpha1"
+	"github.com/integr8ly/integreatly-operator/pkg/config"
+	"github.com/integr8ly/integreatly-operator/pkg/products"
+)
+
+func init() {
+	products.Register(products.Registration{
+		Name: integreatlyv1alpha1.ProductCloudResources,
+		InstallationTypes: []integreatlyv1alpha1.InstallationType{
+			integreatlyv1alpha1.InstallationTypeManagedApi,
+			integreatlyv1alpha1.InstallationTypeMultitenantManagedApi,
+		},
+		Dependencies: []integreatlyv1alpha1.ProductName{
+			integreatlyv1alpha1.ProductObservability,
+		},
+		Version:         integreatlyv1alpha1.VersionCloudResources,
+		OperatorVersion: integreatlyv1alpha1.OperatorVersionCloudResources,
+		NewConfig: func(productConfig config.ProductConfig) config.ConfigReadable {
+			return config.NewCloudResources(productConfig)
+		},
+		WatchableCRDs: config.NewCloudResources(config.ProductConfig{}).GetWatchableCRDs(),
+		NewReconciler: func(opts products.ReconcilerOptions) (products.Interface, error) {
+			return NewReconciler(opts.ConfigManager, opts.Installation, opts.MPM, opts.Recorder, opts.Log, opts.ProductDeclaration)
+		},
+	})
+}
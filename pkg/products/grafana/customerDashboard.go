@@ -1,13 +1,43 @@
 package grafana
 
+import (
+	"fmt"
+	"strconv"
+
+	marin3rconfig "github.com/integr8ly/integreatly-operator/pkg/products/marin3r/config"
+)
+
 // This dashboard json is dynamically configured based on soft limits and perUnitRequests provided in the quota-configs-managed-api-service config map
 // present in the operator namespace for RHOAM installations
 // For example if there are softLimits provided of [500000,10000000,15000000] Five, Ten and Fifteen Million per day
 // Each of these soft limits are then dynamically added as queries to the Rate Limit Graph.
 //
 // Each of the hard limit and soft limits are calculated to a perMinute amount.
+// The additional windows of the rate limit are added as queries to the Per Minute API Requests Graph.
+
+// getRateLimitWindowTargets returns a query of the Per Minute API Requests Graph for each of the
+// windows, with the limit of the window calculated to a perMinute amount
+func getRateLimitWindowTargets(windows []marin3rconfig.RateLimitWindow) (string, error) {
+	targets := ""
+	for i, window := range windows {
+		perMinute, err := marin3rconfig.ConvertRate(window.Unit, "minute", int(window.RequestsPerUnit))
+		if err != nil {
+			return "", fmt.Errorf("failed to convert the window of %d requests per %s to a rate per minute: %w", window.RequestsPerUnit, window.Unit, err)
+		}
+		targets += `,
+		{
+          "expr": "` + strconv.FormatFloat(perMinute, 'f', -1, 64) + `",
+          "instant": false,
+          "interval": "30s",
+          "legendFormat": "Rate Limit - ` + fmt.Sprintf("%d per %s", window.RequestsPerUnit, window.Unit) + `",
+          "refId": "` + string(rune('C'+i)) + `"
+        }`
+	}
+
+	return targets, nil
+}
 
-func getCustomerMonitoringGrafanaRateLimitJSON(requestsPerUnit, activeQuota string) string {
+func getCustomerMonitoringGrafanaRateLimitJSON(requestsPerUnit, activeQuota, windowTargets string) string {
 	return `{
   "annotations": {
     "list": [
@@ -110,7 +140,7 @@ func getCustomerMonitoringGrafanaRateLimitJSON(requestsPerUnit, activeQuota stri
       "tableColumn": "",
       "targets": [
         {
-          "expr": "sum(increase(authorized_calls[1m]) or vector(0)) + sum(increase(limited_calls[1m]) or vector(0))",
+          "expr": "sum(increase(authorized_calls{limitador_namespace=\"apicast-ratelimit\"}[1m]) or vector(0)) + sum(increase(limited_calls{limitador_namespace=\"apicast-ratelimit\"}[1m]) or vector(0))",
           "instant": true,
           "refId": "A"
         }
@@ -196,7 +226,7 @@ func getCustomerMonitoringGrafanaRateLimitJSON(requestsPerUnit, activeQuota stri
       "tableColumn": "",
       "targets": [
         {
-          "expr": "sum(increase(limited_calls[1m])) > 0 or vector(0)",
+          "expr": "sum(increase(limited_calls{limitador_namespace=\"apicast-ratelimit\"}[1m])) > 0 or vector(0)",
           "instant": true,
           "refId": "A"
         }
@@ -281,7 +311,7 @@ func getCustomerMonitoringGrafanaRateLimitJSON(requestsPerUnit, activeQuota stri
       "tableColumn": "",
       "targets": [
         {
-          "expr": "(sum(increase(limited_calls[1m])) > 0 or vector(0))/(sum(increase(authorized_calls[1m]) or vector(0)) + sum(increase(limited_calls[1m]) or vector(0)))*100 > 0 or vector(0)",
+          "expr": "(sum(increase(limited_calls{limitador_namespace=\"apicast-ratelimit\"}[1m])) > 0 or vector(0))/(sum(increase(authorized_calls{limitador_namespace=\"apicast-ratelimit\"}[1m]) or vector(0)) + sum(increase(limited_calls{limitador_namespace=\"apicast-ratelimit\"}[1m]) or vector(0)))*100 > 0 or vector(0)",
           "instant": true,
           "refId": "A"
         }
@@ -348,7 +378,7 @@ func getCustomerMonitoringGrafanaRateLimitJSON(requestsPerUnit, activeQuota stri
       "steppedLine": false,
       "targets": [
         {
-          "expr": "sum(increase(authorized_calls[1m]) or vector(0)) + sum(increase(limited_calls[1m]) or vector(0))",
+          "expr": "sum(increase(authorized_calls{limitador_namespace=\"apicast-ratelimit\"}[1m]) or vector(0)) + sum(increase(limited_calls{limitador_namespace=\"apicast-ratelimit\"}[1m]) or vector(0))",
           "instant": false,
           "interval": "30s",
           "legendFormat": "No. of Requests",
@@ -360,7 +390,7 @@ func getCustomerMonitoringGrafanaRateLimitJSON(requestsPerUnit, activeQuota stri
           "interval": "30s",
           "legendFormat": "Active Quota - ` + activeQuota + ` Per Day - Rate Limit - ` + requestsPerUnit + ` per minute",
           "refId": "B"
-        }
+        }` + windowTargets + `
       ],
       "thresholds": [],
       "timeFrom": null,
@@ -469,7 +499,7 @@ func getCustomerMonitoringGrafanaRateLimitJSON(requestsPerUnit, activeQuota stri
       "tableColumn": "",
       "targets": [
         {
-          "expr": "sum(increase(authorized_calls[24h]) or vector(0)) + sum(increase(limited_calls[24h]) or vector(0)) > 0 or vector(0)",
+          "expr": "sum(increase(authorized_calls{limitador_namespace=\"apicast-ratelimit\"}[24h]) or vector(0)) + sum(increase(limited_calls{limitador_namespace=\"apicast-ratelimit\"}[24h]) or vector(0)) > 0 or vector(0)",
           "instant": true,
           "refId": "A"
         }
@@ -554,7 +584,7 @@ func getCustomerMonitoringGrafanaRateLimitJSON(requestsPerUnit, activeQuota stri
       "tableColumn": "",
       "targets": [
         {
-          "expr": "sum(increase(limited_calls[24h]) or vector(0))",
+          "expr": "sum(increase(limited_calls{limitador_namespace=\"apicast-ratelimit\"}[24h]) or vector(0))",
           "format": "time_series",
           "instant": true,
           "refId": "A"
@@ -640,7 +670,7 @@ func getCustomerMonitoringGrafanaRateLimitJSON(requestsPerUnit, activeQuota stri
       "tableColumn": "",
       "targets": [
         {
-          "expr": "(sum(increase(limited_calls[24h])) > 0 or vector(0))/(sum(increase(authorized_calls[24h]) or vector(0)) + sum(increase(limited_calls[24h]) or vector(0)))*100 > 0 or vector(0)",
+          "expr": "(sum(increase(limited_calls{limitador_namespace=\"apicast-ratelimit\"}[24h])) > 0 or vector(0))/(sum(increase(authorized_calls{limitador_namespace=\"apicast-ratelimit\"}[24h]) or vector(0)) + sum(increase(limited_calls{limitador_namespace=\"apicast-ratelimit\"}[24h]) or vector(0)))*100 > 0 or vector(0)",
           "instant": true,
           "legendFormat": "",
           "refId": "A"
@@ -660,6 +690,184 @@ func getCustomerMonitoringGrafanaRateLimitJSON(requestsPerUnit, activeQuota stri
         }
       ],
       "valueName": "avg"
+    },
+    {
+      "cacheTimeout": null,
+      "colorBackground": true,
+      "colorValue": false,
+      "colors": [
+        "#299c46",
+        "rgba(237, 129, 40, 0.89)",
+        "#d44a3a"
+      ],
+      "datasource": "Prometheus",
+      "description": "Requests counted in the current window of the rate limit, against the requests allowed in each window",
+      "format": "percent",
+      "gauge": {
+        "maxValue": 100,
+        "minValue": 0,
+        "show": true,
+        "thresholdLabels": false,
+        "thresholdMarkers": true
+      },
+      "gridPos": {
+        "h": 8,
+        "w": 9,
+        "x": 0,
+        "y": 11
+      },
+      "id": 24,
+      "interval": "",
+      "links": [],
+      "mappingType": 1,
+      "mappingTypes": [
+        {
+          "name": "value to text",
+          "value": 1
+        },
+        {
+          "name": "range to text",
+          "value": 2
+        }
+      ],
+      "maxDataPoints": 100,
+      "nullPointMode": "connected",
+      "nullText": null,
+      "options": {},
+      "postfix": "",
+      "postfixFontSize": "50%",
+      "prefix": "",
+      "prefixFontSize": "50%",
+      "rangeMaps": [
+        {
+          "from": "null",
+          "text": "N/A",
+          "to": "null"
+        }
+      ],
+      "sparkline": {
+        "fillColor": "rgba(31, 118, 189, 0.18)",
+        "full": false,
+        "lineColor": "rgb(31, 120, 193)",
+        "show": false,
+        "ymax": null,
+        "ymin": null
+      },
+      "tableColumn": "",
+      "targets": [
+        {
+          "expr": "sum(rhoam_rate_limit_usage_requests{scope=\"global\"}) / sum(rhoam_rate_limit_usage_limit_requests{scope=\"global\"}) * 100",
+          "instant": true,
+          "refId": "A"
+        }
+      ],
+      "thresholds": "80,95",
+      "timeFrom": null,
+      "timeShift": null,
+      "title": "Current Window - Rate Limit Usage",
+      "type": "singlestat",
+      "valueFontSize": "80%",
+      "valueMaps": [
+        {
+          "op": "=",
+          "text": "N/A",
+          "value": "null"
+        }
+      ],
+      "valueName": "current"
+    },
+    {
+      "aliasColors": {},
+      "bars": false,
+      "dashLength": 10,
+      "dashes": false,
+      "datasource": "Prometheus",
+      "decimals": 0,
+      "description": "Requests of each tenant counted in the current window of its rate limit, against the requests allowed in each window. Only the 10 tenants closest to their limit are shown",
+      "fill": 1,
+      "fillGradient": 0,
+      "gridPos": {
+        "h": 8,
+        "w": 15,
+        "x": 9,
+        "y": 11
+      },
+      "hiddenSeries": false,
+      "id": 26,
+      "legend": {
+        "alignAsTable": true,
+        "avg": false,
+        "current": true,
+        "max": false,
+        "min": false,
+        "rightSide": true,
+        "show": true,
+        "total": false,
+        "values": true
+      },
+      "lines": true,
+      "linewidth": 1,
+      "nullPointMode": "null",
+      "options": {
+        "dataLinks": []
+      },
+      "percentage": false,
+      "pointradius": 2,
+      "points": false,
+      "renderer": "flot",
+      "seriesOverrides": [],
+      "spaceLength": 10,
+      "stack": false,
+      "steppedLine": false,
+      "targets": [
+        {
+          "expr": "topk(10, rhoam_rate_limit_usage_requests{scope=\"tenant\"} / rhoam_rate_limit_usage_limit_requests{scope=\"tenant\"} * 100)",
+          "instant": false,
+          "interval": "1m",
+          "legendFormat": "{{tenant}}",
+          "refId": "A"
+        }
+      ],
+      "thresholds": [],
+      "timeFrom": null,
+      "timeRegions": [],
+      "timeShift": null,
+      "title": "Tenant Rate Limit Usage",
+      "tooltip": {
+        "shared": true,
+        "sort": 2,
+        "value_type": "individual"
+      },
+      "type": "graph",
+      "xaxis": {
+        "buckets": null,
+        "mode": "time",
+        "name": null,
+        "show": true,
+        "values": []
+      },
+      "yaxes": [
+        {
+          "format": "percent",
+          "label": null,
+          "logBase": 1,
+          "max": null,
+          "min": 0,
+          "show": true
+        },
+        {
+          "format": "short",
+          "label": null,
+          "logBase": 1,
+          "max": null,
+          "min": null,
+          "show": false
+        }
+      ],
+      "yaxis": {
+        "align": false,
+        "alignLevel": null
+      }
     }
   ],
   "refresh": "1m",
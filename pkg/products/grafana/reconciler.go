@@ -12,10 +12,12 @@ import (
 	prometheus "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	appsv1 "k8s.io/api/apps/v1"
 	"k8s.io/client-go/util/retry"
+	"strconv"
 
 	grafanav1alpha1 "github.com/grafana-operator/grafana-operator/v4/api/integreatly/v1alpha1"
 	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/apis/v1alpha1"
 	"github.com/integr8ly/integreatly-operator/pkg/config"
+	"github.com/integr8ly/integreatly-operator/pkg/products"
 	marin3rconfig "github.com/integr8ly/integreatly-operator/pkg/products/marin3r/config"
 	"github.com/integr8ly/integreatly-operator/pkg/resources"
 	"github.com/integr8ly/integreatly-operator/pkg/resources/backup"
@@ -23,7 +25,6 @@ import (
 	"github.com/integr8ly/integreatly-operator/pkg/resources/events"
 	"github.com/integr8ly/integreatly-operator/pkg/resources/marketplace"
 	"github.com/integr8ly/integreatly-operator/pkg/resources/owner"
-	"github.com/integr8ly/integreatly-operator/version"
 	routev1 "github.com/openshift/api/route/v1"
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/api/core/v1"
@@ -61,11 +62,7 @@ func (r *Reconciler) GetPreflightObject(_ string) runtime.Object {
 }
 
 func (r *Reconciler) VerifyVersion(installation *integreatlyv1alpha1.RHMI) bool {
-	return version.VerifyProductAndOperatorVersion(
-		installation.Status.Stages[integreatlyv1alpha1.InstallStage].Products[integreatlyv1alpha1.ProductGrafana],
-		string(integreatlyv1alpha1.VersionGrafana),
-		string(integreatlyv1alpha1.OperatorVersionGrafana),
-	)
+	return products.VerifyVersion(installation, integreatlyv1alpha1.InstallStage, integreatlyv1alpha1.ProductGrafana)
 }
 
 func NewReconciler(configManager config.ConfigReadWriter, installation *integreatlyv1alpha1.RHMI, mpm marketplace.MarketplaceInterface, recorder record.EventRecorder, logger l.Logger, productDeclaration *marketplace.ProductDeclaration) (*Reconciler, error) {
@@ -232,13 +229,22 @@ func (r *Reconciler) reconcileGrafanaDashboards(ctx context.Context, serverClien
 		},
 	}
 
+	requestsPerMinute, err := marin3rconfig.ConvertRate(limitConfig.Unit, "minute", int(limitConfig.RequestsPerUnit))
+	if err != nil {
+		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to convert the rate limit to a rate per minute: %w", err)
+	}
+	windowTargets, err := getRateLimitWindowTargets(limitConfig.Windows)
+	if err != nil {
+		return integreatlyv1alpha1.PhaseFailed, err
+	}
+
 	opRes, err := controllerutil.CreateOrUpdate(ctx, serverClient, grafanaDB, func() error {
 		grafanaDB.Labels = map[string]string{
 			"monitoring-key": "customer",
 		}
 
 		grafanaDB.Spec = grafanav1alpha1.GrafanaDashboardSpec{
-			Json: getCustomerMonitoringGrafanaRateLimitJSON(fmt.Sprintf("%d", limitConfig.RequestsPerUnit), activeQuota),
+			Json: getCustomerMonitoringGrafanaRateLimitJSON(strconv.FormatFloat(requestsPerMinute, 'f', -1, 64), activeQuota, windowTargets),
 		}
 		return nil
 	})
@@ -0,0 +1,127 @@
+package marin3r
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/apis/v1alpha1"
+	marin3rconfig "github.com/integr8ly/integreatly-operator/pkg/products/marin3r/config"
+	"github.com/integr8ly/integreatly-operator/pkg/resources"
+	l "github.com/integr8ly/integreatly-operator/pkg/resources/logger"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// loadAlertPolicies reads the alerts of the RateLimitAlertPolicies in the namespace of the
+// installation into r.AlertsConfig. A policy that is not valid, or that defines an alert already
+// defined by a policy before it, is skipped and the reason is reported in its status
+func (r *Reconciler) loadAlertPolicies(ctx context.Context, client k8sclient.Client) error {
+	policies, err := marin3rconfig.GetAlertPolicies(ctx, client, r.installation.Namespace)
+	if err != nil {
+		return fmt.Errorf("failed to list rate limit alert policies: %w", err)
+	}
+
+	r.AlertPolicies = policies
+	r.AlertsConfig = map[string]*marin3rconfig.AlertConfig{}
+	r.alertPolicyErrors = map[string]error{}
+
+	alertPolicyNames := map[string]string{}
+	for _, policy := range policies {
+		err := policy.Validate()
+		if err == nil {
+			for _, alert := range policy.Spec.Alerts {
+				if policyName, ok := alertPolicyNames[alert.Name]; ok {
+					err = fmt.Errorf("alert %s is already defined by policy %s", alert.Name, policyName)
+					break
+				}
+			}
+		}
+		if err != nil {
+			r.log.Warningf("Skipping invalid rate limit alert policy", l.Fields{"policy": policy.Name, "error": err.Error()})
+			r.alertPolicyErrors[policy.Name] = err
+			continue
+		}
+
+		for _, alert := range policy.Spec.Alerts {
+			alertPolicyNames[alert.Name] = policy.Name
+			r.AlertsConfig[alert.Name] = marin3rconfig.AlertConfigFromPolicy(alert)
+		}
+	}
+
+	return nil
+}
+
+// getPolicyPrometheusRules returns the namespaced names of the PrometheusRules generated from
+// the alerts of a policy
+func (r *Reconciler) getPolicyPrometheusRules(policy integreatlyv1alpha1.RateLimitAlertPolicy, namespace string) []string {
+	rules := make([]string, 0, len(policy.Spec.Alerts))
+	for _, alert := range policy.Spec.Alerts {
+		rules = append(rules, fmt.Sprintf("%s/%s", namespace, getPrometheusRuleName(alert.Name, r.installation.Spec.Type)))
+	}
+
+	return rules
+}
+
+// getRemovedPolicyAlerts returns the PrometheusRules reported in the status of the valid
+// policies that are no longer generated from them, so they are deleted
+func (r *Reconciler) getRemovedPolicyAlerts(namespace string) []resources.AlertConfiguration {
+	removed := []resources.AlertConfiguration{}
+	for _, policy := range r.AlertPolicies {
+		if _, ok := r.alertPolicyErrors[policy.Name]; ok {
+			continue
+		}
+
+		current := map[string]bool{}
+		for _, rule := range r.getPolicyPrometheusRules(policy, namespace) {
+			current[rule] = true
+		}
+		for _, rule := range policy.Status.PrometheusRules {
+			if current[rule] {
+				continue
+			}
+			ruleNamespace, ruleName, ok := strings.Cut(rule, "/")
+			if !ok {
+				continue
+			}
+			removed = append(removed, resources.AlertConfiguration{AlertName: ruleName, Namespace: ruleNamespace})
+		}
+	}
+
+	return removed
+}
+
+// updateAlertPolicyStatuses reports the PrometheusRules generated from each policy, or the
+// reason the policy was skipped
+func (r *Reconciler) updateAlertPolicyStatuses(ctx context.Context, client k8sclient.Client) error {
+	observabilityConfig, err := r.ConfigManager.ReadObservability()
+	if err != nil {
+		return fmt.Errorf("failed to get observability config: %w", err)
+	}
+	namespace := observabilityConfig.GetNamespace()
+
+	for i := range r.AlertPolicies {
+		policy := &r.AlertPolicies[i]
+
+		status := integreatlyv1alpha1.RateLimitAlertPolicyStatus{
+			ObservedGeneration: policy.Generation,
+		}
+		if err, ok := r.alertPolicyErrors[policy.Name]; ok {
+			// the rules generated before the policy became invalid are left in place
+			status.PrometheusRules = policy.Status.PrometheusRules
+			status.Error = err.Error()
+		} else {
+			status.PrometheusRules = r.getPolicyPrometheusRules(*policy, namespace)
+		}
+
+		if reflect.DeepEqual(policy.Status, status) {
+			continue
+		}
+		policy.Status = status
+		if err := client.Status().Update(ctx, policy); err != nil {
+			return fmt.Errorf("failed to update status of rate limit alert policy %s: %w", policy.Name, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,89 @@
+package marin3r
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/apis/v1alpha1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func getAlertPolicy(name string, alerts ...integreatlyv1alpha1.RateLimitAlert) *integreatlyv1alpha1.RateLimitAlertPolicy {
+	return &integreatlyv1alpha1.RateLimitAlertPolicy{
+		ObjectMeta: v1.ObjectMeta{Name: name, Namespace: defaultInstallationNamespace},
+		Spec:       integreatlyv1alpha1.RateLimitAlertPolicySpec{Alerts: alerts},
+	}
+}
+
+func getSpikeAlert(name string) integreatlyv1alpha1.RateLimitAlert {
+	return integreatlyv1alpha1.RateLimitAlert{
+		Name:     name,
+		Type:     integreatlyv1alpha1.RateLimitAlertTypeSpike,
+		RuleName: "RHOAMApiUsageOverLimit",
+		Level:    "warning",
+		Period:   "30m",
+	}
+}
+
+func TestReconciler_alertPolicies(t *testing.T) {
+	scheme, err := getBuildScheme()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := integreatlyv1alpha1.SchemeBuilder.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	removedAlertPolicy := getAlertPolicy("a-policy", getSpikeAlert("spike"))
+	removedAlertPolicy.Status.PrometheusRules = []string{"redhat-rhoam-observability/spike", "redhat-rhoam-observability/removed"}
+	invalidAlert := getSpikeAlert("invalid")
+	invalidAlert.Period = ""
+
+	client := fakeclient.NewFakeClientWithScheme(scheme,
+		removedAlertPolicy,
+		getAlertPolicy("b-policy", getSpikeAlert("spike")),
+		getAlertPolicy("c-policy", invalidAlert),
+		getGrafanaRoute(),
+		getRateLimitConfigMap(),
+	)
+
+	reconciler, err := NewReconciler(getBasicConfig(), getBasicInstallation(), nil, setupRecorder(), getLogger(), localProductDeclaration, &http.Client{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	reconciler.RateLimitConfig = RateLimitConfig
+
+	if err := reconciler.loadAlertPolicies(context.TODO(), client); err != nil {
+		t.Fatal(err)
+	}
+	if len(reconciler.AlertsConfig) != 1 || reconciler.AlertsConfig["spike"] == nil {
+		t.Fatalf("expected only the alert of the first valid policy, got %v", reconciler.AlertsConfig)
+	}
+	removed := reconciler.getRemovedPolicyAlerts("redhat-rhoam-observability")
+	if len(removed) != 1 || removed[0].AlertName != "removed" {
+		t.Errorf("expected the alert removed from the policy to be deleted, got %v", removed)
+	}
+
+	if _, err := reconciler.reconcileAlerts(context.TODO(), client, reconciler.installation); err != nil {
+		t.Fatal(err)
+	}
+
+	wantStatuses := map[string]integreatlyv1alpha1.RateLimitAlertPolicyStatus{
+		"a-policy": {PrometheusRules: []string{"redhat-rhoam-observability/spike"}},
+		"b-policy": {Error: "alert spike is already defined by policy a-policy"},
+		"c-policy": {Error: "invalid alert invalid: period is required by the Spike type"},
+	}
+	for name, want := range wantStatuses {
+		policy := &integreatlyv1alpha1.RateLimitAlertPolicy{}
+		if err := client.Get(context.TODO(), k8sclient.ObjectKey{Name: name, Namespace: defaultInstallationNamespace}, policy); err != nil {
+			t.Fatal(err)
+		}
+		if fmt.Sprint(policy.Status.PrometheusRules) != fmt.Sprint(want.PrometheusRules) || policy.Status.Error != want.Error {
+			t.Errorf("expected status of policy %s %+v, got %+v", name, want, policy.Status)
+		}
+	}
+}
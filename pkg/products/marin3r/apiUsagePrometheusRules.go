@@ -10,6 +10,7 @@ import (
 
 	marin3rconfig "github.com/integr8ly/integreatly-operator/pkg/products/marin3r/config"
 	l "github.com/integr8ly/integreatly-operator/pkg/resources/logger"
+	"github.com/integr8ly/integreatly-operator/pkg/resources/ratelimit"
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 
 	"github.com/integr8ly/integreatly-operator/pkg/resources"
@@ -17,14 +18,25 @@ import (
 )
 
 var (
-	totalRequestsMetric = "authorized_calls"
+	// the requests exempt from the rate limit are counted in their own limitador namespace, the
+	// usage only counts the requests in the namespace of the rate limit
+	totalRequestsMetric   = fmt.Sprintf(`authorized_calls{limitador_namespace="%s"}`, ratelimit.RateLimitDomain)
+	limitedRequestsMetric = fmt.Sprintf(`limited_calls{limitador_namespace="%s"}`, ratelimit.RateLimitDomain)
 )
 
 func (r *Reconciler) newAlertsReconciler(grafanaDashboardURL string) (resources.AlertReconciler, error) {
 
-	requestsAllowedPerSecond, err := r.getRateLimitInSeconds(r.RateLimitConfig.Unit, r.RateLimitConfig.RequestsPerUnit)
-	if err != nil {
-		return nil, err
+	// the sustained usage is bound by the window that allows the fewest requests per second
+	windows := r.RateLimitConfig.GetWindows()
+	var requestsAllowedPerSecond float64
+	for i, window := range windows {
+		windowRequestsPerSecond, err := r.getRateLimitInSeconds(window.Unit, window.RequestsPerUnit)
+		if err != nil {
+			return nil, err
+		}
+		if i == 0 || windowRequestsPerSecond < requestsAllowedPerSecond {
+			requestsAllowedPerSecond = windowRequestsPerSecond
+		}
 	}
 
 	observabilityConfig, err := r.ConfigManager.ReadObservability()
@@ -34,38 +46,39 @@ func (r *Reconciler) newAlertsReconciler(grafanaDashboardURL string) (resources.
 
 	namespace := observabilityConfig.GetNamespace()
 
-	alerts, err := mapAlertsConfiguration(r.log, namespace, r.RateLimitConfig.Unit, r.RateLimitConfig.RequestsPerUnit, requestsAllowedPerSecond, r.AlertsConfig, grafanaDashboardURL, r.installation.Spec.Type)
+	alerts, err := mapAlertsConfiguration(r.log, namespace, windows, requestsAllowedPerSecond, r.AlertsConfig, grafanaDashboardURL, r.installation.Spec.Type)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create alerts from configuration: %w", err)
 	}
 
 	return &resources.AlertReconcilerImpl{
-		ProductName:  "3Scale",
-		Installation: r.installation,
-		Log:          r.log,
-		Alerts:       alerts,
+		ProductName:   "3Scale",
+		Installation:  r.installation,
+		Log:           r.log,
+		Alerts:        alerts,
+		RemovedAlerts: r.getRemovedPolicyAlerts(namespace),
 	}, nil
 }
 
 // mapAlertsConfiguration maps each value from alertsConfig into a
 // resources.AlertConfiguration object, resulting into a list of the
 // prometheus alerts to be created
-func mapAlertsConfiguration(logger l.Logger, namespace, rateLimitUnit string, rateLimitRequestsPerUnit uint32, requestsAllowedPerSecond float64, alertsConfig map[string]*marin3rconfig.AlertConfig, grafanaDashboardURL string, installationName string) ([]resources.AlertConfiguration, error) {
+func mapAlertsConfiguration(logger l.Logger, namespace string, windows []marin3rconfig.RateLimitWindow, requestsAllowedPerSecond float64, alertsConfig map[string]*marin3rconfig.AlertConfig, grafanaDashboardURL string, installationName string) ([]resources.AlertConfiguration, error) {
 	result := make([]resources.AlertConfiguration, 0, len(alertsConfig))
+	windowsMessage := getWindowsMessage(windows)
 
 	for alertName, alertConfig := range alertsConfig {
 
-		if installationName == string(integreatlyv1alpha1.InstallationTypeManagedApi) {
-			alertName = "marin3r-" + alertName
-		}
+		alertName = getPrometheusRuleName(alertName, installationName)
 
 		switch alertConfig.Type {
 		case marin3rconfig.AlertTypeSpike:
-			expr := fmt.Sprintf(
-				"max_over_time((sum(increase(authorized_calls[1m])) + sum(increase(limited_calls[1m])))[%s:]) > %d",
-				alertConfig.Period, rateLimitRequestsPerUnit)
+			expr, err := spikeExpr(windows, alertConfig.Period)
+			if err != nil {
+				return nil, err
+			}
 			annotations := map[string]string{
-				"message":        fmt.Sprintf("hard limit of %d breached at least once in the last %s", rateLimitRequestsPerUnit, alertConfig.Period),
+				"message":        fmt.Sprintf("hard limit of %s breached at least once in the last %s", windowsMessage, alertConfig.Period),
 				"grafanaConsole": grafanaDashboardURL,
 			}
 			alert := mapSpikeAlert(alertConfig, alertName, namespace, expr, annotations, installationName)
@@ -99,13 +112,36 @@ func mapAlertsConfiguration(logger l.Logger, namespace, rateLimitUnit string, ra
 			}
 			annotations := map[string]string{
 				"message": fmt.Sprintf(
-					"Total API usage in your API Management service is between %s and %s of the allowable threshold, %d requests per %s, during the last %s",
-					alertConfig.Threshold.MinRate, upperMessage, rateLimitRequestsPerUnit, rateLimitUnit, alertConfig.Period,
+					"Total API usage in your API Management service is between %s and %s of the allowable threshold, %s, during the last %s",
+					alertConfig.Threshold.MinRate, upperMessage, windowsMessage, alertConfig.Period,
 				),
 				"grafanaConsole": grafanaDashboardURL,
 			}
 			alert := mapThresholdAlert(alertConfig, alertName, namespace, expr, annotations, installationName)
 
+			result = append(result, alert)
+		case marin3rconfig.AlertTypeSustained:
+			if alertConfig.Sustained == nil {
+				return nil, fmt.Errorf("sustained is required by the %s alert %s", alertConfig.Type, alertName)
+			}
+			minRateValue, err := parsePercentage(&alertConfig.Sustained.MinRate)
+			if err != nil {
+				return nil, err
+			}
+
+			expr := fmt.Sprintf(
+				"sum(rate(%s[1m])) >= (%f / 100 * %d)",
+				totalRequestsMetric, requestsAllowedPerSecond, *minRateValue,
+			)
+			annotations := map[string]string{
+				"message": fmt.Sprintf(
+					"Total API usage in your API Management service has been over %s of the allowable threshold, %s, for %d minutes",
+					alertConfig.Sustained.MinRate, windowsMessage, alertConfig.Sustained.Minutes,
+				),
+				"grafanaConsole": grafanaDashboardURL,
+			}
+			alert := mapSustainedAlert(alertConfig, alertName, namespace, expr, annotations, installationName)
+
 			result = append(result, alert)
 		default:
 			logger.Infof("Unsupported Alert Type found", l.Fields{"alertName": alertName})
@@ -148,6 +184,67 @@ func mapThresholdAlert(alertConfig *marin3rconfig.AlertConfig, alertName string,
 	}
 }
 
+func mapSustainedAlert(alertConfig *marin3rconfig.AlertConfig, alertName string, namespace string, expr string, annotations map[string]string, installationName string) resources.AlertConfiguration {
+	return resources.AlertConfiguration{
+		AlertName: alertName,
+		GroupName: "api-usage-sustained.rules",
+		Namespace: namespace,
+		Rules: []monitoringv1.Rule{
+			{
+				Alert:       alertConfig.RuleName,
+				Annotations: annotations,
+				Expr:        intstr.FromString(expr),
+				For:         fmt.Sprintf("%dm", alertConfig.Sustained.Minutes),
+				Labels:      map[string]string{"severity": alertConfig.Level, "product": installationName},
+			},
+		},
+	}
+}
+
+// getPrometheusRuleName returns the name of the PrometheusRule generated for an alert
+func getPrometheusRuleName(alertName string, installationName string) string {
+	if installationName == string(integreatlyv1alpha1.InstallationTypeManagedApi) {
+		return "marin3r-" + alertName
+	}
+
+	return alertName
+}
+
+// spikeExpr returns an expression that is true when the requests in any of the windows went over
+// the limit of the window at least once during the period. The requests of a window of a second
+// are averaged over a minute as the counters are not scraped more often
+func spikeExpr(windows []marin3rconfig.RateLimitWindow, period string) (string, error) {
+	exprs := make([]string, 0, len(windows))
+	for _, window := range windows {
+		var requests string
+		switch window.Unit {
+		case "second":
+			requests = fmt.Sprintf("(sum(rate(%s[1m])) + sum(rate(%s[1m])))", totalRequestsMetric, limitedRequestsMetric)
+		case "minute":
+			requests = fmt.Sprintf("(sum(increase(%s[1m])) + sum(increase(%s[1m])))", totalRequestsMetric, limitedRequestsMetric)
+		case "hour":
+			requests = fmt.Sprintf("(sum(increase(%s[1h])) + sum(increase(%s[1h])))", totalRequestsMetric, limitedRequestsMetric)
+		case "day":
+			requests = fmt.Sprintf("(sum(increase(%s[1d])) + sum(increase(%s[1d])))", totalRequestsMetric, limitedRequestsMetric)
+		default:
+			return "", fmt.Errorf("unexpected Rate Limit Unit %v, while creating the spike alert", window.Unit)
+		}
+		exprs = append(exprs, fmt.Sprintf("max_over_time(%s[%s:]) > %d", requests, period, window.RequestsPerUnit))
+	}
+
+	return strings.Join(exprs, " or "), nil
+}
+
+// getWindowsMessage returns the limit of each window as "<requests> requests per <unit>"
+func getWindowsMessage(windows []marin3rconfig.RateLimitWindow) string {
+	messages := make([]string, 0, len(windows))
+	for _, window := range windows {
+		messages = append(messages, fmt.Sprintf("%d requests per %s", window.RequestsPerUnit, window.Unit))
+	}
+
+	return strings.Join(messages, ", ")
+}
+
 func increaseExpr(totalRequestsMetric, period string, comparisonOperator string, requestsAllowedOverTimePeriod float64, percenteageLimit *int) *string {
 	if percenteageLimit == nil {
 		return nil
@@ -0,0 +1,103 @@
+package marin3r
+
+import (
+	"testing"
+
+	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/apis/v1alpha1"
+	marin3rconfig "github.com/integr8ly/integreatly-operator/pkg/products/marin3r/config"
+)
+
+func TestMapAlertsConfiguration(t *testing.T) {
+	maxRate := "90%"
+	alertsConfig := map[string]*marin3rconfig.AlertConfig{
+		"api-usage-spike": {
+			Type:     marin3rconfig.AlertTypeSpike,
+			Level:    "warning",
+			RuleName: "RHOAMApiUsageSpike",
+			Period:   "30m",
+		},
+		"api-usage-level": {
+			Type:      marin3rconfig.AlertTypeThreshold,
+			Level:     "info",
+			RuleName:  "RHOAMApiUsageLevel",
+			Period:    "4h",
+			Threshold: &marin3rconfig.AlertThresholdConfig{MinRate: "80%", MaxRate: &maxRate},
+		},
+		"api-usage-sustained": {
+			Type:      marin3rconfig.AlertTypeSustained,
+			Level:     "warning",
+			RuleName:  "RHOAMApiUsageSustained",
+			Sustained: &marin3rconfig.AlertSustainedConfig{MinRate: "85%", Minutes: 15},
+		},
+	}
+
+	tests := []struct {
+		name          string
+		windows       []marin3rconfig.RateLimitWindow
+		wantSpikeExpr string
+		wantSpikeMsg  string
+		wantThreshold string
+		wantErr       bool
+	}{
+		{
+			name:          "test alerts for a single window",
+			windows:       []marin3rconfig.RateLimitWindow{{Unit: "minute", RequestsPerUnit: 100}},
+			wantSpikeExpr: "max_over_time((sum(increase(authorized_calls{limitador_namespace=\"apicast-ratelimit\"}[1m])) + sum(increase(limited_calls{limitador_namespace=\"apicast-ratelimit\"}[1m])))[30m:]) > 100",
+			wantSpikeMsg:  "hard limit of 100 requests per minute breached at least once in the last 30m",
+			wantThreshold: "Total API usage in your API Management service is between 80% and 90% of the allowable threshold, 100 requests per minute, during the last 4h",
+		},
+		{
+			name: "test alerts account for every window",
+			windows: []marin3rconfig.RateLimitWindow{
+				{Unit: "minute", RequestsPerUnit: 100},
+				{Unit: "second", RequestsPerUnit: 5},
+				{Unit: "day", RequestsPerUnit: 10000},
+			},
+			wantSpikeExpr: "max_over_time((sum(increase(authorized_calls{limitador_namespace=\"apicast-ratelimit\"}[1m])) + sum(increase(limited_calls{limitador_namespace=\"apicast-ratelimit\"}[1m])))[30m:]) > 100" +
+				" or max_over_time((sum(rate(authorized_calls{limitador_namespace=\"apicast-ratelimit\"}[1m])) + sum(rate(limited_calls{limitador_namespace=\"apicast-ratelimit\"}[1m])))[30m:]) > 5" +
+				" or max_over_time((sum(increase(authorized_calls{limitador_namespace=\"apicast-ratelimit\"}[1d])) + sum(increase(limited_calls{limitador_namespace=\"apicast-ratelimit\"}[1d])))[30m:]) > 10000",
+			wantSpikeMsg:  "hard limit of 100 requests per minute, 5 requests per second, 10000 requests per day breached at least once in the last 30m",
+			wantThreshold: "Total API usage in your API Management service is between 80% and 90% of the allowable threshold, 100 requests per minute, 5 requests per second, 10000 requests per day, during the last 4h",
+		},
+		{
+			name:    "test error for an unsupported unit",
+			windows: []marin3rconfig.RateLimitWindow{{Unit: "week", RequestsPerUnit: 100}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alerts, err := mapAlertsConfiguration(getLogger(), "observability", tt.windows, 1, alertsConfig, "grafana", string(integreatlyv1alpha1.InstallationTypeManagedApi))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("mapAlertsConfiguration() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			for _, alert := range alerts {
+				rule := alert.Rules[0]
+				switch alert.AlertName {
+				case "marin3r-api-usage-spike":
+					if rule.Expr.String() != tt.wantSpikeExpr {
+						t.Errorf("expected spike expression %s but got %s", tt.wantSpikeExpr, rule.Expr.String())
+					}
+					if rule.Annotations["message"] != tt.wantSpikeMsg {
+						t.Errorf("expected spike message %q but got %q", tt.wantSpikeMsg, rule.Annotations["message"])
+					}
+				case "marin3r-api-usage-level":
+					if rule.Annotations["message"] != tt.wantThreshold {
+						t.Errorf("expected threshold message %q but got %q", tt.wantThreshold, rule.Annotations["message"])
+					}
+				case "marin3r-api-usage-sustained":
+					wantExpr := "sum(rate(authorized_calls{limitador_namespace=\"apicast-ratelimit\"}[1m])) >= (1.000000 / 100 * 85)"
+					if rule.Expr.String() != wantExpr || rule.For != "15m" {
+						t.Errorf("expected sustained expression %s for 15m but got %s for %s", wantExpr, rule.Expr.String(), rule.For)
+					}
+				default:
+					t.Errorf("unexpected alert %s", alert.AlertName)
+				}
+			}
+		})
+	}
+}
@@ -4,17 +4,24 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sort"
+
+	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/apis/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
 	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 const (
 	RateLimitConfigMapName = "sku-limits-managed-api-service"
+	// AlertConfigMapName is the ConfigMap the alerts were configured in before the
+	// RateLimitAlertPolicy CR, it is only read to migrate the alerts to AlertPolicyName
 	AlertConfigMapName     = "rate-limit-alerts"
+	AlertPolicyName        = "rate-limit-alerts"
 	ManagedApiServiceQuota = "RHOAM SERVICE SKU"
 
 	AlertTypeThreshold = "Threshold"
 	AlertTypeSpike     = "Spike"
+	AlertTypeSustained = "Sustained"
 
 	DefaultRateLimitUnit     = "minute"
 	DefaultRateLimitRequests = 13860
@@ -23,6 +30,25 @@ const (
 type RateLimitConfig struct {
 	Unit            string `json:"unit"`
 	RequestsPerUnit uint32 `json:"requests_per_unit"`
+	// Windows are applied together with the limit above, a request is rejected when any of the
+	// windows is used up. A short window bounds the bursts a long window allows
+	Windows []RateLimitWindow `json:"windows,omitempty"`
+	// RouteLimits are applied on top of the limit above to the requests that match them
+	RouteLimits []RouteRateLimitConfig `json:"route_limits,omitempty"`
+	// Exemptions are the requests that are not counted against the limits above, such as the probes
+	// of the blackbox exporter. They are counted separately
+	Exemptions []RateLimitExemption `json:"exemptions,omitempty"`
+}
+
+// RateLimitWindow is a number of requests allowed in each window of a unit of time
+type RateLimitWindow struct {
+	Unit            string `json:"unit"`
+	RequestsPerUnit uint32 `json:"requests_per_unit"`
+}
+
+// GetWindows returns the window of the limit followed by the additional windows
+func (c RateLimitConfig) GetWindows() []RateLimitWindow {
+	return append([]RateLimitWindow{{Unit: c.Unit, RequestsPerUnit: c.RequestsPerUnit}}, c.Windows...)
 }
 
 type AlertConfig struct {
@@ -31,6 +57,7 @@ type AlertConfig struct {
 	RuleName  string                `json:"ruleName"`
 	Period    string                `json:"period"`
 	Threshold *AlertThresholdConfig `json:"threshold,omitempty"`
+	Sustained *AlertSustainedConfig `json:"sustained,omitempty"`
 }
 
 type AlertThresholdConfig struct {
@@ -38,6 +65,11 @@ type AlertThresholdConfig struct {
 	MaxRate *string `json:"maxRate,omitempty"`
 }
 
+type AlertSustainedConfig struct {
+	MinRate string `json:"minRate"`
+	Minutes int32  `json:"minutes"`
+}
+
 func GetAlertConfig(ctx context.Context, client k8sclient.Client, namespace string) (map[string]*AlertConfig, error) {
 	alertsConfig := map[string]*AlertConfig{}
 	err := getFromJSONConfigMap(
@@ -49,6 +81,82 @@ func GetAlertConfig(ctx context.Context, client k8sclient.Client, namespace stri
 	return alertsConfig, err
 }
 
+// GetAlertPolicies returns the RateLimitAlertPolicies in the namespace sorted by name
+func GetAlertPolicies(ctx context.Context, client k8sclient.Client, namespace string) ([]integreatlyv1alpha1.RateLimitAlertPolicy, error) {
+	policies := &integreatlyv1alpha1.RateLimitAlertPolicyList{}
+	if err := client.List(ctx, policies, k8sclient.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(policies.Items, func(i, j int) bool {
+		return policies.Items[i].Name < policies.Items[j].Name
+	})
+
+	return policies.Items, nil
+}
+
+// AlertConfigFromPolicy maps an alert of a RateLimitAlertPolicy to the AlertConfig the
+// PrometheusRule is generated from
+func AlertConfigFromPolicy(alert integreatlyv1alpha1.RateLimitAlert) *AlertConfig {
+	alertConfig := &AlertConfig{
+		Type:     string(alert.Type),
+		Level:    alert.Level,
+		RuleName: alert.RuleName,
+		Period:   alert.Period,
+	}
+	if alert.Threshold != nil {
+		alertConfig.Threshold = &AlertThresholdConfig{
+			MinRate: alert.Threshold.MinRate,
+			MaxRate: alert.Threshold.MaxRate,
+		}
+	}
+	if alert.Sustained != nil {
+		alertConfig.Sustained = &AlertSustainedConfig{
+			MinRate: alert.Sustained.MinRate,
+			Minutes: alert.Sustained.Minutes,
+		}
+	}
+
+	return alertConfig
+}
+
+// PolicyAlertsFromConfig maps the alerts of the AlertConfigMapName ConfigMap to the alerts of
+// a RateLimitAlertPolicy, sorted by name
+func PolicyAlertsFromConfig(alertsConfig map[string]*AlertConfig) []integreatlyv1alpha1.RateLimitAlert {
+	alerts := make([]integreatlyv1alpha1.RateLimitAlert, 0, len(alertsConfig))
+	for name, alertConfig := range alertsConfig {
+		if alertConfig == nil {
+			continue
+		}
+		alert := integreatlyv1alpha1.RateLimitAlert{
+			Name:     name,
+			Type:     integreatlyv1alpha1.RateLimitAlertType(alertConfig.Type),
+			RuleName: alertConfig.RuleName,
+			Level:    alertConfig.Level,
+			Period:   alertConfig.Period,
+		}
+		if alertConfig.Threshold != nil {
+			alert.Threshold = &integreatlyv1alpha1.RateLimitAlertThreshold{
+				MinRate: alertConfig.Threshold.MinRate,
+				MaxRate: alertConfig.Threshold.MaxRate,
+			}
+		}
+		if alertConfig.Sustained != nil {
+			alert.Sustained = &integreatlyv1alpha1.RateLimitAlertSustained{
+				MinRate: alertConfig.Sustained.MinRate,
+				Minutes: alertConfig.Sustained.Minutes,
+			}
+		}
+		alerts = append(alerts, alert)
+	}
+
+	sort.Slice(alerts, func(i, j int) bool {
+		return alerts[i].Name < alerts[j].Name
+	})
+
+	return alerts
+}
+
 func GetQuota(_ context.Context, _ k8sclient.Client) (string, error) {
 	return ManagedApiServiceQuota, nil
 }
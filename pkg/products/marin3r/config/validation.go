@@ -0,0 +1,186 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	routeLimitNameRegex  = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+	routeLimitHeaderName = regexp.MustCompile(`^[a-z0-9-]+$`)
+
+	routeLimitMethods = []string{"GET", "HEAD", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"}
+)
+
+// RouteRateLimitConfig limits the requests to APIcast that match a path prefix, and optionally an
+// HTTP method and the values of request headers
+//
+// Example, limit the token requests to 100 per minute:
+//
+//	{"name": "oauth-token", "path_prefix": "/oauth/token", "method": "POST", "unit": "minute", "requests_per_unit": 100}
+type RouteRateLimitConfig struct {
+	// Name identifies the limit, it is sent to the rate limit service as the value of the route descriptor
+	Name       string `json:"name"`
+	PathPrefix string `json:"path_prefix"`
+	// Method is matched when set, every method matches otherwise
+	Method string `json:"method,omitempty"`
+	// Headers are matched by exact value, header names are lower case
+	Headers         map[string]string `json:"headers,omitempty"`
+	Unit            string            `json:"unit"`
+	RequestsPerUnit uint32            `json:"requests_per_unit"`
+}
+
+// RateLimitExemption identifies requests that bypass the rate limit. A request is exempt when it
+// comes from one of the source CIDRs, or when it matches the service IDs. Any client can send a
+// header, so the header only narrows the requests from the source CIDRs down to the ones that also
+// have it. The service IDs are matched against the service_id query parameter of the requests to
+// backend-listener, APIcast requests don't have the ID of the service
+//
+// The source CIDRs are matched against the address of the connection to the Envoy sidecar, so they
+// only apply to requests sent to the APIcast and backend-listener Services from inside the cluster.
+// Requests through the OpenShift routes reach the sidecar from the router and are never exempt by
+// source CIDR, they are told apart by the X-Forwarded-For header the router adds
+//
+// Example, exempt the probes sent by a monitoring host:
+//
+//	{"name": "blackbox-exporter", "source_cidrs": ["10.0.12.7/32"], "header": {"name": "user-agent", "value": "blackbox-exporter"}}
+type RateLimitExemption struct {
+	// Name identifies the exemption, the exempt requests are counted by name
+	Name        string                    `json:"name"`
+	SourceCIDRs []string                  `json:"source_cidrs,omitempty"`
+	Header      *RateLimitExemptionHeader `json:"header,omitempty"`
+	ServiceIDs  []string                  `json:"service_ids,omitempty"`
+}
+
+// RateLimitExemptionHeader is matched by exact value, the header name is lower case
+type RateLimitExemptionHeader struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// Validate returns an error when the windows, the route limits or the exemptions can't be applied. Envoy sends a
+// request through the first route it matches, so a route limit with the same match as a previous one
+// is rejected as it would never apply
+func (c RateLimitConfig) Validate() error {
+	units := map[string]bool{c.Unit: true}
+	for i, window := range c.Windows {
+		if _, ok := conversionFactors[window.Unit]; !ok {
+			return fmt.Errorf("invalid window %d: unsupported unit %q", i, window.Unit)
+		}
+		if window.RequestsPerUnit == 0 {
+			return fmt.Errorf("invalid window %d: the requests per unit must be greater than 0", i)
+		}
+		if units[window.Unit] {
+			return fmt.Errorf("invalid window %d: another window has the unit %s", i, window.Unit)
+		}
+		units[window.Unit] = true
+	}
+
+	names := map[string]bool{}
+	for i, routeLimit := range c.RouteLimits {
+		if err := routeLimit.validate(); err != nil {
+			return fmt.Errorf("invalid route limit %d %q: %w", i, routeLimit.Name, err)
+		}
+		if names[routeLimit.Name] {
+			return fmt.Errorf("invalid route limit %d %q: the name is used by another route limit", i, routeLimit.Name)
+		}
+		names[routeLimit.Name] = true
+
+		for _, previous := range c.RouteLimits[:i] {
+			if previous.sameMatch(routeLimit) {
+				return fmt.Errorf("invalid route limit %d %q: route limit %q matches the same requests", i, routeLimit.Name, previous.Name)
+			}
+		}
+	}
+
+	exemptionNames := map[string]bool{}
+	for i, exemption := range c.Exemptions {
+		if err := exemption.validate(); err != nil {
+			return fmt.Errorf("invalid exemption %d %q: %w", i, exemption.Name, err)
+		}
+		if exemptionNames[exemption.Name] {
+			return fmt.Errorf("invalid exemption %d %q: the name is used by another exemption", i, exemption.Name)
+		}
+		exemptionNames[exemption.Name] = true
+	}
+
+	return nil
+}
+
+func (r RouteRateLimitConfig) validate() error {
+	if !routeLimitNameRegex.MatchString(r.Name) || len(r.Name) > 63 {
+		return fmt.Errorf("the name must be at most 63 lower case alphanumeric characters or '-'")
+	}
+	if !strings.HasPrefix(r.PathPrefix, "/") {
+		return fmt.Errorf("the path prefix must start with /")
+	}
+	if r.Method != "" && !contains(routeLimitMethods, r.Method) {
+		return fmt.Errorf("unsupported method %s, expected one of %s", r.Method, strings.Join(routeLimitMethods, ", "))
+	}
+	for name, value := range r.Headers {
+		if !routeLimitHeaderName.MatchString(name) {
+			return fmt.Errorf("invalid header name %q, header names must be lower case", name)
+		}
+		if value == "" {
+			return fmt.Errorf("the value of header %s must not be empty", name)
+		}
+	}
+	if _, ok := conversionFactors[r.Unit]; !ok {
+		return fmt.Errorf("unsupported unit %q", r.Unit)
+	}
+	if r.RequestsPerUnit == 0 {
+		return fmt.Errorf("the requests per unit must be greater than 0")
+	}
+
+	return nil
+}
+
+func (e RateLimitExemption) validate() error {
+	if !routeLimitNameRegex.MatchString(e.Name) || len(e.Name) > 63 {
+		return fmt.Errorf("the name must be at most 63 lower case alphanumeric characters or '-'")
+	}
+	if len(e.SourceCIDRs) == 0 && len(e.ServiceIDs) == 0 {
+		return fmt.Errorf("at least one of source_cidrs or service_ids must be set")
+	}
+	if e.Header != nil && len(e.SourceCIDRs) == 0 {
+		return fmt.Errorf("the header is only matched for the requests from source_cidrs, which must be set")
+	}
+	for _, cidr := range e.SourceCIDRs {
+		if _, ipNet, err := net.ParseCIDR(cidr); err != nil || ipNet.IP.To4() == nil {
+			return fmt.Errorf("invalid source CIDR %q, expected an IPv4 CIDR such as 10.0.0.0/8", cidr)
+		}
+	}
+	if e.Header != nil {
+		if !routeLimitHeaderName.MatchString(e.Header.Name) {
+			return fmt.Errorf("invalid header name %q, header names must be lower case", e.Header.Name)
+		}
+		if e.Header.Value == "" {
+			return fmt.Errorf("the value of header %s must not be empty", e.Header.Name)
+		}
+	}
+	for _, serviceID := range e.ServiceIDs {
+		if _, err := strconv.ParseUint(serviceID, 10, 64); err != nil {
+			return fmt.Errorf("invalid service ID %q, expected the numeric ID of a 3scale service", serviceID)
+		}
+	}
+
+	return nil
+}
+
+func (r RouteRateLimitConfig) sameMatch(other RouteRateLimitConfig) bool {
+	return r.PathPrefix == other.PathPrefix && r.Method == other.Method &&
+		(len(r.Headers) == 0 && len(other.Headers) == 0 || reflect.DeepEqual(r.Headers, other.Headers))
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,174 @@
+package config
+
+import "testing"
+
+func TestRateLimitConfig_Validate(t *testing.T) {
+	oauthToken := RouteRateLimitConfig{
+		Name:            "oauth-token",
+		PathPrefix:      "/oauth/token",
+		Method:          "POST",
+		Unit:            Minute,
+		RequestsPerUnit: 100,
+	}
+	withChange := func(change func(*RouteRateLimitConfig)) RouteRateLimitConfig {
+		routeLimit := oauthToken
+		change(&routeLimit)
+		return routeLimit
+	}
+
+	blackbox := RateLimitExemption{
+		Name:        "blackbox-exporter",
+		SourceCIDRs: []string{"10.0.12.7/32"},
+		Header:      &RateLimitExemptionHeader{Name: "user-agent", Value: "blackbox-exporter"},
+		ServiceIDs:  []string{"2555417777820"},
+	}
+
+	tests := []struct {
+		name        string
+		windows     []RateLimitWindow
+		routeLimits []RouteRateLimitConfig
+		exemptions  []RateLimitExemption
+		wantErr     bool
+	}{
+		{
+			name: "test config without route limits is valid",
+		},
+		{
+			name:    "test valid windows",
+			windows: []RateLimitWindow{{Unit: Second, RequestsPerUnit: 50}, {Unit: Day, RequestsPerUnit: 500000}},
+		},
+		{
+			name:    "test window with an unsupported unit",
+			windows: []RateLimitWindow{{Unit: "week", RequestsPerUnit: 50}},
+			wantErr: true,
+		},
+		{
+			name:    "test window requests per unit must be set",
+			windows: []RateLimitWindow{{Unit: Second}},
+			wantErr: true,
+		},
+		{
+			name:    "test window with the unit of the limit",
+			windows: []RateLimitWindow{{Unit: Minute, RequestsPerUnit: 50}},
+			wantErr: true,
+		},
+		{
+			name:    "test windows with the same unit",
+			windows: []RateLimitWindow{{Unit: Second, RequestsPerUnit: 50}, {Unit: Second, RequestsPerUnit: 20}},
+			wantErr: true,
+		},
+		{
+			name: "test valid route limits",
+			routeLimits: []RouteRateLimitConfig{
+				oauthToken,
+				withChange(func(r *RouteRateLimitConfig) { r.Name = "oauth-token-get"; r.Method = "GET" }),
+				withChange(func(r *RouteRateLimitConfig) {
+					r.Name = "admin-api"
+					r.PathPrefix = "/admin/api"
+					r.Method = ""
+					r.Headers = map[string]string{"x-client": "batch"}
+				}),
+			},
+		},
+		{
+			name:        "test invalid name",
+			routeLimits: []RouteRateLimitConfig{withChange(func(r *RouteRateLimitConfig) { r.Name = "OAuth Token" })},
+			wantErr:     true,
+		},
+		{
+			name:        "test duplicated name",
+			routeLimits: []RouteRateLimitConfig{oauthToken, withChange(func(r *RouteRateLimitConfig) { r.PathPrefix = "/other" })},
+			wantErr:     true,
+		},
+		{
+			name:        "test path prefix must be absolute",
+			routeLimits: []RouteRateLimitConfig{withChange(func(r *RouteRateLimitConfig) { r.PathPrefix = "oauth/token" })},
+			wantErr:     true,
+		},
+		{
+			name:        "test unsupported method",
+			routeLimits: []RouteRateLimitConfig{withChange(func(r *RouteRateLimitConfig) { r.Method = "post" })},
+			wantErr:     true,
+		},
+		{
+			name:        "test header names must be lower case",
+			routeLimits: []RouteRateLimitConfig{withChange(func(r *RouteRateLimitConfig) { r.Headers = map[string]string{"X-Client": "batch"} })},
+			wantErr:     true,
+		},
+		{
+			name:        "test header values must not be empty",
+			routeLimits: []RouteRateLimitConfig{withChange(func(r *RouteRateLimitConfig) { r.Headers = map[string]string{"x-client": ""} })},
+			wantErr:     true,
+		},
+		{
+			name:        "test unsupported unit",
+			routeLimits: []RouteRateLimitConfig{withChange(func(r *RouteRateLimitConfig) { r.Unit = "week" })},
+			wantErr:     true,
+		},
+		{
+			name:        "test requests per unit must be set",
+			routeLimits: []RouteRateLimitConfig{withChange(func(r *RouteRateLimitConfig) { r.RequestsPerUnit = 0 })},
+			wantErr:     true,
+		},
+		{
+			name:        "test route limit matching the same requests as a previous one",
+			routeLimits: []RouteRateLimitConfig{oauthToken, withChange(func(r *RouteRateLimitConfig) { r.Name = "oauth-token-strict" })},
+			wantErr:     true,
+		},
+		{
+			name:       "test valid exemptions",
+			exemptions: []RateLimitExemption{blackbox, {Name: "internal", ServiceIDs: []string{"3"}}},
+		},
+		{
+			name:       "test exemption without matchers",
+			exemptions: []RateLimitExemption{{Name: "blackbox-exporter"}},
+			wantErr:    true,
+		},
+		{
+			name:       "test duplicated exemption name",
+			exemptions: []RateLimitExemption{blackbox, blackbox},
+			wantErr:    true,
+		},
+		{
+			name:       "test exemption source CIDRs must be IPv4 CIDRs",
+			exemptions: []RateLimitExemption{{Name: "ipv6", SourceCIDRs: []string{"fd00::/8"}}},
+			wantErr:    true,
+		},
+		{
+			name:       "test exemption header names must be lower case",
+			exemptions: []RateLimitExemption{{Name: "probes", SourceCIDRs: []string{"10.0.12.7/32"}, Header: &RateLimitExemptionHeader{Name: "User-Agent", Value: "probe"}}},
+			wantErr:    true,
+		},
+		{
+			name:       "test exemption header requires source CIDRs",
+			exemptions: []RateLimitExemption{{Name: "probes", Header: &RateLimitExemptionHeader{Name: "user-agent", Value: "probe"}, ServiceIDs: []string{"3"}}},
+			wantErr:    true,
+		},
+		{
+			name:       "test exemption service IDs must be numeric",
+			exemptions: []RateLimitExemption{{Name: "internal", ServiceIDs: []string{"api"}}},
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := RateLimitConfig{Unit: Minute, RequestsPerUnit: 1000, Windows: tt.windows, RouteLimits: tt.routeLimits, Exemptions: tt.exemptions}
+			if err := config.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRateLimitConfig_GetWindows(t *testing.T) {
+	config := RateLimitConfig{
+		Unit:            Minute,
+		RequestsPerUnit: 1000,
+		Windows:         []RateLimitWindow{{Unit: Second, RequestsPerUnit: 50}},
+	}
+
+	windows := config.GetWindows()
+	if len(windows) != 2 || windows[0] != (RateLimitWindow{Unit: Minute, RequestsPerUnit: 1000}) || windows[1] != config.Windows[0] {
+		t.Errorf("expected the window of the limit followed by the additional windows but got %v", windows)
+	}
+}
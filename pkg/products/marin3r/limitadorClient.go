@@ -2,52 +2,164 @@ package marin3r
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
-	"github.com/integr8ly/integreatly-operator/pkg/resources"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/integr8ly/integreatly-operator/pkg/resources/quota"
+	"k8s.io/apimachinery/pkg/util/wait"
 )
 
+const limitadorAdminPort = 8080
+
+// defaultLimitadorBackoff retries a request twice, the rate limit pods are often restarted to
+// reload their limits and take a moment to serve requests again
+var defaultLimitadorBackoff = wait.Backoff{
+	Steps:    3,
+	Duration: 500 * time.Millisecond,
+	Factor:   2,
+	Jitter:   0.1,
+}
+
+// LimitadorClientInterface is a client of the limitador admin API. The limitador version deployed
+// by the operator loads its limits from the limits file and has no endpoint to create them, the
+// limits can only be read and deleted
 type LimitadorClientInterface interface {
 	GetLimitsByName(string) ([]limitadorLimit, error)
-	CurlDeleteLimitsByNameUsingPod(string, string, string, string) error
+	DeleteLimitsByName(string) error
+	GetCountersByName(string) ([]limitadorCounter, error)
 }
 
+// LimitadorClient talks to the limitador admin API through the rate limit Service
 type LimitadorClient struct {
-	PodExecutor resources.PodExecutorInterface
-	PodName     string
-	Namespace   string
+	HTTPClient *http.Client
+	BaseURL    string
+	// Backoff is how often and how long apart the requests that find limitador unavailable are
+	// made, a request is made once when it has no steps
+	Backoff wait.Backoff
 }
 
 var _ LimitadorClientInterface = &LimitadorClient{}
 
-func NewLimitadorClient(podExecutor resources.PodExecutorInterface, nameSpace, podName string) *LimitadorClient {
+// limitadorCounter is the usage of a limit for one set of values of its variables
+type limitadorCounter struct {
+	Limit            limitadorLimit    `json:"limit"`
+	SetVariables     map[string]string `json:"set_variables"`
+	Remaining        *int64            `json:"remaining"`
+	ExpiresInSeconds *uint64           `json:"expires_in_seconds"`
+}
+
+// LimitadorError is returned when the limitador admin API responds with an unexpected status
+type LimitadorError struct {
+	Method     string
+	Path       string
+	StatusCode int
+	Message    string
+}
+
+func (e *LimitadorError) Error() string {
+	return fmt.Sprintf("limitador %s %s failed with status %d: %s", e.Method, e.Path, e.StatusCode, e.Message)
+}
+
+// IsLimitadorNotFound returns true when err is a LimitadorError for a not found response
+func IsLimitadorNotFound(err error) bool {
+	var limitadorErr *LimitadorError
+	return errors.As(err, &limitadorErr) && limitadorErr.StatusCode == http.StatusNotFound
+}
+
+// IsLimitadorUnavailable returns true when err is for a request that did not reach limitador or
+// that limitador failed with a server error, the request can be made again on a later reconcile
+func IsLimitadorUnavailable(err error) bool {
+	var limitadorErr *LimitadorError
+	if errors.As(err, &limitadorErr) {
+		return limitadorErr.StatusCode >= http.StatusInternalServerError
+	}
+	var urlErr *url.Error
+	return errors.As(err, &urlErr)
+}
+
+// NewLimitadorClient returns a client of the limitador admin API served at baseURL
+func NewLimitadorClient(httpClient *http.Client, baseURL string) *LimitadorClient {
 	return &LimitadorClient{
-		PodExecutor: podExecutor,
-		Namespace:   nameSpace,
-		PodName:     podName,
+		HTTPClient: httpClient,
+		BaseURL:    baseURL,
+		Backoff:    defaultLimitadorBackoff,
 	}
 }
 
-func (l LimitadorClient) GetLimitsByName(limitName string) ([]limitadorLimit, error) {
-	response, _, err := l.PodExecutor.ExecuteRemoteCommand(l.Namespace, l.PodName, []string{"/bin/sh",
-		"-c", fmt.Sprintf("wget -qO - http://127.0.0.1:8080/limits/%s", limitName)})
-	if err != nil {
+// GetLimitadorServiceURL returns the URL of the limitador admin API of the rate limit Service in namespace
+func GetLimitadorServiceURL(namespace string) string {
+	return fmt.Sprintf("http://%s.%s.svc:%d", quota.RateLimitName, namespace, limitadorAdminPort)
+}
+
+func (l *LimitadorClient) GetLimitsByName(limitName string) ([]limitadorLimit, error) {
+	limits := []limitadorLimit{}
+	if err := l.do(http.MethodGet, "/limits/"+url.PathEscape(limitName), &limits); err != nil {
 		return nil, err
 	}
+	return limits, nil
+}
 
-	limitadorLimitsInRedis := []limitadorLimit{}
-	err = json.Unmarshal([]byte(response), &limitadorLimitsInRedis)
-	if err != nil {
+func (l *LimitadorClient) DeleteLimitsByName(limitName string) error {
+	return l.do(http.MethodDelete, "/limits/"+url.PathEscape(limitName), nil)
+}
+
+func (l *LimitadorClient) GetCountersByName(limitName string) ([]limitadorCounter, error) {
+	counters := []limitadorCounter{}
+	if err := l.do(http.MethodGet, "/counters/"+url.PathEscape(limitName), &counters); err != nil {
 		return nil, err
 	}
+	return counters, nil
+}
 
-	return limitadorLimitsInRedis, nil
+// do sends the request and decodes the response into out. Requests that find limitador unavailable
+// are retried with the backoff of the client, the error of the last request is returned once the
+// backoff is used up so callers can check IsLimitadorUnavailable and try again on a later reconcile
+func (l *LimitadorClient) do(method, path string, out interface{}) error {
+	backoff := l.Backoff
+	for {
+		err := l.send(method, path, out)
+		if !IsLimitadorUnavailable(err) || backoff.Steps <= 1 {
+			return err
+		}
+		time.Sleep(backoff.Step())
+	}
 }
 
-func (l LimitadorClient) CurlDeleteLimitsByNameUsingPod(limitName, namespace, podName, rateLimitPodIP string) error {
-	_, _, err := l.PodExecutor.ExecuteRemoteCommand(namespace, podName, []string{"/bin/sh",
-		"-c", fmt.Sprintf("curl -X DELETE %s:8080/limits/%s", rateLimitPodIP, limitName)})
+func (l *LimitadorClient) send(method, path string, out interface{}) error {
+	req, err := http.NewRequest(method, l.BaseURL+path, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create limitador request: %w", err)
+	}
+
+	resp, err := l.HTTPClient.Do(req)
 	if err != nil {
-		return err
+		return fmt.Errorf("limitador %s %s failed: %w", method, path, err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read limitador response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return &LimitadorError{
+			Method:     method,
+			Path:       path,
+			StatusCode: resp.StatusCode,
+			Message:    string(respBody),
+		}
+	}
+
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return fmt.Errorf("failed to unmarshal limitador response: %w", err)
 	}
 
 	return nil
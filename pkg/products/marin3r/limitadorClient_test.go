@@ -0,0 +1,179 @@
+package marin3r
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeLimitador serves the limitador admin API from memory
+type fakeLimitador struct {
+	mu       sync.Mutex
+	limits   []limitadorLimit
+	counters []limitadorCounter
+	// failures is the number of requests answered with a server error before the requests are served
+	failures int
+	requests int
+}
+
+func (f *fakeLimitador) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.requests++
+	if f.failures > 0 {
+		f.failures--
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+		return
+	}
+
+	switch {
+	case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/limits/"):
+		namespace := strings.TrimPrefix(r.URL.Path, "/limits/")
+		limits := []limitadorLimit{}
+		for _, limit := range f.limits {
+			if limit.Namespace == namespace {
+				limits = append(limits, limit)
+			}
+		}
+		json.NewEncoder(w).Encode(limits)
+	case r.Method == http.MethodDelete && strings.HasPrefix(r.URL.Path, "/limits/"):
+		namespace := strings.TrimPrefix(r.URL.Path, "/limits/")
+		limits := []limitadorLimit{}
+		for _, limit := range f.limits {
+			if limit.Namespace != namespace {
+				limits = append(limits, limit)
+			}
+		}
+		f.limits = limits
+	case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/counters/"):
+		namespace := strings.TrimPrefix(r.URL.Path, "/counters/")
+		counters := []limitadorCounter{}
+		for _, counter := range f.counters {
+			if counter.Limit.Namespace == namespace {
+				counters = append(counters, counter)
+			}
+		}
+		json.NewEncoder(w).Encode(counters)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func newTestLimitadorClient(t *testing.T, handler http.Handler) *LimitadorClient {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	client := NewLimitadorClient(server.Client(), server.URL)
+	client.Backoff.Duration = time.Millisecond
+	return client
+}
+
+func TestLimitadorClient_GetLimitsByName(t *testing.T) {
+	slowpath := limitadorLimit{Namespace: "apicast-ratelimit", MaxValue: 1, Seconds: 60, Conditions: []string{"generic_key == slowpath"}, Variables: []string{"generic_key"}}
+	other := limitadorLimit{Namespace: "other", MaxValue: 5, Seconds: 1, Conditions: []string{}, Variables: []string{}}
+
+	tests := []struct {
+		name            string
+		limitador       *fakeLimitador
+		want            []limitadorLimit
+		wantErr         bool
+		wantUnavailable bool
+		wantRequests    int
+	}{
+		{
+			name:         "test limits of the namespace are returned",
+			limitador:    &fakeLimitador{limits: []limitadorLimit{slowpath, other}},
+			want:         []limitadorLimit{slowpath},
+			wantRequests: 1,
+		},
+		{
+			name:         "test server errors are retried",
+			limitador:    &fakeLimitador{limits: []limitadorLimit{slowpath}, failures: 2},
+			want:         []limitadorLimit{slowpath},
+			wantRequests: 3,
+		},
+		{
+			name:            "test server errors are returned as unavailable once the retries are used up",
+			limitador:       &fakeLimitador{limits: []limitadorLimit{slowpath}, failures: 3},
+			wantErr:         true,
+			wantUnavailable: true,
+			wantRequests:    3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := newTestLimitadorClient(t, tt.limitador).GetLimitsByName("apicast-ratelimit")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetLimitsByName() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if IsLimitadorUnavailable(err) != tt.wantUnavailable {
+				t.Errorf("expected IsLimitadorUnavailable() to be %v for %v", tt.wantUnavailable, err)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetLimitsByName() got = %v, want %v", got, tt.want)
+			}
+			if tt.limitador.requests != tt.wantRequests {
+				t.Errorf("expected %d requests but got %d", tt.wantRequests, tt.limitador.requests)
+			}
+		})
+	}
+}
+
+func TestLimitadorClient_Errors(t *testing.T) {
+	client := newTestLimitadorClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/counters/invalid" {
+			w.Write([]byte("notJson"))
+			return
+		}
+		http.NotFound(w, r)
+	}))
+
+	err := client.DeleteLimitsByName("missing")
+	if !IsLimitadorNotFound(err) {
+		t.Errorf("expected a not found error but got %v", err)
+	}
+	if IsLimitadorUnavailable(err) {
+		t.Errorf("expected a not found error not to be unavailable")
+	}
+
+	if _, err := client.GetCountersByName("invalid"); err == nil || IsLimitadorNotFound(err) {
+		t.Errorf("expected an error decoding the response but got %v", err)
+	}
+}
+
+func TestLimitadorClient_DeleteLimitsByName(t *testing.T) {
+	limit := limitadorLimit{Namespace: "apicast-ratelimit", MaxValue: 10, Seconds: 60, Conditions: []string{"generic_key == slowpath"}, Variables: []string{"generic_key"}}
+	client := newTestLimitadorClient(t, &fakeLimitador{limits: []limitadorLimit{limit}})
+
+	if err := client.DeleteLimitsByName(limit.Namespace); err != nil {
+		t.Fatalf("DeleteLimitsByName() error = %v", err)
+	}
+	if got, err := client.GetLimitsByName(limit.Namespace); err != nil || len(got) != 0 {
+		t.Fatalf("expected no limits but got %v, %v", got, err)
+	}
+}
+
+func TestLimitadorClient_GetCountersByName(t *testing.T) {
+	remaining := int64(4)
+	expires := uint64(30)
+	counter := limitadorCounter{
+		Limit:            limitadorLimit{Namespace: "apicast-ratelimit", MaxValue: 10, Seconds: 60, Conditions: []string{"generic_key == slowpath"}, Variables: []string{"generic_key"}},
+		SetVariables:     map[string]string{"generic_key": "slowpath"},
+		Remaining:        &remaining,
+		ExpiresInSeconds: &expires,
+	}
+
+	got, err := newTestLimitadorClient(t, &fakeLimitador{counters: []limitadorCounter{counter}}).GetCountersByName("apicast-ratelimit")
+	if err != nil {
+		t.Fatalf("GetCountersByName() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, []limitadorCounter{counter}) {
+		t.Errorf("GetCountersByName() got = %v, want %v", got, []limitadorCounter{counter})
+	}
+}
@@ -5,23 +5,25 @@ import (
 	"crypto/sha256"
 	"fmt"
 	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/apis/v1alpha1"
-	"github.com/integr8ly/integreatly-operator/pkg/config"
 	marin3rconfig "github.com/integr8ly/integreatly-operator/pkg/products/marin3r/config"
 	"github.com/integr8ly/integreatly-operator/pkg/resources"
 	"github.com/integr8ly/integreatly-operator/pkg/resources/quota"
 	"github.com/integr8ly/integreatly-operator/pkg/resources/ratelimit"
 	"gopkg.in/yaml.v2"
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
 	corev1 "k8s.io/api/core/v1"
 	k8sError "k8s.io/apimachinery/pkg/api/errors"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"math"
 	"reflect"
 	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sort"
 	"strconv"
+	"strings"
 )
 
 const (
@@ -38,23 +40,24 @@ const (
 	rateLimitImage                = "quay.io/3scale/limitador:v0.5.1"
 )
 
+// rateLimitDomains are the limitador namespaces of the limits managed by the operator
+var rateLimitDomains = []string{ratelimit.RateLimitDomain, ratelimit.RateLimitExemptDomain}
+
 type RateLimitServiceReconciler struct {
 	Namespace       string
 	RedisSecretName string
 	Installation    *integreatlyv1alpha1.RHMI
 	RateLimitConfig marin3rconfig.RateLimitConfig
-	PodExecutor     resources.PodExecutorInterface
-	ConfigManager   config.ConfigReadWriter
+	LimitadorClient LimitadorClientInterface
 }
 
-func NewRateLimitServiceReconciler(config marin3rconfig.RateLimitConfig, installation *integreatlyv1alpha1.RHMI, namespace, redisSecretName string, podExecutor resources.PodExecutorInterface, configManager config.ConfigReadWriter) *RateLimitServiceReconciler {
+func NewRateLimitServiceReconciler(config marin3rconfig.RateLimitConfig, installation *integreatlyv1alpha1.RHMI, namespace, redisSecretName string, limitadorClient LimitadorClientInterface) *RateLimitServiceReconciler {
 	return &RateLimitServiceReconciler{
 		RateLimitConfig: config,
 		Installation:    installation,
 		Namespace:       namespace,
 		RedisSecretName: redisSecretName,
-		PodExecutor:     podExecutor,
-		ConfigManager:   configManager,
+		LimitadorClient: limitadorClient,
 	}
 }
 
@@ -70,6 +73,10 @@ type limitadorLimit struct {
 // It reconciles a ConfigMap to configure the service, a Deployment to run it, and
 // exposes it as a Service
 func (r *RateLimitServiceReconciler) ReconcileRateLimitService(ctx context.Context, client k8sclient.Client, productConfig quota.ProductConfig) (integreatlyv1alpha1.StatusPhase, error) {
+	if err := r.RateLimitConfig.Validate(); err != nil {
+		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("invalid rate limit config: %w", err)
+	}
+
 	phase, err := r.reconcileConfigMap(ctx, client)
 	if err != nil {
 		return integreatlyv1alpha1.PhaseFailed, err
@@ -80,6 +87,11 @@ func (r *RateLimitServiceReconciler) ReconcileRateLimitService(ctx context.Conte
 		return phase, err
 	}
 
+	phase, err = r.reconcileAutoscaler(ctx, client, productConfig)
+	if phase != integreatlyv1alpha1.PhaseCompleted {
+		return phase, err
+	}
+
 	phase, err = r.reconcileService(ctx, client)
 	if phase != integreatlyv1alpha1.PhaseCompleted {
 		return phase, err
@@ -313,6 +325,20 @@ func (r *RateLimitServiceReconciler) reconcileDeployment(ctx context.Context, cl
 	return integreatlyv1alpha1.PhaseCompleted, nil
 }
 
+// reconcileAutoscaler creates the horizontal pod autoscaler of the deployment if the quota autoscales
+// the rate limit service, and deletes it otherwise
+func (r *RateLimitServiceReconciler) reconcileAutoscaler(ctx context.Context, client k8sclient.Client, productConfig quota.ProductConfig) (integreatlyv1alpha1.StatusPhase, error) {
+	target := autoscalingv2beta2.CrossVersionObjectReference{
+		APIVersion: appsv1.SchemeGroupVersion.String(),
+		Kind:       "Deployment",
+		Name:       quota.RateLimitName,
+	}
+	if err := quota.ReconcileAutoscaler(ctx, client, productConfig, quota.RateLimitName, r.Namespace, target); err != nil {
+		return integreatlyv1alpha1.PhaseFailed, err
+	}
+	return integreatlyv1alpha1.PhaseCompleted, nil
+}
+
 func (r *RateLimitServiceReconciler) reconcileService(ctx context.Context, client k8sclient.Client) (integreatlyv1alpha1.StatusPhase, error) {
 	service := &corev1.Service{
 		ObjectMeta: v1.ObjectMeta{
@@ -475,16 +501,18 @@ func (r *RateLimitServiceReconciler) getCurrentLimitPerTenant(client k8sclient.C
 	return currentLimit, nil
 }
 
+// getRHOAMLimitadorSetting returns a limit of the requests of every tenant for each window of the
+// rate limit config, a request is rejected when any of the windows is used up
 func (r *RateLimitServiceReconciler) getRHOAMLimitadorSetting() ([]limitadorLimit, error) {
-	unitInSeconds, err := r.getUnitInSeconds(r.RateLimitConfig.Unit)
-	if err != nil {
-		return nil, err
-	}
-
-	return []limitadorLimit{
-		{
+	limits := []limitadorLimit{}
+	for _, window := range r.RateLimitConfig.GetWindows() {
+		unitInSeconds, err := r.getUnitInSeconds(window.Unit)
+		if err != nil {
+			return nil, err
+		}
+		limits = append(limits, limitadorLimit{
 			Namespace: ratelimit.RateLimitDomain,
-			MaxValue:  r.RateLimitConfig.RequestsPerUnit,
+			MaxValue:  window.RequestsPerUnit,
 			Seconds:   unitInSeconds,
 			Conditions: []string{
 				fmt.Sprintf("%s == %s", genericKey, ratelimit.RateLimitDescriptorValue),
@@ -492,8 +520,10 @@ func (r *RateLimitServiceReconciler) getRHOAMLimitadorSetting() ([]limitadorLimi
 			Variables: []string{
 				genericKey,
 			},
-		},
-	}, nil
+		})
+	}
+
+	return limits, nil
 }
 
 func (r *RateLimitServiceReconciler) getMultitenantRHOAMLimitadorSetting(ctx context.Context, client k8sclient.Client) ([]limitadorLimit, error) {
@@ -508,30 +538,49 @@ func (r *RateLimitServiceReconciler) getMultitenantRHOAMLimitadorSetting(ctx con
 		return nil, err
 	}
 
-	return []limitadorLimit{
-		{
-			Namespace: ratelimit.RateLimitDomain,
-			MaxValue:  r.RateLimitConfig.RequestsPerUnit,
-			Seconds:   unitInSeconds,
-			Conditions: []string{
-				fmt.Sprintf("%s == %s", genericKey, ratelimit.RateLimitDescriptorValue),
-			},
-			Variables: []string{
-				genericKey,
-			},
+	overrides, err := ratelimit.GetTenantRateLimitOverrides(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+
+	limits, err := r.getRHOAMLimitadorSetting()
+	if err != nil {
+		return nil, err
+	}
+	limits = append(limits, limitadorLimit{
+		Namespace: ratelimit.RateLimitDomain,
+		MaxValue:  limitPerTenant,
+		Seconds:   unitInSeconds,
+		Conditions: []string{
+			fmt.Sprintf("%s == %s", headerMatch, multitenantDescriptorValue),
 		},
-		{
+		Variables: []string{
+			headerKey,
+		},
+	})
+
+	// the requests of the tenants with an override are sent with their own descriptor value, so
+	// only the override of the tenant applies to them
+	for _, override := range overrides {
+		overrideUnitInSeconds, err := r.getUnitInSeconds(override.Unit)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rate limit override for tenant %s: %w", override.TenantName, err)
+		}
+		limits = append(limits, limitadorLimit{
 			Namespace: ratelimit.RateLimitDomain,
-			MaxValue:  limitPerTenant,
-			Seconds:   unitInSeconds,
+			MaxValue:  override.RequestsPerUnit,
+			Seconds:   overrideUnitInSeconds,
 			Conditions: []string{
-				fmt.Sprintf("%s == %s", headerMatch, multitenantDescriptorValue),
+				fmt.Sprintf("%s == %s", headerMatch, ratelimit.TenantOverrideDescriptorValue),
+				fmt.Sprintf("%s == %s", headerKey, override.TenantName),
 			},
 			Variables: []string{
 				headerKey,
 			},
-		},
-	}, nil
+		})
+	}
+
+	return limits, nil
 }
 
 func (r *RateLimitServiceReconciler) ensureLimits(ctx context.Context, client k8sclient.Client) (integreatlyv1alpha1.StatusPhase, error) {
@@ -556,21 +605,34 @@ func (r *RateLimitServiceReconciler) ensureLimits(ctx context.Context, client k8
 	}
 
 	// Get current limits in redis
-	limitadorClient := NewLimitadorClient(r.PodExecutor, r.Namespace, rateLimitPods.Items[0].Name)
-	limitadorLimitsInRedis, err := limitadorClient.GetLimitsByName(ratelimit.RateLimitDomain)
-	if err != nil {
-		return integreatlyv1alpha1.PhaseFailed, err
+	var limitadorLimitsInRedis []limitadorLimit
+	for _, domain := range rateLimitDomains {
+		limits, err := r.LimitadorClient.GetLimitsByName(domain)
+		if IsLimitadorUnavailable(err) {
+			return integreatlyv1alpha1.PhaseInProgress, nil
+		}
+		if err != nil {
+			return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to get the limits from limitador: %w", err)
+		}
+		limitadorLimitsInRedis = append(limitadorLimitsInRedis, limits...)
 	}
 
 	// Get limits from configuration
 	limitadorSetting, err := r.getLimitadorSetting(ctx, client)
+	if err != nil {
+		return integreatlyv1alpha1.PhaseFailed, err
+	}
 
-	// If there are difference, delete the limits and delete a pod to reload the limits from the config map
+	// If there are differences, delete the limits and delete a pod to reload the limits from the config map
 	if r.differentLimitSettings(limitadorLimitsInRedis, limitadorSetting) {
-		phase, err := r.deleteRedisLimitsUsingObservabilityOperator(ctx, client, limitadorClient)
-
-		if phase != integreatlyv1alpha1.PhaseCompleted {
-			return phase, err
+		for _, domain := range rateLimitDomains {
+			err := r.LimitadorClient.DeleteLimitsByName(domain)
+			if IsLimitadorUnavailable(err) {
+				return integreatlyv1alpha1.PhaseInProgress, nil
+			}
+			if err != nil && !IsLimitadorNotFound(err) {
+				return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to delete the limits from limitador: %w", err)
+			}
 		}
 
 		if err := client.Delete(ctx, &corev1.Pod{
@@ -578,7 +640,7 @@ func (r *RateLimitServiceReconciler) ensureLimits(ctx context.Context, client k8
 				Name:      rateLimitPods.Items[0].Name,
 				Namespace: rateLimitPods.Items[0].Namespace,
 			},
-		}); err != nil {
+		}); err != nil && !k8sError.IsNotFound(err) {
 			return integreatlyv1alpha1.PhaseFailed, err
 		}
 
@@ -588,52 +650,84 @@ func (r *RateLimitServiceReconciler) ensureLimits(ctx context.Context, client k8
 	return integreatlyv1alpha1.PhaseCompleted, nil
 }
 
-func (r *RateLimitServiceReconciler) deleteRedisLimitsUsingObservabilityOperator(ctx context.Context, client k8sclient.Client, limitadorClient *LimitadorClient) (integreatlyv1alpha1.StatusPhase, error) {
-	observabilityConfig, err := r.ConfigManager.ReadObservability()
+func (r *RateLimitServiceReconciler) getLimitadorSetting(ctx context.Context, client k8sclient.Client) ([]limitadorLimit, error) {
+	var limits []limitadorLimit
+	var err error
+	if !integreatlyv1alpha1.IsRHOAMMultitenant(integreatlyv1alpha1.InstallationType(r.Installation.Spec.Type)) {
+		limits, err = r.getRHOAMLimitadorSetting()
+	} else {
+		limits, err = r.getMultitenantRHOAMLimitadorSetting(ctx, client)
+	}
 	if err != nil {
-		return integreatlyv1alpha1.PhaseFailed, err
+		return nil, fmt.Errorf("failed to marshall rate limit config: %v", err)
 	}
 
-	observabilityOperatorPods := &corev1.PodList{}
-	opts := []k8sclient.ListOption{
-		k8sclient.InNamespace(observabilityConfig.GetOperatorNamespace()),
-		k8sclient.MatchingLabels(map[string]string{"control-plane": "controller-manager"}),
-	}
-	if err := client.List(ctx, observabilityOperatorPods, opts...); err != nil {
-		return integreatlyv1alpha1.PhaseFailed, err
+	routeLimits, err := r.getRouteLimitadorSetting()
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshall rate limit config: %v", err)
 	}
 
-	if len(observabilityOperatorPods.Items) == 0 {
-		return integreatlyv1alpha1.PhaseAwaitingComponents, nil
+	exemptionLimits, err := r.getExemptionLimitadorSetting()
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshall rate limit config: %v", err)
 	}
 
-	rateLimitService := &corev1.Service{}
-	if err := client.Get(ctx, k8sclient.ObjectKey{Name: quota.RateLimitName, Namespace: r.Namespace}, rateLimitService); err != nil {
-		return integreatlyv1alpha1.PhaseFailed, err
+	return append(append(limits, routeLimits...), exemptionLimits...), nil
+}
+
+// getExemptionLimitadorSetting returns the limit that counts the exempt requests by exemption in
+// the window of the global limit. The exempt requests are sent to their own domain, the limit is
+// never reached so they are not rejected
+func (r *RateLimitServiceReconciler) getExemptionLimitadorSetting() ([]limitadorLimit, error) {
+	if len(r.RateLimitConfig.Exemptions) == 0 {
+		return []limitadorLimit{}, nil
 	}
 
-	if err := limitadorClient.CurlDeleteLimitsByNameUsingPod(ratelimit.RateLimitDomain, observabilityConfig.GetOperatorNamespace(), observabilityOperatorPods.Items[0].Name, rateLimitService.Spec.ClusterIP); err != nil {
-		return integreatlyv1alpha1.PhaseFailed, err
+	unitInSeconds, err := r.getUnitInSeconds(r.RateLimitConfig.Unit)
+	if err != nil {
+		return nil, err
 	}
 
-	return integreatlyv1alpha1.PhaseCompleted, nil
+	return []limitadorLimit{{
+		Namespace: ratelimit.RateLimitExemptDomain,
+		MaxValue:  math.MaxUint32,
+		Seconds:   unitInSeconds,
+		Conditions: []string{
+			fmt.Sprintf("%s == %s", genericKey, ratelimit.ExemptDescriptorValue),
+		},
+		Variables: []string{
+			ratelimit.ExemptionDescriptorKey,
+		},
+	}}, nil
 }
 
-func (r *RateLimitServiceReconciler) getLimitadorSetting(ctx context.Context, client k8sclient.Client) ([]limitadorLimit, error) {
-	if !integreatlyv1alpha1.IsRHOAMMultitenant(integreatlyv1alpha1.InstallationType(r.Installation.Spec.Type)) {
-		limitadorLimit, err := r.getRHOAMLimitadorSetting()
-		if err != nil {
-			return nil, fmt.Errorf("failed to marshall rate limit config: %v", err)
-		}
-		return limitadorLimit, nil
+// getRouteLimitadorSetting returns a limit for each route limit in the rate limit config. The
+// requests that match a route limit are sent with its name as the route descriptor, in multitenant
+// installations the route limits are counted per tenant
+func (r *RateLimitServiceReconciler) getRouteLimitadorSetting() ([]limitadorLimit, error) {
+	variables := []string{}
+	if integreatlyv1alpha1.IsRHOAMMultitenant(integreatlyv1alpha1.InstallationType(r.Installation.Spec.Type)) {
+		variables = []string{headerKey}
 	}
 
-	limitadorLimit, err := r.getMultitenantRHOAMLimitadorSetting(ctx, client)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshall rate limit config: %v", err)
+	limits := make([]limitadorLimit, 0, len(r.RateLimitConfig.RouteLimits))
+	for _, routeLimit := range r.RateLimitConfig.RouteLimits {
+		unitInSeconds, err := r.getUnitInSeconds(routeLimit.Unit)
+		if err != nil {
+			return nil, fmt.Errorf("invalid route limit %s: %w", routeLimit.Name, err)
+		}
+		limits = append(limits, limitadorLimit{
+			Namespace: ratelimit.RateLimitDomain,
+			MaxValue:  routeLimit.RequestsPerUnit,
+			Seconds:   unitInSeconds,
+			Conditions: []string{
+				fmt.Sprintf("%s == %s", ratelimit.RouteDescriptorKey, routeLimit.Name),
+			},
+			Variables: variables,
+		})
 	}
 
-	return limitadorLimit, nil
+	return limits, nil
 }
 
 func (r *RateLimitServiceReconciler) differentLimitSettings(redisLimits []limitadorLimit, currentLimits []limitadorLimit) bool {
@@ -652,10 +746,21 @@ func (r *RateLimitServiceReconciler) differentLimitSettings(redisLimits []limita
 }
 
 func sortByNamespaceAndMaxValue(elems []limitadorLimit) {
+	// limits with the same max value, such as the overrides of several tenants, are ordered by
+	// their conditions so the order is the same for the limits in redis and in the config
+	for i := range elems {
+		sort.Strings(elems[i].Conditions)
+	}
 	sort.Slice(elems, func(i, j int) bool {
 		if elems[i].Namespace != elems[j].Namespace {
 			return elems[i].Namespace < elems[j].Namespace
 		}
-		return elems[i].MaxValue < elems[j].MaxValue
+		if elems[i].MaxValue != elems[j].MaxValue {
+			return elems[i].MaxValue < elems[j].MaxValue
+		}
+		if elems[i].Seconds != elems[j].Seconds {
+			return elems[i].Seconds < elems[j].Seconds
+		}
+		return strings.Join(elems[i].Conditions, ",") < strings.Join(elems[j].Conditions, ",")
 	})
 }
@@ -2,12 +2,14 @@ package marin3r
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	moqclient "github.com/integr8ly/integreatly-operator/pkg/client"
 	"github.com/integr8ly/integreatly-operator/pkg/config"
-	"github.com/integr8ly/integreatly-operator/pkg/resources"
 	"github.com/integr8ly/integreatly-operator/pkg/resources/quota"
 	"github.com/integr8ly/integreatly-operator/pkg/resources/ratelimit"
+	"math"
+	"net/http"
 	"reflect"
 	"testing"
 
@@ -15,6 +17,7 @@ import (
 	marin3rconfig "github.com/integr8ly/integreatly-operator/pkg/products/marin3r/config"
 	"github.com/pkg/errors"
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
 	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -37,11 +40,9 @@ func TestRateLimitService(t *testing.T) {
 		},
 	}
 
-	podExecutorMock := &resources.PodExecutorInterfaceMock{
-		ExecuteRemoteCommandFunc: func(ns string, podName string, command []string) (string, string, error) {
-			return "[{\"namespace\":\"apicast-ratelimit\",\"max_value\":1,\"seconds\":60,\"name\":null,\"conditions\":[\"generic_key == slowpath\"],\"variables\":[\"generic_key\"]}]", "", nil
-		},
-	}
+	limitadorClient := newTestLimitadorClient(t, &fakeLimitador{limits: []limitadorLimit{
+		{Namespace: ratelimit.RateLimitDomain, MaxValue: 1, Seconds: 60, Conditions: []string{"generic_key == slowpath"}, Variables: []string{"generic_key"}},
+	}})
 
 	scenarios := []struct {
 		Name          string
@@ -57,12 +58,15 @@ func TestRateLimitService(t *testing.T) {
 				RequestsPerUnit: 1,
 			},
 				&integreatlyv1alpha1.RHMI{}, "redhat-test-marin3r", "ratelimit-redis",
-				podExecutorMock, &config.ConfigReadWriterMock{},
+				limitadorClient,
 			),
 			ProductConfig: &quota.ProductConfigMock{
 				ConfigureFunc: func(obj metav1.Object) error {
 					return nil
 				},
+				GetAutoscalingFunc: func(ddcssName string) *quota.AutoscalingConfig {
+					return nil
+				},
 			},
 			InitObjs: []runtime.Object{
 				&corev1.Secret{
@@ -139,13 +143,15 @@ func TestRateLimitService(t *testing.T) {
 				&integreatlyv1alpha1.RHMI{},
 				"redhat-test-marin3r",
 				"ratelimit-redis",
-				podExecutorMock,
-				&config.ConfigReadWriterMock{},
+				limitadorClient,
 			),
 			ProductConfig: &quota.ProductConfigMock{
 				ConfigureFunc: func(obj metav1.Object) error {
 					return nil
 				},
+				GetAutoscalingFunc: func(ddcssName string) *quota.AutoscalingConfig {
+					return nil
+				},
 			},
 			Assert: allOf(
 				assertNoError,
@@ -191,12 +197,15 @@ func TestRateLimitService(t *testing.T) {
 				Unit:            "minute",
 				RequestsPerUnit: 1,
 			},
-				&integreatlyv1alpha1.RHMI{}, "redhat-test-marin3r", "ratelimit-redis", resources.PodExecutor{}, &config.ConfigReadWriterMock{},
+				&integreatlyv1alpha1.RHMI{}, "redhat-test-marin3r", "ratelimit-redis", &LimitadorClient{},
 			),
 			ProductConfig: &quota.ProductConfigMock{
 				ConfigureFunc: func(obj metav1.Object) error {
 					return nil
 				},
+				GetAutoscalingFunc: func(ddcssName string) *quota.AutoscalingConfig {
+					return nil
+				},
 			},
 			Assert: allOf(
 				assertNoError,
@@ -236,13 +245,15 @@ func TestRateLimitService(t *testing.T) {
 				},
 				"redhat-test-marin3r",
 				"ratelimit-redis",
-				podExecutorMock,
-				&config.ConfigReadWriterMock{},
+				limitadorClient,
 			),
 			ProductConfig: &quota.ProductConfigMock{
 				ConfigureFunc: func(obj metav1.Object) error {
 					return nil
 				},
+				GetAutoscalingFunc: func(ddcssName string) *quota.AutoscalingConfig {
+					return nil
+				},
 			},
 			Assert: allOf(
 				assertNoError,
@@ -273,6 +284,8 @@ func newScheme() *runtime.Scheme {
 	scheme := runtime.NewScheme()
 	corev1.AddToScheme(scheme)
 	appsv1.AddToScheme(scheme)
+	autoscalingv2beta2.AddToScheme(scheme)
+	integreatlyv1alpha1.AddToScheme(scheme)
 
 	return scheme
 }
@@ -445,6 +458,36 @@ func TestRateLimitServiceReconciler_differentLimitSettings(t *testing.T) {
 			},
 			want: false,
 		},
+		{
+			name: "test slices are sorted by Conditions if matching MaxValue",
+			args: args{
+				redisLimits: []limitadorLimit{
+					{
+						Namespace:  "test",
+						MaxValue:   10,
+						Conditions: []string{"tenant == tenant-b", "header_match == per-tenant-limit"},
+					},
+					{
+						Namespace:  "test",
+						MaxValue:   10,
+						Conditions: []string{"tenant == tenant-a", "header_match == per-tenant-limit"},
+					},
+				},
+				currentLimits: []limitadorLimit{
+					{
+						Namespace:  "test",
+						MaxValue:   10,
+						Conditions: []string{"header_match == per-tenant-limit", "tenant == tenant-a"},
+					},
+					{
+						Namespace:  "test",
+						MaxValue:   10,
+						Conditions: []string{"header_match == per-tenant-limit", "tenant == tenant-b"},
+					},
+				},
+			},
+			want: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -469,7 +512,6 @@ func TestRateLimitServiceReconciler_getLimitadorSetting(t *testing.T) {
 		RedisSecretName string
 		Installation    *integreatlyv1alpha1.RHMI
 		RateLimitConfig marin3rconfig.RateLimitConfig
-		PodExecutor     resources.PodExecutorInterface
 	}
 	type args struct {
 		ctx    context.Context
@@ -506,6 +548,139 @@ func TestRateLimitServiceReconciler_getLimitadorSetting(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "test get rhoam limitator config with route limits",
+			fields: fields{
+				Installation: &integreatlyv1alpha1.RHMI{
+					Spec: integreatlyv1alpha1.RHMISpec{
+						Type: string(integreatlyv1alpha1.InstallationTypeManagedApi),
+					},
+				},
+				RateLimitConfig: marin3rconfig.RateLimitConfig{
+					Unit:            "second",
+					RequestsPerUnit: 1,
+					RouteLimits: []marin3rconfig.RouteRateLimitConfig{
+						{Name: "oauth-token", PathPrefix: "/oauth/token", Method: "POST", Unit: "minute", RequestsPerUnit: 10},
+					},
+				},
+			},
+			want: []limitadorLimit{
+				{
+					Namespace: ratelimit.RateLimitDomain,
+					MaxValue:  1,
+					Seconds:   1,
+					Conditions: []string{
+						fmt.Sprintf("%s == %s", genericKey, ratelimit.RateLimitDescriptorValue),
+					},
+					Variables: []string{
+						genericKey,
+					},
+				},
+				{
+					Namespace: ratelimit.RateLimitDomain,
+					MaxValue:  10,
+					Seconds:   60,
+					Conditions: []string{
+						"route == oauth-token",
+					},
+					Variables: []string{},
+				},
+			},
+		},
+		{
+			name: "test get rhoam limitator config with exemptions",
+			fields: fields{
+				Installation: &integreatlyv1alpha1.RHMI{
+					Spec: integreatlyv1alpha1.RHMISpec{
+						Type: string(integreatlyv1alpha1.InstallationTypeManagedApi),
+					},
+				},
+				RateLimitConfig: marin3rconfig.RateLimitConfig{
+					Unit:            "minute",
+					RequestsPerUnit: 1,
+					Exemptions: []marin3rconfig.RateLimitExemption{
+						{Name: "blackbox-exporter", SourceCIDRs: []string{"10.128.0.0/14"}},
+					},
+				},
+			},
+			want: []limitadorLimit{
+				{
+					Namespace: ratelimit.RateLimitDomain,
+					MaxValue:  1,
+					Seconds:   60,
+					Conditions: []string{
+						fmt.Sprintf("%s == %s", genericKey, ratelimit.RateLimitDescriptorValue),
+					},
+					Variables: []string{
+						genericKey,
+					},
+				},
+				{
+					Namespace: ratelimit.RateLimitExemptDomain,
+					MaxValue:  math.MaxUint32,
+					Seconds:   60,
+					Conditions: []string{
+						"generic_key == exempt",
+					},
+					Variables: []string{
+						"exemption",
+					},
+				},
+			},
+		},
+		{
+			name: "test get rhoam limitator config with multiple windows",
+			fields: fields{
+				Installation: &integreatlyv1alpha1.RHMI{
+					Spec: integreatlyv1alpha1.RHMISpec{
+						Type: string(integreatlyv1alpha1.InstallationTypeManagedApi),
+					},
+				},
+				RateLimitConfig: marin3rconfig.RateLimitConfig{
+					Unit:            "minute",
+					RequestsPerUnit: 100,
+					Windows: []marin3rconfig.RateLimitWindow{
+						{Unit: "second", RequestsPerUnit: 5},
+						{Unit: "day", RequestsPerUnit: 10000},
+					},
+				},
+			},
+			want: []limitadorLimit{
+				{
+					Namespace: ratelimit.RateLimitDomain,
+					MaxValue:  100,
+					Seconds:   60,
+					Conditions: []string{
+						fmt.Sprintf("%s == %s", genericKey, ratelimit.RateLimitDescriptorValue),
+					},
+					Variables: []string{
+						genericKey,
+					},
+				},
+				{
+					Namespace: ratelimit.RateLimitDomain,
+					MaxValue:  5,
+					Seconds:   1,
+					Conditions: []string{
+						fmt.Sprintf("%s == %s", genericKey, ratelimit.RateLimitDescriptorValue),
+					},
+					Variables: []string{
+						genericKey,
+					},
+				},
+				{
+					Namespace: ratelimit.RateLimitDomain,
+					MaxValue:  10000,
+					Seconds:   86400,
+					Conditions: []string{
+						fmt.Sprintf("%s == %s", genericKey, ratelimit.RateLimitDescriptorValue),
+					},
+					Variables: []string{
+						genericKey,
+					},
+				},
+			},
+		},
 		{
 			name: "test error get rhoam limitator config",
 			fields: fields{
@@ -567,6 +742,102 @@ func TestRateLimitServiceReconciler_getLimitadorSetting(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "test get rhoam multitenant limitator config with tenant overrides",
+			args: args{
+				ctx: context.TODO(),
+				client: fake.NewFakeClientWithScheme(scheme,
+					&corev1.ConfigMap{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      multitenantLimitConfigMap,
+							Namespace: "test",
+						},
+						Data: map[string]string{
+							multitenantRateLimit: "10",
+						},
+					},
+					&integreatlyv1alpha1.APIManagementTenant{
+						ObjectMeta: metav1.ObjectMeta{Name: "example", Namespace: "paying.user-dev"},
+						Spec: integreatlyv1alpha1.APIManagementTenantSpec{
+							RateLimit: &integreatlyv1alpha1.TenantRateLimit{Unit: "minute", RequestsPerUnit: 500},
+						},
+						Status: integreatlyv1alpha1.APIManagementTenantStatus{ProvisioningStatus: integreatlyv1alpha1.ThreeScaleAccountReady},
+					},
+					&integreatlyv1alpha1.APIManagementTenant{
+						ObjectMeta: metav1.ObjectMeta{Name: "example", Namespace: "paying.user-stage"},
+						Spec: integreatlyv1alpha1.APIManagementTenantSpec{
+							RateLimit: &integreatlyv1alpha1.TenantRateLimit{Unit: "minute", RequestsPerUnit: 1000},
+						},
+						Status: integreatlyv1alpha1.APIManagementTenantStatus{ProvisioningStatus: integreatlyv1alpha1.WontProvisionTenant},
+					},
+					&integreatlyv1alpha1.APIManagementTenant{
+						ObjectMeta: metav1.ObjectMeta{Name: "example", Namespace: "trial-dev"},
+					},
+					&integreatlyv1alpha1.APIManagementTenant{
+						ObjectMeta: metav1.ObjectMeta{Name: "example", Namespace: "suspended-dev"},
+						Spec:       integreatlyv1alpha1.APIManagementTenantSpec{Suspended: true},
+						Status:     integreatlyv1alpha1.APIManagementTenantStatus{ProvisioningStatus: integreatlyv1alpha1.ThreeScaleAccountSuspended},
+					},
+				),
+			},
+			fields: fields{
+				Namespace: "test",
+				Installation: &integreatlyv1alpha1.RHMI{
+					Spec: integreatlyv1alpha1.RHMISpec{
+						Type: string(integreatlyv1alpha1.InstallationTypeMultitenantManagedApi),
+					},
+				},
+				RateLimitConfig: marin3rconfig.RateLimitConfig{Unit: "second", RequestsPerUnit: 1},
+			},
+			want: []limitadorLimit{
+				{
+					Namespace: ratelimit.RateLimitDomain,
+					MaxValue:  1,
+					Seconds:   1,
+					Conditions: []string{
+						fmt.Sprintf("%s == %s", genericKey, ratelimit.RateLimitDescriptorValue),
+					},
+					Variables: []string{
+						genericKey,
+					},
+				},
+				{
+					Namespace: ratelimit.RateLimitDomain,
+					MaxValue:  10,
+					Seconds:   1,
+					Conditions: []string{
+						fmt.Sprintf("%s == %s", headerMatch, multitenantDescriptorValue),
+					},
+					Variables: []string{
+						headerKey,
+					},
+				},
+				{
+					Namespace: ratelimit.RateLimitDomain,
+					MaxValue:  500,
+					Seconds:   60,
+					Conditions: []string{
+						fmt.Sprintf("%s == %s", headerMatch, ratelimit.TenantOverrideDescriptorValue),
+						fmt.Sprintf("%s == %s", headerKey, "paying-user"),
+					},
+					Variables: []string{
+						headerKey,
+					},
+				},
+				{
+					Namespace: ratelimit.RateLimitDomain,
+					MaxValue:  0,
+					Seconds:   60,
+					Conditions: []string{
+						fmt.Sprintf("%s == %s", headerMatch, ratelimit.TenantOverrideDescriptorValue),
+						fmt.Sprintf("%s == %s", headerKey, "suspended"),
+					},
+					Variables: []string{
+						headerKey,
+					},
+				},
+			},
+		},
 		{
 			name: "test error get rhoam multitenant limitator config",
 			args: args{
@@ -593,7 +864,6 @@ func TestRateLimitServiceReconciler_getLimitadorSetting(t *testing.T) {
 				RedisSecretName: tt.fields.RedisSecretName,
 				Installation:    tt.fields.Installation,
 				RateLimitConfig: tt.fields.RateLimitConfig,
-				PodExecutor:     tt.fields.PodExecutor,
 			}
 			got, err := r.getLimitadorSetting(tt.args.ctx, tt.args.client)
 			if (err != nil) != tt.wantErr {
@@ -645,47 +915,36 @@ func TestRateLimitServiceReconciler_ensureLimits(t *testing.T) {
 		},
 	}
 
-	configManager := &config.ConfigReadWriterMock{
-		ReadObservabilityFunc: func() (*config.Observability, error) {
-			return &config.Observability{
-				Config: config.ProductConfig{
-					"NAMESPACE": namespace,
-				},
-			}, nil
-		},
+	slowpathLimit := func(maxValue uint32) limitadorLimit {
+		return limitadorLimit{Namespace: ratelimit.RateLimitDomain, MaxValue: maxValue, Seconds: 60, Conditions: []string{"generic_key == slowpath"}, Variables: []string{"generic_key"}}
 	}
 
-	observabilityOperatorPod := &corev1.Pod{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      "oo-pod",
-			Namespace: namespace,
-			Labels:    map[string]string{"control-plane": "controller-manager"},
+	managedAPI := &integreatlyv1alpha1.RHMI{
+		Spec: integreatlyv1alpha1.RHMISpec{
+			Type: string(integreatlyv1alpha1.InstallationTypeManagedApi),
 		},
 	}
 
-	rateLimitService := &corev1.Service{
-		ObjectMeta: metav1.ObjectMeta{Name: quota.RateLimitName, Namespace: namespace},
-		Spec:       corev1.ServiceSpec{ClusterIP: "1.1.1.1"},
-	}
-
 	type fields struct {
 		Namespace       string
 		RedisSecretName string
 		Installation    *integreatlyv1alpha1.RHMI
 		RateLimitConfig marin3rconfig.RateLimitConfig
-		PodExecutor     resources.PodExecutorInterface
-		ConfigManager   config.ConfigReadWriter
+		Limitador       http.Handler
 	}
 	type args struct {
 		ctx    context.Context
 		client k8sclient.Client
 	}
 	tests := []struct {
-		name    string
-		fields  fields
-		args    args
-		want    integreatlyv1alpha1.StatusPhase
-		wantErr bool
+		name       string
+		fields     fields
+		args       args
+		want       integreatlyv1alpha1.StatusPhase
+		wantErr    bool
+		wantLimits []limitadorLimit
+		// wantPodDeleted is true when the rate limit pod is deleted to reload the limits
+		wantPodDeleted bool
 	}{
 		{
 			name: "test phase failed listing rate limit pods",
@@ -737,27 +996,24 @@ func TestRateLimitServiceReconciler_ensureLimits(t *testing.T) {
 			want: integreatlyv1alpha1.PhaseAwaitingComponents,
 		},
 		{
-			name: "test phase failed if unable to list limits from rate limit pod",
+			name: "test phase in progress if limitador is unavailable",
 			fields: fields{
 				Namespace: namespace,
-				PodExecutor: &resources.PodExecutorInterfaceMock{ExecuteRemoteCommandFunc: func(ns string, podName string, command []string) (string, string, error) {
-					return "", "", fmt.Errorf("listError")
-				}},
+				Limitador: &fakeLimitador{failures: 3},
 			},
 			args: args{
 				ctx:    context.TODO(),
 				client: fake.NewFakeClientWithScheme(scheme, rateLimitPod),
 			},
-			want:    integreatlyv1alpha1.PhaseFailed,
-			wantErr: true,
+			want: integreatlyv1alpha1.PhaseInProgress,
 		},
 		{
-			name: "test phase failed marshalling json response",
+			name: "test phase failed unmarshalling json response",
 			fields: fields{
 				Namespace: namespace,
-				PodExecutor: &resources.PodExecutorInterfaceMock{ExecuteRemoteCommandFunc: func(ns string, podName string, command []string) (string, string, error) {
-					return "notJson", "", nil
-				}},
+				Limitador: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					w.Write([]byte("notJson"))
+				}),
 			},
 			args: args{
 				ctx:    context.TODO(),
@@ -769,257 +1025,74 @@ func TestRateLimitServiceReconciler_ensureLimits(t *testing.T) {
 		{
 			name: "test phase failed deleting limits in redis",
 			fields: fields{
-				Installation: &integreatlyv1alpha1.RHMI{
-					Spec: integreatlyv1alpha1.RHMISpec{
-						Type: string(integreatlyv1alpha1.InstallationTypeManagedApi),
-					},
-				},
-				RateLimitConfig: marin3rconfig.RateLimitConfig{Unit: "minute", RequestsPerUnit: 1},
-				Namespace:       namespace,
-				PodExecutor: &resources.PodExecutorInterfaceMock{ExecuteRemoteCommandFunc: func(ns string, podName string, command []string) (string, string, error) {
-					return "[{\"namespace\":\"apicast-ratelimit\",\"max_value\":70,\"seconds\":60,\"name\":null,\"conditions\":[\"generic_key == slowpath\"],\"variables\":[\"generic_key\"]}]", "", nil
-				}},
-				ConfigManager: &config.ConfigReadWriterMock{ReadObservabilityFunc: func() (*config.Observability, error) {
-					return nil, fmt.Errorf("genericError")
-				}}},
-			args: args{
-				ctx:    context.TODO(),
-				client: fake.NewFakeClientWithScheme(scheme, rateLimitPod, observabilityOperatorPod, rateLimitService),
-			},
-			want:    integreatlyv1alpha1.PhaseFailed,
-			wantErr: true,
-		},
-		{
-			name: "test phase in progress after deleting rate limit pod due to differences",
-			fields: fields{
-				Installation: &integreatlyv1alpha1.RHMI{
-					Spec: integreatlyv1alpha1.RHMISpec{
-						Type: string(integreatlyv1alpha1.InstallationTypeManagedApi),
-					},
-				},
-				Namespace:       namespace,
-				RateLimitConfig: marin3rconfig.RateLimitConfig{Unit: "second", RequestsPerUnit: 1},
-				PodExecutor: &resources.PodExecutorInterfaceMock{ExecuteRemoteCommandFunc: func(ns string, podName string, command []string) (string, string, error) {
-					return "[{\"namespace\":\"apicast-ratelimit\",\"max_value\":1,\"seconds\":60,\"name\":null,\"conditions\":[\"generic_key == slowpath\"],\"variables\":[\"generic_key\"]}]", "", nil
-				}},
-				ConfigManager: configManager,
-			},
-			args: args{
-				ctx:    context.TODO(),
-				client: fake.NewFakeClientWithScheme(scheme, rateLimitPod, observabilityOperatorPod, rateLimitService),
-			},
-			want: integreatlyv1alpha1.PhaseInProgress,
-		},
-		{
-			name: "test phase complete when no differences found",
-			fields: fields{
-				Installation: &integreatlyv1alpha1.RHMI{
-					Spec: integreatlyv1alpha1.RHMISpec{
-						Type: string(integreatlyv1alpha1.InstallationTypeManagedApi),
-					},
-				},
-				Namespace:       namespace,
+				Installation:    managedAPI,
 				RateLimitConfig: marin3rconfig.RateLimitConfig{Unit: "minute", RequestsPerUnit: 1},
-				PodExecutor: &resources.PodExecutorInterfaceMock{ExecuteRemoteCommandFunc: func(ns string, podName string, command []string) (string, string, error) {
-					return "[{\"namespace\":\"apicast-ratelimit\",\"max_value\":1,\"seconds\":60,\"name\":null,\"conditions\":[\"generic_key == slowpath\"],\"variables\":[\"generic_key\"]}]", "", nil
-				}},
-				ConfigManager: configManager,
-			},
-			args: args{
-				ctx:    context.TODO(),
-				client: fake.NewFakeClientWithScheme(scheme, rateLimitPod, observabilityOperatorPod, rateLimitService),
-			},
-			want: integreatlyv1alpha1.PhaseCompleted,
-		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			r := &RateLimitServiceReconciler{
-				Namespace:       tt.fields.Namespace,
-				RedisSecretName: tt.fields.RedisSecretName,
-				Installation:    tt.fields.Installation,
-				RateLimitConfig: tt.fields.RateLimitConfig,
-				PodExecutor:     tt.fields.PodExecutor,
-				ConfigManager:   tt.fields.ConfigManager,
-			}
-			got, err := r.ensureLimits(tt.args.ctx, tt.args.client)
-			if (err != nil) != tt.wantErr {
-				t.Errorf("ensureLimits() error = %v, wantErr %v", err, tt.wantErr)
-				return
-			}
-			if got != tt.want {
-				t.Errorf("ensureLimits() got = %v, want %v", got, tt.want)
-			}
-		})
-	}
-}
-
-func TestRateLimitServiceReconciler_deleteRedisLimitsUsingObservabilityOperator(t *testing.T) {
-	scheme := newScheme()
-
-	const namespace = "redhat-test-marin3r"
-
-	configManager := &config.ConfigReadWriterMock{
-		ReadObservabilityFunc: func() (*config.Observability, error) {
-			return &config.Observability{
-				Config: config.ProductConfig{
-					"NAMESPACE": namespace,
-				},
-			}, nil
-		},
-	}
-
-	observabilityOperatorPod := &corev1.Pod{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      "oo-pod",
-			Namespace: namespace,
-			Labels:    map[string]string{"control-plane": "controller-manager"},
-		},
-	}
-
-	rateLimitService := &corev1.Service{
-		ObjectMeta: metav1.ObjectMeta{Name: quota.RateLimitName, Namespace: namespace},
-		Spec:       corev1.ServiceSpec{ClusterIP: "1.1.1.1"},
-	}
-
-	type fields struct {
-		Namespace       string
-		RedisSecretName string
-		Installation    *integreatlyv1alpha1.RHMI
-		RateLimitConfig marin3rconfig.RateLimitConfig
-		PodExecutor     resources.PodExecutorInterface
-		ConfigManager   config.ConfigReadWriter
-	}
-	type args struct {
-		ctx             context.Context
-		client          k8sclient.Client
-		limitadorClient *LimitadorClient
-	}
-	tests := []struct {
-		name    string
-		fields  fields
-		args    args
-		want    integreatlyv1alpha1.StatusPhase
-		wantErr bool
-	}{
-		{
-			name: "test phase failed getting observability config",
-			fields: fields{
-				Installation: &integreatlyv1alpha1.RHMI{
-					Spec: integreatlyv1alpha1.RHMISpec{
-						Type: string(integreatlyv1alpha1.InstallationTypeManagedApi),
-					},
-				},
 				Namespace:       namespace,
-				RateLimitConfig: marin3rconfig.RateLimitConfig{Unit: "second", RequestsPerUnit: 1},
-				PodExecutor: &resources.PodExecutorInterfaceMock{ExecuteRemoteCommandFunc: func(ns string, podName string, command []string) (string, string, error) {
-					return "[{\"namespace\":\"apicast-ratelimit\",\"max_value\":1,\"seconds\":60,\"name\":null,\"conditions\":[\"generic_key == slowpath\"],\"variables\":[\"generic_key\"]}]", "", nil
-				}},
-				ConfigManager: &config.ConfigReadWriterMock{ReadObservabilityFunc: func() (*config.Observability, error) {
-					return nil, fmt.Errorf("genericError")
-				}},
+				Limitador: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					if r.Method == http.MethodDelete {
+						http.Error(w, "forbidden", http.StatusForbidden)
+						return
+					}
+					json.NewEncoder(w).Encode([]limitadorLimit{slowpathLimit(70)})
+				}),
 			},
 			args: args{
 				ctx:    context.TODO(),
-				client: fake.NewFakeClientWithScheme(scheme),
-			},
-			want:    integreatlyv1alpha1.PhaseFailed,
-			wantErr: true,
-		},
-		{
-			name: "test phase failed listing observability operator pods",
-			fields: fields{
-				Namespace:     namespace,
-				ConfigManager: configManager,
-			},
-			args: args{
-				ctx: context.TODO(),
-				client: &moqclient.SigsClientInterfaceMock{ListFunc: func(ctx context.Context, list runtime.Object, opts ...k8sclient.ListOption) error {
-					return fmt.Errorf("listError")
-				}},
+				client: fake.NewFakeClientWithScheme(scheme, rateLimitPod),
 			},
 			want:    integreatlyv1alpha1.PhaseFailed,
 			wantErr: true,
 		},
 		{
-			name: "test phase awaiting components when waiting for observability pod",
+			name: "test phase in progress after deleting the limits and the pod due to differences",
 			fields: fields{
-				Installation: &integreatlyv1alpha1.RHMI{
-					Spec: integreatlyv1alpha1.RHMISpec{
-						Type: string(integreatlyv1alpha1.InstallationTypeManagedApi),
-					},
-				},
+				Installation:    managedAPI,
 				Namespace:       namespace,
-				RateLimitConfig: marin3rconfig.RateLimitConfig{Unit: "second", RequestsPerUnit: 1},
-				PodExecutor: &resources.PodExecutorInterfaceMock{ExecuteRemoteCommandFunc: func(ns string, podName string, command []string) (string, string, error) {
-					return "[{\"namespace\":\"apicast-ratelimit\",\"max_value\":1,\"seconds\":60,\"name\":null,\"conditions\":[\"generic_key == slowpath\"],\"variables\":[\"generic_key\"]}]", "", nil
-				}},
-				ConfigManager: configManager,
+				RateLimitConfig: marin3rconfig.RateLimitConfig{Unit: "minute", RequestsPerUnit: 5},
+				Limitador:       &fakeLimitador{limits: []limitadorLimit{slowpathLimit(1)}},
 			},
 			args: args{
 				ctx:    context.TODO(),
-				client: fake.NewFakeClientWithScheme(scheme),
+				client: fake.NewFakeClientWithScheme(scheme, rateLimitPod),
 			},
-			want: integreatlyv1alpha1.PhaseAwaitingComponents,
+			want:           integreatlyv1alpha1.PhaseInProgress,
+			wantLimits:     []limitadorLimit{},
+			wantPodDeleted: true,
 		},
 		{
-			name: "test phase failed getting rate limit service",
+			name: "test phase in progress when the limit of the exemptions is missing",
 			fields: fields{
-				Installation: &integreatlyv1alpha1.RHMI{
-					Spec: integreatlyv1alpha1.RHMISpec{
-						Type: string(integreatlyv1alpha1.InstallationTypeManagedApi),
-					},
+				Installation: managedAPI,
+				Namespace:    namespace,
+				RateLimitConfig: marin3rconfig.RateLimitConfig{
+					Unit:            "minute",
+					RequestsPerUnit: 1,
+					Exemptions:      []marin3rconfig.RateLimitExemption{{Name: "internal", ServiceIDs: []string{"3"}}},
 				},
-				Namespace:       namespace,
-				RateLimitConfig: marin3rconfig.RateLimitConfig{Unit: "second", RequestsPerUnit: 1},
-				PodExecutor: &resources.PodExecutorInterfaceMock{ExecuteRemoteCommandFunc: func(ns string, podName string, command []string) (string, string, error) {
-					return "[{\"namespace\":\"apicast-ratelimit\",\"max_value\":1,\"seconds\":60,\"name\":null,\"conditions\":[\"generic_key == slowpath\"],\"variables\":[\"generic_key\"]}]", "", nil
-				}},
-				ConfigManager: configManager,
+				Limitador: &fakeLimitador{limits: []limitadorLimit{slowpathLimit(1)}},
 			},
 			args: args{
 				ctx:    context.TODO(),
-				client: fake.NewFakeClientWithScheme(scheme, observabilityOperatorPod),
-			},
-			want:    integreatlyv1alpha1.PhaseFailed,
-			wantErr: true,
-		},
-		{
-			name: "test phase failed if unable to delete limits from rate limit pod",
-			fields: fields{
-				Namespace: namespace,
-				PodExecutor: &resources.PodExecutorInterfaceMock{ExecuteRemoteCommandFunc: func(ns string, podName string, command []string) (string, string, error) {
-					return "", "", fmt.Errorf("genericError")
-				}},
-				ConfigManager: configManager,
-			},
-			args: args{
-				ctx:    context.TODO(),
-				client: fake.NewFakeClientWithScheme(scheme, observabilityOperatorPod, rateLimitService),
+				client: fake.NewFakeClientWithScheme(scheme, rateLimitPod),
 			},
-			want:    integreatlyv1alpha1.PhaseFailed,
-			wantErr: true,
+			want:           integreatlyv1alpha1.PhaseInProgress,
+			wantLimits:     []limitadorLimit{},
+			wantPodDeleted: true,
 		},
 		{
-			name: "test phase complete when successfully deleted limits",
+			name: "test phase complete when no differences found",
 			fields: fields{
-				Installation: &integreatlyv1alpha1.RHMI{
-					Spec: integreatlyv1alpha1.RHMISpec{
-						Type: string(integreatlyv1alpha1.InstallationTypeManagedApi),
-					},
-				},
+				Installation:    managedAPI,
 				Namespace:       namespace,
 				RateLimitConfig: marin3rconfig.RateLimitConfig{Unit: "minute", RequestsPerUnit: 1},
-				PodExecutor: &resources.PodExecutorInterfaceMock{ExecuteRemoteCommandFunc: func(ns string, podName string, command []string) (string, string, error) {
-					return "", "", nil
-				}},
-				ConfigManager: configManager,
+				Limitador:       &fakeLimitador{limits: []limitadorLimit{slowpathLimit(1)}},
 			},
 			args: args{
 				ctx:    context.TODO(),
-				client: fake.NewFakeClientWithScheme(scheme, observabilityOperatorPod, rateLimitService),
+				client: fake.NewFakeClientWithScheme(scheme, rateLimitPod),
 			},
-			want: integreatlyv1alpha1.PhaseCompleted,
+			want:       integreatlyv1alpha1.PhaseCompleted,
+			wantLimits: []limitadorLimit{slowpathLimit(1)},
 		},
 	}
 	for _, tt := range tests {
@@ -1029,17 +1102,27 @@ func TestRateLimitServiceReconciler_deleteRedisLimitsUsingObservabilityOperator(
 				RedisSecretName: tt.fields.RedisSecretName,
 				Installation:    tt.fields.Installation,
 				RateLimitConfig: tt.fields.RateLimitConfig,
-				PodExecutor:     tt.fields.PodExecutor,
-				ConfigManager:   tt.fields.ConfigManager,
 			}
-
-			got, err := r.deleteRedisLimitsUsingObservabilityOperator(tt.args.ctx, tt.args.client, NewLimitadorClient(tt.fields.PodExecutor, tt.fields.Namespace, observabilityOperatorPod.Name))
+			if tt.fields.Limitador != nil {
+				r.LimitadorClient = newTestLimitadorClient(t, tt.fields.Limitador)
+			}
+			got, err := r.ensureLimits(tt.args.ctx, tt.args.client)
 			if (err != nil) != tt.wantErr {
-				t.Errorf("deleteRedisLimitsUsingObservabilityOperator() error = %v, wantErr %v", err, tt.wantErr)
+				t.Errorf("ensureLimits() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
 			if got != tt.want {
-				t.Errorf("deleteRedisLimitsUsingObservabilityOperator() got = %v, want %v", got, tt.want)
+				t.Errorf("ensureLimits() got = %v, want %v", got, tt.want)
+			}
+			if limitador, ok := tt.fields.Limitador.(*fakeLimitador); ok && tt.wantLimits != nil && !reflect.DeepEqual(limitador.limits, tt.wantLimits) {
+				t.Errorf("expected limits %v in limitador but got %v", tt.wantLimits, limitador.limits)
+			}
+			if tt.wantErr || tt.fields.Limitador == nil {
+				return
+			}
+			err = tt.args.client.Get(tt.args.ctx, k8sclient.ObjectKey{Name: rateLimitPod.Name, Namespace: namespace}, &corev1.Pod{})
+			if podDeleted := k8serrors.IsNotFound(err); podDeleted != tt.wantPodDeleted {
+				t.Errorf("expected the rate limit pod to be deleted to be %v but got %v", tt.wantPodDeleted, err)
 			}
 		})
 	}
@@ -0,0 +1,246 @@
+package marin3r
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/apis/v1alpha1"
+	"github.com/integr8ly/integreatly-operator/pkg/metrics"
+	marin3rconfig "github.com/integr8ly/integreatly-operator/pkg/products/marin3r/config"
+	"github.com/integr8ly/integreatly-operator/pkg/resources/ratelimit"
+	"github.com/integr8ly/integreatly-operator/pkg/resources/user"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ReconcileRateLimitUsage reads the counters of the rate limit domain from limitador and reports the
+// usage of the global limit and of the limit of each tenant. The usage is reported as metrics and as
+// a summary in the status of the installation, the requests exempt from the rate limit are reported
+// separately by exemption. The usage in the status of the APIManagementTenant CRs is reported by
+// ReconcileTenantRateLimitUsage
+func (r *RateLimitServiceReconciler) ReconcileRateLimitUsage(ctx context.Context, client k8sclient.Client) error {
+	counters, err := r.LimitadorClient.GetCountersByName(ratelimit.RateLimitDomain)
+	if err != nil {
+		return fmt.Errorf("failed to get the counters from limitador: %w", err)
+	}
+	globalSeconds, err := r.getUnitInSeconds(r.RateLimitConfig.Unit)
+	if err != nil {
+		return err
+	}
+	globalRequests, tenantCounters := getRequestsFromCounters(counters, globalSeconds)
+
+	exemptRequests := map[string]uint32{}
+	if len(r.RateLimitConfig.Exemptions) > 0 {
+		exemptCounters, err := r.LimitadorClient.GetCountersByName(ratelimit.RateLimitExemptDomain)
+		if err != nil {
+			return fmt.Errorf("failed to get the exempt counters from limitador: %w", err)
+		}
+		exemptRequests = getExemptRequestsFromCounters(exemptCounters, r.RateLimitConfig.Exemptions)
+	}
+
+	summary := &integreatlyv1alpha1.RateLimitUsageSummary{
+		Global: &integreatlyv1alpha1.RateLimitUsage{
+			TenantRateLimit: integreatlyv1alpha1.TenantRateLimit{
+				Unit:            r.RateLimitConfig.Unit,
+				RequestsPerUnit: r.RateLimitConfig.RequestsPerUnit,
+			},
+			Requests: globalRequests,
+		},
+	}
+
+	tenantUsage := map[string]integreatlyv1alpha1.RateLimitUsage{}
+	if integreatlyv1alpha1.IsRHOAMMultitenant(integreatlyv1alpha1.InstallationType(r.Installation.Spec.Type)) {
+		tenantUsage = getTenantUsageFromCounters(tenantCounters)
+	}
+	for _, usage := range tenantUsage {
+		if usage.Requests > 0 {
+			summary.Tenants++
+		}
+		if usage.Requests >= usage.RequestsPerUnit {
+			summary.TenantsAtLimit++
+		}
+	}
+
+	r.Installation.Status.RateLimitUsage = summary
+	metrics.SetRateLimitUsage(summary.Global, tenantUsage)
+	metrics.SetRateLimitExemptRequests(exemptRequests)
+
+	return nil
+}
+
+// ReconcileTenantRateLimitUsage reads the limits and the counters of the rate limit domain from the
+// limitador of limitadorClient and reports the limit applied to each tenant and its requests in the
+// status of its APIManagementTenant CR. The limit of a tenant is its override, or the limit shared by
+// the tenants without one. Tenants without a limit in limitador are left out
+func ReconcileTenantRateLimitUsage(ctx context.Context, client k8sclient.Client, limitadorClient LimitadorClientInterface) error {
+	limits, err := limitadorClient.GetLimitsByName(ratelimit.RateLimitDomain)
+	if err != nil {
+		return fmt.Errorf("failed to get the limits from limitador: %w", err)
+	}
+	tenantLimit, err := getTenantRateLimitFromLimits(limits)
+	if err != nil {
+		return err
+	}
+
+	counters, err := limitadorClient.GetCountersByName(ratelimit.RateLimitDomain)
+	if err != nil {
+		return fmt.Errorf("failed to get the counters from limitador: %w", err)
+	}
+	_, tenantCounters := getRequestsFromCounters(counters, 0)
+
+	tenants := &integreatlyv1alpha1.APIManagementTenantList{}
+	if err := client.List(ctx, tenants); err != nil {
+		return fmt.Errorf("failed to list APIManagementTenant CRs: %w", err)
+	}
+
+	for i := range tenants.Items {
+		tenant := &tenants.Items[i]
+		if tenant.Status.ProvisioningStatus == integreatlyv1alpha1.WontProvisionTenant {
+			continue
+		}
+
+		rateLimit := &integreatlyv1alpha1.TenantRateLimitStatus{}
+		if tenant.Spec.RateLimit != nil {
+			rateLimit.TenantRateLimit = *tenant.Spec.RateLimit
+			rateLimit.Override = true
+		} else if tenantLimit != nil {
+			rateLimit.TenantRateLimit = *tenantLimit
+		} else {
+			continue
+		}
+
+		usage := &integreatlyv1alpha1.RateLimitUsage{
+			TenantRateLimit: rateLimit.TenantRateLimit,
+		}
+		if counter, ok := tenantCounters[user.GetTenantName(tenant)]; ok {
+			usage.Requests = counter.requests()
+		}
+
+		if reflect.DeepEqual(tenant.Status.RateLimit, rateLimit) && reflect.DeepEqual(tenant.Status.RateLimitUsage, usage) {
+			continue
+		}
+		tenant.Status.RateLimit = rateLimit
+		tenant.Status.RateLimitUsage = usage
+		if err := client.Status().Update(ctx, tenant); err != nil {
+			return fmt.Errorf("failed to update the rate limit usage of tenant %s/%s: %w", tenant.Namespace, tenant.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// getTenantRateLimitFromLimits returns the limit shared by the tenants without an override, nil is
+// returned when limitador has no such limit
+func getTenantRateLimitFromLimits(limits []limitadorLimit) (*integreatlyv1alpha1.TenantRateLimit, error) {
+	conditions := []string{fmt.Sprintf("%s == %s", headerMatch, multitenantDescriptorValue)}
+	for _, limit := range limits {
+		if !reflect.DeepEqual(limit.Conditions, conditions) {
+			continue
+		}
+		unit, err := GetSecondsInUnit(limit.Seconds)
+		if err != nil {
+			return nil, fmt.Errorf("invalid limit of the tenants: %w", err)
+		}
+		return &integreatlyv1alpha1.TenantRateLimit{Unit: unit, RequestsPerUnit: limit.MaxValue}, nil
+	}
+	return nil, nil
+}
+
+// getRequestsFromCounters returns the requests counted against the window of the global limit with
+// the given seconds and the counter of the limit of each tenant by tenant name, the counter with the
+// most requests is kept when a tenant has more than one. Limits without a counter have no requests
+// in their current window. The route limits are also counted per tenant in multitenant
+// installations, their counters are not the usage of the limit of the tenant
+func getRequestsFromCounters(counters []limitadorCounter, globalSeconds uint64) (uint32, map[string]limitadorCounter) {
+	globalConditions := []string{fmt.Sprintf("%s == %s", genericKey, ratelimit.RateLimitDescriptorValue)}
+
+	var globalRequests uint32
+	tenantCounters := map[string]limitadorCounter{}
+	for _, counter := range counters {
+		requests := counter.requests()
+		if tenantName, ok := counter.SetVariables[headerKey]; ok {
+			if !isTenantLimit(counter.Limit) {
+				continue
+			}
+			if current, ok := tenantCounters[tenantName]; !ok || requests > current.requests() {
+				tenantCounters[tenantName] = counter
+			}
+			continue
+		}
+		if counter.Limit.Seconds != globalSeconds || !reflect.DeepEqual(counter.Limit.Conditions, globalConditions) {
+			continue
+		}
+		if requests > globalRequests {
+			globalRequests = requests
+		}
+	}
+
+	return globalRequests, tenantCounters
+}
+
+// isTenantLimit returns true for the limit shared by the tenants and for the overrides of the
+// tenants
+func isTenantLimit(limit limitadorLimit) bool {
+	tenantLimit := false
+	for _, condition := range limit.Conditions {
+		if strings.HasPrefix(condition, ratelimit.RouteDescriptorKey+" ==") {
+			return false
+		}
+		if condition == fmt.Sprintf("%s == %s", headerMatch, multitenantDescriptorValue) ||
+			condition == fmt.Sprintf("%s == %s", headerMatch, ratelimit.TenantOverrideDescriptorValue) {
+			tenantLimit = true
+		}
+	}
+	return tenantLimit
+}
+
+// getTenantUsageFromCounters returns the usage of the limit of each tenant with a counter by tenant
+// name, tenants without requests in the current window have no counter and are left out
+func getTenantUsageFromCounters(tenantCounters map[string]limitadorCounter) map[string]integreatlyv1alpha1.RateLimitUsage {
+	tenantUsage := make(map[string]integreatlyv1alpha1.RateLimitUsage, len(tenantCounters))
+	for tenantName, counter := range tenantCounters {
+		// the unit is only known for the windows of the units a limit can be configured with
+		unit, _ := GetSecondsInUnit(counter.Limit.Seconds)
+		tenantUsage[tenantName] = integreatlyv1alpha1.RateLimitUsage{
+			TenantRateLimit: integreatlyv1alpha1.TenantRateLimit{
+				Unit:            unit,
+				RequestsPerUnit: counter.Limit.MaxValue,
+			},
+			Requests: counter.requests(),
+		}
+	}
+	return tenantUsage
+}
+
+// getExemptRequestsFromCounters returns the requests counted for each exemption, exemptions without
+// a counter have no requests in the current window
+func getExemptRequestsFromCounters(counters []limitadorCounter, exemptions []marin3rconfig.RateLimitExemption) map[string]uint32 {
+	exemptRequests := make(map[string]uint32, len(exemptions))
+	for _, exemption := range exemptions {
+		exemptRequests[exemption.Name] = 0
+	}
+	for _, counter := range counters {
+		exemption, ok := counter.SetVariables[ratelimit.ExemptionDescriptorKey]
+		if !ok || counter.Limit.Namespace != ratelimit.RateLimitExemptDomain {
+			continue
+		}
+		if _, ok := exemptRequests[exemption]; !ok {
+			continue
+		}
+		exemptRequests[exemption] = counter.requests()
+	}
+
+	return exemptRequests
+}
+
+// requests returns the number of requests counted in the current window of the limit of the counter
+func (c limitadorCounter) requests() uint32 {
+	if c.Remaining == nil || *c.Remaining >= int64(c.Limit.MaxValue) {
+		return 0
+	}
+	if *c.Remaining <= 0 {
+		return c.Limit.MaxValue
+	}
+	return c.Limit.MaxValue - uint32(*c.Remaining)
+}
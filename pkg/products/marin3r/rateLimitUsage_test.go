@@ -0,0 +1,272 @@
+package marin3r
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/apis/v1alpha1"
+	marin3rconfig "github.com/integr8ly/integreatly-operator/pkg/products/marin3r/config"
+	"github.com/integr8ly/integreatly-operator/pkg/resources/ratelimit"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestRateLimitServiceReconciler_ReconcileRateLimitUsage(t *testing.T) {
+	scheme := newScheme()
+
+	counter := func(maxValue uint32, remaining int64, conditions []string, variables map[string]string) limitadorCounter {
+		return limitadorCounter{
+			Limit:        limitadorLimit{Namespace: ratelimit.RateLimitDomain, MaxValue: maxValue, Seconds: 60, Conditions: conditions},
+			SetVariables: variables,
+			Remaining:    &remaining,
+		}
+	}
+	globalConditions := []string{"generic_key == slowpath"}
+	tenantConditions := []string{"header_match == per-mt-limit"}
+	routeConditions := []string{"route == token"}
+
+	usage := func(requestsPerUnit, requests uint32) *integreatlyv1alpha1.RateLimitUsage {
+		return &integreatlyv1alpha1.RateLimitUsage{
+			TenantRateLimit: integreatlyv1alpha1.TenantRateLimit{Unit: "minute", RequestsPerUnit: requestsPerUnit},
+			Requests:        requests,
+		}
+	}
+
+	tests := []struct {
+		name             string
+		installationType integreatlyv1alpha1.InstallationType
+		counters         []limitadorCounter
+		failures         int
+		wantErr          bool
+		wantSummary      *integreatlyv1alpha1.RateLimitUsageSummary
+	}{
+		{
+			name:             "test error when the counters can not be read",
+			installationType: integreatlyv1alpha1.InstallationTypeManagedApi,
+			failures:         3,
+			wantErr:          true,
+		},
+		{
+			name:             "test global usage without requests in the current window",
+			installationType: integreatlyv1alpha1.InstallationTypeManagedApi,
+			wantSummary:      &integreatlyv1alpha1.RateLimitUsageSummary{Global: usage(100, 0)},
+		},
+		{
+			name:             "test global usage",
+			installationType: integreatlyv1alpha1.InstallationTypeManagedApi,
+			counters:         []limitadorCounter{counter(100, 75, globalConditions, map[string]string{"generic_key": "slowpath"})},
+			wantSummary:      &integreatlyv1alpha1.RateLimitUsageSummary{Global: usage(100, 25)},
+		},
+		{
+			name:             "test global usage is read from the counter of the window of the limit",
+			installationType: integreatlyv1alpha1.InstallationTypeManagedApi,
+			counters: []limitadorCounter{
+				counter(100, 60, globalConditions, map[string]string{"generic_key": "slowpath"}),
+				func() limitadorCounter {
+					perSecond := counter(5, 0, globalConditions, map[string]string{"generic_key": "slowpath"})
+					perSecond.Limit.Seconds = 1
+					return perSecond
+				}(),
+			},
+			wantSummary: &integreatlyv1alpha1.RateLimitUsageSummary{Global: usage(100, 40)},
+		},
+		{
+			name:             "test tenant usage is reported for multitenant installations",
+			installationType: integreatlyv1alpha1.InstallationTypeMultitenantManagedApi,
+			counters: []limitadorCounter{
+				counter(100, 90, globalConditions, map[string]string{"generic_key": "slowpath"}),
+				counter(10, 0, tenantConditions, map[string]string{"tenant": "busy"}),
+				counter(10, 8, tenantConditions, map[string]string{"tenant": "quiet"}),
+			},
+			wantSummary: &integreatlyv1alpha1.RateLimitUsageSummary{
+				Global:         usage(100, 10),
+				Tenants:        2,
+				TenantsAtLimit: 1,
+			},
+		},
+		{
+			name:             "test tenant usage is not read from the counters of the route limits",
+			installationType: integreatlyv1alpha1.InstallationTypeMultitenantManagedApi,
+			counters: []limitadorCounter{
+				counter(100, 90, globalConditions, map[string]string{"generic_key": "slowpath"}),
+				counter(10, 0, tenantConditions, map[string]string{"tenant": "busy"}),
+				counter(10, 8, tenantConditions, map[string]string{"tenant": "quiet"}),
+				// the route limit is used up by both tenants and by a tenant without a tenant limit counter
+				counter(1000, 0, routeConditions, map[string]string{"tenant": "busy"}),
+				counter(1000, 0, routeConditions, map[string]string{"tenant": "quiet"}),
+				counter(1000, 0, routeConditions, map[string]string{"tenant": "idle"}),
+			},
+			wantSummary: &integreatlyv1alpha1.RateLimitUsageSummary{
+				Global:         usage(100, 10),
+				Tenants:        2,
+				TenantsAtLimit: 1,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := fake.NewFakeClientWithScheme(scheme)
+
+			installation := &integreatlyv1alpha1.RHMI{Spec: integreatlyv1alpha1.RHMISpec{Type: string(tt.installationType)}}
+			r := &RateLimitServiceReconciler{
+				Installation:    installation,
+				RateLimitConfig: marin3rconfig.RateLimitConfig{Unit: "minute", RequestsPerUnit: 100},
+				LimitadorClient: newTestLimitadorClient(t, &fakeLimitador{counters: tt.counters, failures: tt.failures}),
+			}
+
+			err := r.ReconcileRateLimitUsage(context.TODO(), client)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ReconcileRateLimitUsage() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(installation.Status.RateLimitUsage, tt.wantSummary) {
+				t.Errorf("expected summary %+v but got %+v", tt.wantSummary, installation.Status.RateLimitUsage)
+			}
+		})
+	}
+}
+
+func TestReconcileTenantRateLimitUsage(t *testing.T) {
+	scheme := newScheme()
+
+	tenantLimit := limitadorLimit{Namespace: ratelimit.RateLimitDomain, MaxValue: 10, Seconds: 60, Conditions: []string{"header_match == per-mt-limit"}, Variables: []string{"tenant"}}
+	overrideLimit := limitadorLimit{Namespace: ratelimit.RateLimitDomain, MaxValue: 5000, Seconds: 3600, Conditions: []string{"header_match == per-tenant-limit", "tenant == paying"}, Variables: []string{"tenant"}}
+	routeLimit := limitadorLimit{Namespace: ratelimit.RateLimitDomain, MaxValue: 20000, Seconds: 3600, Conditions: []string{"route == token"}, Variables: []string{"tenant"}}
+	counter := func(limit limitadorLimit, remaining int64, tenantName string) limitadorCounter {
+		return limitadorCounter{
+			Limit:        limit,
+			SetVariables: map[string]string{"tenant": tenantName},
+			Remaining:    &remaining,
+		}
+	}
+	tenant := func(namespace string) *integreatlyv1alpha1.APIManagementTenant {
+		return &integreatlyv1alpha1.APIManagementTenant{
+			ObjectMeta: metav1.ObjectMeta{Name: "example", Namespace: namespace},
+		}
+	}
+	limit := func(unit string, requestsPerUnit uint32) integreatlyv1alpha1.TenantRateLimit {
+		return integreatlyv1alpha1.TenantRateLimit{Unit: unit, RequestsPerUnit: requestsPerUnit}
+	}
+	unprovisioned := tenant("rejected-dev")
+	unprovisioned.Status.ProvisioningStatus = integreatlyv1alpha1.WontProvisionTenant
+	paying := tenant("paying-dev")
+	paying.Spec.RateLimit = &integreatlyv1alpha1.TenantRateLimit{Unit: "hour", RequestsPerUnit: 5000}
+
+	tests := []struct {
+		name            string
+		limits          []limitadorLimit
+		counters        []limitadorCounter
+		failures        int
+		tenants         []*integreatlyv1alpha1.APIManagementTenant
+		wantErr         bool
+		wantRateLimit   map[string]*integreatlyv1alpha1.TenantRateLimitStatus
+		wantTenantUsage map[string]*integreatlyv1alpha1.RateLimitUsage
+	}{
+		{
+			name:     "test error when limitador can not be read",
+			failures: 3,
+			tenants:  []*integreatlyv1alpha1.APIManagementTenant{tenant("busy-dev")},
+			wantErr:  true,
+			wantRateLimit: map[string]*integreatlyv1alpha1.TenantRateLimitStatus{
+				"busy-dev": nil,
+			},
+			wantTenantUsage: map[string]*integreatlyv1alpha1.RateLimitUsage{
+				"busy-dev": nil,
+			},
+		},
+		{
+			name:   "test the limit and the usage are reported in the status of the tenants",
+			limits: []limitadorLimit{tenantLimit, overrideLimit, routeLimit},
+			counters: []limitadorCounter{
+				counter(tenantLimit, 0, "busy"),
+				counter(tenantLimit, 8, "quiet"),
+				counter(tenantLimit, 0, "rejected"),
+				counter(overrideLimit, 4000, "paying"),
+				// the usage of the route limits is not the usage of the limit of the tenant
+				counter(routeLimit, 0, "quiet"),
+				counter(routeLimit, 0, "idle"),
+				counter(routeLimit, 0, "paying"),
+			},
+			tenants: []*integreatlyv1alpha1.APIManagementTenant{tenant("busy-dev"), tenant("quiet-dev"), tenant("idle-dev"), paying, unprovisioned},
+			wantRateLimit: map[string]*integreatlyv1alpha1.TenantRateLimitStatus{
+				"busy-dev":     {TenantRateLimit: limit("minute", 10)},
+				"quiet-dev":    {TenantRateLimit: limit("minute", 10)},
+				"idle-dev":     {TenantRateLimit: limit("minute", 10)},
+				"paying-dev":   {TenantRateLimit: limit("hour", 5000), Override: true},
+				"rejected-dev": nil,
+			},
+			wantTenantUsage: map[string]*integreatlyv1alpha1.RateLimitUsage{
+				"busy-dev":     {TenantRateLimit: limit("minute", 10), Requests: 10},
+				"quiet-dev":    {TenantRateLimit: limit("minute", 10), Requests: 2},
+				"idle-dev":     {TenantRateLimit: limit("minute", 10), Requests: 0},
+				"paying-dev":   {TenantRateLimit: limit("hour", 5000), Requests: 1000},
+				"rejected-dev": nil,
+			},
+		},
+		{
+			name:    "test only the overrides are reported while limitador has no limit for the tenants",
+			limits:  []limitadorLimit{overrideLimit},
+			tenants: []*integreatlyv1alpha1.APIManagementTenant{tenant("busy-dev"), paying},
+			wantRateLimit: map[string]*integreatlyv1alpha1.TenantRateLimitStatus{
+				"busy-dev":   nil,
+				"paying-dev": {TenantRateLimit: limit("hour", 5000), Override: true},
+			},
+			wantTenantUsage: map[string]*integreatlyv1alpha1.RateLimitUsage{
+				"busy-dev":   nil,
+				"paying-dev": {TenantRateLimit: limit("hour", 5000), Requests: 0},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			initObjs := []runtime.Object{}
+			for _, tenant := range tt.tenants {
+				initObjs = append(initObjs, tenant.DeepCopy())
+			}
+			client := fake.NewFakeClientWithScheme(scheme, initObjs...)
+
+			limitador := &fakeLimitador{limits: tt.limits, counters: tt.counters, failures: tt.failures}
+			err := ReconcileTenantRateLimitUsage(context.TODO(), client, newTestLimitadorClient(t, limitador))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ReconcileTenantRateLimitUsage() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			for namespace, want := range tt.wantTenantUsage {
+				got := &integreatlyv1alpha1.APIManagementTenant{}
+				if err := client.Get(context.TODO(), k8sclient.ObjectKey{Name: "example", Namespace: namespace}, got); err != nil {
+					t.Fatal(err)
+				}
+				if !reflect.DeepEqual(got.Status.RateLimit, tt.wantRateLimit[namespace]) {
+					t.Errorf("expected rate limit %+v for tenant %s but got %+v", tt.wantRateLimit[namespace], namespace, got.Status.RateLimit)
+				}
+				if !reflect.DeepEqual(got.Status.RateLimitUsage, want) {
+					t.Errorf("expected usage %+v for tenant %s but got %+v", want, namespace, got.Status.RateLimitUsage)
+				}
+			}
+		})
+	}
+}
+
+func TestGetExemptRequestsFromCounters(t *testing.T) {
+	counter := func(namespace string, remaining int64, variables map[string]string) limitadorCounter {
+		return limitadorCounter{
+			Limit:        limitadorLimit{Namespace: namespace, MaxValue: 1000, Seconds: 60},
+			SetVariables: variables,
+			Remaining:    &remaining,
+		}
+	}
+	exemptions := []marin3rconfig.RateLimitExemption{{Name: "blackbox-exporter"}, {Name: "internal"}}
+
+	got := getExemptRequestsFromCounters([]limitadorCounter{
+		counter(ratelimit.RateLimitExemptDomain, 990, map[string]string{"exemption": "blackbox-exporter"}),
+		counter(ratelimit.RateLimitExemptDomain, 500, map[string]string{"exemption": "removed"}),
+		counter(ratelimit.RateLimitDomain, 0, map[string]string{"generic_key": "slowpath"}),
+	}, exemptions)
+
+	want := map[string]uint32{"blackbox-exporter": 10, "internal": 0}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected exempt requests %v but got %v", want, got)
+	}
+}
@@ -3,6 +3,7 @@ package marin3r
 import (
 	"context"
 	"fmt"
+	"net/http"
 
 	"github.com/integr8ly/integreatly-operator/pkg/resources/quota"
 
@@ -20,6 +21,7 @@ import (
 	marin3roperator "github.com/3scale-ops/marin3r/apis/operator.marin3r/v1alpha1"
 	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/apis/v1alpha1"
 	"github.com/integr8ly/integreatly-operator/pkg/config"
+	"github.com/integr8ly/integreatly-operator/pkg/products"
 	marin3rconfig "github.com/integr8ly/integreatly-operator/pkg/products/marin3r/config"
 	"github.com/integr8ly/integreatly-operator/pkg/resources"
 	"github.com/integr8ly/integreatly-operator/pkg/resources/backup"
@@ -27,7 +29,6 @@ import (
 	"github.com/integr8ly/integreatly-operator/pkg/resources/events"
 	"github.com/integr8ly/integreatly-operator/pkg/resources/marketplace"
 	"github.com/integr8ly/integreatly-operator/pkg/resources/ratelimit"
-	"github.com/integr8ly/integreatly-operator/version"
 	k8serr "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
@@ -48,10 +49,14 @@ type Reconciler struct {
 	Config          *config.Marin3r
 	RateLimitConfig marin3rconfig.RateLimitConfig
 	AlertsConfig    map[string]*marin3rconfig.AlertConfig
+	AlertPolicies   []integreatlyv1alpha1.RateLimitAlertPolicy
 	installation    *integreatlyv1alpha1.RHMI
 	mpm             marketplace.MarketplaceInterface
 	log             l.Logger
 	recorder        record.EventRecorder
+	httpClient      *http.Client
+
+	alertPolicyErrors map[string]error
 }
 
 func (r *Reconciler) GetPreflightObject(ns string) runtime.Object {
@@ -59,14 +64,10 @@ func (r *Reconciler) GetPreflightObject(ns string) runtime.Object {
 }
 
 func (r *Reconciler) VerifyVersion(installation *integreatlyv1alpha1.RHMI) bool {
-	return version.VerifyProductAndOperatorVersion(
-		installation.Status.Stages[integreatlyv1alpha1.InstallStage].Products[integreatlyv1alpha1.ProductMarin3r],
-		string(integreatlyv1alpha1.VersionMarin3r),
-		string(integreatlyv1alpha1.OperatorVersionMarin3r),
-	)
+	return products.VerifyVersion(installation, integreatlyv1alpha1.InstallStage, integreatlyv1alpha1.ProductMarin3r)
 }
 
-func NewReconciler(configManager config.ConfigReadWriter, installation *integreatlyv1alpha1.RHMI, mpm marketplace.MarketplaceInterface, recorder record.EventRecorder, logger l.Logger, productDeclaration *marketplace.ProductDeclaration) (*Reconciler, error) {
+func NewReconciler(configManager config.ConfigReadWriter, installation *integreatlyv1alpha1.RHMI, mpm marketplace.MarketplaceInterface, recorder record.EventRecorder, logger l.Logger, productDeclaration *marketplace.ProductDeclaration, httpClient *http.Client) (*Reconciler, error) {
 	if productDeclaration == nil {
 		return nil, fmt.Errorf("no product declaration found for marin3r")
 	}
@@ -98,6 +99,7 @@ func NewReconciler(configManager config.ConfigReadWriter, installation *integrea
 		log:           logger,
 		Reconciler:    resources.NewReconciler(mpm).WithProductDeclaration(*productDeclaration),
 		recorder:      recorder,
+		httpClient:    httpClient,
 	}, nil
 }
 
@@ -146,12 +148,10 @@ func (r *Reconciler) Reconcile(ctx context.Context, installation *integreatlyv1a
 
 	r.RateLimitConfig = productConfig.GetRateLimitConfig()
 
-	alertsConfig, err := marin3rconfig.GetAlertConfig(ctx, client, r.installation.Namespace)
-	if err != nil {
+	if err := r.loadAlertPolicies(ctx, client); err != nil {
 		events.HandleError(r.recorder, installation, phase, "Failed to obtain rate limit alerts config", err)
 		return integreatlyv1alpha1.PhaseFailed, err
 	}
-	r.AlertsConfig = alertsConfig
 
 	phase, err = r.ReconcileNamespace(ctx, operatorNamespace, installation, client, r.log)
 	if err != nil || phase != integreatlyv1alpha1.PhaseCompleted {
@@ -193,8 +193,8 @@ func (r *Reconciler) Reconcile(ctx context.Context, installation *integreatlyv1a
 		return phase, nil
 	}
 
-	phase, err = NewRateLimitServiceReconciler(r.RateLimitConfig, installation, productNamespace, externalRedisSecretName, resources.NewPodExecutor(r.log), r.ConfigManager).
-		ReconcileRateLimitService(ctx, client, productConfig)
+	rateLimitServiceReconciler := NewRateLimitServiceReconciler(r.RateLimitConfig, installation, productNamespace, externalRedisSecretName, NewLimitadorClient(r.httpClient, GetLimitadorServiceURL(productNamespace)))
+	phase, err = rateLimitServiceReconciler.ReconcileRateLimitService(ctx, client, productConfig)
 	if err != nil {
 		events.HandleError(r.recorder, installation, phase, "Failed to reconcile rate limit service", err)
 		return phase, err
@@ -203,6 +203,11 @@ func (r *Reconciler) Reconcile(ctx context.Context, installation *integreatlyv1a
 		return phase, nil
 	}
 
+	// the usage is only reported, failing to read it must not block the installation
+	if err := rateLimitServiceReconciler.ReconcileRateLimitUsage(ctx, client); err != nil {
+		r.log.Warning("Failed to report the rate limit usage: " + err.Error())
+	}
+
 	phase, err = r.reconcileServiceMonitor(ctx, client, productNamespace)
 	if err != nil || phase != integreatlyv1alpha1.PhaseCompleted {
 		events.HandleError(r.recorder, installation, phase, fmt.Sprintf("Failed to reconcile Prometheus service monitor"), err)
@@ -273,6 +278,10 @@ func (r *Reconciler) reconcileAlerts(ctx context.Context, client k8sclient.Clien
 		events.HandleError(r.recorder, installation, phase, "Failed to reconcile alerts", err)
 		return phase, err
 	}
+
+	if err := r.updateAlertPolicyStatuses(ctx, client); err != nil {
+		return integreatlyv1alpha1.PhaseFailed, err
+	}
 	return integreatlyv1alpha1.PhaseCompleted, nil
 }
 
@@ -4,6 +4,7 @@ import (
 	"context"
 	"github.com/integr8ly/integreatly-operator/pkg/resources/marketplace"
 	"k8s.io/client-go/tools/record"
+	"net/http"
 	"testing"
 
 	l "github.com/integr8ly/integreatly-operator/pkg/resources/logger"
@@ -195,7 +196,7 @@ func TestAlertCreation(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 
-			reconciler, err := NewReconciler(getBasicConfig(), tt.installation, tt.FakeMPM, tt.Recorder, getLogger(), localProductDeclaration)
+			reconciler, err := NewReconciler(getBasicConfig(), tt.installation, tt.FakeMPM, tt.Recorder, getLogger(), localProductDeclaration, &http.Client{})
 			reconciler.RateLimitConfig = RateLimitConfig
 			if err != nil {
 				t.Fatalf("Could not create new reconiler")
@@ -0,0 +1,34 @@
+package marin3r
+
+import (
+	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/apis/v1alpha1"
+	"github.com/integr8ly/integreatly-operator/pkg/config"
+	"github.com/integr8ly/integreatly-operator/pkg/products"
+	"github.com/integr8ly/integreatly-operator/pkg/resources/quota"
+)
+
+func init() {
+	products.Register(products.Registration{
+		Name: integreatlyv1alpha1.ProductMarin3r,
+		InstallationTypes: []integreatlyv1alpha1.InstallationType{
+			integreatlyv1alpha1.InstallationTypeManagedApi,
+			integreatlyv1alpha1.InstallationTypeMultitenantManagedApi,
+		},
+		Dependencies: []integreatlyv1alpha1.ProductName{
+			integreatlyv1alpha1.ProductCloudResources,
+			integreatlyv1alpha1.ProductObservability,
+		},
+		Version:         integreatlyv1alpha1.VersionMarin3r,
+		OperatorVersion: integreatlyv1alpha1.OperatorVersionMarin3r,
+		NewConfig: func(productConfig config.ProductConfig) config.ConfigReadable {
+			return config.NewMarin3r(productConfig)
+		},
+		QuotaComponents: []string{
+			quota.RateLimitName,
+		},
+		WatchableCRDs: config.NewMarin3r(config.ProductConfig{}).GetWatchableCRDs(),
+		NewReconciler: func(opts products.ReconcilerOptions) (products.Interface, error) {
+			return NewReconciler(opts.ConfigManager, opts.Installation, opts.MPM, opts.Recorder, opts.Log, opts.ProductDeclaration, opts.HTTPClient())
+		},
+	})
+}
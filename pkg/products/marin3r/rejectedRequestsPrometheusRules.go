@@ -2,6 +2,8 @@ package marin3r
 
 import (
 	"fmt"
+	"strings"
+
 	l "github.com/integr8ly/integreatly-operator/pkg/resources/logger"
 
 	"github.com/integr8ly/integreatly-operator/pkg/products/marin3r/config"
@@ -10,7 +12,12 @@ import (
 	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
-const rejectedRequestsAlertExpr = "abs(clamp_min(increase(limited_calls[1m]) - %f, 0) / (sum(increase(authorized_calls[1m])) + sum(increase(limited_calls[1m]))) - (increase(limited_calls[1m]) / (sum(increase(authorized_calls[1m])) + sum(increase(limited_calls[1m]))))) > 0.3"
+// rejectedRequestsAlertExpr compares the rejected requests with the requests over the limit, the
+// requests exempt from the rate limit are left out as they are never rejected
+var rejectedRequestsAlertExpr = strings.NewReplacer(
+	"limited_calls", limitedRequestsMetric,
+	"authorized_calls", totalRequestsMetric,
+).Replace("abs(clamp_min(increase(limited_calls[1m]) - %f, 0) / (sum(increase(authorized_calls[1m])) + sum(increase(limited_calls[1m]))) - (increase(limited_calls[1m]) / (sum(increase(authorized_calls[1m])) + sum(increase(limited_calls[1m]))))) > 0.3")
 
 func (r *Reconciler) newRejectedRequestsAlertsReconciler(logger l.Logger, installType string) (resources.AlertReconciler, error) {
 	installationName := resources.InstallationNames[installType]
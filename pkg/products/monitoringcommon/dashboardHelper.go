@@ -45,6 +45,9 @@ func GetSpecDetailsForDashboard(dashboard string, rhmi *v1alpha1.RHMI, container
 	case "multitenancy-detailed":
 		return monitoringcommon.MonitoringGrafanaDBMultitenancyDetailedJSON, "multitenancy-detailed.json", nil
 
+	case "product-reconcile":
+		return monitoringcommon.MonitoringGrafanaDBProductReconcileJSON, "product-reconcile.json", nil
+
 	default:
 		return "", "", fmt.Errorf("Invalid/Unsupported Grafana Dashboard")
 
@@ -130,6 +130,13 @@ func Test_getSpecDetailsForDashboard(t *testing.T) {
 			wantName:  "multitenancy-detailed.json",
 			wantErr:   "",
 		},
+		{
+			testName:  "successfully get spec for product-reconcile dashboard",
+			dashboard: "product-reconcile",
+			wantSpec:  monitoringcommon.MonitoringGrafanaDBProductReconcileJSON,
+			wantName:  "product-reconcile.json",
+			wantErr:   "",
+		},
 		{
 			testName:  "fail on empty dashboard name",
 			dashboard: "",
@@ -7,8 +7,8 @@ import (
 	"github.com/integr8ly/integreatly-operator/pkg/resources/quota"
 	"strings"
 
+	"github.com/integr8ly/integreatly-operator/pkg/products"
 	"github.com/integr8ly/integreatly-operator/pkg/resources/events"
-	"github.com/integr8ly/integreatly-operator/version"
 	"github.com/operator-framework/operator-registry/pkg/lib/bundle"
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 
@@ -57,11 +57,7 @@ func (r *Reconciler) GetPreflightObject(_ string) runtime.Object {
 }
 
 func (r *Reconciler) VerifyVersion(installation *integreatlyv1alpha1.RHMI) bool {
-	return version.VerifyProductAndOperatorVersion(
-		installation.Status.Stages[integreatlyv1alpha1.MonitoringStage].Products[integreatlyv1alpha1.ProductMonitoringSpec],
-		string(integreatlyv1alpha1.VersionMonitoringSpec),
-		"",
-	)
+	return products.VerifyVersion(installation, integreatlyv1alpha1.MonitoringStage, integreatlyv1alpha1.ProductMonitoringSpec)
 }
 
 func NewReconciler(configManager config.ConfigReadWriter, installation *integreatlyv1alpha1.RHMI,
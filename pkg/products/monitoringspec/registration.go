@@ -0,0 +1,22 @@
+package monitoringspec
+
+import (
+	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/apis/v1alpha1"
+	"github.com/integr8ly/integreatly-operator/pkg/config"
+	"github.com/integr8ly/integreatly-operator/pkg/products"
+)
+
+func init() {
+	products.Register(products.Registration{
+		Name: integreatlyv1alpha1.ProductMonitoringSpec,
+		// the monitoring spec is installed without an operator
+		Version: integreatlyv1alpha1.VersionMonitoringSpec,
+		NewConfig: func(productConfig config.ProductConfig) config.ConfigReadable {
+			return config.NewMonitoringSpec(productConfig)
+		},
+		WatchableCRDs: config.NewMonitoringSpec(config.ProductConfig{}).GetWatchableCRDs(),
+		NewReconciler: func(opts products.ReconcilerOptions) (products.Interface, error) {
+			return NewReconciler(opts.ConfigManager, opts.Installation, opts.MPM, opts.Recorder, opts.Log)
+		},
+	})
+}
@@ -0,0 +1,26 @@
+package observability
+
+import (
+	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/apis/v1alpha1"
+	"github.com/integr8ly/integreatly-operator/pkg/config"
+	"github.com/integr8ly/integreatly-operator/pkg/products"
+)
+
+func init() {
+	products.Register(products.Registration{
+		Name: integreatlyv1alpha1.ProductObservability,
+		InstallationTypes: []integreatlyv1alpha1.InstallationType{
+			integreatlyv1alpha1.InstallationTypeManagedApi,
+			integreatlyv1alpha1.InstallationTypeMultitenantManagedApi,
+		},
+		Version:         integreatlyv1alpha1.VersionObservability,
+		OperatorVersion: integreatlyv1alpha1.OperatorVersionObservability,
+		NewConfig: func(productConfig config.ProductConfig) config.ConfigReadable {
+			return config.NewObservability(productConfig)
+		},
+		WatchableCRDs: config.NewObservability(config.ProductConfig{}).GetWatchableCRDs(),
+		NewReconciler: func(opts products.ReconcilerOptions) (products.Interface, error) {
+			return NewReconciler(opts.ConfigManager, opts.Installation, opts.MPM, opts.Recorder, opts.Log, opts.ProductDeclaration)
+		},
+	})
+}
@@ -2,36 +2,25 @@ package products
 
 import (
 	"context"
-	"crypto/tls"
 	"errors"
-	"github.com/integr8ly/integreatly-operator/pkg/resources/quota"
+	"net/http"
 	"time"
 
-	"github.com/integr8ly/integreatly-operator/pkg/products/marin3r"
-
-	"github.com/integr8ly/integreatly-operator/pkg/products/monitoringspec"
-
-	keycloakCommon "github.com/integr8ly/keycloak-client/pkg/common"
-
 	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/apis/v1alpha1"
+	integreatlyclient "github.com/integr8ly/integreatly-operator/pkg/client"
 	"github.com/integr8ly/integreatly-operator/pkg/config"
-	"github.com/integr8ly/integreatly-operator/pkg/products/cloudresources"
-	"github.com/integr8ly/integreatly-operator/pkg/products/grafana"
-	"github.com/integr8ly/integreatly-operator/pkg/products/observability"
-	"github.com/integr8ly/integreatly-operator/pkg/products/rhsso"
-	"github.com/integr8ly/integreatly-operator/pkg/products/rhssouser"
-	"github.com/integr8ly/integreatly-operator/pkg/resources"
 	l "github.com/integr8ly/integreatly-operator/pkg/resources/logger"
 	"github.com/integr8ly/integreatly-operator/pkg/resources/marketplace"
-	"net/http"
-
-	"github.com/integr8ly/integreatly-operator/pkg/products/threescale"
-	appsv1Client "github.com/openshift/client-go/apps/clientset/versioned/typed/apps/v1"
+	"github.com/integr8ly/integreatly-operator/pkg/resources/quota"
+	"github.com/integr8ly/integreatly-operator/pkg/tracing"
+	keycloakCommon "github.com/integr8ly/keycloak-client/pkg/common"
+	keycloak "github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
 	oauthClient "github.com/openshift/client-go/oauth/clientset/versioned/typed/oauth/v1"
 
 	appsv1 "k8s.io/api/apps/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
 	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 )
@@ -86,25 +75,74 @@ type Interface interface {
 	VerifyVersion(installation *integreatlyv1alpha1.RHMI) bool
 }
 
-func NewReconciler(product integreatlyv1alpha1.ProductName, rc *rest.Config, configManager config.ConfigReadWriter, installation *integreatlyv1alpha1.RHMI, mgr manager.Manager, log l.Logger, productsInstalllationLoader marketplace.ProductsInstallationLoader) (reconciler Interface, err error) {
-	mpm := marketplace.NewManager()
-	/* #nosec */
-	oauthHttpClient := &http.Client{
-		Timeout: time.Second * 10,
-		Transport: &http.Transport{
-			DisableKeepAlives: true,
-			IdleConnTimeout:   time.Second * 10,
-			TLSClientConfig:   &tls.Config{InsecureSkipVerify: installation.Spec.SelfSignedCerts}, // gosec G402, value is read from CR config
-		},
+// ReconcilerOptions holds what the product reconcilers are built from. The clients that more than
+// one product needs are built by its methods, so every product builds them the same way
+type ReconcilerOptions struct {
+	Product            integreatlyv1alpha1.ProductName
+	RestConfig         *rest.Config
+	ConfigManager      config.ConfigReadWriter
+	Installation       *integreatlyv1alpha1.RHMI
+	MPM                marketplace.MarketplaceInterface
+	Recorder           record.EventRecorder
+	Log                l.Logger
+	ProductDeclaration *marketplace.ProductDeclaration
+	HTTPClients        *integreatlyclient.HTTPClientFactory
+	// Trace holds the span of the product reconcile, the calls to the product APIs are traced as
+	// its children
+	Trace context.Context
+}
+
+// OauthClient returns a client for the OpenShift OAuth API
+func (o ReconcilerOptions) OauthClient() (oauthClient.OauthV1Interface, error) {
+	oauthv1Client, err := oauthClient.NewForConfig(o.RestConfig)
+	if err != nil {
+		return nil, err
+	}
+	oauthv1Client.RESTClient().(*rest.RESTClient).Client.Timeout = 10 * time.Second
+	return oauthv1Client, nil
+}
+
+// HTTPClient returns a client for the APIs of the installed products, it trusts the CA bundles
+// configured for the installation and skips TLS verification when the installation uses self
+// signed certificates
+func (o ReconcilerOptions) HTTPClient() *http.Client {
+	if o.Installation.Spec.SelfSignedCerts {
+		o.Log.Warning("TLS insecure skip verify is enabled")
+	}
+
+	httpClient := o.HTTPClients.Client(time.Second * 10)
+	httpClient.Transport = tracing.NewTransport(httpClient.Transport, o.Trace)
+	if o.Installation.IsDryRun() {
+		httpClient.Transport = integreatlyclient.NewReadOnlyRoundTripper(httpClient.Transport)
+	}
+	return httpClient
+}
+
+// KeycloakClientFactory returns the factory for clients of the keycloak API, the keycloak server
+// must be trusted by the clients of the installation
+func (o ReconcilerOptions) KeycloakClientFactory() keycloakCommon.KeycloakClientFactory {
+	if o.Installation.IsDryRun() {
+		return &dryRunKeycloakClientFactory{}
 	}
+	factory := integreatlyclient.NewKeycloakClientFactory(&keycloakCommon.LocalConfigKeycloakFactory{}, o.HTTPClients)
+	return tracing.NewKeycloakClientFactory(factory, o.Trace)
+}
 
-	if installation.Spec.SelfSignedCerts {
-		log.Warning("TLS insecure skip verify is enabled")
+// NewReconciler builds the reconciler of a registered product, see Register
+func NewReconciler(ctx context.Context, product integreatlyv1alpha1.ProductName, rc *rest.Config, configManager config.ConfigReadWriter, installation *integreatlyv1alpha1.RHMI, mgr manager.Manager, log l.Logger, productsInstalllationLoader marketplace.ProductsInstallationLoader, httpClients *integreatlyclient.HTTPClientFactory) (reconciler Interface, err error) {
+	registration, ok := Lookup(product)
+	if !ok {
+		return &NoOp{}, errors.New("unknown products: " + string(product))
 	}
 
-	oauthResolver := resources.NewOauthResolver(oauthHttpClient, log)
-	oauthResolver.Host = rc.Host
-	recorder := mgr.GetEventRecorderFor(string(product))
+	// when the installation is only being planned the product clients must not change anything,
+	// writes to the cluster are recorded by the client passed to Reconcile instead
+	var recorder record.EventRecorder = &record.FakeRecorder{}
+	if installation.IsDryRun() {
+		rc = integreatlyclient.NewReadOnlyConfig(rc)
+	} else {
+		recorder = mgr.GetEventRecorderFor(string(product))
+	}
 
 	productsInstallation, err := productsInstalllationLoader.GetProductsInstallation()
 	if err != nil {
@@ -117,75 +155,27 @@ func NewReconciler(product integreatlyv1alpha1.ProductName, rc *rest.Config, con
 		productDeclaration = &pd
 	}
 
-	switch product {
-	case integreatlyv1alpha1.ProductRHSSO:
-		oauthv1Client, err := oauthClient.NewForConfig(rc)
-		oauthv1Client.RESTClient().(*rest.RESTClient).Client.Timeout = 10 * time.Second
-		if err != nil {
-			return nil, err
-		}
-		reconciler, err = rhsso.NewReconciler(configManager, installation, oauthv1Client, mpm, recorder, rc.Host, &keycloakCommon.LocalConfigKeycloakFactory{}, log, productDeclaration)
-		if err != nil {
-			return nil, err
-		}
-	case integreatlyv1alpha1.ProductRHSSOUser:
-		oauthv1Client, err := oauthClient.NewForConfig(rc)
-		oauthv1Client.RESTClient().(*rest.RESTClient).Client.Timeout = 10 * time.Second
-		if err != nil {
-			return nil, err
-		}
-		reconciler, err = rhssouser.NewReconciler(configManager, installation, oauthv1Client, mpm, recorder, rc.Host, &keycloakCommon.LocalConfigKeycloakFactory{}, log, productDeclaration)
-		if err != nil {
-			return nil, err
-		}
-	case integreatlyv1alpha1.ProductMonitoringSpec:
-		reconciler, err = monitoringspec.NewReconciler(configManager, installation, mpm, recorder, log)
-	case integreatlyv1alpha1.Product3Scale:
-		client, err := appsv1Client.NewForConfig(rc)
-		client.RESTClient().(*rest.RESTClient).Client.Timeout = 10 * time.Second
-		if err != nil {
-			return nil, err
-		}
-
-		oauthv1Client, err := oauthClient.NewForConfig(rc)
-		oauthv1Client.RESTClient().(*rest.RESTClient).Client.Timeout = 10 * time.Second
-		if err != nil {
-			return nil, err
-		}
-
-		/* #nosec */
-		httpc := &http.Client{
-			Timeout: time.Second * 10,
-			Transport: &http.Transport{
-				DisableKeepAlives: true,
-				IdleConnTimeout:   time.Second * 10,
-				TLSClientConfig:   &tls.Config{InsecureSkipVerify: installation.Spec.SelfSignedCerts}, // gosec G402, value is read from CR config
-			},
-		}
-
-		if installation.Spec.SelfSignedCerts {
-			log.Warning("TLS insecure skip verify is enabled")
-		}
-
-		tsClient := threescale.NewThreeScaleClient(httpc, installation.Spec.RoutingSubdomain)
-		reconciler, err = threescale.NewReconciler(configManager, installation, client, oauthv1Client, tsClient, mpm, recorder, log, productDeclaration)
-		if err != nil {
-			return nil, err
-		}
-	case integreatlyv1alpha1.ProductCloudResources:
-		reconciler, err = cloudresources.NewReconciler(configManager, installation, mpm, recorder, log, productDeclaration)
-	case integreatlyv1alpha1.ProductMarin3r:
-		reconciler, err = marin3r.NewReconciler(configManager, installation, mpm, recorder, log, productDeclaration)
-	case integreatlyv1alpha1.ProductGrafana:
-		reconciler, err = grafana.NewReconciler(configManager, installation, mpm, recorder, log, productDeclaration)
-	case integreatlyv1alpha1.ProductObservability:
-		reconciler, err = observability.NewReconciler(configManager, installation, mpm, recorder, log, productDeclaration)
-	default:
-		err = errors.New("unknown products: " + string(product))
-		reconciler = &NoOp{}
-	}
+	return registration.NewReconciler(ReconcilerOptions{
+		Product:            product,
+		RestConfig:         rc,
+		ConfigManager:      configManager,
+		Installation:       installation,
+		MPM:                marketplace.NewManager(),
+		Recorder:           recorder,
+		Log:                log,
+		ProductDeclaration: productDeclaration,
+		HTTPClients:        httpClients,
+		Trace:              ctx,
+	})
+}
+
+// dryRunKeycloakClientFactory refuses to create keycloak clients, requests to the keycloak API can
+// not be recorded so they are not made while an installation is being planned
+type dryRunKeycloakClientFactory struct {
+}
 
-	return reconciler, err
+func (f *dryRunKeycloakClientFactory) AuthenticatedClient(_ keycloak.Keycloak) (keycloakCommon.KeycloakInterface, error) {
+	return nil, errors.New("the keycloak API is not available in dry run mode")
 }
 
 type NoOp struct {
@@ -0,0 +1,129 @@
+package products
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/apis/v1alpha1"
+	"github.com/integr8ly/integreatly-operator/pkg/config"
+	"github.com/integr8ly/integreatly-operator/pkg/resources/quota"
+	"github.com/integr8ly/integreatly-operator/version"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// Registration describes a product the operator can install. Each product package registers itself
+// from an init function, so importing the package is enough to make the product available
+type Registration struct {
+	Name integreatlyv1alpha1.ProductName
+
+	// InstallationTypes are the installation types the product is installed by
+	InstallationTypes []integreatlyv1alpha1.InstallationType
+	// Dependencies are the products that must complete before the product is reconciled
+	Dependencies []integreatlyv1alpha1.ProductName
+
+	// Version and OperatorVersion are the versions of the product and of its operator that the
+	// operator installs, the product status reports a version mismatch until they are installed
+	Version         integreatlyv1alpha1.ProductVersion
+	OperatorVersion integreatlyv1alpha1.OperatorVersion
+
+	// NewConfig builds the config type of the product from its entry in the installation config map
+	NewConfig func(config.ProductConfig) config.ConfigReadable
+	// QuotaComponents are the names of the deployments, deployment configs and stateful sets of the
+	// product that are sized by the quota
+	QuotaComponents []string
+	// WatchableCRDs trigger a reconcile of the installation when they change in the namespace of the
+	// product
+	WatchableCRDs []runtime.Object
+
+	NewReconciler ReconcilerFactory
+}
+
+// ReconcilerFactory builds the reconciler of a product, it is called on every reconcile of the
+// installation
+type ReconcilerFactory func(opts ReconcilerOptions) (Interface, error)
+
+var (
+	registrations     = map[integreatlyv1alpha1.ProductName]Registration{}
+	registrationsLock sync.RWMutex
+)
+
+// Register makes a product available to the installation controller. Like the registration of
+// schemes it is expected to be called from an init function, so it panics if the registration is
+// invalid or the product has already been registered
+func Register(registration Registration) {
+	if registration.Name == "" {
+		panic("products: product registered without a name")
+	}
+	if registration.NewReconciler == nil {
+		panic(fmt.Sprintf("products: no reconciler factory registered for %s", registration.Name))
+	}
+
+	registrationsLock.Lock()
+	defer registrationsLock.Unlock()
+
+	if _, ok := registrations[registration.Name]; ok {
+		panic(fmt.Sprintf("products: %s registered twice", registration.Name))
+	}
+	registrations[registration.Name] = registration
+
+	if registration.NewConfig != nil {
+		config.RegisterProductConfig(registration.Name, registration.NewConfig)
+	}
+	if len(registration.QuotaComponents) > 0 {
+		quota.RegisterProductComponents(registration.Name, registration.QuotaComponents)
+	}
+}
+
+// Lookup returns the registration of the product
+func Lookup(product integreatlyv1alpha1.ProductName) (Registration, bool) {
+	registrationsLock.RLock()
+	defer registrationsLock.RUnlock()
+
+	registration, ok := registrations[product]
+	return registration, ok
+}
+
+// Registered returns every registered product ordered by name
+func Registered() []Registration {
+	registrationsLock.RLock()
+	defer registrationsLock.RUnlock()
+
+	registered := make([]Registration, 0, len(registrations))
+	for _, registration := range registrations {
+		registered = append(registered, registration)
+	}
+	sort.Slice(registered, func(i, j int) bool {
+		return registered[i].Name < registered[j].Name
+	})
+	return registered
+}
+
+// VerifyVersion returns true when the status of the product in the stage reports the versions the
+// product is registered with
+func VerifyVersion(installation *integreatlyv1alpha1.RHMI, stage integreatlyv1alpha1.StageName, product integreatlyv1alpha1.ProductName) bool {
+	registration, ok := Lookup(product)
+	if !ok {
+		return false
+	}
+	return version.VerifyProductAndOperatorVersion(
+		installation.Status.Stages[stage].Products[product],
+		string(registration.Version),
+		string(registration.OperatorVersion),
+	)
+}
+
+// ForInstallationType returns the registered products that are installed by the installation type
+// ordered by name
+func ForInstallationType(installationType integreatlyv1alpha1.InstallationType) []Registration {
+	var products []Registration
+	for _, registration := range Registered() {
+		for _, t := range registration.InstallationTypes {
+			if t == installationType {
+				products = append(products, registration)
+				break
+			}
+		}
+	}
+	return products
+}
@@ -0,0 +1,138 @@
+package products
+
+import (
+	"context"
+	"testing"
+
+	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/apis/v1alpha1"
+	"github.com/integr8ly/integreatly-operator/pkg/config"
+	"github.com/integr8ly/integreatly-operator/pkg/resources/quota"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const testProduct integreatlyv1alpha1.ProductName = "test-addon"
+
+func TestRegister(t *testing.T) {
+	Register(Registration{
+		Name:              testProduct,
+		InstallationTypes: []integreatlyv1alpha1.InstallationType{integreatlyv1alpha1.InstallationTypeManagedApi},
+		Dependencies:      []integreatlyv1alpha1.ProductName{integreatlyv1alpha1.ProductCloudResources},
+		Version:           "1.0.0",
+		OperatorVersion:   "0.1.0",
+		NewConfig: func(productConfig config.ProductConfig) config.ConfigReadable {
+			return config.NewGrafana(productConfig)
+		},
+		QuotaComponents: []string{"test-addon-deployment"},
+		NewReconciler: func(opts ReconcilerOptions) (Interface, error) {
+			return &NoOp{}, nil
+		},
+	})
+
+	registration, ok := Lookup(testProduct)
+	if !ok {
+		t.Fatalf("expected %s to be registered", testProduct)
+	}
+	if len(registration.Dependencies) != 1 || registration.Dependencies[0] != integreatlyv1alpha1.ProductCloudResources {
+		t.Fatalf("unexpected dependencies %v", registration.Dependencies)
+	}
+
+	if !isRegisteredFor(testProduct, integreatlyv1alpha1.InstallationTypeManagedApi) {
+		t.Errorf("expected %s to be installed by %s", testProduct, integreatlyv1alpha1.InstallationTypeManagedApi)
+	}
+	if isRegisteredFor(testProduct, integreatlyv1alpha1.InstallationTypeMultitenantManagedApi) {
+		t.Errorf("expected %s not to be installed by %s", testProduct, integreatlyv1alpha1.InstallationTypeMultitenantManagedApi)
+	}
+
+	// the config type is returned by the config manager
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	configManager, err := config.NewManager(context.TODO(), fakeclient.NewFakeClientWithScheme(scheme), "ns", "installation-config", &integreatlyv1alpha1.RHMI{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := configManager.ReadProduct(testProduct); err != nil {
+		t.Errorf("expected the config of %s to be readable: %v", testProduct, err)
+	}
+
+	// the quota components are sized by the quota
+	quotaConfig := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: quota.ConfigMapName},
+		Data: map[string]string{
+			quota.ConfigMapData: `[{"name": "100K", "param": "1", "resources": {"test-addon-deployment": {"replicas": 2}}}]`,
+		},
+	}
+	installationQuota := &quota.Quota{}
+	if err := quota.GetQuota("1", quotaConfig, installationQuota); err != nil {
+		t.Fatal(err)
+	}
+	if replicas := installationQuota.GetProduct(testProduct).GetReplicas("test-addon-deployment"); replicas != 2 {
+		t.Errorf("expected 2 replicas for the quota component but got %d", replicas)
+	}
+
+	// the installed versions are verified against the registered versions
+	installation := &integreatlyv1alpha1.RHMI{
+		Status: integreatlyv1alpha1.RHMIStatus{
+			Stages: map[integreatlyv1alpha1.StageName]integreatlyv1alpha1.RHMIStageStatus{
+				integreatlyv1alpha1.InstallStage: {
+					Products: map[integreatlyv1alpha1.ProductName]integreatlyv1alpha1.RHMIProductStatus{
+						testProduct: {Name: testProduct, Version: "1.0.0", OperatorVersion: "0.1.0"},
+					},
+				},
+			},
+		},
+	}
+	if !VerifyVersion(installation, integreatlyv1alpha1.InstallStage, testProduct) {
+		t.Errorf("expected the registered versions of %s to be verified", testProduct)
+	}
+	installation.Status.Stages[integreatlyv1alpha1.InstallStage].Products[testProduct] = integreatlyv1alpha1.RHMIProductStatus{Name: testProduct, Version: "0.9.0", OperatorVersion: "0.1.0"}
+	if VerifyVersion(installation, integreatlyv1alpha1.InstallStage, testProduct) {
+		t.Errorf("expected an older version of %s not to be verified", testProduct)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected registering %s twice to panic", testProduct)
+		}
+	}()
+	Register(registration)
+}
+
+func TestRegister_Invalid(t *testing.T) {
+	tests := []struct {
+		name         string
+		registration Registration
+	}{
+		{
+			name:         "test registration without a name",
+			registration: Registration{NewReconciler: func(opts ReconcilerOptions) (Interface, error) { return &NoOp{}, nil }},
+		},
+		{
+			name:         "test registration without a reconciler",
+			registration: Registration{Name: "test-no-reconciler"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected the registration to panic")
+				}
+			}()
+			Register(tt.registration)
+		})
+	}
+}
+
+func isRegisteredFor(product integreatlyv1alpha1.ProductName, installationType integreatlyv1alpha1.InstallationType) bool {
+	for _, registration := range ForInstallationType(installationType) {
+		if registration.Name == product {
+			return true
+		}
+	}
+	return false
+}
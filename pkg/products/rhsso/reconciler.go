@@ -7,6 +7,7 @@ import (
 	grafanav1alpha1 "github.com/grafana-operator/grafana-operator/v4/api/integreatly/v1alpha1"
 	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/apis/v1alpha1"
 	"github.com/integr8ly/integreatly-operator/pkg/config"
+	"github.com/integr8ly/integreatly-operator/pkg/products"
 	"github.com/integr8ly/integreatly-operator/pkg/products/rhssocommon"
 	"github.com/integr8ly/integreatly-operator/pkg/resources"
 	"github.com/integr8ly/integreatly-operator/pkg/resources/events"
@@ -14,7 +15,6 @@ import (
 	"github.com/integr8ly/integreatly-operator/pkg/resources/marketplace"
 	"github.com/integr8ly/integreatly-operator/pkg/resources/quota"
 	userHelper "github.com/integr8ly/integreatly-operator/pkg/resources/user"
-	"github.com/integr8ly/integreatly-operator/version"
 	keycloakCommon "github.com/integr8ly/keycloak-client/pkg/common"
 	keycloak "github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
 	"github.com/keycloak/keycloak-operator/pkg/common"
@@ -80,11 +80,7 @@ func NewReconciler(configManager config.ConfigReadWriter, installation *integrea
 }
 
 func (r *Reconciler) VerifyVersion(installation *integreatlyv1alpha1.RHMI) bool {
-	return version.VerifyProductAndOperatorVersion(
-		installation.Status.Stages[integreatlyv1alpha1.InstallStage].Products[integreatlyv1alpha1.ProductRHSSO],
-		string(integreatlyv1alpha1.VersionRHSSO),
-		string(integreatlyv1alpha1.OperatorVersionRHSSO),
-	)
+	return products.VerifyVersion(installation, integreatlyv1alpha1.InstallStage, integreatlyv1alpha1.ProductRHSSO)
 }
 
 // Reconcile reads that state of the cluster for rhsso and makes changes based on the state read
@@ -451,6 +447,10 @@ func (r *Reconciler) setupGithubIDP(ctx context.Context, kc *keycloak.Keycloak,
 		return nil
 	}
 
+	if installation.IsDryRun() {
+		return fmt.Errorf("the github identity provider is not synced in dry run mode")
+	}
+
 	r.Log.Info("Syncing github identity provider to the keycloak realm")
 
 	// Get an authenticated keycloak api client for the instance
@@ -550,7 +550,7 @@ func syncronizeWithOpenshiftUsers(ctx context.Context, keycloakUsers []keycloak.
 		return nil, err
 	}
 	for index := range keycloakUsers {
-		keycloakUsers[index].ClientRoles = getKeycloakRoles(integreatlyv1alpha1.InstallationType(installation.Spec.Type))
+		keycloakUsers[index].ClientRoles = GetKeycloakRoles(integreatlyv1alpha1.InstallationType(installation.Spec.Type))
 	}
 
 	return keycloakUsers, nil
@@ -694,7 +694,8 @@ func createAuthDelayAuthenticationFlow(authenticated keycloakCommon.KeycloakInte
 	return nil
 }
 
-func getKeycloakRoles(installationType integreatlyv1alpha1.InstallationType) map[string][]string {
+// GetKeycloakRoles returns the client roles of the users of the realm in installationType
+func GetKeycloakRoles(installationType integreatlyv1alpha1.InstallationType) map[string][]string {
 	var roles map[string][]string
 	if integreatlyv1alpha1.IsRHOAMMultitenant(integreatlyv1alpha1.InstallationType(installationType)) {
 		roles = map[string][]string{
@@ -0,0 +1,34 @@
+package rhsso
+
+import (
+	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/apis/v1alpha1"
+	"github.com/integr8ly/integreatly-operator/pkg/config"
+	"github.com/integr8ly/integreatly-operator/pkg/products"
+)
+
+func init() {
+	products.Register(products.Registration{
+		Name: integreatlyv1alpha1.ProductRHSSO,
+		InstallationTypes: []integreatlyv1alpha1.InstallationType{
+			integreatlyv1alpha1.InstallationTypeManagedApi,
+			integreatlyv1alpha1.InstallationTypeMultitenantManagedApi,
+		},
+		Dependencies: []integreatlyv1alpha1.ProductName{
+			integreatlyv1alpha1.ProductCloudResources,
+			integreatlyv1alpha1.ProductObservability,
+		},
+		Version:         integreatlyv1alpha1.VersionRHSSO,
+		OperatorVersion: integreatlyv1alpha1.OperatorVersionRHSSO,
+		NewConfig: func(productConfig config.ProductConfig) config.ConfigReadable {
+			return config.NewRHSSO(productConfig)
+		},
+		WatchableCRDs: config.NewRHSSO(config.ProductConfig{}).GetWatchableCRDs(),
+		NewReconciler: func(opts products.ReconcilerOptions) (products.Interface, error) {
+			oauthv1Client, err := opts.OauthClient()
+			if err != nil {
+				return nil, err
+			}
+			return NewReconciler(opts.ConfigManager, opts.Installation, oauthv1Client, opts.MPM, opts.Recorder, opts.RestConfig.Host, opts.KeycloakClientFactory(), opts.Log, opts.ProductDeclaration)
+		},
+	})
+}
@@ -9,7 +9,7 @@ import (
 	l "github.com/integr8ly/integreatly-operator/pkg/resources/logger"
 	"github.com/integr8ly/integreatly-operator/pkg/resources/quota"
 
-	"github.com/integr8ly/integreatly-operator/version"
+	"github.com/integr8ly/integreatly-operator/pkg/products"
 
 	userHelper "github.com/integr8ly/integreatly-operator/pkg/resources/user"
 
@@ -31,6 +31,8 @@ import (
 	oauthClient "github.com/openshift/client-go/oauth/clientset/versioned/typed/oauth/v1"
 
 	"github.com/integr8ly/integreatly-operator/pkg/resources/constants"
+	k8sappsv1 "k8s.io/api/apps/v1"
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
 	k8serr "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/tools/record"
@@ -47,6 +49,7 @@ var (
 	ssoType                   = "user sso"
 	postgresResourceName      = "rhssouser-postgres-rhmi"
 	routeName                 = "keycloak"
+	statefulSetName           = "keycloak"
 )
 
 const (
@@ -115,11 +118,7 @@ func NewReconciler(configManager config.ConfigReadWriter, installation *integrea
 }
 
 func (r *Reconciler) VerifyVersion(installation *integreatlyv1alpha1.RHMI) bool {
-	return version.VerifyProductAndOperatorVersion(
-		installation.Status.Stages[integreatlyv1alpha1.InstallStage].Products[integreatlyv1alpha1.ProductRHSSOUser],
-		string(integreatlyv1alpha1.VersionRHSSOUser),
-		string(integreatlyv1alpha1.OperatorVersionRHSSOUser),
-	)
+	return products.VerifyVersion(installation, integreatlyv1alpha1.InstallStage, integreatlyv1alpha1.ProductRHSSOUser)
 }
 
 // Reconcile reads that state of the cluster for rhsso and makes changes based on the state read
@@ -236,6 +235,12 @@ func (r *Reconciler) Reconcile(ctx context.Context, installation *integreatlyv1a
 		return phase, err
 	}
 
+	phase, err = r.reconcileAutoscaler(ctx, serverClient, productConfig)
+	if err != nil || phase != integreatlyv1alpha1.PhaseCompleted {
+		events.HandleError(r.Recorder, installation, phase, "Failed to reconcile autoscaler", err)
+		return phase, err
+	}
+
 	phase, err = r.HandleProgressPhase(ctx, serverClient, keycloakName, masterRealmName, r.Config, r.Config.RHSSOCommon, string(integreatlyv1alpha1.VersionRHSSOUser), string(integreatlyv1alpha1.OperatorVersionRHSSOUser))
 	if err != nil || phase != integreatlyv1alpha1.PhaseCompleted {
 		events.HandleError(r.Recorder, installation, phase, "Failed to handle in progress phase", err)
@@ -290,6 +295,40 @@ func (r *Reconciler) Reconcile(ctx context.Context, installation *integreatlyv1a
 	return integreatlyv1alpha1.PhaseCompleted, nil
 }
 
+// reconcileAutoscaler creates the horizontal pod autoscaler of the keycloak stateful set if the quota
+// autoscales it, and deletes it otherwise
+func (r *Reconciler) reconcileAutoscaler(ctx context.Context, serverClient k8sclient.Client, productConfig quota.ProductConfig) (integreatlyv1alpha1.StatusPhase, error) {
+	target := autoscalingv2beta2.CrossVersionObjectReference{
+		APIVersion: k8sappsv1.SchemeGroupVersion.String(),
+		Kind:       "StatefulSet",
+		Name:       statefulSetName,
+	}
+	if err := quota.ReconcileAutoscaler(ctx, serverClient, productConfig, quota.KeycloakName, r.Config.GetNamespace(), target); err != nil {
+		return integreatlyv1alpha1.PhaseFailed, err
+	}
+	return integreatlyv1alpha1.PhaseCompleted, nil
+}
+
+// syncAutoscaledInstances sets the instances of the keycloak to the replicas the stateful set was
+// scaled to if it is autoscaled, otherwise the keycloak operator would scale it back
+func (r *Reconciler) syncAutoscaledInstances(ctx context.Context, serverClient k8sclient.Client, productConfig quota.ProductConfig, kc *keycloak.Keycloak) error {
+	if productConfig.GetAutoscaling(quota.KeycloakName) == nil {
+		return nil
+	}
+	statefulSet := &k8sappsv1.StatefulSet{}
+	err := serverClient.Get(ctx, k8sclient.ObjectKey{Name: statefulSetName, Namespace: r.Config.GetNamespace()}, statefulSet)
+	if k8serr.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get stateful set %s: %w", statefulSetName, err)
+	}
+	if statefulSet.Spec.Replicas != nil {
+		kc.Spec.Instances = int(*statefulSet.Spec.Replicas)
+	}
+	return nil
+}
+
 func (r *Reconciler) reconcileComponents(ctx context.Context, installation *integreatlyv1alpha1.RHMI, serverClient k8sclient.Client, productConfig quota.ProductConfig) (integreatlyv1alpha1.StatusPhase, error) {
 	r.Log.Info("Reconciling Keycloak components")
 	kc := &keycloak.Keycloak{
@@ -334,6 +373,10 @@ func (r *Reconciler) reconcileComponents(ctx context.Context, installation *inte
 			kc.Spec.Migration.MigrationStrategy = keycloak.StrategyRolling
 		}
 
+		if err := r.syncAutoscaledInstances(ctx, serverClient, productConfig, kc); err != nil {
+			return err
+		}
+
 		err = productConfig.Configure(kc)
 		if err != nil {
 			return err
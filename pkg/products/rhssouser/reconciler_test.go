@@ -48,6 +48,7 @@ import (
 	coreosv1 "github.com/operator-framework/operator-lifecycle-manager/pkg/api/apis/operators/v1"
 	operatorsv1alpha1 "github.com/operator-framework/operator-lifecycle-manager/pkg/api/apis/operators/v1alpha1"
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
 
 	crov1 "github.com/integr8ly/cloud-resource-operator/apis/integreatly/v1alpha1"
 	moqclient "github.com/integr8ly/integreatly-operator/pkg/client"
@@ -134,6 +135,10 @@ func getBuildScheme() (*runtime.Scheme, error) {
 	if err != nil {
 		return nil, err
 	}
+	err = autoscalingv2beta2.AddToScheme(scheme)
+	if err != nil {
+		return nil, err
+	}
 	err = coreosv1.SchemeBuilder.AddToScheme(scheme)
 	if err != nil {
 		return nil, err
@@ -389,6 +394,9 @@ func TestReconciler_reconcileComponents(t *testing.T) {
 				ConfigureFunc: func(obj metav1.Object) error {
 					return nil
 				},
+				GetAutoscalingFunc: func(ddcssName string) *quota.AutoscalingConfig {
+					return nil
+				},
 			},
 		},
 		{
@@ -421,6 +429,9 @@ func TestReconciler_reconcileComponents(t *testing.T) {
 				ConfigureFunc: func(obj metav1.Object) error {
 					return nil
 				},
+				GetAutoscalingFunc: func(ddcssName string) *quota.AutoscalingConfig {
+					return nil
+				},
 			},
 		},
 	}
@@ -716,6 +727,9 @@ func TestReconciler_full_RHMI_Reconcile(t *testing.T) {
 				ConfigureFunc: func(obj metav1.Object) error {
 					return nil
 				},
+				GetAutoscalingFunc: func(ddcssName string) *quota.AutoscalingConfig {
+					return nil
+				},
 			},
 			Uninstall: false,
 		},
@@ -755,6 +769,9 @@ func TestReconciler_full_RHMI_Reconcile(t *testing.T) {
 				ConfigureFunc: func(obj metav1.Object) error {
 					return nil
 				},
+				GetAutoscalingFunc: func(ddcssName string) *quota.AutoscalingConfig {
+					return nil
+				},
 			},
 			Uninstall: false,
 		},
@@ -1054,6 +1071,9 @@ func TestReconciler_full_RHOAM_Reconcile(t *testing.T) {
 				ConfigureFunc: func(obj metav1.Object) error {
 					return nil
 				},
+				GetAutoscalingFunc: func(ddcssName string) *quota.AutoscalingConfig {
+					return nil
+				},
 			},
 			Uninstall: false,
 		},
@@ -1093,6 +1113,9 @@ func TestReconciler_full_RHOAM_Reconcile(t *testing.T) {
 				ConfigureFunc: func(obj metav1.Object) error {
 					return nil
 				},
+				GetAutoscalingFunc: func(ddcssName string) *quota.AutoscalingConfig {
+					return nil
+				},
 			},
 			Uninstall: false,
 		},
@@ -0,0 +1,37 @@
+package rhssouser
+
+import (
+	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/apis/v1alpha1"
+	"github.com/integr8ly/integreatly-operator/pkg/config"
+	"github.com/integr8ly/integreatly-operator/pkg/products"
+	"github.com/integr8ly/integreatly-operator/pkg/resources/quota"
+)
+
+func init() {
+	products.Register(products.Registration{
+		Name: integreatlyv1alpha1.ProductRHSSOUser,
+		InstallationTypes: []integreatlyv1alpha1.InstallationType{
+			integreatlyv1alpha1.InstallationTypeManagedApi,
+		},
+		Dependencies: []integreatlyv1alpha1.ProductName{
+			integreatlyv1alpha1.ProductCloudResources,
+			integreatlyv1alpha1.ProductObservability,
+		},
+		Version:         integreatlyv1alpha1.VersionRHSSOUser,
+		OperatorVersion: integreatlyv1alpha1.OperatorVersionRHSSOUser,
+		NewConfig: func(productConfig config.ProductConfig) config.ConfigReadable {
+			return config.NewRHSSOUser(productConfig)
+		},
+		QuotaComponents: []string{
+			quota.KeycloakName,
+		},
+		WatchableCRDs: config.NewRHSSOUser(config.ProductConfig{}).GetWatchableCRDs(),
+		NewReconciler: func(opts products.ReconcilerOptions) (products.Interface, error) {
+			oauthv1Client, err := opts.OauthClient()
+			if err != nil {
+				return nil, err
+			}
+			return NewReconciler(opts.ConfigManager, opts.Installation, oauthv1Client, opts.MPM, opts.Recorder, opts.RestConfig.Host, opts.KeycloakClientFactory(), opts.Log, opts.ProductDeclaration)
+		},
+	})
+}
@@ -0,0 +1,68 @@
+package threescale
+
+import (
+	"context"
+	"fmt"
+
+	threescalev1 "github.com/3scale/3scale-operator/apis/apps/v1alpha1"
+	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/apis/v1alpha1"
+	"github.com/integr8ly/integreatly-operator/pkg/resources/quota"
+	appsv1 "github.com/openshift/api/apps/v1"
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// autoscaledDeploymentConfigs are the deployment configs of the components the quota can autoscale
+var autoscaledDeploymentConfigs = map[string]string{
+	quota.ApicastProductionName: apicastProductionDCName,
+	quota.BackendListenerName:   backendListenerDCName,
+	quota.BackendWorkerName:     backendWorkerDCName,
+}
+
+// reconcileAutoscalers creates the horizontal pod autoscalers of the deployment configs the quota
+// autoscales, and deletes the ones of the deployment configs with fixed replicas
+func (r *Reconciler) reconcileAutoscalers(ctx context.Context, serverClient k8sclient.Client, productConfig quota.ProductConfig) (integreatlyv1alpha1.StatusPhase, error) {
+	for name, dcName := range autoscaledDeploymentConfigs {
+		target := autoscalingv2beta2.CrossVersionObjectReference{
+			APIVersion: appsv1.GroupVersion.String(),
+			Kind:       "DeploymentConfig",
+			Name:       dcName,
+		}
+		if err := quota.ReconcileAutoscaler(ctx, serverClient, productConfig, name, r.Config.GetNamespace(), target); err != nil {
+			return integreatlyv1alpha1.PhaseFailed, err
+		}
+	}
+	return integreatlyv1alpha1.PhaseCompleted, nil
+}
+
+// syncAutoscaledReplicas sets the replicas of the autoscaled components in the api manager to the
+// replicas their deployment configs were scaled to, otherwise the 3scale operator would scale the
+// deployment configs back to the replicas in the api manager
+func (r *Reconciler) syncAutoscaledReplicas(ctx context.Context, serverClient k8sclient.Client, productConfig quota.ProductConfig, apim *threescalev1.APIManager) error {
+	if apim.Spec.Apicast == nil || apim.Spec.Apicast.ProductionSpec == nil || apim.Spec.Backend == nil ||
+		apim.Spec.Backend.ListenerSpec == nil || apim.Spec.Backend.WorkerSpec == nil {
+		return nil
+	}
+	replicas := map[string]*int64{
+		quota.ApicastProductionName: apim.Spec.Apicast.ProductionSpec.Replicas,
+		quota.BackendListenerName:   apim.Spec.Backend.ListenerSpec.Replicas,
+		quota.BackendWorkerName:     apim.Spec.Backend.WorkerSpec.Replicas,
+	}
+
+	for name, dcName := range autoscaledDeploymentConfigs {
+		if productConfig.GetAutoscaling(name) == nil || replicas[name] == nil {
+			continue
+		}
+		dc := &appsv1.DeploymentConfig{}
+		err := serverClient.Get(ctx, k8sclient.ObjectKey{Name: dcName, Namespace: r.Config.GetNamespace()}, dc)
+		if k8serr.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get deployment config %s: %w", dcName, err)
+		}
+		*replicas[name] = int64(dc.Spec.Replicas)
+	}
+	return nil
+}
@@ -1,6 +1,7 @@
 package threescale
 
 import (
+	"encoding/binary"
 	"fmt"
 	envoycorev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	envoylistenerv3 "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
@@ -13,8 +14,14 @@ import (
 	"github.com/golang/protobuf/ptypes/duration"
 	"github.com/golang/protobuf/ptypes/wrappers"
 	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/apis/v1alpha1"
+	marin3rconfig "github.com/integr8ly/integreatly-operator/pkg/products/marin3r/config"
 	"github.com/integr8ly/integreatly-operator/pkg/resources/ratelimit"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/anypb"
+	"net"
+	"regexp"
+	"sort"
+	"strings"
 )
 
 const (
@@ -37,6 +44,11 @@ const (
 	tenantHeaderName         = "tenant"
 	safeRegex                = ".*apicast.*"
 	multitenantDescriptorKey = "per-mt-limit"
+	// exemptionHeaderName is set by the exemption filter to the name of the exemption that matches
+	// the source address of a request, it is removed from every request first so it can't be sent
+	// by the client
+	exemptionHeaderName = "x-rhoam-ratelimit-exemption"
+	serviceIDQueryParam = "service_id"
 )
 
 /*
@@ -105,27 +117,58 @@ var multiTenantRatelimitDescriptor = envoyroutev3.RateLimit{
 
 /**
  httpFilters:
+	- &exemptionFilter
 	- &tsHTTPRateLimitFilter
+	- &exemptHTTPRateLimitFilter
 	- name: envoy.filters.http.router
 **/
-func getAPICastHTTPFilters() ([]*hcm.HttpFilter, error) {
-	/*
-		Defines http filters for the rate limit service
-		   httpFilters:
-		   - config:
-		       domain: apicast-ratelimit
-		       rate_limit_service:
-		         grpc_service:
-		           envoy_grpc:
-		             cluster_name: ratelimit
-		           timeout: 2s
-		       stage: 0
-		     name: envoy.envoy.filters.http.ratelimit
-	*/
+func getAPICastHTTPFilters(exemptions []marin3rconfig.RateLimitExemption) ([]*hcm.HttpFilter, error) {
+	httpFilters, err := getExemptionHTTPFilters(exemptions)
+	if err != nil {
+		return nil, err
+	}
+
+	tsHTTPRateLimitFilter, err := getRateLimitHTTPFilter(ratelimit.RateLimitDomain, 0)
+	if err != nil {
+		return nil, err
+	}
+	httpFilters = append(httpFilters, tsHTTPRateLimitFilter)
+
+	// the exempt requests are only sent with the descriptors of the second stage, so they are
+	// counted in their own domain instead of the domain of the rate limit
+	if len(exemptions) > 0 {
+		exemptHTTPRateLimitFilter, err := getRateLimitHTTPFilter(ratelimit.RateLimitExemptDomain, 1)
+		if err != nil {
+			return nil, err
+		}
+		httpFilters = append(httpFilters, exemptHTTPRateLimitFilter)
+	}
+
+	httpFilters = append(httpFilters, &hcm.HttpFilter{
+		Name: "envoy.filters.http.router",
+	})
+
+	return httpFilters, nil
+}
+
+/*
+	Defines http filters for the rate limit service
+	   httpFilters:
+	   - config:
+	       domain: apicast-ratelimit
+	       rate_limit_service:
+	         grpc_service:
+	           envoy_grpc:
+	             cluster_name: ratelimit
+	           timeout: 2s
+	       stage: 0
+	     name: envoy.envoy.filters.http.ratelimit
+*/
+func getRateLimitHTTPFilter(domain string, stage uint32) (*hcm.HttpFilter, error) {
 	serial, err := anypb.New(
 		&envoyratelimitv3.RateLimit{
-			Domain: ratelimit.RateLimitDomain,
-			Stage:  0,
+			Domain: domain,
+			Stage:  stage,
 			RateLimitService: &envoyratelimitconfigv3.RateLimitServiceConfig{
 				GrpcService: &envoycorev3.GrpcService{
 					TargetSpecifier: &envoycorev3.GrpcService_EnvoyGrpc_{
@@ -146,19 +189,78 @@ func getAPICastHTTPFilters() ([]*hcm.HttpFilter, error) {
 		return nil, fmt.Errorf("failed to convert rate limit filter for rate limiting")
 	}
 
-	var tsHTTPRateLimitFilter = hcm.HttpFilter{
+	return &hcm.HttpFilter{
 		Name:       "envoy.filters.http.ratelimit",
 		ConfigType: &hcm.HttpFilter_TypedConfig{TypedConfig: serial},
+	}, nil
+}
+
+/*
+	Sets the exemption header to the name of the first exemption with a source CIDR that contains
+	the address of the downstream connection, the ranges of the CIDRs are compared as numbers.
+	Requests through the OpenShift routes come from the address of the router, which adds the
+	X-Forwarded-For header, so they are never exempt by source CIDR
+	local exemptions = {{'blackbox-exporter', 176160768, 176422911}}
+	function envoy_on_request(request_handle)
+	...
+	end
+*/
+const exemptionLuaCode = `local exemptions = {%s}
+function envoy_on_request(request_handle)
+  local headers = request_handle:headers()
+  headers:remove('%[2]s')
+  if headers:get('x-forwarded-for') ~= nil then
+    return
+  end
+  local address = request_handle:streamInfo():downstreamDirectRemoteAddress()
+  local a, b, c, d = string.match(address or '', '^(%%d+)%%.(%%d+)%%.(%%d+)%%.(%%d+):')
+  if a == nil then
+    return
+  end
+  local ip = ((tonumber(a) * 256 + tonumber(b)) * 256 + tonumber(c)) * 256 + tonumber(d)
+  for _, exemption in ipairs(exemptions) do
+    if ip >= exemption[2] and ip <= exemption[3] then
+      headers:add('%[2]s', exemption[1])
+      return
+    end
+  end
+end`
+
+// getExemptionHTTPFilters returns the filter that marks the requests from the source CIDRs of the
+// exemptions, no filter is needed when the exemptions have no source CIDRs
+func getExemptionHTTPFilters(exemptions []marin3rconfig.RateLimitExemption) ([]*hcm.HttpFilter, error) {
+	var ranges []string
+	for _, exemption := range exemptions {
+		for _, cidr := range exemption.SourceCIDRs {
+			_, ipNet, err := net.ParseCIDR(cidr)
+			if err != nil || ipNet.IP.To4() == nil {
+				return nil, fmt.Errorf("invalid source CIDR %q of exemption %s", cidr, exemption.Name)
+			}
+			ones, _ := ipNet.Mask.Size()
+			first := uint64(binary.BigEndian.Uint32(ipNet.IP.To4()))
+			last := first + (uint64(1) << (32 - ones)) - 1
+			ranges = append(ranges, fmt.Sprintf("{'%s', %d, %d}", exemption.Name, first, last))
+		}
+	}
+	if len(ranges) == 0 {
+		return []*hcm.HttpFilter{}, nil
 	}
 
-	httpFilters := []*hcm.HttpFilter{
-		&tsHTTPRateLimitFilter,
-		{
-			Name: "envoy.filters.http.router",
-		},
+	pbst, err := anypb.New(&lua.Lua{
+		InlineCode: fmt.Sprintf(exemptionLuaCode, strings.Join(ranges, ", "), exemptionHeaderName),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert exemption filter for rate limiting: %v", err)
 	}
 
-	return httpFilters, nil
+	return []*hcm.HttpFilter{
+		{
+			Name: "envoy.filters.http.lua",
+			ConfigType: &hcm.HttpFilter_TypedConfig{
+				TypedConfig: pbst,
+			},
+		},
+	}, nil
 }
 
 /*
@@ -176,7 +278,7 @@ func getAPICastHTTPFilters() ([]*hcm.HttpFilter, error) {
 	return result;
 	end
 */
-func getMultitenantAPICastHTTPFilters() ([]*hcm.HttpFilter, error) {
+func getMultitenantAPICastHTTPFilters(exemptions []marin3rconfig.RateLimitExemption) ([]*hcm.HttpFilter, error) {
 
 	luaFunctionToAddTSHeaders := "function envoy_on_request(request_handle) host = request_handle:headers():get('Host') local headers = request_handle:headers() split_string = Split(host, '-apicast') headers:add('tenant', split_string[1]) end function Split(s, delimiter) result = {}; for match in (s..delimiter):gmatch('(.-)'..delimiter) do table.insert(result, match); end return result; end"
 
@@ -197,7 +299,7 @@ func getMultitenantAPICastHTTPFilters() ([]*hcm.HttpFilter, error) {
 		},
 	}
 
-	filters, err := getAPICastHTTPFilters()
+	filters, err := getAPICastHTTPFilters(exemptions)
 	if err != nil {
 		return nil, err
 	}
@@ -230,7 +332,7 @@ func getMultitenantAPICastHTTPFilters() ([]*hcm.HttpFilter, error) {
 	  name: envoy.filters.http.ratelimit
 	- name: envoy.filters.http.router
 **/
-func getBackendListenerHTTPFilters() ([]*hcm.HttpFilter, error) {
+func getBackendListenerHTTPFilters(exemptions []marin3rconfig.RateLimitExemption) ([]*hcm.HttpFilter, error) {
 
 	// function envoy_on_response(response_handle)
 	// 	rate_limit = response_handle:headers():get("x-envoy-ratelimited")
@@ -256,7 +358,7 @@ func getBackendListenerHTTPFilters() ([]*hcm.HttpFilter, error) {
 			},
 		},
 	}
-	filters, err := getAPICastHTTPFilters()
+	filters, err := getAPICastHTTPFilters(exemptions)
 	if err != nil {
 		return nil, err
 	}
@@ -271,6 +373,25 @@ virtualHosts:
 	- '*'
 	name: apicast-ratelimit
 	routes:
+	- match:
+		prefix: /oauth/token
+		headers:
+		- name: :method
+		  safe_regex_match:
+		    google_re2: {}
+		    regex: "^POST$"
+		route:
+		cluster: apicast-ratelimit
+		rateLimits:
+		- actions:
+			- genericKey:
+				descriptorValue: slowpath
+			stage: 0
+		- actions:
+			- genericKey:
+				descriptorKey: route
+				descriptorValue: oauth-token
+			stage: 0
 	- match:
 		prefix: /
 		route:
@@ -281,44 +402,272 @@ virtualHosts:
 				descriptorValue: slowpath
 			stage: 0
 */
-func getAPICastVirtualHosts(installation *integreatlyv1alpha1.RHMI, clusterName string) []*envoyroutev3.VirtualHost {
+func getAPICastVirtualHosts(installation *integreatlyv1alpha1.RHMI, clusterName string, overriddenTenants []string, routeLimits []marin3rconfig.RouteRateLimitConfig, exemptions []marin3rconfig.RateLimitExemption) []*envoyroutev3.VirtualHost {
+	rateLimits := getRateLimitsPerInstallType(installation, overriddenTenants)
+
+	// envoy sends a request through the first route it matches, so the exemptions go before the
+	// route limits and the route limits before the route for every request. APIcast requests don't
+	// have the ID of the service, so the service IDs of the exemptions are not matched
+	routes := getExemptionRoutes(clusterName, exemptions, false)
+	for _, routeLimit := range routeLimits {
+		routes = append(routes, getRouteLimitRoute(installation, clusterName, routeLimit, rateLimits))
+	}
+	routes = append(routes, &envoyroutev3.Route{
+		Match: &envoyroutev3.RouteMatch{
+			PathSpecifier: &envoyroutev3.RouteMatch_Prefix{
+				Prefix: "/",
+			},
+		},
+		Action: &envoyroutev3.Route_Route{
+			Route: &envoyroutev3.RouteAction{
+				ClusterSpecifier: &envoyroutev3.RouteAction_Cluster{
+					Cluster: clusterName,
+				},
+				RateLimits: rateLimits,
+			},
+		},
+	})
+
 	virtualHost := envoyroutev3.VirtualHost{
 		Name:    clusterName,
 		Domains: []string{"*"},
+		Routes:  routes,
+	}
+	return []*envoyroutev3.VirtualHost{&virtualHost}
+}
+
+// getRouteLimitRoute returns the route for the requests that match the route limit, they are sent
+// with the rate limits of every request and the route descriptor. In multitenant installations the
+// route descriptor has the tenant so the route limit is counted per tenant
+func getRouteLimitRoute(installation *integreatlyv1alpha1.RHMI, clusterName string, routeLimit marin3rconfig.RouteRateLimitConfig, rateLimits []*envoyroutev3.RateLimit) *envoyroutev3.Route {
+	var headers []*envoyroutev3.HeaderMatcher
+	if routeLimit.Method != "" {
+		headers = append(headers, getExactHeaderMatcher(":method", routeLimit.Method))
+	}
+	headerNames := make([]string, 0, len(routeLimit.Headers))
+	for name := range routeLimit.Headers {
+		headerNames = append(headerNames, name)
+	}
+	sort.Strings(headerNames)
+	for _, name := range headerNames {
+		headers = append(headers, getExactHeaderMatcher(name, routeLimit.Headers[name]))
+	}
+
+	routeDescriptor := &envoyroutev3.RateLimit{
+		Stage: &wrappers.UInt32Value{Value: 0},
+		Actions: []*envoyroutev3.RateLimit_Action{{
+			ActionSpecifier: &envoyroutev3.RateLimit_Action_GenericKey_{
+				GenericKey: &envoyroutev3.RateLimit_Action_GenericKey{
+					DescriptorKey:   ratelimit.RouteDescriptorKey,
+					DescriptorValue: routeLimit.Name,
+				},
+			},
+		}},
+	}
+	if integreatlyv1alpha1.IsRHOAMMultitenant(integreatlyv1alpha1.InstallationType(installation.Spec.Type)) {
+		routeDescriptor.Actions = append(routeDescriptor.Actions, &envoyroutev3.RateLimit_Action{
+			ActionSpecifier: &envoyroutev3.RateLimit_Action_RequestHeaders_{
+				RequestHeaders: &envoyroutev3.RateLimit_Action_RequestHeaders{
+					HeaderName:    tenantHeaderName,
+					DescriptorKey: tenantHeaderName,
+				},
+			},
+		})
+	}
+
+	return &envoyroutev3.Route{
+		Match: &envoyroutev3.RouteMatch{
+			PathSpecifier: &envoyroutev3.RouteMatch_Prefix{
+				Prefix: routeLimit.PathPrefix,
+			},
+			Headers: headers,
+		},
+		Action: &envoyroutev3.Route_Route{
+			Route: &envoyroutev3.RouteAction{
+				ClusterSpecifier: &envoyroutev3.RouteAction_Cluster{
+					Cluster: clusterName,
+				},
+				RateLimits: append(append([]*envoyroutev3.RateLimit{}, rateLimits...), routeDescriptor),
+			},
+		},
+	}
+}
 
-		Routes: []*envoyroutev3.Route{
-			{
-				Match: &envoyroutev3.RouteMatch{
-					PathSpecifier: &envoyroutev3.RouteMatch_Prefix{
-						Prefix: "/",
+/*
+	Exempt requests are sent with the exemption descriptor of the second stage only, so the rate
+	limit filter of the first stage doesn't call the rate limit service for them
+	- match:
+		prefix: /
+		headers:
+		- name: x-rhoam-ratelimit-exemption
+		  safe_regex_match:
+		    google_re2: {}
+		    regex: "^blackbox-exporter$"
+		- name: user-agent
+		  safe_regex_match:
+		    google_re2: {}
+		    regex: "^blackbox-exporter$"
+		route:
+		cluster: apicast-ratelimit
+		rateLimits:
+		- actions:
+			- genericKey:
+				descriptorValue: exempt
+			- genericKey:
+				descriptorKey: exemption
+				descriptorValue: blackbox-exporter
+			stage: 1
+*/
+func getExemptionRoutes(clusterName string, exemptions []marin3rconfig.RateLimitExemption, matchServiceIDs bool) []*envoyroutev3.Route {
+	routes := []*envoyroutev3.Route{}
+	for _, exemption := range exemptions {
+		var matches []*envoyroutev3.RouteMatch
+		if len(exemption.SourceCIDRs) > 0 {
+			// the header is only trusted from the source CIDRs, both have to match
+			headers := []*envoyroutev3.HeaderMatcher{getExactHeaderMatcher(exemptionHeaderName, exemption.Name)}
+			if exemption.Header != nil {
+				headers = append(headers, getExactHeaderMatcher(exemption.Header.Name, exemption.Header.Value))
+			}
+			matches = append(matches, &envoyroutev3.RouteMatch{Headers: headers})
+		}
+		if matchServiceIDs && len(exemption.ServiceIDs) > 0 {
+			quoted := make([]string, 0, len(exemption.ServiceIDs))
+			for _, serviceID := range exemption.ServiceIDs {
+				quoted = append(quoted, regexp.QuoteMeta(serviceID))
+			}
+			matches = append(matches, &envoyroutev3.RouteMatch{
+				QueryParameters: []*envoyroutev3.QueryParameterMatcher{{
+					Name: serviceIDQueryParam,
+					QueryParameterMatchSpecifier: &envoyroutev3.QueryParameterMatcher_StringMatch{
+						StringMatch: &matcher.StringMatcher{
+							MatchPattern: &matcher.StringMatcher_SafeRegex{
+								SafeRegex: &matcher.RegexMatcher{
+									EngineType: &matcher.RegexMatcher_GoogleRe2{},
+									Regex:      fmt.Sprintf("^(%s)$", strings.Join(quoted, "|")),
+								},
+							},
+						},
+					},
+				}},
+			})
+		}
+
+		exemptDescriptor := &envoyroutev3.RateLimit{
+			Stage: &wrappers.UInt32Value{Value: 1},
+			Actions: []*envoyroutev3.RateLimit_Action{
+				{
+					ActionSpecifier: &envoyroutev3.RateLimit_Action_GenericKey_{
+						GenericKey: &envoyroutev3.RateLimit_Action_GenericKey{
+							DescriptorValue: ratelimit.ExemptDescriptorValue,
+						},
 					},
 				},
+				{
+					ActionSpecifier: &envoyroutev3.RateLimit_Action_GenericKey_{
+						GenericKey: &envoyroutev3.RateLimit_Action_GenericKey{
+							DescriptorKey:   ratelimit.ExemptionDescriptorKey,
+							DescriptorValue: exemption.Name,
+						},
+					},
+				},
+			},
+		}
+		for _, match := range matches {
+			match.PathSpecifier = &envoyroutev3.RouteMatch_Prefix{Prefix: "/"}
+			routes = append(routes, &envoyroutev3.Route{
+				Match: match,
 				Action: &envoyroutev3.Route_Route{
 					Route: &envoyroutev3.RouteAction{
 						ClusterSpecifier: &envoyroutev3.RouteAction_Cluster{
 							Cluster: clusterName,
 						},
-						RateLimits: getRateLimitsPerInstallType(installation),
+						RateLimits: []*envoyroutev3.RateLimit{exemptDescriptor},
 					},
 				},
+			})
+		}
+	}
+
+	return routes
+}
+
+func getExactHeaderMatcher(name, value string) *envoyroutev3.HeaderMatcher {
+	return &envoyroutev3.HeaderMatcher{
+		Name: name,
+		HeaderMatchSpecifier: &envoyroutev3.HeaderMatcher_SafeRegexMatch{
+			SafeRegexMatch: &matcher.RegexMatcher{
+				EngineType: &matcher.RegexMatcher_GoogleRe2{},
+				Regex:      fmt.Sprintf("^%s$", regexp.QuoteMeta(value)),
 			},
 		},
 	}
-	return []*envoyroutev3.VirtualHost{&virtualHost}
 }
 
-func getRateLimitsPerInstallType(installation *integreatlyv1alpha1.RHMI) []*envoyroutev3.RateLimit {
+func getRateLimitsPerInstallType(installation *integreatlyv1alpha1.RHMI, overriddenTenants []string) []*envoyroutev3.RateLimit {
 	var routes []*envoyroutev3.RateLimit
 
 	if !integreatlyv1alpha1.IsRHOAMMultitenant(integreatlyv1alpha1.InstallationType(installation.Spec.Type)) {
 		routes = []*envoyroutev3.RateLimit{&tsRatelimitDescriptor}
 	} else {
-		routes = []*envoyroutev3.RateLimit{&tsRatelimitDescriptor, &multiTenantRatelimitDescriptor}
+		routes = append([]*envoyroutev3.RateLimit{&tsRatelimitDescriptor}, getMultitenantRatelimitDescriptors(overriddenTenants)...)
 	}
 
 	return routes
 }
 
+/*
+	Tenants that override their limit are left out of the per tenant descriptor and are sent with
+	their own descriptor value instead, limitador applies every limit that matches a request so the
+	limit per tenant would otherwise still apply to them
+        - actions:
+            - header_value_match:
+                descriptor_value: per-tenant-limit
+                headers:
+                - name: host
+                  safe_regex_match:
+                    google_re2: {}
+                    regex: ".*apicast.*"
+                - name: tenant
+                  safe_regex_match:
+                    google_re2: {}
+                    regex: "^(tenant-a|tenant-b)$"
+            - request_headers:
+                header_name: tenant
+                descriptor_key: tenant
+*/
+func getMultitenantRatelimitDescriptors(overriddenTenants []string) []*envoyroutev3.RateLimit {
+	if len(overriddenTenants) == 0 {
+		return []*envoyroutev3.RateLimit{&multiTenantRatelimitDescriptor}
+	}
+
+	quoted := make([]string, 0, len(overriddenTenants))
+	for _, tenant := range overriddenTenants {
+		quoted = append(quoted, regexp.QuoteMeta(tenant))
+	}
+	overriddenTenantsMatcher := &envoyroutev3.HeaderMatcher{
+		Name: tenantHeaderName,
+		HeaderMatchSpecifier: &envoyroutev3.HeaderMatcher_SafeRegexMatch{
+			SafeRegexMatch: &matcher.RegexMatcher{
+				EngineType: &matcher.RegexMatcher_GoogleRe2{},
+				Regex:      fmt.Sprintf("^(%s)$", strings.Join(quoted, "|")),
+			},
+		},
+	}
+
+	perTenantDescriptor := proto.Clone(&multiTenantRatelimitDescriptor).(*envoyroutev3.RateLimit)
+	notOverridden := proto.Clone(overriddenTenantsMatcher).(*envoyroutev3.HeaderMatcher)
+	notOverridden.InvertMatch = true
+	perTenantMatch := perTenantDescriptor.Actions[0].GetHeaderValueMatch()
+	perTenantMatch.Headers = append(perTenantMatch.Headers, notOverridden)
+
+	overrideDescriptor := proto.Clone(&multiTenantRatelimitDescriptor).(*envoyroutev3.RateLimit)
+	overrideMatch := overrideDescriptor.Actions[0].GetHeaderValueMatch()
+	overrideMatch.DescriptorValue = ratelimit.TenantOverrideDescriptorValue
+	overrideMatch.Headers = append(overrideMatch.Headers, overriddenTenantsMatcher)
+
+	return []*envoyroutev3.RateLimit{perTenantDescriptor, overrideDescriptor}
+}
+
 /**
 virtual_hosts:
 	- name: backend-listener-ratelimit
@@ -330,29 +679,29 @@ virtual_hosts:
 			cluster: backend-listener-ratelimit
 			rate_limits:
 **/
-func getBackendListenerVitualHosts(clusterName string) []*envoyroutev3.VirtualHost {
+func getBackendListenerVitualHosts(clusterName string, exemptions []marin3rconfig.RateLimitExemption) []*envoyroutev3.VirtualHost {
+	routes := append(getExemptionRoutes(clusterName, exemptions, true), &envoyroutev3.Route{
+		Match: &envoyroutev3.RouteMatch{
+			PathSpecifier: &envoyroutev3.RouteMatch_Prefix{
+				Prefix: "/",
+			},
+		},
+		Action: &envoyroutev3.Route_Route{
+			Route: &envoyroutev3.RouteAction{
+				ClusterSpecifier: &envoyroutev3.RouteAction_Cluster{
+					Cluster: clusterName,
+				},
+				RateLimits: []*envoyroutev3.RateLimit{&tsRatelimitDescriptor},
+			},
+		},
+	})
+
 	virtualHosts := []*envoyroutev3.VirtualHost{
 		{
 			Name:    clusterName,
 			Domains: []string{"*"},
 
-			Routes: []*envoyroutev3.Route{
-				{
-					Match: &envoyroutev3.RouteMatch{
-						PathSpecifier: &envoyroutev3.RouteMatch_Prefix{
-							Prefix: "/",
-						},
-					},
-					Action: &envoyroutev3.Route_Route{
-						Route: &envoyroutev3.RouteAction{
-							ClusterSpecifier: &envoyroutev3.RouteAction_Cluster{
-								Cluster: clusterName,
-							},
-							RateLimits: []*envoyroutev3.RateLimit{&tsRatelimitDescriptor},
-						},
-					},
-				},
-			},
+			Routes: routes,
 		},
 	}
 
@@ -0,0 +1,221 @@
+package threescale
+
+import (
+	"strings"
+	"testing"
+
+	envoyroutev3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	lua "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/lua/v3"
+	envoyratelimitv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/ratelimit/v3"
+	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/apis/v1alpha1"
+	marin3rconfig "github.com/integr8ly/integreatly-operator/pkg/products/marin3r/config"
+	"github.com/integr8ly/integreatly-operator/pkg/resources/ratelimit"
+)
+
+func TestGetAPICastVirtualHosts(t *testing.T) {
+	routeLimits := []marin3rconfig.RouteRateLimitConfig{
+		{Name: "oauth-token", PathPrefix: "/oauth/token", Method: "POST", Unit: "minute", RequestsPerUnit: 10},
+		{Name: "batch", PathPrefix: "/api", Headers: map[string]string{"x-client": "batch.v1"}, Unit: "minute", RequestsPerUnit: 100},
+	}
+
+	tests := []struct {
+		name             string
+		installationType integreatlyv1alpha1.InstallationType
+		routeLimits      []marin3rconfig.RouteRateLimitConfig
+		wantRoutes       int
+		wantTenantAction bool
+	}{
+		{
+			name:             "test single route without route limits",
+			installationType: integreatlyv1alpha1.InstallationTypeManagedApi,
+			wantRoutes:       1,
+		},
+		{
+			name:             "test a route for each route limit before the route for every request",
+			installationType: integreatlyv1alpha1.InstallationTypeManagedApi,
+			routeLimits:      routeLimits,
+			wantRoutes:       3,
+		},
+		{
+			name:             "test route limits are counted per tenant in multitenant installations",
+			installationType: integreatlyv1alpha1.InstallationTypeMultitenantManagedApi,
+			routeLimits:      routeLimits,
+			wantRoutes:       3,
+			wantTenantAction: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			installation := &integreatlyv1alpha1.RHMI{Spec: integreatlyv1alpha1.RHMISpec{Type: string(tt.installationType)}}
+			virtualHosts := getAPICastVirtualHosts(installation, ApicastClusterName, nil, tt.routeLimits, nil)
+			if len(virtualHosts) != 1 {
+				t.Fatalf("expected 1 virtual host but got %d", len(virtualHosts))
+			}
+
+			routes := virtualHosts[0].Routes
+			if len(routes) != tt.wantRoutes {
+				t.Fatalf("expected %d routes but got %d", tt.wantRoutes, len(routes))
+			}
+			defaultRoute := routes[len(routes)-1]
+			if defaultRoute.Match.GetPrefix() != "/" || len(defaultRoute.Match.Headers) != 0 {
+				t.Errorf("expected the last route to match every request but got %v", defaultRoute.Match)
+			}
+			defaultRateLimits := defaultRoute.GetRoute().RateLimits
+
+			for i, routeLimit := range tt.routeLimits {
+				route := routes[i]
+				if route.Match.GetPrefix() != routeLimit.PathPrefix {
+					t.Errorf("expected prefix %s but got %s", routeLimit.PathPrefix, route.Match.GetPrefix())
+				}
+				wantHeaders := len(routeLimit.Headers)
+				if routeLimit.Method != "" {
+					wantHeaders++
+					if method := route.Match.Headers[0]; method.Name != ":method" || method.GetSafeRegexMatch().Regex != "^"+routeLimit.Method+"$" {
+						t.Errorf("expected the route to match method %s but got %v", routeLimit.Method, method)
+					}
+				}
+				if len(route.Match.Headers) != wantHeaders {
+					t.Errorf("expected %d header matchers but got %d", wantHeaders, len(route.Match.Headers))
+				}
+				if routeLimit.Name == "batch" && route.Match.Headers[0].GetSafeRegexMatch().Regex != `^batch\.v1$` {
+					t.Errorf("expected the header value to be matched exactly but got %s", route.Match.Headers[0].GetSafeRegexMatch().Regex)
+				}
+
+				rateLimits := route.GetRoute().RateLimits
+				if len(rateLimits) != len(defaultRateLimits)+1 {
+					t.Fatalf("expected the rate limits of every request and the route descriptor but got %d rate limits", len(rateLimits))
+				}
+				routeDescriptor := rateLimits[len(rateLimits)-1]
+				genericKey := routeDescriptor.Actions[0].GetGenericKey()
+				if genericKey.DescriptorKey != ratelimit.RouteDescriptorKey || genericKey.DescriptorValue != routeLimit.Name {
+					t.Errorf("expected route descriptor %s but got %v", routeLimit.Name, genericKey)
+				}
+				if hasTenantAction(routeDescriptor) != tt.wantTenantAction {
+					t.Errorf("expected tenant action %v in the route descriptor", tt.wantTenantAction)
+				}
+			}
+		})
+	}
+}
+
+func TestGetExemptionRoutes(t *testing.T) {
+	exemptions := []marin3rconfig.RateLimitExemption{
+		{Name: "blackbox-exporter", SourceCIDRs: []string{"10.0.12.7/32"}, Header: &marin3rconfig.RateLimitExemptionHeader{Name: "user-agent", Value: "blackbox.v1"}},
+		{Name: "internal", ServiceIDs: []string{"2", "3"}},
+	}
+
+	installation := &integreatlyv1alpha1.RHMI{Spec: integreatlyv1alpha1.RHMISpec{Type: string(integreatlyv1alpha1.InstallationTypeManagedApi)}}
+	apicastRoutes := getAPICastVirtualHosts(installation, ApicastClusterName, nil, nil, exemptions)[0].Routes
+	if len(apicastRoutes) != 2 {
+		t.Fatalf("expected a route for the source CIDRs and the header before the route for every request but got %d routes", len(apicastRoutes))
+	}
+	headers := apicastRoutes[0].Match.Headers
+	if len(headers) != 2 {
+		t.Fatalf("expected the route to match the source CIDRs and the header together but got %v", headers)
+	}
+	if regex := headers[0].GetSafeRegexMatch().Regex; headers[0].Name != exemptionHeaderName || regex != "^blackbox-exporter$" {
+		t.Errorf("expected the first route to match the exemption header but got %v", headers[0])
+	}
+	if regex := headers[1].GetSafeRegexMatch().Regex; headers[1].Name != "user-agent" || regex != `^blackbox\.v1$` {
+		t.Errorf("expected the first route to match the header value exactly but got %v", headers[1])
+	}
+
+	backendRoutes := getBackendListenerVitualHosts(BackendClusterName, exemptions)[0].Routes
+	if len(backendRoutes) != 3 {
+		t.Fatalf("expected a route for the service IDs in backend-listener but got %d routes", len(backendRoutes))
+	}
+	serviceIDs := backendRoutes[1].Match.QueryParameters[0]
+	if serviceIDs.Name != serviceIDQueryParam || serviceIDs.GetStringMatch().GetSafeRegex().Regex != "^(2|3)$" {
+		t.Errorf("expected the second route to match the service IDs but got %v", serviceIDs)
+	}
+
+	for _, route := range backendRoutes[:2] {
+		if route.Match.GetPrefix() != "/" {
+			t.Errorf("expected exempt routes to match every path but got %s", route.Match.GetPrefix())
+		}
+		rateLimits := route.GetRoute().RateLimits
+		if len(rateLimits) != 1 || rateLimits[0].Stage.GetValue() != 1 {
+			t.Fatalf("expected only the exemption descriptor of the second stage but got %v", rateLimits)
+		}
+		if exemption := rateLimits[0].Actions[1].GetGenericKey(); exemption.DescriptorKey != ratelimit.ExemptionDescriptorKey {
+			t.Errorf("expected the exemption descriptor but got %v", exemption)
+		}
+	}
+}
+
+func TestGetAPICastHTTPFilters(t *testing.T) {
+	tests := []struct {
+		name            string
+		exemptions      []marin3rconfig.RateLimitExemption
+		wantFilters     int
+		wantLuaRange    string
+		wantExemptStage bool
+	}{
+		{
+			name:        "test rate limit and router filters without exemptions",
+			wantFilters: 2,
+		},
+		{
+			name:            "test exemptions are counted by a rate limit filter of the second stage",
+			exemptions:      []marin3rconfig.RateLimitExemption{{Name: "internal", ServiceIDs: []string{"3"}}},
+			wantFilters:     3,
+			wantExemptStage: true,
+		},
+		{
+			name:            "test source CIDRs are matched by the exemption filter",
+			exemptions:      []marin3rconfig.RateLimitExemption{{Name: "blackbox-exporter", SourceCIDRs: []string{"10.128.0.0/14"}}},
+			wantFilters:     4,
+			wantLuaRange:    "{'blackbox-exporter', 176160768, 176422911}",
+			wantExemptStage: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filters, err := getAPICastHTTPFilters(tt.exemptions)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(filters) != tt.wantFilters {
+				t.Fatalf("expected %d filters but got %d", tt.wantFilters, len(filters))
+			}
+			if filters[len(filters)-1].Name != "envoy.filters.http.router" {
+				t.Errorf("expected the router to be the last filter but got %s", filters[len(filters)-1].Name)
+			}
+
+			if tt.wantLuaRange != "" {
+				luaFilter := &lua.Lua{}
+				if err := filters[0].GetTypedConfig().UnmarshalTo(luaFilter); err != nil {
+					t.Fatal(err)
+				}
+				if !strings.Contains(luaFilter.InlineCode, tt.wantLuaRange) {
+					t.Errorf("expected the exemption filter to contain %s but got %s", tt.wantLuaRange, luaFilter.InlineCode)
+				}
+				// routed requests come from the address of the router, they return before the address
+				// is matched against the source CIDRs
+				routed := strings.Index(luaFilter.InlineCode, "if headers:get('x-forwarded-for') ~= nil then\n    return\n  end")
+				if routed == -1 || routed > strings.Index(luaFilter.InlineCode, "downstreamDirectRemoteAddress()") {
+					t.Errorf("expected routed requests not to be exempt by source CIDR but got %s", luaFilter.InlineCode)
+				}
+			}
+
+			if tt.wantExemptStage {
+				exemptFilter := &envoyratelimitv3.RateLimit{}
+				if err := filters[len(filters)-2].GetTypedConfig().UnmarshalTo(exemptFilter); err != nil {
+					t.Fatal(err)
+				}
+				if exemptFilter.Domain != ratelimit.RateLimitExemptDomain || exemptFilter.Stage != 1 {
+					t.Errorf("expected the exempt domain in stage 1 but got %s in stage %d", exemptFilter.Domain, exemptFilter.Stage)
+				}
+			}
+		})
+	}
+}
+
+func hasTenantAction(rateLimit *envoyroutev3.RateLimit) bool {
+	for _, action := range rateLimit.Actions {
+		if action.GetRequestHeaders().GetDescriptorKey() == tenantHeaderName {
+			return true
+		}
+	}
+	return false
+}
@@ -17,11 +17,12 @@ import (
 	envoycorev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	hcm "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
 	"github.com/integr8ly/integreatly-operator/pkg/addon"
+	"github.com/integr8ly/integreatly-operator/pkg/products"
+	marin3rconfig "github.com/integr8ly/integreatly-operator/pkg/products/marin3r/config"
 	"github.com/integr8ly/integreatly-operator/pkg/products/observability"
 	customDomain "github.com/integr8ly/integreatly-operator/pkg/resources/custom-domain"
 	cs "github.com/integr8ly/integreatly-operator/pkg/resources/custom-smtp"
 	"github.com/integr8ly/integreatly-operator/pkg/resources/quota"
-	"github.com/integr8ly/integreatly-operator/version"
 	prometheus "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 
 	"github.com/integr8ly/integreatly-operator/pkg/metrics"
@@ -84,6 +85,7 @@ const (
 	apicastStagingDCName           = "apicast-staging"
 	apicastProductionDCName        = "apicast-production"
 	backendListenerDCName          = "backend-listener"
+	backendWorkerDCName            = "backend-worker"
 	systemSeedSecretName           = "system-seed"
 	systemMasterApiCastSecretName  = "system-master-apicast"
 	systemAppDCName                = "system-app"
@@ -177,11 +179,7 @@ func (r *Reconciler) GetPreflightObject(ns string) runtime.Object {
 }
 
 func (r *Reconciler) VerifyVersion(installation *integreatlyv1alpha1.RHMI) bool {
-	return version.VerifyProductAndOperatorVersion(
-		installation.Status.Stages[integreatlyv1alpha1.InstallStage].Products[integreatlyv1alpha1.Product3Scale],
-		string(integreatlyv1alpha1.Version3Scale),
-		string(integreatlyv1alpha1.OperatorVersion3Scale),
-	)
+	return products.VerifyVersion(installation, integreatlyv1alpha1.InstallStage, integreatlyv1alpha1.Product3Scale)
 }
 
 func (r *Reconciler) Reconcile(ctx context.Context, installation *integreatlyv1alpha1.RHMI, productStatus *integreatlyv1alpha1.RHMIProductStatus, serverClient k8sclient.Client, productConfig quota.ProductConfig, uninstall bool) (integreatlyv1alpha1.StatusPhase, error) {
@@ -312,6 +310,12 @@ func (r *Reconciler) Reconcile(ctx context.Context, installation *integreatlyv1a
 		return phase, err
 	}
 
+	phase, err = r.reconcileAutoscalers(ctx, serverClient, productConfig)
+	if err != nil || phase != integreatlyv1alpha1.PhaseCompleted {
+		events.HandleError(r.recorder, installation, phase, "Failed to reconcile autoscalers", err)
+		return phase, err
+	}
+
 	ingressRouterService, err := customDomain.GetIngressRouterService(ctx, serverClient)
 	if err != nil || len(ingressRouterService.Status.LoadBalancer.Ingress) == 0 {
 		errorMessage := "failed to retrieve ingress router service"
@@ -338,14 +342,6 @@ func (r *Reconciler) Reconcile(ctx context.Context, installation *integreatlyv1a
 		return phase, err
 	}
 
-	if integreatlyv1alpha1.IsRHOAMMultitenant(integreatlyv1alpha1.InstallationType(installation.Spec.Type)) {
-		phase, err = r.reconcile3scaleMultiTenancy(ctx, serverClient)
-		if err != nil {
-			r.log.Error("reconcile3scaleMultiTenancy", err)
-			return phase, err
-		}
-	}
-
 	r.log.Info("Successfully deployed")
 
 	phase, err = r.reconcileOutgoingEmailAddress(ctx, serverClient)
@@ -436,7 +432,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, installation *integreatlyv1a
 		return phase, err
 	}
 
-	phase, err = r.reconcileRatelimitingTo3scaleComponents(ctx, serverClient, r.installation)
+	phase, err = r.reconcileRatelimitingTo3scaleComponents(ctx, serverClient, r.installation, productConfig.GetRateLimitConfig())
 	if err != nil || phase != integreatlyv1alpha1.PhaseCompleted {
 		events.HandleError(r.recorder, installation, phase, "Failed to reconcile rate limiting to 3scale components", err)
 		return phase, err
@@ -770,6 +766,10 @@ func (r *Reconciler) reconcileComponents(ctx context.Context, serverClient k8scl
 			"threescale_component_element": "zync-que",
 		})
 
+		if err := r.syncAutoscaledReplicas(ctx, serverClient, productConfig, apim); err != nil {
+			return err
+		}
+
 		err = productConfig.Configure(apim)
 
 		if err != nil {
@@ -1464,359 +1464,6 @@ func (r *Reconciler) updateKeycloakUsersAttributeWith3ScaleUserId(ctx context.Co
 	return integreatlyv1alpha1.PhaseCompleted, nil
 }
 
-func (r *Reconciler) reconcile3scaleMultiTenancy(ctx context.Context, serverClient k8sclient.Client) (integreatlyv1alpha1.StatusPhase, error) {
-	mtUserIdentities, err := userHelper.GetMultiTenantUsers(ctx, serverClient, r.installation)
-	if err != nil {
-		return integreatlyv1alpha1.PhaseFailed, err
-	}
-
-	totalIdentities := len(mtUserIdentities)
-	r.log.Infof("Found user identities from MT accounts",
-		l.Fields{"totalIdentities": totalIdentities},
-	)
-
-	// get 3scale master access token
-	accessToken, err := r.GetMasterToken(ctx, serverClient)
-	if err != nil {
-		return integreatlyv1alpha1.PhaseFailed, err
-	}
-
-	defaultPageSize := 500
-	totalPages := 1
-	if totalIdentities > defaultPageSize {
-		totalPages = totalIdentities / defaultPageSize
-	}
-
-	// adding an extra page in case of accounts set to be deleted
-	totalPages++
-
-	allAccounts := []AccountDetail{}
-	for page := 1; page <= totalPages; page++ {
-		// list 3scale tenant accounts
-
-		r.log.Infof("Retrieving list of MT accounts available ",
-			l.Fields{"Page": page},
-		)
-		accounts, err := r.tsClient.ListTenantAccounts(*accessToken, page)
-		if err != nil {
-			r.log.Error("Failed to get accounts from 3scale API:", err)
-			return integreatlyv1alpha1.PhaseFailed, err
-		}
-		allAccounts = append(allAccounts, accounts...)
-	}
-
-	r.log.Infof("Total of accounts available",
-		l.Fields{
-			"totalPages":                totalPages,
-			"totalOpenshiftUsers":       totalIdentities,
-			"total3scaleTenantAccounts": len(allAccounts),
-		},
-	)
-
-	setTenantMetrics(mtUserIdentities, allAccounts)
-
-	r.log.Info("getAccessTokenSecret")
-	signUpAccountsSecret, err := getAccessTokenSecret(ctx, serverClient, r.Config.GetNamespace())
-	if err != nil {
-		return integreatlyv1alpha1.PhaseFailed, err
-	}
-	r.log.Info("getAccessTokenSecret length: " + strconv.Itoa(len(signUpAccountsSecret.Data)))
-
-	tenantsCreated, err := getAccountsCreatedCM(ctx, serverClient, r.Config.GetNamespace())
-	if err != nil {
-		return integreatlyv1alpha1.PhaseFailed, err
-	}
-
-	// looping through the accounts to reconcile default config back
-	for index, account := range allAccounts {
-
-		state, created := tenantsCreated.Data[account.OrgName]
-		if created && state == "true" {
-			continue
-		}
-
-		if account.State == "approved" {
-			breakout := false
-			for _, user := range account.Users.User {
-				if user.State == "pending" {
-					r.log.Infof("Activating user access to new tenant account",
-						l.Fields{
-							"userName":           user.Username,
-							"tenantAccountName":  account.OrgName,
-							"tenantAccountState": account.State,
-						},
-					)
-
-					err = r.tsClient.ActivateUser(*accessToken, account.Id, user.Id)
-					if err != nil {
-						r.log.Errorf("Error activating user access to new tenant account",
-							l.Fields{
-								"userName":          user.Username,
-								"tenantAccountName": account.OrgName,
-							},
-							err,
-						)
-						breakout = true
-					}
-				}
-			}
-			if breakout {
-				continue
-			}
-
-			val, ok := signUpAccountsSecret.Data[string(account.OrgName)]
-			if !ok || string(val) == "" {
-				r.log.Infof("Tenant account does not have access token created",
-					l.Fields{
-						"tenantAccountId":    account.Id,
-						"tenantAccountName":  account.Name,
-						"tenantAccountState": account.State,
-					},
-				)
-				//TODO: delete account?
-				continue
-			}
-
-			signUpAccount := SignUpAccount{
-				AccountDetail: account,
-				AccountAccessToken: AccountAccessToken{
-					Value: string(val),
-				},
-			}
-
-			r.log.Infof("Adding authentication provider to tenant account",
-				l.Fields{
-					"tenantAccountId":    account.Id,
-					"tenantAccountName":  account.Name,
-					"tenantAccountState": account.State,
-				},
-			)
-
-			// verify if the account have the auth provider already
-			err = r.addAuthProviderToMTAccount(ctx, serverClient, signUpAccount)
-			if err != nil {
-				r.log.Errorf("Error adding authentication provider to tenant account",
-					l.Fields{
-						"tenantAccountId":    account.Id,
-						"tenantAccountName":  account.Name,
-						"tenantAccountState": account.State,
-					},
-					err,
-				)
-				continue
-			}
-
-			// Get the account's corresponding KeycloakUser for later verification
-			kcUser, err := r.getKeycloakUserFromAccount(serverClient, account.OrgName)
-			if err != nil {
-				r.log.Errorf("Failed to get KeycloakUser for tenant account",
-					l.Fields{
-						"tenantAccountId":    account.Id,
-						"tenantAccountName":  account.Name,
-						"tenantAccountState": account.State,
-					},
-					err,
-				)
-				continue
-			}
-
-			// Get the account's corresponding KeycloakClient for later verification
-			kcClient, err := r.getKeycloakClientFromAccount(serverClient, account.OrgName)
-			if err != nil {
-				r.log.Errorf("Failed to get KeycloakClient for tenant account",
-					l.Fields{
-						"tenantAccountId":    account.Id,
-						"tenantAccountName":  account.Name,
-						"tenantAccountState": account.State,
-					},
-					err,
-				)
-				continue
-			}
-
-			err = r.reconcileDashboardLink(ctx, serverClient, account.OrgName, account.AdminBaseURL)
-			if err != nil {
-				r.log.Errorf("Error reconciling console link for the tenant account",
-					l.Fields{
-						"tenantAccountId":   account.Id,
-						"tenantAccountName": account.Name,
-					},
-					err,
-				)
-				continue
-			}
-
-			// Only add the ssoReady annotation if the tenant account's corresponding KeycloakUser and KeycloakClient CR's are ready.
-			// If not, continue to next account.
-			if kcUser.Status.Phase == keycloak.UserPhaseReconciled && kcClient.Status.Ready == true {
-				// Add ssoReady annotation to the user CR associated with the tenantAccount's OrgName
-				// This is required by the apimanagementtenant_controller so it can finish reconciling the APIManagementTenant CR
-				err = r.addSSOReadyAnnotationToUser(ctx, serverClient, account.OrgName)
-				if err != nil {
-					r.log.Errorf("Error adding ssoReady annotation for the user associated with the tenant account org",
-						l.Fields{
-							"tenantAccountOrgName": account.OrgName,
-						},
-						err,
-					)
-					continue
-				}
-			} else {
-				continue
-			}
-
-			if _, err := controllerutil.CreateOrUpdate(ctx, serverClient, tenantsCreated, func() error {
-				tenantsCreated.Data[account.OrgName] = "true"
-				tenantsCreated.ObjectMeta.ResourceVersion = ""
-				return nil
-			}); err != nil {
-				return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("error creating/updating tenant created CM: %w", err)
-			}
-		} else if account.State != "scheduled_for_deletion" {
-			r.log.Infof("Deleting broke account for recreation",
-				l.Fields{
-					"tenantAccountId":    account.Id,
-					"tenantAccountName":  account.Name,
-					"tenantAccountState": account.State,
-				},
-			)
-
-			err = r.tsClient.DeleteTenant(*accessToken, account.Id)
-			if err != nil {
-				r.log.Errorf("Error deleting broken account",
-					l.Fields{
-						"tenantAccountId":    account.Id,
-						"tenantAccountName":  account.Name,
-						"tenantAccountState": account.State,
-					},
-					err,
-				)
-			}
-
-			//remove account from the list of accounts so it can be recreated
-			r.log.Infof("Account removed to be recreated",
-				l.Fields{"tenantAccountRemoved": allAccounts[index]},
-			)
-			allAccounts = append(allAccounts[:index], allAccounts[index+1:]...)
-		}
-	}
-
-	r.log.Info("creating new MT accounts in 3scale")
-
-	// creating new MT accounts in 3scale
-	accountsToBeCreated, emailAddrs := getMTAccountsToBeCreated(mtUserIdentities, allAccounts)
-	r.log.Infof("Retrieving tenant accounts to be created",
-		l.Fields{
-			"accountsToBeCreated": accountsToBeCreated,
-			"totalAccounts":       len(accountsToBeCreated),
-		},
-	)
-
-	for idx, account := range accountsToBeCreated {
-
-		r.log.Info("Accounts to be created loop")
-
-		pw, err := r.getTenantAccountPassword(ctx, serverClient, account)
-		if err != nil {
-			r.log.Error("Failed to get account tenant password:", err)
-			return integreatlyv1alpha1.PhaseFailed, err
-		}
-
-		// create account
-		newSignupAccount, err := r.tsClient.CreateTenant(*accessToken, account, pw, emailAddrs[idx])
-		if err != nil {
-			r.log.Errorf("Error creating tenant account",
-				l.Fields{"tenantAccountName": account.OrgName},
-				err,
-			)
-
-			// Attempt a delete of Tenant to force re-entry !!!
-
-			return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("Error creating tenant account: %s, Error=[%v]", account.OrgName, err)
-		}
-
-		r.log.Infof("New tenant account created",
-			l.Fields{
-				"tenantAccountId":    newSignupAccount.AccountDetail.Id,
-				"tenantAccountName":  newSignupAccount.AccountDetail.OrgName,
-				"tenantAccountState": newSignupAccount.AccountDetail.State,
-			},
-		)
-
-		if _, err := controllerutil.CreateOrUpdate(ctx, serverClient, signUpAccountsSecret, func() error {
-			r.log.Info("Creating/updating signUpAccountsSecret " + signUpAccountsSecret.Name + " " + signUpAccountsSecret.Namespace)
-			signUpAccountsSecret.Data[account.OrgName] = []byte(newSignupAccount.AccountAccessToken.Value)
-			signUpAccountsSecret.ObjectMeta.ResourceVersion = ""
-			return nil
-		}); err != nil {
-			r.log.Error("Error creating access token secret ", err)
-			return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("error creating access token secret: %w", err)
-		}
-		r.log.Info("After signUpAccountsSecret " + signUpAccountsSecret.Name + " " + signUpAccountsSecret.Namespace)
-	}
-
-	// deleting MT accounts in 3scale
-	accountsToBeDeleted := getMTAccountsToBeDeleted(mtUserIdentities, allAccounts)
-	r.log.Infof(
-		"Deleting unused tenant accounts",
-		l.Fields{
-			"accountsToBeDeleted": accountsToBeDeleted,
-			"totalAccounts":       len(accountsToBeDeleted),
-		},
-	)
-	err = r.tsClient.DeleteTenants(*accessToken, accountsToBeDeleted)
-	if err != nil {
-		r.log.Error("Error deleting tenant accounts:", err)
-		return integreatlyv1alpha1.PhaseFailed, err
-	}
-
-	// Remove redundant access token secrets
-	for _, account := range accountsToBeDeleted {
-		_, ok := signUpAccountsSecret.Data[string(account.OrgName)]
-		if ok {
-			delete(signUpAccountsSecret.Data, string(account.OrgName))
-		}
-		err := r.removeTenantAccountPassword(ctx, serverClient, account)
-		if err != nil {
-			r.log.Errorf("Error deleting tenant account password",
-				l.Fields{
-					"tenantAccount": account,
-				},
-				err,
-			)
-			return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("Error deleting tenant account password: %s, Error=[%v]", account.OrgName, err)
-		}
-	}
-
-	if len(accountsToBeCreated) > 0 {
-		r.log.Infof("Returning in progess as there were accounts created and users need to be activated",
-			l.Fields{"totalAccountsCreated": len(accountsToBeCreated)},
-		)
-		return integreatlyv1alpha1.PhaseInProgress, nil
-	}
-
-	return integreatlyv1alpha1.PhaseCompleted, nil
-}
-
-func setTenantMetrics(users []userHelper.MultiTenantUser, accounts []AccountDetail) {
-	metrics.ResetNoActivated3ScaleTenantAccount()
-
-	for _, user := range users {
-		if !accountExists(user.TenantName, accounts) {
-			metrics.SetNoActivated3ScaleTenantAccount(user.Username)
-		}
-	}
-}
-
-func accountExists(tenant string, accounts []AccountDetail) bool {
-	for _, acc := range accounts {
-		if tenant == acc.OrgName && acc.State == "approved" {
-			return true
-		}
-	}
-	return false
-}
-
 func getAccessTokenSecret(ctx context.Context, serverClient k8sclient.Client, namespace string) (*corev1.Secret, error) {
 	signUpAccountsSecret := &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
@@ -1834,23 +1481,6 @@ func getAccessTokenSecret(ctx context.Context, serverClient k8sclient.Client, na
 	return signUpAccountsSecret, nil
 }
 
-func getAccountsCreatedCM(ctx context.Context, serverClient k8sclient.Client, namespace string) (*corev1.ConfigMap, error) {
-	accountsCM := &corev1.ConfigMap{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      "tenants-created",
-			Namespace: namespace,
-		},
-	}
-	err := serverClient.Get(ctx, k8sclient.ObjectKey{Name: accountsCM.Name, Namespace: accountsCM.Namespace}, accountsCM)
-	if !k8serr.IsNotFound(err) && err != nil {
-		return nil, fmt.Errorf("Error getting accounts created configmap: %w", err)
-	} else if k8serr.IsNotFound(err) {
-		accountsCM.Data = map[string]string{}
-	}
-
-	return accountsCM, nil
-}
-
 func (r *Reconciler) removeTenantAccountPassword(ctx context.Context, serverClient k8sclient.Client, account AccountDetail) error {
 
 	r.log.Infof("Remove Tenant Account Password", l.Fields{"tenant": account.Name})
@@ -2039,48 +1669,6 @@ func (r *Reconciler) addAuthProviderToMTAccount(ctx context.Context, serverClien
 	return nil
 }
 
-func getMTAccountsToBeCreated(usersIdentity []userHelper.MultiTenantUser, accounts []AccountDetail) (accountsToBeCreated []AccountDetail, emailAddrs []string) {
-	accountsToBeCreated = []AccountDetail{}
-	email := ""
-	for _, identity := range usersIdentity {
-		foundAccount := false
-		for _, account := range accounts {
-			if account.OrgName == identity.TenantName {
-				foundAccount = true
-			}
-		}
-		if !foundAccount {
-			accountsToBeCreated = append(accountsToBeCreated, AccountDetail{
-				Name:    identity.TenantName,
-				OrgName: identity.TenantName,
-			})
-			if identity.Email != "" {
-				email = identity.Email
-			} else {
-				email = userHelper.SetUserNameAsEmail(identity.TenantName)
-			}
-			emailAddrs = append(emailAddrs, email)
-		}
-	}
-	return accountsToBeCreated, emailAddrs
-}
-
-func getMTAccountsToBeDeleted(usersIdentity []userHelper.MultiTenantUser, accounts []AccountDetail) []AccountDetail {
-	accountsToBeDeleted := []AccountDetail{}
-	for _, account := range accounts {
-		foundUser := false
-		for _, identity := range usersIdentity {
-			if account.OrgName == identity.TenantName {
-				foundUser = true
-			}
-		}
-		if !foundUser {
-			accountsToBeDeleted = append(accountsToBeDeleted, account)
-		}
-	}
-	return accountsToBeDeleted
-}
-
 func (r *Reconciler) preUpgradeBackupExecutor() backup.BackupExecutor {
 	if r.installation.Spec.UseClusterStorage != "false" {
 		return backup.NewNoopBackupExecutor()
@@ -2899,10 +2487,14 @@ func (r *Reconciler) ensureDeploymentConfigsReady(ctx context.Context, serverCli
 	return integreatlyv1alpha1.PhaseCompleted, nil
 }
 
-func (r *Reconciler) reconcileRatelimitingTo3scaleComponents(ctx context.Context, serverClient k8sclient.Client, installation *integreatlyv1alpha1.RHMI) (integreatlyv1alpha1.StatusPhase, error) {
+func (r *Reconciler) reconcileRatelimitingTo3scaleComponents(ctx context.Context, serverClient k8sclient.Client, installation *integreatlyv1alpha1.RHMI, rateLimitConfig marin3rconfig.RateLimitConfig) (integreatlyv1alpha1.StatusPhase, error) {
 
 	r.log.Info("Reconciling rate limiting settings to 3scale components")
 
+	if err := rateLimitConfig.Validate(); err != nil {
+		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("invalid rate limit config: %w", err)
+	}
+
 	proxyServer := ratelimit.NewEnvoyProxyServer(ctx, serverClient, r.log)
 
 	err := r.createBackendListenerProxyService(ctx, serverClient)
@@ -2975,22 +2567,33 @@ func (r *Reconciler) reconcileRatelimitingTo3scaleComponents(ctx context.Context
 	var apicastHTTPFilters []*hcm.HttpFilter
 	// apicast filters based on installation type
 	if !integreatlyv1alpha1.IsRHOAMMultitenant(integreatlyv1alpha1.InstallationType(r.installation.Spec.Type)) {
-		apicastHTTPFilters, err = getAPICastHTTPFilters()
+		apicastHTTPFilters, err = getAPICastHTTPFilters(rateLimitConfig.Exemptions)
 		if err != nil {
 			r.log.Errorf("Failed to create envoyconfig filters for multitenant RHOAM", l.Fields{"APICast": ApicastClusterName}, err)
 			return integreatlyv1alpha1.PhaseFailed, err
 		}
 	} else {
-		apicastHTTPFilters, err = getMultitenantAPICastHTTPFilters()
+		apicastHTTPFilters, err = getMultitenantAPICastHTTPFilters(rateLimitConfig.Exemptions)
 		if err != nil {
 			r.log.Errorf("Failed to create envoyconfig filters for multitenant RHOAM", l.Fields{"APICast": ApicastClusterName}, err)
 			return integreatlyv1alpha1.PhaseFailed, err
 		}
 	}
 
+	var overriddenTenants []string
+	if integreatlyv1alpha1.IsRHOAMMultitenant(integreatlyv1alpha1.InstallationType(r.installation.Spec.Type)) {
+		overrides, err := ratelimit.GetTenantRateLimitOverrides(ctx, serverClient)
+		if err != nil {
+			return integreatlyv1alpha1.PhaseFailed, err
+		}
+		for _, override := range overrides {
+			overriddenTenants = append(overriddenTenants, override.TenantName)
+		}
+	}
+
 	// apicast listener
 	apiCastFilters, _ := getListenerResourceFilters(
-		getAPICastVirtualHosts(installation, ApicastClusterName),
+		getAPICastVirtualHosts(installation, ApicastClusterName, overriddenTenants, rateLimitConfig.RouteLimits, rateLimitConfig.Exemptions),
 		apicastHTTPFilters,
 	)
 
@@ -3016,10 +2619,14 @@ func (r *Reconciler) reconcileRatelimitingTo3scaleComponents(ctx context.Context
 		BackendContainerPort,
 	)
 
-	backendHTTPFilters, _ := getBackendListenerHTTPFilters()
+	backendHTTPFilters, err := getBackendListenerHTTPFilters(rateLimitConfig.Exemptions)
+	if err != nil {
+		r.log.Errorf("Failed to create envoyconfig filters for backend-listener", l.Fields{"BackendListener": BackendClusterName}, err)
+		return integreatlyv1alpha1.PhaseFailed, err
+	}
 	// backend listener listener
 	backendFilters, _ := getListenerResourceFilters(
-		getBackendListenerVitualHosts(BackendClusterName),
+		getBackendListenerVitualHosts(BackendClusterName, rateLimitConfig.Exemptions),
 		backendHTTPFilters,
 	)
 	backendListenerResource := ratelimit.CreateListenerResource(
@@ -3124,37 +2731,6 @@ func (r *Reconciler) getBackendListenerRoute(ctx context.Context, serverClient k
 	return backendRoute, nil
 }
 
-func (r *Reconciler) addSSOReadyAnnotationToUser(ctx context.Context, client k8sclient.Client, name string) error {
-	// Get the User CR to annotate
-	userToAnnotate := &usersv1.User{
-		ObjectMeta: metav1.ObjectMeta{
-			Name: name,
-		},
-	}
-	key, err := k8sclient.ObjectKeyFromObject(userToAnnotate)
-	if err != nil {
-		return fmt.Errorf("error getting ObjectKey for user %s: %v", name, err)
-	}
-	err = client.Get(context.TODO(), key, userToAnnotate)
-	if err != nil {
-		return fmt.Errorf("error getting user %s: %v", name, err)
-	}
-
-	// Add the annotation `ssoReady: 'yes'` to the User CR
-	_, err = controllerutil.CreateOrUpdate(context.TODO(), client, userToAnnotate, func() error {
-		if userToAnnotate.Annotations == nil {
-			userToAnnotate.Annotations = map[string]string{}
-		}
-		userToAnnotate.Annotations["ssoReady"] = "yes"
-		return nil
-	})
-	if err != nil {
-		return fmt.Errorf("failed to add ssoReady annotation to user %s: %v", userToAnnotate.Name, err)
-	}
-
-	return nil
-}
-
 func (r *Reconciler) reconcileRatelimitPortAnnotation(ctx context.Context, client k8sclient.Client) (integreatlyv1alpha1.StatusPhase, error) {
 	apim := &threescalev1.APIManager{
 		ObjectMeta: metav1.ObjectMeta{
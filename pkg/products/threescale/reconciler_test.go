@@ -12,6 +12,7 @@ import (
 	"testing"
 
 	"github.com/foxcpp/go-mockdns"
+	marin3rconfig "github.com/integr8ly/integreatly-operator/pkg/products/marin3r/config"
 	"github.com/integr8ly/integreatly-operator/pkg/resources/quota"
 	customdomainv1alpha1 "github.com/openshift/custom-domains-operator/api/v1alpha1"
 
@@ -21,6 +22,7 @@ import (
 	openshiftv1 "github.com/openshift/api/apps/v1"
 	configv1 "github.com/openshift/api/config/v1"
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
 	k8sTypes "k8s.io/apimachinery/pkg/types"
 
 	crov1 "github.com/integr8ly/cloud-resource-operator/apis/integreatly/v1alpha1"
@@ -88,6 +90,7 @@ func getBuildScheme() (*runtime.Scheme, error) {
 	err = customdomainv1alpha1.AddToScheme(scheme)
 	err = marin3rv1alpha1.AddToScheme(scheme)
 	err = cloudcredentialv1.AddToScheme(scheme)
+	err = autoscalingv2beta2.AddToScheme(scheme)
 
 	return scheme, err
 }
@@ -151,6 +154,12 @@ func TestReconciler_Reconcile3scale(t *testing.T) {
 					ConfigureFunc: func(obj metav1.Object) error {
 						return nil
 					},
+					GetAutoscalingFunc: func(ddcssName string) *quota.AutoscalingConfig {
+						return nil
+					},
+					GetRateLimitConfigFunc: func() marin3rconfig.RateLimitConfig {
+						return marin3rconfig.RateLimitConfig{Unit: "minute", RequestsPerUnit: 1}
+					},
 				},
 				uninstall: false,
 			},
@@ -177,6 +186,12 @@ func TestReconciler_Reconcile3scale(t *testing.T) {
 					ConfigureFunc: func(obj metav1.Object) error {
 						return nil
 					},
+					GetAutoscalingFunc: func(ddcssName string) *quota.AutoscalingConfig {
+						return nil
+					},
+					GetRateLimitConfigFunc: func() marin3rconfig.RateLimitConfig {
+						return marin3rconfig.RateLimitConfig{Unit: "minute", RequestsPerUnit: 1}
+					},
 				},
 				uninstall: false,
 			},
@@ -203,6 +218,12 @@ func TestReconciler_Reconcile3scale(t *testing.T) {
 					ConfigureFunc: func(obj metav1.Object) error {
 						return nil
 					},
+					GetAutoscalingFunc: func(ddcssName string) *quota.AutoscalingConfig {
+						return nil
+					},
+					GetRateLimitConfigFunc: func() marin3rconfig.RateLimitConfig {
+						return marin3rconfig.RateLimitConfig{Unit: "minute", RequestsPerUnit: 1}
+					},
 				},
 				uninstall: false,
 			},
@@ -229,6 +250,12 @@ func TestReconciler_Reconcile3scale(t *testing.T) {
 					ConfigureFunc: func(obj metav1.Object) error {
 						return nil
 					},
+					GetAutoscalingFunc: func(ddcssName string) *quota.AutoscalingConfig {
+						return nil
+					},
+					GetRateLimitConfigFunc: func() marin3rconfig.RateLimitConfig {
+						return marin3rconfig.RateLimitConfig{Unit: "minute", RequestsPerUnit: 1}
+					},
 				},
 				uninstall: false,
 			},
@@ -263,6 +290,12 @@ func TestReconciler_Reconcile3scale(t *testing.T) {
 					ConfigureFunc: func(obj metav1.Object) error {
 						return nil
 					},
+					GetAutoscalingFunc: func(ddcssName string) *quota.AutoscalingConfig {
+						return nil
+					},
+					GetRateLimitConfigFunc: func() marin3rconfig.RateLimitConfig {
+						return marin3rconfig.RateLimitConfig{Unit: "minute", RequestsPerUnit: 1}
+					},
 				},
 				uninstall: false,
 			},
@@ -2500,54 +2533,6 @@ func TestReconciler_removeTenantAccountPassword(t *testing.T) {
 	}
 }
 
-func TestReconciler_getAccountsCreatedCM(t *testing.T) {
-	scheme, err := getBuildScheme()
-	if err != nil {
-		t.Fatal(err)
-	}
-
-	type args struct {
-		ctx          context.Context
-		serverClient k8sclient.Client
-	}
-	tests := []struct {
-		name    string
-		args    args
-		wantErr bool
-	}{
-		{
-			name: "tenants-created ConfigMap doesn't exist",
-			args: args{
-				ctx:          context.TODO(),
-				serverClient: fake.NewFakeClientWithScheme(scheme),
-			},
-			wantErr: false,
-		},
-		{
-			name: "tenants-created ConfigMap exists",
-			args: args{
-				ctx: context.TODO(),
-				serverClient: fake.NewFakeClientWithScheme(scheme,
-					&corev1.ConfigMap{
-						ObjectMeta: metav1.ObjectMeta{
-							Name:      "tenants-created",
-							Namespace: "test-namespace",
-						},
-					}),
-			},
-			wantErr: false,
-		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			_, err := getAccountsCreatedCM(tt.args.ctx, tt.args.serverClient, "test-namespace")
-			if (err != nil) != tt.wantErr {
-				t.Errorf("getAccountsCreatedCM() error = %v, wantErr %v", err, tt.wantErr)
-			}
-		})
-	}
-}
-
 func verifyMessageBusDoesNotExist(serverClient k8sclient.Client) bool {
 	redisSecret := &corev1.Secret{}
 	err := serverClient.Get(context.TODO(), k8sclient.ObjectKey{Name: "system-redis", Namespace: "test"}, redisSecret)
@@ -3056,6 +3041,9 @@ func productConfigMock() *quota.ProductConfigMock {
 		ConfigureFunc: func(obj metav1.Object) error {
 			return nil
 		},
+		GetAutoscalingFunc: func(ddcssName string) *quota.AutoscalingConfig {
+			return nil
+		},
 		GetActiveQuotaFunc:     nil,
 		GetRateLimitConfigFunc: nil,
 		GetReplicasFunc:        nil,
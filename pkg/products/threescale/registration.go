@@ -0,0 +1,54 @@
+package threescale
+
+import (
+	"time"
+
+	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/apis/v1alpha1"
+	"github.com/integr8ly/integreatly-operator/pkg/config"
+	"github.com/integr8ly/integreatly-operator/pkg/products"
+	"github.com/integr8ly/integreatly-operator/pkg/resources/quota"
+	appsv1Client "github.com/openshift/client-go/apps/clientset/versioned/typed/apps/v1"
+	"k8s.io/client-go/rest"
+)
+
+func init() {
+	products.Register(products.Registration{
+		Name: integreatlyv1alpha1.Product3Scale,
+		InstallationTypes: []integreatlyv1alpha1.InstallationType{
+			integreatlyv1alpha1.InstallationTypeManagedApi,
+			integreatlyv1alpha1.InstallationTypeMultitenantManagedApi,
+		},
+		Dependencies: []integreatlyv1alpha1.ProductName{
+			integreatlyv1alpha1.ProductCloudResources,
+			integreatlyv1alpha1.ProductRHSSO,
+			integreatlyv1alpha1.ProductObservability,
+		},
+		Version:         integreatlyv1alpha1.Version3Scale,
+		OperatorVersion: integreatlyv1alpha1.OperatorVersion3Scale,
+		NewConfig: func(productConfig config.ProductConfig) config.ConfigReadable {
+			return config.NewThreeScale(productConfig)
+		},
+		QuotaComponents: []string{
+			quota.BackendListenerName,
+			quota.BackendWorkerName,
+			quota.ApicastProductionName,
+			quota.ApicastStagingName,
+		},
+		WatchableCRDs: config.NewThreeScale(config.ProductConfig{}).GetWatchableCRDs(),
+		NewReconciler: func(opts products.ReconcilerOptions) (products.Interface, error) {
+			appsClient, err := appsv1Client.NewForConfig(opts.RestConfig)
+			if err != nil {
+				return nil, err
+			}
+			appsClient.RESTClient().(*rest.RESTClient).Client.Timeout = 10 * time.Second
+
+			oauthv1Client, err := opts.OauthClient()
+			if err != nil {
+				return nil, err
+			}
+
+			tsClient := NewThreeScaleClient(opts.HTTPClient(), opts.Installation.Spec.RoutingSubdomain)
+			return NewReconciler(opts.ConfigManager, opts.Installation, appsClient, oauthv1Client, tsClient, opts.MPM, opts.Recorder, opts.Log, opts.ProductDeclaration)
+		},
+	})
+}
@@ -0,0 +1,368 @@
+package threescale
+
+import (
+	"context"
+	"fmt"
+
+	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/apis/v1alpha1"
+	"github.com/integr8ly/integreatly-operator/pkg/config"
+	"github.com/integr8ly/integreatly-operator/pkg/products/rhsso"
+	l "github.com/integr8ly/integreatly-operator/pkg/resources/logger"
+	userHelper "github.com/integr8ly/integreatly-operator/pkg/resources/user"
+	keycloak "github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
+	consolev1 "github.com/openshift/api/console/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	tenantAccountStateApproved             = "approved"
+	tenantAccountStateScheduledForDeletion = "scheduled_for_deletion"
+	tenantAccountStateSuspended            = "suspended"
+	tenantUserStatePending                 = "pending"
+
+	// TenantOwnerLabel is set on the RHSSO users of the owners of tenants to the name of their tenant
+	TenantOwnerLabel = "integreatly.org/tenant-owner"
+)
+
+// TenantAccountReconciler provisions the 3scale account of a single tenant. It is used by the
+// APIManagementTenant controller so each tenant is reconciled on its own, instead of listing every
+// tenant account in each reconcile of the installation
+type TenantAccountReconciler struct {
+	r *Reconciler
+}
+
+// TenantAccountStatus is the state of the 3scale account of a tenant
+type TenantAccountStatus struct {
+	// ID is the ID of the account, it is 0 when the tenant has no account
+	ID           int
+	AdminBaseURL string
+	// Ready is true when the users of the account are activated and the tenant can log in through SSO
+	Ready bool
+	// Suspended is true when the account is suspended, a suspended account is never ready
+	Suspended bool
+	// Message is the reason the account is not ready
+	Message string
+}
+
+func NewTenantAccountReconciler(configManager config.ConfigReadWriter, installation *integreatlyv1alpha1.RHMI, tsClient ThreeScaleInterface, logger l.Logger) (*TenantAccountReconciler, error) {
+	threescaleConfig, err := configManager.ReadThreeScale()
+	if err != nil {
+		return nil, fmt.Errorf("could not retrieve threescale config: %w", err)
+	}
+	if threescaleConfig.GetNamespace() == "" {
+		return nil, fmt.Errorf("threescale namespace is not set, 3scale is not installed yet")
+	}
+
+	return &TenantAccountReconciler{
+		r: &Reconciler{
+			ConfigManager: configManager,
+			Config:        threescaleConfig,
+			installation:  installation,
+			tsClient:      tsClient,
+			log:           logger,
+		},
+	}, nil
+}
+
+// ReconcileTenantAccount creates the 3scale account of user when it doesn't exist, activates its
+// users and adds the RHSSO auth provider to it. accountID is the ID of the account found in a
+// previous reconcile, accounts created before the ID was recorded are looked up once by name.
+// When suspended is true the account is suspended instead, and resumed once it is false again
+func (t *TenantAccountReconciler) ReconcileTenantAccount(ctx context.Context, serverClient k8sclient.Client, user userHelper.MultiTenantUser, accountID int, suspended bool) (TenantAccountStatus, error) {
+	r := t.r
+	accessToken, err := r.GetMasterToken(ctx, serverClient)
+	if err != nil {
+		return TenantAccountStatus{}, err
+	}
+
+	signUpAccountsSecret, err := getAccessTokenSecret(ctx, serverClient, r.Config.GetNamespace())
+	if err != nil {
+		return TenantAccountStatus{}, err
+	}
+	accountAccessToken, hasAccessToken := signUpAccountsSecret.Data[user.TenantName]
+
+	var account *AccountDetail
+	switch {
+	case accountID != 0:
+		signUpAccount, err := r.tsClient.GetTenantAccount(*accessToken, accountID)
+		if err != nil {
+			return TenantAccountStatus{}, fmt.Errorf("failed to get tenant account %d: %w", accountID, err)
+		}
+		account = &signUpAccount.AccountDetail
+	case hasAccessToken:
+		account, err = t.findTenantAccount(*accessToken, user.TenantName)
+		if err != nil {
+			return TenantAccountStatus{}, err
+		}
+	}
+
+	if account == nil && suspended {
+		return TenantAccountStatus{Suspended: true}, nil
+	}
+	if account == nil {
+		newAccount := AccountDetail{Name: user.TenantName, OrgName: user.TenantName}
+		pw, err := r.getTenantAccountPassword(ctx, serverClient, newAccount)
+		if err != nil {
+			return TenantAccountStatus{}, fmt.Errorf("failed to get tenant account password: %w", err)
+		}
+		email := user.Email
+		if email == "" {
+			email = userHelper.SetUserNameAsEmail(user.TenantName)
+		}
+
+		signUpAccount, err := r.tsClient.CreateTenant(*accessToken, newAccount, pw, email)
+		if err != nil {
+			return TenantAccountStatus{}, fmt.Errorf("error creating tenant account %s: %w", user.TenantName, err)
+		}
+		r.log.Infof("New tenant account created", l.Fields{
+			"tenantAccountId":    signUpAccount.AccountDetail.Id,
+			"tenantAccountName":  signUpAccount.AccountDetail.OrgName,
+			"tenantAccountState": signUpAccount.AccountDetail.State,
+		})
+
+		if _, err := controllerutil.CreateOrUpdate(ctx, serverClient, signUpAccountsSecret, func() error {
+			if signUpAccountsSecret.Data == nil {
+				signUpAccountsSecret.Data = map[string][]byte{}
+			}
+			signUpAccountsSecret.Data[user.TenantName] = []byte(signUpAccount.AccountAccessToken.Value)
+			return nil
+		}); err != nil {
+			return TenantAccountStatus{}, fmt.Errorf("error creating access token secret: %w", err)
+		}
+		accountAccessToken = []byte(signUpAccount.AccountAccessToken.Value)
+		account = &signUpAccount.AccountDetail
+	}
+
+	status := TenantAccountStatus{
+		ID:           account.Id,
+		AdminBaseURL: account.AdminBaseURL,
+	}
+
+	switch {
+	case account.State == tenantAccountStateSuspended && suspended:
+		status.Suspended = true
+		return status, nil
+	case account.State == tenantAccountStateSuspended:
+		r.log.Infof("Resuming tenant account", l.Fields{"tenantAccountId": account.Id, "tenantAccountName": account.OrgName})
+		if err := r.tsClient.ResumeTenant(*accessToken, account.Id); err != nil {
+			return status, fmt.Errorf("error resuming tenant account %s: %w", account.OrgName, err)
+		}
+		account.State = tenantAccountStateApproved
+	case account.State == tenantAccountStateApproved && suspended:
+		r.log.Infof("Suspending tenant account", l.Fields{"tenantAccountId": account.Id, "tenantAccountName": account.OrgName})
+		if err := r.tsClient.SuspendTenant(*accessToken, account.Id); err != nil {
+			return status, fmt.Errorf("error suspending tenant account %s: %w", account.OrgName, err)
+		}
+		status.Suspended = true
+		return status, nil
+	}
+
+	if account.State != tenantAccountStateApproved {
+		if account.State == tenantAccountStateScheduledForDeletion {
+			status.Message = fmt.Sprintf("3scale account %s is scheduled for deletion", account.OrgName)
+			return status, nil
+		}
+
+		r.log.Infof("Deleting broken account for recreation", l.Fields{
+			"tenantAccountId":    account.Id,
+			"tenantAccountName":  account.Name,
+			"tenantAccountState": account.State,
+		})
+		if err := t.DeleteTenantAccount(ctx, serverClient, user.TenantName, account.Id); err != nil {
+			return status, fmt.Errorf("error deleting broken account %s: %w", account.OrgName, err)
+		}
+		return TenantAccountStatus{Message: fmt.Sprintf("3scale account %s was in state %s and is being recreated", account.OrgName, account.State)}, nil
+	}
+
+	for _, accountUser := range account.Users.User {
+		if accountUser.State != tenantUserStatePending {
+			continue
+		}
+		r.log.Infof("Activating user access to new tenant account", l.Fields{
+			"userName":          accountUser.Username,
+			"tenantAccountName": account.OrgName,
+		})
+		if err := r.tsClient.ActivateUser(*accessToken, account.Id, accountUser.Id); err != nil {
+			return status, fmt.Errorf("error activating user %s of tenant account %s: %w", accountUser.Username, account.OrgName, err)
+		}
+	}
+
+	if len(accountAccessToken) == 0 {
+		return status, fmt.Errorf("access token of tenant account %s not found", account.OrgName)
+	}
+	signUpAccount := SignUpAccount{
+		AccountDetail:      *account,
+		AccountAccessToken: AccountAccessToken{Value: string(accountAccessToken)},
+	}
+	if err := r.addAuthProviderToMTAccount(ctx, serverClient, signUpAccount); err != nil {
+		return status, fmt.Errorf("error adding authentication provider to tenant account %s: %w", account.OrgName, err)
+	}
+
+	if err := r.reconcileDashboardLink(ctx, serverClient, account.OrgName, account.AdminBaseURL); err != nil {
+		return status, err
+	}
+
+	kcUser, err := r.getKeycloakUserFromAccount(serverClient, account.OrgName)
+	if err != nil {
+		status.Message = err.Error()
+		return status, nil
+	}
+	kcClient, err := r.getKeycloakClientFromAccount(serverClient, account.OrgName)
+	if err != nil {
+		status.Message = err.Error()
+		return status, nil
+	}
+	if kcUser.Status.Phase != keycloak.UserPhaseReconciled || !kcClient.Status.Ready {
+		status.Message = fmt.Sprintf("waiting for SSO for tenant account %s to be ready", account.OrgName)
+		return status, nil
+	}
+
+	status.Ready = true
+	return status, nil
+}
+
+// DeleteTenantAccount deletes the 3scale account of the tenant with accountID and removes its
+// credentials and dashboard link. An accountID of 0 only removes the credentials
+func (t *TenantAccountReconciler) DeleteTenantAccount(ctx context.Context, serverClient k8sclient.Client, tenantName string, accountID int) error {
+	r := t.r
+	if accountID != 0 {
+		accessToken, err := r.GetMasterToken(ctx, serverClient)
+		if err != nil {
+			return err
+		}
+		if err := r.tsClient.DeleteTenant(*accessToken, accountID); err != nil {
+			return fmt.Errorf("error deleting tenant account %d: %w", accountID, err)
+		}
+	}
+
+	signUpAccountsSecret, err := getAccessTokenSecret(ctx, serverClient, r.Config.GetNamespace())
+	if err != nil {
+		return err
+	}
+	if _, ok := signUpAccountsSecret.Data[tenantName]; ok {
+		delete(signUpAccountsSecret.Data, tenantName)
+		if err := serverClient.Update(ctx, signUpAccountsSecret); err != nil {
+			return fmt.Errorf("error removing access token of tenant account %s: %w", tenantName, err)
+		}
+	}
+
+	if err := r.removeTenantAccountPassword(ctx, serverClient, AccountDetail{Name: tenantName, OrgName: tenantName}); err != nil {
+		return fmt.Errorf("error deleting tenant account password: %w", err)
+	}
+
+	consoleLink := &consolev1.ConsoleLink{ObjectMeta: metav1.ObjectMeta{Name: tenantName + "-3scale"}}
+	if err := serverClient.Delete(ctx, consoleLink); err != nil && !k8serr.IsNotFound(err) {
+		return fmt.Errorf("error deleting console link of tenant account %s: %w", tenantName, err)
+	}
+
+	return nil
+}
+
+// ReconcileTenantOwner creates the RHSSO user owner logs in to the 3scale account of user with.
+// Tenants owned by an OpenShift user get theirs from the RHSSO reconciler instead, which only
+// synchronizes users with the labels of the realm, so the user of owner is left alone by it
+func (t *TenantAccountReconciler) ReconcileTenantOwner(ctx context.Context, serverClient k8sclient.Client, user userHelper.MultiTenantUser, owner *integreatlyv1alpha1.TenantOwner) error {
+	rhssoConfig, err := t.r.ConfigManager.ReadRHSSO()
+	if err != nil {
+		return fmt.Errorf("could not retrieve rhsso config: %w", err)
+	}
+	if rhssoConfig.GetNamespace() == "" {
+		return fmt.Errorf("rhsso namespace is not set, RHSSO is not installed yet")
+	}
+
+	userID := owner.UserID
+	if userID == "" {
+		userID = owner.Email
+	}
+	kcUser := &keycloak.KeycloakUser{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      getTenantOwnerUserName(user.TenantName),
+			Namespace: rhssoConfig.GetNamespace(),
+		},
+	}
+	or, err := controllerutil.CreateOrUpdate(ctx, serverClient, kcUser, func() error {
+		kcUser.Labels = map[string]string{TenantOwnerLabel: user.TenantName}
+		kcUser.Spec.RealmSelector = &metav1.LabelSelector{
+			MatchLabels: rhsso.GetInstanceLabels(),
+		}
+		// The ID and attributes set on the user once it exists are kept
+		kcUser.Spec.User.Enabled = true
+		kcUser.Spec.User.UserName = user.TenantName
+		kcUser.Spec.User.Email = user.Email
+		kcUser.Spec.User.EmailVerified = true
+		kcUser.Spec.User.FederatedIdentities = []keycloak.FederatedIdentity{
+			{
+				IdentityProvider: owner.IdentityProvider,
+				UserID:           userID,
+				UserName:         owner.Email,
+			},
+		}
+		kcUser.Spec.User.ClientRoles = rhsso.GetKeycloakRoles(integreatlyv1alpha1.InstallationType(t.r.installation.Spec.Type))
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create/update the RHSSO user of tenant account %s: %w", user.TenantName, err)
+	}
+	if or != controllerutil.OperationResultNone {
+		t.r.log.Infof("Operation result", l.Fields{"keycloakuser": kcUser.Name, "result": or})
+	}
+	return nil
+}
+
+// DeleteTenantOwner deletes the RHSSO user of the owner of tenantName
+func (t *TenantAccountReconciler) DeleteTenantOwner(ctx context.Context, serverClient k8sclient.Client, tenantName string) error {
+	rhssoConfig, err := t.r.ConfigManager.ReadRHSSO()
+	if err != nil {
+		return fmt.Errorf("could not retrieve rhsso config: %w", err)
+	}
+
+	kcUser := &keycloak.KeycloakUser{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      getTenantOwnerUserName(tenantName),
+			Namespace: rhssoConfig.GetNamespace(),
+		},
+	}
+	if err := serverClient.Delete(ctx, kcUser); err != nil && !k8serr.IsNotFound(err) {
+		return fmt.Errorf("error deleting the RHSSO user of tenant account %s: %w", tenantName, err)
+	}
+	return nil
+}
+
+func getTenantOwnerUserName(tenantName string) string {
+	return fmt.Sprintf("tenant-owner-%s", tenantName)
+}
+
+// findTenantAccount looks up the account of tenantName in every page of tenant accounts, it returns
+// nil when the account doesn't exist
+func (t *TenantAccountReconciler) findTenantAccount(accessToken, tenantName string) (*AccountDetail, error) {
+	for page := 1; ; page++ {
+		accounts, err := t.r.tsClient.ListTenantAccounts(accessToken, page)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get accounts from 3scale API: %w", err)
+		}
+		if len(accounts) == 0 {
+			return nil, nil
+		}
+		for i := range accounts {
+			if accounts[i].OrgName == tenantName {
+				return &accounts[i], nil
+			}
+		}
+	}
+}
+
+// GetTenantAccessToken returns the access token of the admin API of the 3scale account of tenantName
+func (t *TenantAccountReconciler) GetTenantAccessToken(ctx context.Context, serverClient k8sclient.Client, tenantName string) (string, error) {
+	signUpAccountsSecret, err := getAccessTokenSecret(ctx, serverClient, t.r.Config.GetNamespace())
+	if err != nil {
+		return "", err
+	}
+	accessToken, ok := signUpAccountsSecret.Data[tenantName]
+	if !ok || len(accessToken) == 0 {
+		return "", fmt.Errorf("access token of tenant account %s not found", tenantName)
+	}
+	return string(accessToken), nil
+}
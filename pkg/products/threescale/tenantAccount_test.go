@@ -0,0 +1,363 @@
+package threescale
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/apis/v1alpha1"
+	"github.com/integr8ly/integreatly-operator/pkg/config"
+	userHelper "github.com/integr8ly/integreatly-operator/pkg/resources/user"
+	keycloak "github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
+	consolev1 "github.com/openshift/api/console/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const tenantAccountTestNamespace = "test-namespace"
+
+func getTenantAccountTestObjects(accessTokens map[string][]byte, ssoReady bool) []runtime.Object {
+	phase := keycloak.UserPhaseFailing
+	if ssoReady {
+		phase = keycloak.UserPhaseReconciled
+	}
+	return []runtime.Object{
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "system-seed", Namespace: tenantAccountTestNamespace},
+			Data:       map[string][]byte{"MASTER_ACCESS_TOKEN": []byte("master-token")},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "mt-signupaccount-3scale-access-token", Namespace: tenantAccountTestNamespace},
+			Data:       accessTokens,
+		},
+		&keycloak.KeycloakUser{
+			ObjectMeta: metav1.ObjectMeta{Name: "generated-tenant", Namespace: "rhsso"},
+			Spec:       keycloak.KeycloakUserSpec{User: keycloak.KeycloakAPIUser{UserName: "tenant"}},
+			Status:     keycloak.KeycloakUserStatus{Phase: phase},
+		},
+		&keycloak.KeycloakClient{
+			ObjectMeta: metav1.ObjectMeta{Name: "tenant-client", Namespace: "rhsso"},
+			Status:     keycloak.KeycloakClientStatus{Ready: ssoReady},
+		},
+	}
+}
+
+func getTenantAccount(id int, state string) AccountDetail {
+	return AccountDetail{
+		Id:           id,
+		Name:         "tenant",
+		OrgName:      "tenant",
+		AdminBaseURL: "https://tenant-admin.example.com",
+		State:        state,
+		Users: XMLUsers{User: []XMLUserDetails{
+			{Id: 10, State: "pending", Username: "tenant"},
+		}},
+	}
+}
+
+func getTenantAccountTSClient(account AccountDetail) *ThreeScaleInterfaceMock {
+	return &ThreeScaleInterfaceMock{
+		GetTenantAccountFunc: func(accessToken string, id int) (*SignUpAccount, error) {
+			return &SignUpAccount{AccountDetail: account}, nil
+		},
+		ListTenantAccountsFunc: func(accessToken string, page int) ([]AccountDetail, error) {
+			switch page {
+			case 1:
+				return []AccountDetail{{Id: 3, OrgName: "other-tenant"}}, nil
+			case 2:
+				return []AccountDetail{account}, nil
+			}
+			return []AccountDetail{}, nil
+		},
+		CreateTenantFunc: func(accessToken string, newAccount AccountDetail, password string, email string) (*SignUpAccount, error) {
+			return &SignUpAccount{AccountDetail: account, AccountAccessToken: AccountAccessToken{Value: "new-token"}}, nil
+		},
+		DeleteTenantFunc: func(accessToken string, id int) error {
+			return nil
+		},
+		ActivateUserFunc: func(accessToken string, accountId, userId int) error {
+			return nil
+		},
+		IsAuthProviderAddedFunc: func(accessToken string, authProviderName string, account AccountDetail) (bool, error) {
+			return true, nil
+		},
+		SuspendTenantFunc: func(accessToken string, id int) error {
+			return nil
+		},
+		ResumeTenantFunc: func(accessToken string, id int) error {
+			return nil
+		},
+	}
+}
+
+func TestTenantAccountReconciler_ReconcileTenantAccount(t *testing.T) {
+	scheme, err := getBuildScheme()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	user := userHelper.MultiTenantUser{Username: "tenant", TenantName: "tenant", Email: "tenant@example.com"}
+
+	tests := []struct {
+		name         string
+		accountID    int
+		accessTokens map[string][]byte
+		account      AccountDetail
+		ssoReady     bool
+		suspended    bool
+		wantStatus   TenantAccountStatus
+		wantCreated  bool
+		wantDeleted  bool
+		wantListed   bool
+		wantSuspend  bool
+		wantResume   bool
+		wantToken    string
+	}{
+		{
+			name:        "test account is created for a new tenant",
+			account:     getTenantAccount(4, "approved"),
+			wantStatus:  TenantAccountStatus{ID: 4, AdminBaseURL: "https://tenant-admin.example.com", Message: "waiting for SSO for tenant account tenant to be ready"},
+			wantCreated: true,
+			wantToken:   "new-token",
+		},
+		{
+			name:         "test account without recorded ID is looked up once by name",
+			accessTokens: map[string][]byte{"tenant": []byte("token")},
+			account:      getTenantAccount(4, "approved"),
+			ssoReady:     true,
+			wantStatus:   TenantAccountStatus{ID: 4, AdminBaseURL: "https://tenant-admin.example.com", Ready: true},
+			wantListed:   true,
+			wantToken:    "token",
+		},
+		{
+			name:         "test account is ready once SSO for the tenant is ready",
+			accountID:    4,
+			accessTokens: map[string][]byte{"tenant": []byte("token")},
+			account:      getTenantAccount(4, "approved"),
+			ssoReady:     true,
+			wantStatus:   TenantAccountStatus{ID: 4, AdminBaseURL: "https://tenant-admin.example.com", Ready: true},
+			wantToken:    "token",
+		},
+		{
+			name:         "test broken account is deleted to be recreated",
+			accountID:    4,
+			accessTokens: map[string][]byte{"tenant": []byte("token")},
+			account:      getTenantAccount(4, "pending"),
+			wantStatus:   TenantAccountStatus{Message: "3scale account tenant was in state pending and is being recreated"},
+			wantDeleted:  true,
+		},
+		{
+			name:         "test account scheduled for deletion is left as it is",
+			accountID:    4,
+			accessTokens: map[string][]byte{"tenant": []byte("token")},
+			account:      getTenantAccount(4, "scheduled_for_deletion"),
+			wantStatus:   TenantAccountStatus{ID: 4, AdminBaseURL: "https://tenant-admin.example.com", Message: "3scale account tenant is scheduled for deletion"},
+			wantToken:    "token",
+		},
+		{
+			name:         "test account is suspended",
+			accountID:    4,
+			accessTokens: map[string][]byte{"tenant": []byte("token")},
+			account:      getTenantAccount(4, "approved"),
+			suspended:    true,
+			wantStatus:   TenantAccountStatus{ID: 4, AdminBaseURL: "https://tenant-admin.example.com", Suspended: true},
+			wantSuspend:  true,
+			wantToken:    "token",
+		},
+		{
+			name:         "test suspended account is kept suspended",
+			accountID:    4,
+			accessTokens: map[string][]byte{"tenant": []byte("token")},
+			account:      getTenantAccount(4, "suspended"),
+			suspended:    true,
+			wantStatus:   TenantAccountStatus{ID: 4, AdminBaseURL: "https://tenant-admin.example.com", Suspended: true},
+			wantToken:    "token",
+		},
+		{
+			name:         "test suspended account is resumed",
+			accountID:    4,
+			accessTokens: map[string][]byte{"tenant": []byte("token")},
+			account:      getTenantAccount(4, "suspended"),
+			ssoReady:     true,
+			wantStatus:   TenantAccountStatus{ID: 4, AdminBaseURL: "https://tenant-admin.example.com", Ready: true},
+			wantResume:   true,
+			wantToken:    "token",
+		},
+		{
+			name:       "test account is not created for a suspended tenant",
+			account:    getTenantAccount(4, "approved"),
+			suspended:  true,
+			wantStatus: TenantAccountStatus{Suspended: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			serverClient := fake.NewFakeClientWithScheme(scheme, getTenantAccountTestObjects(tt.accessTokens, tt.ssoReady)...)
+			tsClient := getTenantAccountTSClient(tt.account)
+			configManager := &config.ConfigReadWriterMock{
+				ReadThreeScaleFunc: func() (*config.ThreeScale, error) {
+					return config.NewThreeScale(config.ProductConfig{"NAMESPACE": tenantAccountTestNamespace}), nil
+				},
+			}
+			accounts, err := NewTenantAccountReconciler(configManager, getTestInstallation("multitenant-managed-api"), tsClient, getLogger())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			status, err := accounts.ReconcileTenantAccount(context.TODO(), serverClient, user, tt.accountID, tt.suspended)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if status != tt.wantStatus {
+				t.Errorf("expected status %+v but got %+v", tt.wantStatus, status)
+			}
+			if created := len(tsClient.CreateTenantCalls()) == 1; created != tt.wantCreated {
+				t.Errorf("expected account created %v", tt.wantCreated)
+			}
+			if deleted := len(tsClient.DeleteTenantCalls()) == 1; deleted != tt.wantDeleted {
+				t.Errorf("expected account deleted %v", tt.wantDeleted)
+			}
+			if listed := len(tsClient.ListTenantAccountsCalls()) > 0; listed != tt.wantListed {
+				t.Errorf("expected accounts listed %v", tt.wantListed)
+			}
+			if suspended := len(tsClient.SuspendTenantCalls()) == 1; suspended != tt.wantSuspend {
+				t.Errorf("expected account suspended %v", tt.wantSuspend)
+			}
+			if resumed := len(tsClient.ResumeTenantCalls()) == 1; resumed != tt.wantResume {
+				t.Errorf("expected account resumed %v", tt.wantResume)
+			}
+			if status.Ready && len(tsClient.ActivateUserCalls()) != 1 {
+				t.Errorf("expected the pending user to be activated")
+			}
+
+			signUpAccountsSecret, err := getAccessTokenSecret(context.TODO(), serverClient, tenantAccountTestNamespace)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if token := string(signUpAccountsSecret.Data["tenant"]); token != tt.wantToken {
+				t.Errorf("expected access token %q but got %q", tt.wantToken, token)
+			}
+		})
+	}
+}
+
+func TestTenantAccountReconciler_DeleteTenantAccount(t *testing.T) {
+	scheme, err := getBuildScheme()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	serverClient := fake.NewFakeClientWithScheme(scheme, append(getTenantAccountTestObjects(map[string][]byte{"tenant": []byte("token"), "other-tenant": []byte("other-token")}, true),
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "tenant-account-passwords", Namespace: tenantAccountTestNamespace},
+			Data:       map[string][]byte{"tenant": []byte("password")},
+		},
+		&consolev1.ConsoleLink{ObjectMeta: metav1.ObjectMeta{Name: "tenant-3scale"}},
+	)...)
+	tsClient := getTenantAccountTSClient(getTenantAccount(4, "approved"))
+	configManager := &config.ConfigReadWriterMock{
+		ReadThreeScaleFunc: func() (*config.ThreeScale, error) {
+			return config.NewThreeScale(config.ProductConfig{"NAMESPACE": tenantAccountTestNamespace}), nil
+		},
+	}
+	accounts, err := NewTenantAccountReconciler(configManager, getTestInstallation("multitenant-managed-api"), tsClient, getLogger())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := accounts.DeleteTenantAccount(context.TODO(), serverClient, "tenant", 4); err != nil {
+		t.Fatal(err)
+	}
+	if calls := tsClient.DeleteTenantCalls(); len(calls) != 1 || calls[0].ID != 4 {
+		t.Errorf("expected account 4 to be deleted but got %v", calls)
+	}
+
+	signUpAccountsSecret, err := getAccessTokenSecret(context.TODO(), serverClient, tenantAccountTestNamespace)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := signUpAccountsSecret.Data["tenant"]; ok || len(signUpAccountsSecret.Data) != 1 {
+		t.Errorf("expected only the access token of the tenant to be removed but got %v", signUpAccountsSecret.Data)
+	}
+	passwords := &corev1.Secret{}
+	if err := serverClient.Get(context.TODO(), k8sclient.ObjectKey{Name: "tenant-account-passwords", Namespace: tenantAccountTestNamespace}, passwords); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := passwords.Data["tenant"]; ok {
+		t.Errorf("expected the password of the tenant to be removed")
+	}
+	if err := serverClient.Get(context.TODO(), k8sclient.ObjectKey{Name: "tenant-3scale"}, &consolev1.ConsoleLink{}); err == nil {
+		t.Errorf("expected the console link of the tenant to be deleted")
+	}
+}
+
+func TestTenantAccountReconciler_ReconcileTenantOwner(t *testing.T) {
+	scheme, err := getBuildScheme()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	serverClient := fake.NewFakeClientWithScheme(scheme)
+	configManager := &config.ConfigReadWriterMock{
+		ReadThreeScaleFunc: func() (*config.ThreeScale, error) {
+			return config.NewThreeScale(config.ProductConfig{"NAMESPACE": tenantAccountTestNamespace}), nil
+		},
+		ReadRHSSOFunc: func() (*config.RHSSO, error) {
+			return config.NewRHSSO(config.ProductConfig{"NAMESPACE": "rhsso"}), nil
+		},
+	}
+	accounts, err := NewTenantAccountReconciler(configManager, getTestInstallation("multitenant-managed-api"), &ThreeScaleInterfaceMock{}, getLogger())
+	if err != nil {
+		t.Fatal(err)
+	}
+	user := userHelper.MultiTenantUser{Username: "example-org", TenantName: "example-org", Email: "admin@example.com"}
+	owner := &integreatlyv1alpha1.TenantOwner{Email: "admin@example.com", OrganizationName: "Example Org", IdentityProvider: "example-idp"}
+
+	if err := accounts.ReconcileTenantOwner(context.TODO(), serverClient, user, owner); err != nil {
+		t.Fatal(err)
+	}
+	kcUser := &keycloak.KeycloakUser{}
+	if err := serverClient.Get(context.TODO(), k8sclient.ObjectKey{Name: "tenant-owner-example-org", Namespace: "rhsso"}, kcUser); err != nil {
+		t.Fatal(err)
+	}
+	// users with the labels of the realm are removed by the RHSSO reconciler when they have no OpenShift user
+	if !reflect.DeepEqual(kcUser.Labels, map[string]string{TenantOwnerLabel: "example-org"}) {
+		t.Errorf("expected only the tenant owner label but got %v", kcUser.Labels)
+	}
+	if kcUser.Spec.User.UserName != "example-org" || kcUser.Spec.User.Email != "admin@example.com" {
+		t.Errorf("expected user example-org with email admin@example.com but got %+v", kcUser.Spec.User)
+	}
+	wantIdentities := []keycloak.FederatedIdentity{{IdentityProvider: "example-idp", UserID: "admin@example.com", UserName: "admin@example.com"}}
+	if !reflect.DeepEqual(kcUser.Spec.User.FederatedIdentities, wantIdentities) {
+		t.Errorf("expected federated identities %+v but got %+v", wantIdentities, kcUser.Spec.User.FederatedIdentities)
+	}
+
+	// the ID set by the keycloak operator is kept when the owner changes
+	kcUser.Spec.User.ID = "keycloak-id"
+	if err := serverClient.Update(context.TODO(), kcUser); err != nil {
+		t.Fatal(err)
+	}
+	owner.UserID = "idp-user-id"
+	if err := accounts.ReconcileTenantOwner(context.TODO(), serverClient, user, owner); err != nil {
+		t.Fatal(err)
+	}
+	if err := serverClient.Get(context.TODO(), k8sclient.ObjectKey{Name: "tenant-owner-example-org", Namespace: "rhsso"}, kcUser); err != nil {
+		t.Fatal(err)
+	}
+	if kcUser.Spec.User.ID != "keycloak-id" || kcUser.Spec.User.FederatedIdentities[0].UserID != "idp-user-id" {
+		t.Errorf("expected user keycloak-id with identity provider user idp-user-id but got %+v", kcUser.Spec.User)
+	}
+
+	if err := accounts.DeleteTenantOwner(context.TODO(), serverClient, "example-org"); err != nil {
+		t.Fatal(err)
+	}
+	if err := serverClient.Get(context.TODO(), k8sclient.ObjectKey{Name: "tenant-owner-example-org", Namespace: "rhsso"}, kcUser); err == nil {
+		t.Errorf("expected the RHSSO user of the tenant owner to be deleted")
+	}
+	if err := accounts.DeleteTenantOwner(context.TODO(), serverClient, "example-org"); err != nil {
+		t.Errorf("expected deleting a missing RHSSO user to succeed but got %v", err)
+	}
+}
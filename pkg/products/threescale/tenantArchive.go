@@ -0,0 +1,538 @@
+package threescale
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strconv"
+)
+
+// tenantArchiveVersion is the version of the format of the tenant archives written by the operator
+const tenantArchiveVersion = 1
+
+// TenantArchive is the 3scale configuration of a tenant in a form that can be imported into any
+// tenant. The IDs in the archive are the IDs in the exported tenant, they are only used to link the
+// objects of the archive to each other. The archive holds the credentials of the applications of
+// the tenant, which are imported with them
+type TenantArchive struct {
+	Version  int               `json:"version"`
+	Backends []ArchivedBackend `json:"backends"`
+	Services []ArchivedService `json:"services"`
+	Accounts []ArchivedAccount `json:"accounts"`
+}
+
+type ArchivedBackend struct {
+	Backend
+	Metrics      []Metric      `json:"metrics"`
+	MappingRules []MappingRule `json:"mapping_rules"`
+}
+
+type ArchivedService struct {
+	Service
+	Metrics          []Metric                  `json:"metrics"`
+	MappingRules     []MappingRule             `json:"mapping_rules"`
+	BackendUsages    []BackendUsage            `json:"backend_usages"`
+	ApplicationPlans []ArchivedApplicationPlan `json:"application_plans"`
+}
+
+type ArchivedApplicationPlan struct {
+	ApplicationPlan
+	// Limits are the limits of the metrics of the service and of its backends
+	Limits []Limit `json:"limits"`
+}
+
+type ArchivedAccount struct {
+	ID           int                   `json:"id"`
+	OrgName      string                `json:"org_name"`
+	Username     string                `json:"username"`
+	Applications []ArchivedApplication `json:"applications"`
+}
+
+type ArchivedApplication struct {
+	Application
+	// Keys are the app keys of an application authenticated with an app ID
+	Keys []string `json:"keys,omitempty"`
+}
+
+// ExportTenant reads the backends, services and developer accounts of the tenant of tsClient
+func ExportTenant(tsClient ThreeScaleInterface, accessToken string) (*TenantArchive, error) {
+	archive := &TenantArchive{Version: tenantArchiveVersion}
+
+	backends, err := listAllBackends(tsClient, accessToken)
+	if err != nil {
+		return nil, err
+	}
+	for _, backend := range backends {
+		metrics, err := tsClient.ListBackendMetrics(accessToken, backend.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list metrics of backend %s: %w", backend.Name, err)
+		}
+		rules, err := tsClient.ListBackendMappingRules(accessToken, backend.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list mapping rules of backend %s: %w", backend.Name, err)
+		}
+		archive.Backends = append(archive.Backends, ArchivedBackend{Backend: backend, Metrics: metrics, MappingRules: rules})
+	}
+
+	services, err := listAllServices(tsClient, accessToken)
+	if err != nil {
+		return nil, err
+	}
+	for _, service := range services {
+		serviceID := strconv.Itoa(service.ID)
+		metrics, err := tsClient.ListServiceMetrics(accessToken, serviceID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list metrics of service %s: %w", service.SystemName, err)
+		}
+		rules, err := tsClient.ListServiceMappingRules(accessToken, serviceID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list mapping rules of service %s: %w", service.SystemName, err)
+		}
+		usages, err := tsClient.ListBackendUsages(accessToken, serviceID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list backend usages of service %s: %w", service.SystemName, err)
+		}
+		plans, err := tsClient.ListApplicationPlans(accessToken, serviceID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list application plans of service %s: %w", service.SystemName, err)
+		}
+		archivedPlans := make([]ArchivedApplicationPlan, 0, len(plans))
+		for _, plan := range plans {
+			limits, err := tsClient.ListApplicationPlanLimits(accessToken, strconv.Itoa(plan.ID))
+			if err != nil {
+				return nil, fmt.Errorf("failed to list limits of application plan %s of service %s: %w", plan.Name, service.SystemName, err)
+			}
+			archivedPlans = append(archivedPlans, ArchivedApplicationPlan{ApplicationPlan: plan, Limits: limits})
+		}
+		archive.Services = append(archive.Services, ArchivedService{
+			Service:          service,
+			Metrics:          metrics,
+			MappingRules:     rules,
+			BackendUsages:    usages,
+			ApplicationPlans: archivedPlans,
+		})
+	}
+
+	accounts, err := listAllAccounts(tsClient, accessToken)
+	if err != nil {
+		return nil, err
+	}
+	for _, account := range accounts {
+		accountID := strconv.Itoa(account.Id)
+		applications, err := listAllAccountApplications(tsClient, accessToken, accountID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list applications of account %s: %w", account.OrgName, err)
+		}
+		archivedApplications := make([]ArchivedApplication, 0, len(applications))
+		for _, application := range applications {
+			archived := ArchivedApplication{Application: application}
+			if application.AppID != "" {
+				archived.Keys, err = tsClient.ListApplicationKeys(accessToken, accountID, application.ID)
+				if err != nil {
+					return nil, fmt.Errorf("failed to list keys of application %s of account %s: %w", application.Name, account.OrgName, err)
+				}
+			}
+			archivedApplications = append(archivedApplications, archived)
+		}
+		username := account.OrgName
+		if len(account.Users.User) > 0 {
+			username = account.Users.User[0].Username
+		}
+		archive.Accounts = append(archive.Accounts, ArchivedAccount{
+			ID:           account.Id,
+			OrgName:      account.OrgName,
+			Username:     username,
+			Applications: archivedApplications,
+		})
+	}
+
+	return archive, nil
+}
+
+// ImportTenant creates the objects of archive in the tenant of tsClient. Objects that already exist
+// in the tenant are matched by name and kept, so an import that failed can be run again
+func ImportTenant(tsClient ThreeScaleInterface, accessToken string, archive *TenantArchive) error {
+	// metricIDs are the IDs of the imported metrics of the backends and services by their ID in the
+	// archive, the limits of the application plans can be on either
+	metricIDs := map[int]int{}
+	backendIDs, err := importBackends(tsClient, accessToken, archive.Backends, metricIDs)
+	if err != nil {
+		return err
+	}
+	planIDs, err := importServices(tsClient, accessToken, archive.Services, backendIDs, metricIDs)
+	if err != nil {
+		return err
+	}
+	return importAccounts(tsClient, accessToken, archive.Accounts, planIDs)
+}
+
+// importBackends returns the IDs of the imported backends by their ID in the archive
+func importBackends(tsClient ThreeScaleInterface, accessToken string, archived []ArchivedBackend, metricIDs map[int]int) (map[int]int, error) {
+	existing, err := listAllBackends(tsClient, accessToken)
+	if err != nil {
+		return nil, err
+	}
+	existingIDs := map[string]int{}
+	for _, backend := range existing {
+		existingIDs[backend.Name] = backend.ID
+	}
+
+	backendIDs := map[int]int{}
+	for _, backend := range archived {
+		backendID, ok := existingIDs[backend.Name]
+		if !ok {
+			backendID, err = tsClient.CreateBackend(accessToken, backend.Name, backend.PrivateEndpoint)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create backend %s: %w", backend.Name, err)
+			}
+		}
+		backendIDs[backend.ID] = backendID
+
+		metrics, err := tsClient.ListBackendMetrics(accessToken, backendID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list metrics of backend %s: %w", backend.Name, err)
+		}
+		err = importMetrics(metrics, backend.Metrics, metricIDs, func(metric Metric) (int, error) {
+			return tsClient.CreateMetric(accessToken, backendID, metric.FriendlyName, metric.Unit)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to import metrics of backend %s: %w", backend.Name, err)
+		}
+
+		rules, err := tsClient.ListBackendMappingRules(accessToken, backendID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list mapping rules of backend %s: %w", backend.Name, err)
+		}
+		err = importMappingRules(rules, backend.MappingRules, metricIDs, func(rule MappingRule, metricID int) error {
+			return tsClient.CreateBackendMappingRule(accessToken, backendID, metricID, rule.HTTPMethod, rule.Pattern, rule.Delta)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to import mapping rules of backend %s: %w", backend.Name, err)
+		}
+	}
+	return backendIDs, nil
+}
+
+// importMetrics records the IDs of the imported metrics in metricIDs by their ID in the archive.
+// Metrics 3scale creates with a backend or service, like hits, are matched by their friendly name
+func importMetrics(existing, archived []Metric, metricIDs map[int]int, create func(metric Metric) (int, error)) error {
+	existingIDs := map[string]int{}
+	for _, metric := range existing {
+		existingIDs[metric.FriendlyName] = metric.ID
+	}
+
+	for _, metric := range archived {
+		metricID, ok := existingIDs[metric.FriendlyName]
+		if !ok {
+			var err error
+			metricID, err = create(metric)
+			if err != nil {
+				return fmt.Errorf("failed to create metric %s: %w", metric.FriendlyName, err)
+			}
+		}
+		metricIDs[metric.ID] = metricID
+	}
+	return nil
+}
+
+func importMappingRules(existing, archived []MappingRule, metricIDs map[int]int, create func(rule MappingRule, metricID int) error) error {
+	existingRules := map[string]bool{}
+	for _, rule := range existing {
+		existingRules[rule.HTTPMethod+" "+rule.Pattern] = true
+	}
+
+	for _, rule := range archived {
+		if existingRules[rule.HTTPMethod+" "+rule.Pattern] {
+			continue
+		}
+		metricID, ok := metricIDs[rule.MetricID]
+		if !ok {
+			return fmt.Errorf("metric %d of mapping rule %s %s is not in the archive", rule.MetricID, rule.HTTPMethod, rule.Pattern)
+		}
+		if err := create(rule, metricID); err != nil {
+			return fmt.Errorf("failed to create mapping rule %s %s: %w", rule.HTTPMethod, rule.Pattern, err)
+		}
+	}
+	return nil
+}
+
+// importServices returns the IDs of the imported application plans by their ID in the archive
+func importServices(tsClient ThreeScaleInterface, accessToken string, archived []ArchivedService, backendIDs, metricIDs map[int]int) (map[int]string, error) {
+	existing, err := listAllServices(tsClient, accessToken)
+	if err != nil {
+		return nil, err
+	}
+	existingIDs := map[string]string{}
+	for _, service := range existing {
+		existingIDs[service.SystemName] = strconv.Itoa(service.ID)
+	}
+
+	planIDs := map[int]string{}
+	for _, service := range archived {
+		serviceID, ok := existingIDs[service.SystemName]
+		if !ok {
+			serviceID, err = tsClient.CreateService(accessToken, service.Name, service.SystemName)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create service %s: %w", service.SystemName, err)
+			}
+		}
+
+		metrics, err := tsClient.ListServiceMetrics(accessToken, serviceID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list metrics of service %s: %w", service.SystemName, err)
+		}
+		err = importMetrics(metrics, service.Metrics, metricIDs, func(metric Metric) (int, error) {
+			return tsClient.CreateServiceMetric(accessToken, serviceID, metric.FriendlyName, metric.Unit)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to import metrics of service %s: %w", service.SystemName, err)
+		}
+
+		rules, err := tsClient.ListServiceMappingRules(accessToken, serviceID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list mapping rules of service %s: %w", service.SystemName, err)
+		}
+		err = importMappingRules(rules, service.MappingRules, metricIDs, func(rule MappingRule, metricID int) error {
+			return tsClient.CreateServiceMappingRule(accessToken, serviceID, metricID, rule.HTTPMethod, rule.Pattern, rule.Delta)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to import mapping rules of service %s: %w", service.SystemName, err)
+		}
+
+		usages, err := tsClient.ListBackendUsages(accessToken, serviceID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list backend usages of service %s: %w", service.SystemName, err)
+		}
+		usedBackends := map[int]bool{}
+		for _, usage := range usages {
+			usedBackends[usage.BackendID] = true
+		}
+		for _, usage := range service.BackendUsages {
+			backendID, ok := backendIDs[usage.BackendID]
+			if !ok {
+				return nil, fmt.Errorf("backend %d used by service %s is not in the archive", usage.BackendID, service.SystemName)
+			}
+			if usedBackends[backendID] {
+				continue
+			}
+			if err := tsClient.CreateBackendUsage(accessToken, serviceID, backendID, usage.Path); err != nil {
+				return nil, fmt.Errorf("failed to add backend %d to service %s: %w", backendID, service.SystemName, err)
+			}
+		}
+
+		plans, err := tsClient.ListApplicationPlans(accessToken, serviceID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list application plans of service %s: %w", service.SystemName, err)
+		}
+		existingPlanIDs := map[string]string{}
+		for _, plan := range plans {
+			existingPlanIDs[plan.Name] = strconv.Itoa(plan.ID)
+		}
+		for _, plan := range service.ApplicationPlans {
+			planID, ok := existingPlanIDs[plan.Name]
+			if !ok {
+				planID, err = tsClient.CreateApplicationPlan(accessToken, serviceID, plan.Name)
+				if err != nil {
+					return nil, fmt.Errorf("failed to create application plan %s of service %s: %w", plan.Name, service.SystemName, err)
+				}
+			}
+			planIDs[plan.ID] = planID
+
+			if err := importLimits(tsClient, accessToken, planID, plan.Limits, metricIDs); err != nil {
+				return nil, fmt.Errorf("failed to import limits of application plan %s of service %s: %w", plan.Name, service.SystemName, err)
+			}
+		}
+	}
+	return planIDs, nil
+}
+
+// importLimits creates the limits of an application plan, limits of a metric and period that
+// already exist in the plan are kept
+func importLimits(tsClient ThreeScaleInterface, accessToken, planID string, archived []Limit, metricIDs map[int]int) error {
+	existing, err := tsClient.ListApplicationPlanLimits(accessToken, planID)
+	if err != nil {
+		return err
+	}
+	existingLimits := map[string]bool{}
+	for _, limit := range existing {
+		existingLimits[fmt.Sprintf("%d %s", limit.MetricID, limit.Period)] = true
+	}
+
+	for _, limit := range archived {
+		metricID, ok := metricIDs[limit.MetricID]
+		if !ok {
+			return fmt.Errorf("metric %d of the %s limit is not in the archive", limit.MetricID, limit.Period)
+		}
+		if existingLimits[fmt.Sprintf("%d %s", metricID, limit.Period)] {
+			continue
+		}
+		if err := tsClient.CreateApplicationPlanLimit(accessToken, planID, metricID, limit.Period, limit.Value); err != nil {
+			return fmt.Errorf("failed to create the %s limit of metric %d: %w", limit.Period, metricID, err)
+		}
+	}
+	return nil
+}
+
+func importAccounts(tsClient ThreeScaleInterface, accessToken string, archived []ArchivedAccount, planIDs map[int]string) error {
+	existing, err := listAllAccounts(tsClient, accessToken)
+	if err != nil {
+		return err
+	}
+	existingIDs := map[string]string{}
+	for _, account := range existing {
+		existingIDs[account.OrgName] = strconv.Itoa(account.Id)
+	}
+
+	for _, account := range archived {
+		accountID, ok := existingIDs[account.OrgName]
+		if !ok {
+			accountID, err = tsClient.CreateAccount(accessToken, account.OrgName, account.Username)
+			if err != nil {
+				return fmt.Errorf("failed to create account %s: %w", account.OrgName, err)
+			}
+		}
+
+		applications, err := listAllAccountApplications(tsClient, accessToken, accountID)
+		if err != nil {
+			return fmt.Errorf("failed to list applications of account %s: %w", account.OrgName, err)
+		}
+		existingApplications := map[string]bool{}
+		for _, application := range applications {
+			existingApplications[application.Name] = true
+		}
+		for _, application := range account.Applications {
+			if existingApplications[application.Name] {
+				continue
+			}
+			planID, ok := planIDs[application.PlanID]
+			if !ok {
+				return fmt.Errorf("plan %d of application %s is not in the archive", application.PlanID, application.Name)
+			}
+			if err := importApplication(tsClient, accessToken, accountID, planID, application); err != nil {
+				return fmt.Errorf("failed to create application %s of account %s: %w", application.Name, account.OrgName, err)
+			}
+		}
+	}
+	return nil
+}
+
+// importApplication creates application with its credentials, the first app key is created with
+// the application and the others are added to it
+func importApplication(tsClient ThreeScaleInterface, accessToken, accountID, planID string, application ArchivedApplication) error {
+	appKey := ""
+	if len(application.Keys) > 0 {
+		appKey = application.Keys[0]
+	}
+	applicationID, err := tsClient.CreateApplicationWithCredentials(accessToken, accountID, planID, application.Application, appKey)
+	if err != nil {
+		return err
+	}
+	for i := 1; i < len(application.Keys); i++ {
+		if err := tsClient.CreateApplicationKey(accessToken, accountID, applicationID, application.Keys[i]); err != nil {
+			return fmt.Errorf("failed to add key %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+// listAllServices lists the services of every page, the last page is the first one that isn't full
+func listAllServices(tsClient ThreeScaleInterface, accessToken string) ([]Service, error) {
+	var services []Service
+	for page := 1; ; page++ {
+		pageServices, err := tsClient.ListServices(accessToken, page)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list services: %w", err)
+		}
+		services = append(services, pageServices...)
+		if len(pageServices) < listPageSize {
+			return services, nil
+		}
+	}
+}
+
+func listAllBackends(tsClient ThreeScaleInterface, accessToken string) ([]Backend, error) {
+	var backends []Backend
+	for page := 1; ; page++ {
+		pageBackends, err := tsClient.ListBackends(accessToken, page)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list backends: %w", err)
+		}
+		backends = append(backends, pageBackends...)
+		if len(pageBackends) < listPageSize {
+			return backends, nil
+		}
+	}
+}
+
+func listAllAccounts(tsClient ThreeScaleInterface, accessToken string) ([]AccountDetail, error) {
+	var accounts []AccountDetail
+	for page := 1; ; page++ {
+		pageAccounts, err := tsClient.ListAccounts(accessToken, page)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list accounts: %w", err)
+		}
+		accounts = append(accounts, pageAccounts...)
+		if len(pageAccounts) < listPageSize {
+			return accounts, nil
+		}
+	}
+}
+
+func listAllAccountApplications(tsClient ThreeScaleInterface, accessToken, accountID string) ([]Application, error) {
+	var applications []Application
+	for page := 1; ; page++ {
+		pageApplications, err := tsClient.ListAccountApplications(accessToken, accountID, page)
+		if err != nil {
+			return nil, err
+		}
+		applications = append(applications, pageApplications...)
+		if len(pageApplications) < listPageSize {
+			return applications, nil
+		}
+	}
+}
+
+// Summary describes the number of objects in the archive
+func (a *TenantArchive) Summary() string {
+	applications := 0
+	for _, account := range a.Accounts {
+		applications += len(account.Applications)
+	}
+	return fmt.Sprintf("%d services, %d backends, %d accounts and %d applications", len(a.Services), len(a.Backends), len(a.Accounts), applications)
+}
+
+// Marshal returns the archive as gzipped JSON
+func (a *TenantArchive) Marshal() ([]byte, error) {
+	buf := &bytes.Buffer{}
+	zw := gzip.NewWriter(buf)
+	if err := json.NewEncoder(zw).Encode(a); err != nil {
+		return nil, fmt.Errorf("failed to encode tenant archive: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to compress tenant archive: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalTenantArchive reads an archive written by Marshal
+func UnmarshalTenantArchive(data []byte) (*TenantArchive, error) {
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress tenant archive: %w", err)
+	}
+	content, err := ioutil.ReadAll(zr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress tenant archive: %w", err)
+	}
+
+	archive := &TenantArchive{}
+	if err := json.Unmarshal(content, archive); err != nil {
+		return nil, fmt.Errorf("failed to decode tenant archive: %w", err)
+	}
+	if archive.Version != tenantArchiveVersion {
+		return nil, fmt.Errorf("unsupported tenant archive version %d", archive.Version)
+	}
+	return archive, nil
+}
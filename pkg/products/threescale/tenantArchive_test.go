@@ -0,0 +1,507 @@
+package threescale
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+const tenantArchiveTestToken = "tenant-token"
+
+// fakeTenant is an in memory 3scale tenant served by the admin API endpoints used by the tenant
+// export, import and quota
+type fakeTenant struct {
+	mu       sync.Mutex
+	nextID   int
+	services []Service
+	backends []Backend
+	// metrics and rules are the metrics and mapping rules of the backends and services by their ID
+	metrics      map[int][]Metric
+	rules        map[int][]MappingRule
+	usages       map[int][]BackendUsage
+	plans        map[int][]ApplicationPlan
+	limits       map[int][]Limit
+	accounts     []ArchivedAccount
+	applications map[int][]Application
+	keys         map[int][]string
+	users        []string
+}
+
+func newFakeTenant(firstID int) *fakeTenant {
+	return &fakeTenant{
+		nextID:       firstID,
+		metrics:      map[int][]Metric{},
+		rules:        map[int][]MappingRule{},
+		usages:       map[int][]BackendUsage{},
+		plans:        map[int][]ApplicationPlan{},
+		limits:       map[int][]Limit{},
+		applications: map[int][]Application{},
+		keys:         map[int][]string{},
+	}
+}
+
+func (f *fakeTenant) id() int {
+	f.nextID++
+	return f.nextID
+}
+
+func (f *fakeTenant) addBackend(name, endpoint string) int {
+	backend := Backend{ID: f.id(), Name: name, SystemName: name, PrivateEndpoint: endpoint}
+	f.backends = append(f.backends, backend)
+	// 3scale creates the hits metric with every backend
+	f.metrics[backend.ID] = []Metric{{ID: f.id(), FriendlyName: "Hits", SystemName: fmt.Sprintf("hits.%d", backend.ID), Unit: "hit"}}
+	return backend.ID
+}
+
+func (f *fakeTenant) addMetric(parentID int, friendlyName, unit string) int {
+	metric := Metric{ID: f.id(), FriendlyName: friendlyName, SystemName: friendlyName, Unit: unit}
+	f.metrics[parentID] = append(f.metrics[parentID], metric)
+	return metric.ID
+}
+
+func (f *fakeTenant) addService(name, systemName string) int {
+	service := Service{ID: f.id(), Name: name, SystemName: systemName}
+	f.services = append(f.services, service)
+	// 3scale creates the hits metric with every service
+	f.metrics[service.ID] = []Metric{{ID: f.id(), FriendlyName: "Hits", SystemName: "hits", Unit: "hit"}}
+	return service.ID
+}
+
+func (f *fakeTenant) addPlan(serviceID int, name string) int {
+	plan := ApplicationPlan{ID: f.id(), Name: name}
+	f.plans[serviceID] = append(f.plans[serviceID], plan)
+	return plan.ID
+}
+
+func (f *fakeTenant) addAccount(orgName, username string) int {
+	account := ArchivedAccount{ID: f.id(), OrgName: orgName, Username: username}
+	f.accounts = append(f.accounts, account)
+	return account.ID
+}
+
+func (f *fakeTenant) planServiceID(planID int) int {
+	for serviceID, plans := range f.plans {
+		for _, plan := range plans {
+			if plan.ID == planID {
+				return serviceID
+			}
+		}
+	}
+	return 0
+}
+
+var fakeTenantRoutes = regexp.MustCompile(`^/admin/api/(\w+)(?:/(\d+)/([\w/]+?)(?:/(\d+)/(\w+))?)?\.(json|xml)$`)
+
+func (f *fakeTenant) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	params := map[string]interface{}{}
+	if accessToken := r.URL.Query().Get("access_token"); accessToken != "" {
+		params["access_token"] = accessToken
+	} else if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if params["access_token"] != tenantArchiveTestToken {
+		http.Error(w, "invalid access token", http.StatusForbidden)
+		return
+	}
+	str := func(key string) string {
+		if value, ok := params[key]; ok {
+			return fmt.Sprint(value)
+		}
+		return ""
+	}
+	num := func(key string) int {
+		n, _ := params[key].(float64)
+		return int(n)
+	}
+	// paginate returns the items of the requested page, every item is on the first page when no
+	// page size is requested
+	paginate := func(items []interface{}) []interface{} {
+		page, perPage := num("page"), num("per_page")
+		if page == 0 {
+			page = 1
+		}
+		if perPage == 0 {
+			perPage = len(items)
+		}
+		start, end := (page-1)*perPage, page*perPage
+		if start >= len(items) {
+			return []interface{}{}
+		}
+		if end > len(items) {
+			end = len(items)
+		}
+		return items[start:end]
+	}
+
+	match := fakeTenantRoutes.FindStringSubmatch(r.URL.Path)
+	if match == nil {
+		http.NotFound(w, r)
+		return
+	}
+	parentID, _ := strconv.Atoi(match[2])
+	childID, _ := strconv.Atoi(match[4])
+	route := r.Method + " " + match[1]
+	if match[3] != "" {
+		route += "/" + match[3]
+	}
+	if match[5] != "" {
+		route += "/" + match[5]
+	}
+
+	var body interface{}
+	status := http.StatusOK
+	switch route {
+	case "GET services":
+		services := []interface{}{}
+		for _, s := range f.services {
+			services = append(services, map[string]interface{}{"service": s})
+		}
+		body = map[string]interface{}{"services": paginate(services)}
+	case "POST services":
+		status = http.StatusCreated
+		body = fmt.Sprintf("<service><id>%d</id></service>", f.addService(str("name"), str("system_name")))
+	case "GET backend_apis":
+		backends := []interface{}{}
+		for _, b := range f.backends {
+			backends = append(backends, map[string]interface{}{"backend_api": b})
+		}
+		body = map[string]interface{}{"backend_apis": paginate(backends)}
+	case "POST backend_apis":
+		status = http.StatusCreated
+		body = map[string]interface{}{"backend_api": map[string]int{"id": f.addBackend(str("name"), str("private_endpoint"))}}
+	case "GET backend_apis/metrics", "GET services/metrics":
+		metrics := []interface{}{}
+		for _, m := range f.metrics[parentID] {
+			metrics = append(metrics, map[string]interface{}{"metric": m})
+		}
+		body = map[string]interface{}{"metrics": metrics}
+	case "POST backend_apis/metrics", "POST services/metrics":
+		status = http.StatusCreated
+		body = map[string]interface{}{"metric": map[string]int{"id": f.addMetric(parentID, str("friendly_name"), str("unit"))}}
+	case "GET backend_apis/mapping_rules", "GET services/proxy/mapping_rules":
+		rules := []interface{}{}
+		for _, rule := range f.rules[parentID] {
+			rules = append(rules, map[string]interface{}{"mapping_rule": rule})
+		}
+		body = map[string]interface{}{"mapping_rules": rules}
+	case "POST backend_apis/mapping_rules", "POST services/proxy/mapping_rules":
+		status = http.StatusCreated
+		f.rules[parentID] = append(f.rules[parentID], MappingRule{
+			ID: f.id(), MetricID: num("metric_id"), HTTPMethod: str("http_method"), Pattern: str("pattern"), Delta: num("delta"),
+		})
+		body = map[string]interface{}{}
+	case "GET services/backend_usages":
+		usages := []interface{}{}
+		for _, u := range f.usages[parentID] {
+			usages = append(usages, map[string]interface{}{"backend_usage": u})
+		}
+		body = usages
+	case "POST services/backend_usages":
+		status = http.StatusCreated
+		f.usages[parentID] = append(f.usages[parentID], BackendUsage{ID: f.id(), BackendID: num("backend_api_id"), Path: str("path")})
+		body = map[string]interface{}{}
+	case "GET services/application_plans":
+		plans := []interface{}{}
+		for _, p := range f.plans[parentID] {
+			plans = append(plans, map[string]interface{}{"application_plan": p})
+		}
+		body = map[string]interface{}{"plans": plans}
+	case "POST services/application_plans":
+		status = http.StatusCreated
+		body = fmt.Sprintf("<plan><id>%d</id></plan>", f.addPlan(parentID, str("name")))
+	case "GET application_plans/limits":
+		limits := []interface{}{}
+		for _, l := range f.limits[parentID] {
+			limits = append(limits, map[string]interface{}{"limit": l})
+		}
+		body = map[string]interface{}{"limits": limits}
+	case "POST application_plans/metrics/limits":
+		status = http.StatusCreated
+		limit := Limit{ID: f.id(), MetricID: childID, Period: str("period"), Value: num("value")}
+		f.limits[parentID] = append(f.limits[parentID], limit)
+		body = map[string]interface{}{"limit": limit}
+	case "GET accounts":
+		accounts := []interface{}{}
+		for _, a := range f.accounts {
+			accounts = append(accounts, fmt.Sprintf("<account><id>%d</id><org_name>%s</org_name><users><user><username>%s</username></user></users></account>", a.ID, a.OrgName, a.Username))
+		}
+		body = "<accounts>" + fmt.Sprint(paginate(accounts)...) + "</accounts>"
+	case "POST signup":
+		status = http.StatusCreated
+		body = fmt.Sprintf("<account><id>%d</id></account>", f.addAccount(str("org_name"), str("username")))
+	case "GET accounts/applications":
+		applications := []interface{}{}
+		for _, a := range f.applications[parentID] {
+			applications = append(applications, map[string]interface{}{"application": a})
+		}
+		body = map[string]interface{}{"applications": paginate(applications)}
+	case "POST accounts/applications":
+		status = http.StatusCreated
+		planID, _ := strconv.Atoi(str("plan_id"))
+		application := Application{
+			ID:          f.id(),
+			Name:        str("name"),
+			Description: str("description"),
+			PlanID:      planID,
+			ServiceID:   f.planServiceID(planID),
+			UserKey:     str("user_key"),
+			AppID:       str("application_id"),
+		}
+		// 3scale generates the credentials of applications created without them
+		if application.UserKey == "" && application.AppID == "" {
+			application.UserKey = fmt.Sprintf("key-%d", application.ID)
+		}
+		if key := str("application_key"); key != "" {
+			f.keys[application.ID] = []string{key}
+		}
+		f.applications[parentID] = append(f.applications[parentID], application)
+		body = map[string]interface{}{"application": application}
+		if match[6] == "xml" {
+			body = fmt.Sprintf("<application><user_key>%s</user_key></application>", application.UserKey)
+		}
+	case "GET accounts/applications/keys":
+		keys := []interface{}{}
+		for _, key := range f.keys[childID] {
+			keys = append(keys, map[string]interface{}{"key": map[string]string{"value": key}})
+		}
+		body = map[string]interface{}{"keys": keys}
+	case "POST accounts/applications/keys":
+		status = http.StatusCreated
+		f.keys[childID] = append(f.keys[childID], str("key"))
+		body = map[string]interface{}{}
+	case "GET users":
+		users := []interface{}{}
+		for i, username := range f.users {
+			users = append(users, map[string]interface{}{"user": UserDetails{Id: i + 1, Username: username}})
+		}
+		body = map[string]interface{}{"users": users}
+	default:
+		http.NotFound(w, r)
+		return
+	}
+
+	w.WriteHeader(status)
+	if xmlBody, ok := body.(string); ok {
+		_, _ = w.Write([]byte(xmlBody))
+		return
+	}
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+// newFakeTenantClient returns a client of the admin API of tenant served by an httptest server
+func newFakeTenantClient(t *testing.T, tenant *fakeTenant) ThreeScaleInterface {
+	server := httptest.NewServer(tenant)
+	t.Cleanup(server.Close)
+	return NewTenantThreeScaleClient(server.Client(), server.URL)
+}
+
+func getSourceFakeTenant() *fakeTenant {
+	tenant := newFakeTenant(100)
+	backendID := tenant.addBackend("echo", "https://echo-api.3scale.net:443")
+	requestsID := tenant.addMetric(backendID, "Requests", "request")
+	tenant.rules[backendID] = []MappingRule{
+		{ID: tenant.id(), MetricID: tenant.metrics[backendID][0].ID, HTTPMethod: "GET", Pattern: "/", Delta: 1},
+		{ID: tenant.id(), MetricID: requestsID, HTTPMethod: "POST", Pattern: "/requests", Delta: 2},
+	}
+	serviceID := tenant.addService("Echo API", "echo_api")
+	searchesID := tenant.addMetric(serviceID, "Searches", "search")
+	tenant.rules[serviceID] = []MappingRule{
+		{ID: tenant.id(), MetricID: searchesID, HTTPMethod: "GET", Pattern: "/echo/search", Delta: 1},
+	}
+	tenant.usages[serviceID] = []BackendUsage{{ID: tenant.id(), BackendID: backendID, Path: "/echo"}}
+	basicID := tenant.addPlan(serviceID, "Basic")
+	tenant.limits[basicID] = []Limit{
+		{ID: tenant.id(), MetricID: tenant.metrics[serviceID][0].ID, Period: "day", Value: 1000},
+		{ID: tenant.id(), MetricID: requestsID, Period: "minute", Value: 10},
+	}
+	tenant.addPlan(serviceID, "Unlimited")
+	accountID := tenant.addAccount("Developer", "john")
+	keysAppID := tenant.id()
+	tenant.applications[accountID] = []Application{
+		{ID: tenant.id(), Name: "Echo App", Description: "echo", PlanID: basicID, ServiceID: serviceID, UserKey: "echo-user-key"},
+		{ID: keysAppID, Name: "Echo Keys App", Description: "echo", PlanID: basicID, ServiceID: serviceID, AppID: "echo-app-id"},
+	}
+	tenant.keys[keysAppID] = []string{"echo-key-1", "echo-key-2"}
+	return tenant
+}
+
+// describeFakeTenant lists the objects of tenant and the objects they link to by name, so tenants
+// can be compared regardless of their IDs
+func describeFakeTenant(tenant *fakeTenant) []string {
+	tenant.mu.Lock()
+	defer tenant.mu.Unlock()
+
+	var objects []string
+	metricNames := map[int]string{}
+	describeMetrics := func(parentName string, parentID int) {
+		for _, metric := range tenant.metrics[parentID] {
+			metricNames[metric.ID] = parentName + "/" + metric.FriendlyName
+			objects = append(objects, fmt.Sprintf("metric %s/%s %s", parentName, metric.FriendlyName, metric.Unit))
+		}
+		for _, rule := range tenant.rules[parentID] {
+			objects = append(objects, fmt.Sprintf("mapping rule %s %s %s %d %s", parentName, rule.HTTPMethod, rule.Pattern, rule.Delta, metricNames[rule.MetricID]))
+		}
+	}
+	backendNames := map[int]string{}
+	for _, backend := range tenant.backends {
+		backendNames[backend.ID] = backend.Name
+		objects = append(objects, fmt.Sprintf("backend %s %s", backend.Name, backend.PrivateEndpoint))
+		describeMetrics(backend.Name, backend.ID)
+	}
+	planNames := map[int]string{}
+	for _, service := range tenant.services {
+		objects = append(objects, fmt.Sprintf("service %s %s", service.Name, service.SystemName))
+		describeMetrics(service.SystemName, service.ID)
+		for _, usage := range tenant.usages[service.ID] {
+			objects = append(objects, fmt.Sprintf("backend usage %s %s %s", service.SystemName, backendNames[usage.BackendID], usage.Path))
+		}
+		for _, plan := range tenant.plans[service.ID] {
+			planNames[plan.ID] = service.SystemName + "/" + plan.Name
+			objects = append(objects, fmt.Sprintf("application plan %s/%s", service.SystemName, plan.Name))
+		}
+	}
+	for planID, limits := range tenant.limits {
+		for _, limit := range limits {
+			objects = append(objects, fmt.Sprintf("limit %s %s %s %d", planNames[planID], metricNames[limit.MetricID], limit.Period, limit.Value))
+		}
+	}
+	for _, account := range tenant.accounts {
+		objects = append(objects, fmt.Sprintf("account %s %s", account.OrgName, account.Username))
+		for _, application := range tenant.applications[account.ID] {
+			objects = append(objects, fmt.Sprintf("application %s %s %s %s %s %s %v", account.OrgName, application.Name, application.Description, planNames[application.PlanID],
+				application.UserKey, application.AppID, tenant.keys[application.ID]))
+		}
+	}
+	sort.Strings(objects)
+	return objects
+}
+
+func TestExportImportTenant(t *testing.T) {
+	source := getSourceFakeTenant()
+	target := newFakeTenant(500)
+	sourceClient := newFakeTenantClient(t, source)
+	targetClient := newFakeTenantClient(t, target)
+
+	exported, err := ExportTenant(sourceClient, tenantArchiveTestToken)
+	if err != nil {
+		t.Fatalf("unexpected error exporting tenant: %v", err)
+	}
+	if summary := exported.Summary(); summary != "1 services, 1 backends, 1 accounts and 2 applications" {
+		t.Fatalf("unexpected archive summary %q", summary)
+	}
+
+	data, err := exported.Marshal()
+	if err != nil {
+		t.Fatalf("unexpected error marshalling archive: %v", err)
+	}
+	archive, err := UnmarshalTenantArchive(data)
+	if err != nil {
+		t.Fatalf("unexpected error unmarshalling archive: %v", err)
+	}
+	if !reflect.DeepEqual(exported, archive) {
+		t.Fatalf("expected unmarshalled archive %+v, got %+v", exported, archive)
+	}
+
+	if err := ImportTenant(targetClient, tenantArchiveTestToken, archive); err != nil {
+		t.Fatalf("unexpected error importing tenant: %v", err)
+	}
+	expected := describeFakeTenant(source)
+	if imported := describeFakeTenant(target); !reflect.DeepEqual(expected, imported) {
+		t.Fatalf("expected imported tenant:\n%s\ngot:\n%s", strings.Join(expected, "\n"), strings.Join(imported, "\n"))
+	}
+
+	// An import that is run again keeps the objects that were imported
+	if err := ImportTenant(targetClient, tenantArchiveTestToken, archive); err != nil {
+		t.Fatalf("unexpected error importing tenant again: %v", err)
+	}
+	if imported := describeFakeTenant(target); !reflect.DeepEqual(expected, imported) {
+		t.Fatalf("expected tenant imported again:\n%s\ngot:\n%s", strings.Join(expected, "\n"), strings.Join(imported, "\n"))
+	}
+}
+
+func TestExportTenant_InvalidAccessToken(t *testing.T) {
+	tsClient := newFakeTenantClient(t, getSourceFakeTenant())
+
+	if _, err := ExportTenant(tsClient, "invalid-token"); err == nil || !strings.Contains(err.Error(), "failed to list backends") {
+		t.Fatalf("expected error listing backends, got %v", err)
+	}
+}
+
+func TestImportTenant_IncompleteArchive(t *testing.T) {
+	cases := []struct {
+		Name          string
+		Archive       *TenantArchive
+		ExpectedError string
+	}{
+		{
+			Name: "mapping rule of a metric not in the archive",
+			Archive: &TenantArchive{Backends: []ArchivedBackend{{
+				Backend:      Backend{ID: 1, Name: "echo"},
+				MappingRules: []MappingRule{{MetricID: 2, HTTPMethod: "GET", Pattern: "/"}},
+			}}},
+			ExpectedError: "metric 2 of mapping rule GET / is not in the archive",
+		},
+		{
+			Name: "service using a backend not in the archive",
+			Archive: &TenantArchive{Services: []ArchivedService{{
+				Service:       Service{ID: 1, Name: "echo", SystemName: "echo"},
+				BackendUsages: []BackendUsage{{BackendID: 2, Path: "/"}},
+			}}},
+			ExpectedError: "backend 2 used by service echo is not in the archive",
+		},
+		{
+			Name: "limit of a metric not in the archive",
+			Archive: &TenantArchive{Services: []ArchivedService{{
+				Service: Service{ID: 1, Name: "echo", SystemName: "echo"},
+				ApplicationPlans: []ArchivedApplicationPlan{{
+					ApplicationPlan: ApplicationPlan{ID: 2, Name: "Basic"},
+					Limits:          []Limit{{MetricID: 3, Period: "minute", Value: 10}},
+				}},
+			}}},
+			ExpectedError: "metric 3 of the minute limit is not in the archive",
+		},
+		{
+			Name: "application of a plan not in the archive",
+			Archive: &TenantArchive{Accounts: []ArchivedAccount{{
+				OrgName:      "Developer",
+				Username:     "john",
+				Applications: []ArchivedApplication{{Application: Application{Name: "Echo App", PlanID: 3}}},
+			}}},
+			ExpectedError: "plan 3 of application Echo App is not in the archive",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			tsClient := newFakeTenantClient(t, newFakeTenant(0))
+
+			err := ImportTenant(tsClient, tenantArchiveTestToken, tc.Archive)
+			if err == nil || !strings.Contains(err.Error(), tc.ExpectedError) {
+				t.Fatalf("expected error %q, got %v", tc.ExpectedError, err)
+			}
+		})
+	}
+}
+
+func TestUnmarshalTenantArchive_UnsupportedVersion(t *testing.T) {
+	data, err := (&TenantArchive{Version: tenantArchiveVersion + 1}).Marshal()
+	if err != nil {
+		t.Fatalf("unexpected error marshalling archive: %v", err)
+	}
+
+	if _, err := UnmarshalTenantArchive(data); err == nil || !strings.Contains(err.Error(), "unsupported tenant archive version") {
+		t.Fatalf("expected unsupported version error, got %v", err)
+	}
+}
@@ -0,0 +1,152 @@
+package threescale
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/apis/v1alpha1"
+	l "github.com/integr8ly/integreatly-operator/pkg/resources/logger"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Kinds of objects capped by the quota of a tenant
+const (
+	TenantQuotaServices     = "services"
+	TenantQuotaBackends     = "backends"
+	TenantQuotaApplications = "applications"
+	TenantQuotaUsers        = "users"
+)
+
+// TenantQuotaKinds are the kinds of objects capped by the quota of a tenant
+var TenantQuotaKinds = []string{TenantQuotaServices, TenantQuotaBackends, TenantQuotaApplications, TenantQuotaUsers}
+
+// ReconcileTenantQuota compares the objects in the 3scale account of tenantName to the quota of the
+// tenant, which is quota merged with the tenantQuota of the installation. The objects are counted
+// again through tsClient when the count in current is older than interval. It returns nil when the
+// tenant has no quota
+func (t *TenantAccountReconciler) ReconcileTenantQuota(ctx context.Context, serverClient k8sclient.Client, tsClient ThreeScaleInterface, tenantName string, quota *integreatlyv1alpha1.TenantQuota, current *integreatlyv1alpha1.TenantQuotaStatus, interval time.Duration) (*integreatlyv1alpha1.TenantQuotaStatus, error) {
+	tenantQuota := MergeTenantQuota(quota, t.r.installation.Spec.TenantQuota)
+	if tenantQuota == nil {
+		return nil, nil
+	}
+
+	status := &integreatlyv1alpha1.TenantQuotaStatus{Quota: *tenantQuota}
+	if current != nil && time.Since(current.LastCounted.Time) < interval {
+		status.Usage = current.Usage
+		status.LastCounted = current.LastCounted
+	} else {
+		accessToken, err := t.GetTenantAccessToken(ctx, serverClient, tenantName)
+		if err != nil {
+			return nil, err
+		}
+		usage, err := CountTenantObjects(tsClient, accessToken)
+		if err != nil {
+			return nil, fmt.Errorf("failed to count objects of tenant account %s: %w", tenantName, err)
+		}
+		status.Usage = usage
+		status.LastCounted = metav1.Now()
+	}
+
+	status.Exceeded = ExceededTenantQuota(status.Quota, status.Usage)
+	if len(status.Exceeded) > 0 {
+		t.r.log.Warningf("Tenant account is over quota", l.Fields{"tenantAccountName": tenantName, "exceeded": status.Exceeded})
+	}
+	return status, nil
+}
+
+// MergeTenantQuota returns quota with the caps it doesn't set taken from defaultQuota, it returns
+// nil when neither sets a cap
+func MergeTenantQuota(quota, defaultQuota *integreatlyv1alpha1.TenantQuota) *integreatlyv1alpha1.TenantQuota {
+	merged := quota.DeepCopy()
+	if merged == nil {
+		merged = &integreatlyv1alpha1.TenantQuota{}
+	}
+	if defaults := defaultQuota.DeepCopy(); defaults != nil {
+		if merged.Services == nil {
+			merged.Services = defaults.Services
+		}
+		if merged.Backends == nil {
+			merged.Backends = defaults.Backends
+		}
+		if merged.Applications == nil {
+			merged.Applications = defaults.Applications
+		}
+		if merged.Users == nil {
+			merged.Users = defaults.Users
+		}
+	}
+
+	if merged.Services == nil && merged.Backends == nil && merged.Applications == nil && merged.Users == nil {
+		return nil
+	}
+	return merged
+}
+
+// ExceededTenantQuota returns the kinds of objects usage has more of than quota allows
+func ExceededTenantQuota(quota integreatlyv1alpha1.TenantQuota, usage integreatlyv1alpha1.TenantObjectCounts) []string {
+	var exceeded []string
+	for _, kind := range TenantQuotaKinds {
+		limit, count := GetTenantQuotaLimit(quota, usage, kind)
+		if limit != nil && count > *limit {
+			exceeded = append(exceeded, kind)
+		}
+	}
+	return exceeded
+}
+
+// GetTenantQuotaLimit returns the cap of kind in quota, or nil when kind is not capped, and the
+// number of objects of kind in usage
+func GetTenantQuotaLimit(quota integreatlyv1alpha1.TenantQuota, usage integreatlyv1alpha1.TenantObjectCounts, kind string) (*int32, int32) {
+	switch kind {
+	case TenantQuotaServices:
+		return quota.Services, usage.Services
+	case TenantQuotaBackends:
+		return quota.Backends, usage.Backends
+	case TenantQuotaApplications:
+		return quota.Applications, usage.Applications
+	case TenantQuotaUsers:
+		return quota.Users, usage.Users
+	default:
+		return nil, 0
+	}
+}
+
+// CountTenantObjects counts the objects of each kind capped by a quota in the tenant of tsClient
+func CountTenantObjects(tsClient ThreeScaleInterface, accessToken string) (integreatlyv1alpha1.TenantObjectCounts, error) {
+	counts := integreatlyv1alpha1.TenantObjectCounts{}
+
+	services, err := listAllServices(tsClient, accessToken)
+	if err != nil {
+		return counts, err
+	}
+	counts.Services = int32(len(services))
+
+	backends, err := listAllBackends(tsClient, accessToken)
+	if err != nil {
+		return counts, err
+	}
+	counts.Backends = int32(len(backends))
+
+	accounts, err := listAllAccounts(tsClient, accessToken)
+	if err != nil {
+		return counts, err
+	}
+	for _, account := range accounts {
+		applications, err := listAllAccountApplications(tsClient, accessToken, strconv.Itoa(account.Id))
+		if err != nil {
+			return counts, fmt.Errorf("failed to list applications of account %s: %w", account.OrgName, err)
+		}
+		counts.Applications += int32(len(applications))
+	}
+
+	users, err := tsClient.GetUsers(accessToken)
+	if err != nil {
+		return counts, fmt.Errorf("failed to list users: %w", err)
+	}
+	counts.Users = int32(len(users.Users))
+
+	return counts, nil
+}
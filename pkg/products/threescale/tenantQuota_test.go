@@ -0,0 +1,240 @@
+package threescale
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/apis/v1alpha1"
+	"github.com/integr8ly/integreatly-operator/pkg/config"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func quotaCap(n int32) *int32 {
+	return &n
+}
+
+func TestMergeTenantQuota(t *testing.T) {
+	cases := []struct {
+		Name         string
+		Quota        *integreatlyv1alpha1.TenantQuota
+		DefaultQuota *integreatlyv1alpha1.TenantQuota
+		Expected     *integreatlyv1alpha1.TenantQuota
+	}{
+		{
+			Name: "no quota",
+		},
+		{
+			Name:         "quota without caps",
+			Quota:        &integreatlyv1alpha1.TenantQuota{},
+			DefaultQuota: &integreatlyv1alpha1.TenantQuota{},
+		},
+		{
+			Name:         "default quota",
+			DefaultQuota: &integreatlyv1alpha1.TenantQuota{Services: quotaCap(5), Users: quotaCap(10)},
+			Expected:     &integreatlyv1alpha1.TenantQuota{Services: quotaCap(5), Users: quotaCap(10)},
+		},
+		{
+			Name:         "tenant quota overrides the caps it sets",
+			Quota:        &integreatlyv1alpha1.TenantQuota{Services: quotaCap(20), Backends: quotaCap(0)},
+			DefaultQuota: &integreatlyv1alpha1.TenantQuota{Services: quotaCap(5), Users: quotaCap(10)},
+			Expected:     &integreatlyv1alpha1.TenantQuota{Services: quotaCap(20), Backends: quotaCap(0), Users: quotaCap(10)},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			merged := MergeTenantQuota(tc.Quota, tc.DefaultQuota)
+			if !reflect.DeepEqual(merged, tc.Expected) {
+				t.Fatalf("expected quota %+v, got %+v", tc.Expected, merged)
+			}
+			if merged != nil && tc.Quota != nil && tc.Quota.Services != nil && merged.Services == tc.Quota.Services {
+				t.Fatal("expected the merged quota to be a copy of the tenant quota")
+			}
+		})
+	}
+}
+
+func TestTenantAccountReconciler_ReconcileTenantQuota(t *testing.T) {
+	scheme, err := getBuildScheme()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tenant := getSourceFakeTenant()
+	tenant.addService("Other API", "other_api")
+	tenant.users = []string{"tenant", "john"}
+	tsClient := newFakeTenantClient(t, tenant)
+	countedUsage := integreatlyv1alpha1.TenantObjectCounts{Services: 2, Backends: 1, Applications: 2, Users: 2}
+
+	lastCounted := metav1.NewTime(time.Now().Add(-time.Minute).Truncate(time.Second))
+	staleCount := metav1.NewTime(time.Now().Add(-time.Hour).Truncate(time.Second))
+
+	cases := []struct {
+		Name         string
+		Quota        *integreatlyv1alpha1.TenantQuota
+		DefaultQuota *integreatlyv1alpha1.TenantQuota
+		Current      *integreatlyv1alpha1.TenantQuotaStatus
+		AccessTokens map[string][]byte
+		Expected     *integreatlyv1alpha1.TenantQuotaStatus
+		Counted      bool
+	}{
+		{
+			Name:         "tenant without quota",
+			AccessTokens: map[string][]byte{"tenant": []byte(tenantArchiveTestToken)},
+		},
+		{
+			Name:         "objects are counted",
+			Quota:        &integreatlyv1alpha1.TenantQuota{Services: quotaCap(1)},
+			DefaultQuota: &integreatlyv1alpha1.TenantQuota{Services: quotaCap(10), Applications: quotaCap(0), Users: quotaCap(2)},
+			AccessTokens: map[string][]byte{"tenant": []byte(tenantArchiveTestToken)},
+			Expected: &integreatlyv1alpha1.TenantQuotaStatus{
+				Quota:    integreatlyv1alpha1.TenantQuota{Services: quotaCap(1), Applications: quotaCap(0), Users: quotaCap(2)},
+				Usage:    countedUsage,
+				Exceeded: []string{TenantQuotaServices, TenantQuotaApplications},
+			},
+			Counted: true,
+		},
+		{
+			Name:  "recent count is compared to the current quota",
+			Quota: &integreatlyv1alpha1.TenantQuota{Backends: quotaCap(2)},
+			Current: &integreatlyv1alpha1.TenantQuotaStatus{
+				Quota:       integreatlyv1alpha1.TenantQuota{Backends: quotaCap(5)},
+				Usage:       integreatlyv1alpha1.TenantObjectCounts{Backends: 3},
+				LastCounted: lastCounted,
+			},
+			// the account is not called, the access token would be needed to count its objects
+			AccessTokens: map[string][]byte{},
+			Expected: &integreatlyv1alpha1.TenantQuotaStatus{
+				Quota:       integreatlyv1alpha1.TenantQuota{Backends: quotaCap(2)},
+				Usage:       integreatlyv1alpha1.TenantObjectCounts{Backends: 3},
+				Exceeded:    []string{TenantQuotaBackends},
+				LastCounted: lastCounted,
+			},
+		},
+		{
+			Name:  "stale count is replaced",
+			Quota: &integreatlyv1alpha1.TenantQuota{Backends: quotaCap(2)},
+			Current: &integreatlyv1alpha1.TenantQuotaStatus{
+				Quota:       integreatlyv1alpha1.TenantQuota{Backends: quotaCap(2)},
+				Usage:       integreatlyv1alpha1.TenantObjectCounts{Backends: 3},
+				Exceeded:    []string{TenantQuotaBackends},
+				LastCounted: staleCount,
+			},
+			AccessTokens: map[string][]byte{"tenant": []byte(tenantArchiveTestToken)},
+			Expected: &integreatlyv1alpha1.TenantQuotaStatus{
+				Quota: integreatlyv1alpha1.TenantQuota{Backends: quotaCap(2)},
+				Usage: countedUsage,
+			},
+			Counted: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			serverClient := fake.NewFakeClientWithScheme(scheme, []runtime.Object{
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Name: "mt-signupaccount-3scale-access-token", Namespace: tenantAccountTestNamespace},
+					Data:       tc.AccessTokens,
+				},
+			}...)
+			configManager := &config.ConfigReadWriterMock{
+				ReadThreeScaleFunc: func() (*config.ThreeScale, error) {
+					return config.NewThreeScale(config.ProductConfig{"NAMESPACE": tenantAccountTestNamespace}), nil
+				},
+			}
+			installation := getTestInstallation("multitenant-managed-api")
+			installation.Spec.TenantQuota = tc.DefaultQuota
+			accounts, err := NewTenantAccountReconciler(configManager, installation, tsClient, getLogger())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			status, err := accounts.ReconcileTenantQuota(context.TODO(), serverClient, tsClient, "tenant", tc.Quota, tc.Current, time.Minute*10)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if status == nil || tc.Expected == nil {
+				if status != tc.Expected {
+					t.Fatalf("expected quota status %+v, got %+v", tc.Expected, status)
+				}
+				return
+			}
+			if tc.Counted {
+				if time.Since(status.LastCounted.Time) > time.Minute {
+					t.Fatalf("expected objects to be counted now, last counted %v", status.LastCounted)
+				}
+				tc.Expected.LastCounted = status.LastCounted
+			}
+			if !reflect.DeepEqual(status, tc.Expected) {
+				t.Fatalf("expected quota status %+v, got %+v", tc.Expected, status)
+			}
+		})
+	}
+}
+
+func TestCountTenantObjects(t *testing.T) {
+	// pages returns the length of a page of a list of count objects
+	pages := func(page, count int) int {
+		if n := count - (page-1)*listPageSize; n < listPageSize {
+			if n < 0 {
+				return 0
+			}
+			return n
+		}
+		return listPageSize
+	}
+	tsClient := &ThreeScaleInterfaceMock{
+		ListServicesFunc: func(accessToken string, page int) ([]Service, error) {
+			return make([]Service, pages(page, listPageSize+2)), nil
+		},
+		ListBackendsFunc: func(accessToken string, page int) ([]Backend, error) {
+			return make([]Backend, pages(page, 1)), nil
+		},
+		ListAccountsFunc: func(accessToken string, page int) ([]AccountDetail, error) {
+			accounts := make([]AccountDetail, pages(page, listPageSize+1))
+			for i := range accounts {
+				accounts[i].Id = (page-1)*listPageSize + i + 1
+			}
+			return accounts, nil
+		},
+		ListAccountApplicationsFunc: func(accessToken string, accountID string, page int) ([]Application, error) {
+			// the first account has exactly one full page of applications
+			if accountID == "1" {
+				return make([]Application, pages(page, listPageSize)), nil
+			}
+			return make([]Application, pages(page, 1)), nil
+		},
+		GetUsersFunc: func(accessToken string) (*Users, error) {
+			return &Users{Users: []*User{{}, {}}}, nil
+		},
+	}
+
+	counts, err := CountTenantObjects(tsClient, tenantArchiveTestToken)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := integreatlyv1alpha1.TenantObjectCounts{
+		Services:     listPageSize + 2,
+		Backends:     1,
+		Applications: 2 * listPageSize,
+		Users:        2,
+	}
+	if counts != expected {
+		t.Fatalf("expected counts %+v, got %+v", expected, counts)
+	}
+	if calls := len(tsClient.ListServicesCalls()); calls != 2 {
+		t.Errorf("expected 2 pages of services to be listed, got %d", calls)
+	}
+	if calls := len(tsClient.ListAccountsCalls()); calls != 2 {
+		t.Errorf("expected 2 pages of accounts to be listed, got %d", calls)
+	}
+	// the account with a full page of applications is listed until its empty page
+	if calls := len(tsClient.ListAccountApplicationsCalls()); calls != listPageSize+2 {
+		t.Errorf("expected %d pages of applications to be listed, got %d", listPageSize+2, calls)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/antchfx/xmlquery"
@@ -37,9 +38,27 @@ type ThreeScaleInterface interface {
 	CreateBackendUsage(accessToken, serviceID string, backendID int, path string) error
 	CreateApplicationPlan(accessToken, serviceID, name string) (string, error)
 	CreateApplication(accessToken, accountID, planID, name, description string) (string, error)
+	CreateServiceMetric(accessToken, serviceID, friendlyName, unit string) (int, error)
+	CreateServiceMappingRule(accessToken, serviceID string, metricID int, httpMethod, pattern string, delta int) error
+	CreateApplicationPlanLimit(accessToken, planID string, metricID int, period string, value int) error
+	CreateApplicationWithCredentials(accessToken, accountID, planID string, application Application, appKey string) (int, error)
+	CreateApplicationKey(accessToken, accountID string, applicationID int, key string) error
 	DeployProxy(accessToken, serviceID string) error
 	PromoteProxy(accessToken, serviceID, env, to string) (string, error)
 
+	ListServices(accessToken string, page int) ([]Service, error)
+	ListBackends(accessToken string, page int) ([]Backend, error)
+	ListBackendMetrics(accessToken string, backendID int) ([]Metric, error)
+	ListBackendMappingRules(accessToken string, backendID int) ([]MappingRule, error)
+	ListBackendUsages(accessToken, serviceID string) ([]BackendUsage, error)
+	ListApplicationPlans(accessToken, serviceID string) ([]ApplicationPlan, error)
+	ListAccounts(accessToken string, page int) ([]AccountDetail, error)
+	ListAccountApplications(accessToken, accountID string, page int) ([]Application, error)
+	ListServiceMetrics(accessToken, serviceID string) ([]Metric, error)
+	ListServiceMappingRules(accessToken, serviceID string) ([]MappingRule, error)
+	ListApplicationPlanLimits(accessToken, planID string) ([]Limit, error)
+	ListApplicationKeys(accessToken, accountID string, applicationID int) ([]string, error)
+
 	DeleteService(accessToken, serviceID string) error
 	DeleteBackend(accessToken string, backendID int) error
 	DeleteAccount(accessToken, accountID string) error
@@ -49,6 +68,8 @@ type ThreeScaleInterface interface {
 	GetTenantAccount(accessToken string, id int) (*SignUpAccount, error)
 	DeleteTenant(accessToken string, id int) error
 	DeleteTenants(accessToken string, accounts []AccountDetail) error
+	SuspendTenant(accessToken string, id int) error
+	ResumeTenant(accessToken string, id int) error
 
 	ActivateUser(accessToken string, accountId, userId int) error
 	AddAuthProviderToAccount(accessToken string, account AccountDetail, authProviderDetail AuthProviderDetails) error
@@ -58,12 +79,18 @@ type ThreeScaleInterface interface {
 const (
 	adminRole  = "admin"
 	memberRole = "member"
+
+	// listPageSize is the number of objects requested in each page of the lists of the admin API,
+	// the largest page 3scale returns
+	listPageSize = 500
 )
 
 type threeScaleClient struct {
 	httpc          *http.Client
 	wildCardDomain string
 	ns             string
+	// adminBaseURL is the admin portal of the tenant the client calls, it is empty for the default tenant
+	adminBaseURL string
 }
 
 var _ ThreeScaleInterface = &threeScaleClient{}
@@ -76,6 +103,15 @@ func NewThreeScaleClient(httpc *http.Client, wildCardDomain string) *threeScaleC
 	}
 }
 
+// NewTenantThreeScaleClient returns a client for the admin API of the tenant whose admin portal is
+// adminBaseURL. Requests to the master API are not supported by the client
+func NewTenantThreeScaleClient(httpc *http.Client, adminBaseURL string) *threeScaleClient {
+	return &threeScaleClient{
+		httpc:        httpc,
+		adminBaseURL: adminBaseURL,
+	}
+}
+
 func (tsc *threeScaleClient) SetNamespace(ns string) {
 	tsc.ns = ns
 }
@@ -88,7 +124,7 @@ func (tsc *threeScaleClient) AddAuthenticationProvider(data map[string]string, a
 	}
 	tsc.httpc.Timeout = time.Second * 10
 	res, err := tsc.httpc.Post(
-		fmt.Sprintf("https://3scale-admin.%s/admin/api/account/authentication_providers.json", tsc.wildCardDomain),
+		tsc.adminAPIURL("account/authentication_providers.json"),
 		"application/json",
 		bytes.NewBuffer(reqData),
 	)
@@ -101,7 +137,7 @@ func (tsc *threeScaleClient) AddAuthenticationProvider(data map[string]string, a
 
 func (tsc *threeScaleClient) GetAuthenticationProviders(accessToken string) (*AuthProviders, error) {
 	res, err := tsc.httpc.Get(
-		fmt.Sprintf("https://3scale-admin.%s/admin/api/account/authentication_providers.json?access_token=%s", tsc.wildCardDomain, accessToken),
+		tsc.adminAPIURL(fmt.Sprintf("account/authentication_providers.json?access_token=%s", accessToken)),
 	)
 	if err != nil {
 		return nil, err
@@ -148,7 +184,7 @@ func (tsc *threeScaleClient) GetUser(username, accessToken string) (*User, error
 
 func (tsc *threeScaleClient) GetUsers(accessToken string) (*Users, error) {
 	res, err := tsc.httpc.Get(
-		fmt.Sprintf("https://3scale-admin.%s/admin/api/users.json?access_token=%s", tsc.wildCardDomain, accessToken),
+		tsc.adminAPIURL(fmt.Sprintf("users.json?access_token=%s", accessToken)),
 	)
 	if err != nil {
 		return nil, err
@@ -172,7 +208,7 @@ func (tsc *threeScaleClient) SetFromEmailAddress(emailAddress string, accessToke
 		"access_token": accessToken,
 		"from_email":   emailAddress,
 	})
-	url := fmt.Sprintf("https://3scale-admin.%s/admin/api/provider.xml", tsc.wildCardDomain)
+	url := tsc.adminAPIURL("provider.xml")
 	req, err := http.NewRequest(
 		"PUT",
 		url,
@@ -204,7 +240,7 @@ func (tsc *threeScaleClient) AddUser(username string, email string, password str
 	reqData, err := json.Marshal(data)
 
 	res, err := tsc.httpc.Post(
-		fmt.Sprintf("https://3scale-admin.%s/admin/api/users.json", tsc.wildCardDomain),
+		tsc.adminAPIURL("users.json"),
 		"application/json",
 		bytes.NewBuffer(reqData),
 	)
@@ -222,7 +258,7 @@ func (tsc *threeScaleClient) DeleteUser(userID int, accessToken string) (*http.R
 
 	req, err := http.NewRequest(
 		http.MethodDelete,
-		fmt.Sprintf("https://3scale-admin.%s/admin/api/users/%d.json", tsc.wildCardDomain, userID),
+		tsc.adminAPIURL(fmt.Sprintf("users/%d.json", userID)),
 		bytes.NewBuffer(reqData))
 	req.Header.Add("Content-type", "application/json")
 	tsc.httpc.Timeout = time.Second * 10
@@ -238,7 +274,7 @@ func (tsc *threeScaleClient) SetUserAsAdmin(userID int, accessToken string) (*ht
 	data, err := json.Marshal(map[string]string{
 		"access_token": accessToken,
 	})
-	url := fmt.Sprintf("https://3scale-admin.%s/admin/api/users/%d/admin.json", tsc.wildCardDomain, userID)
+	url := tsc.adminAPIURL(fmt.Sprintf("users/%d/admin.json", userID))
 	req, err := http.NewRequest(
 		"PUT",
 		url,
@@ -260,7 +296,7 @@ func (tsc *threeScaleClient) SetUserAsMember(userID int, accessToken string) (*h
 	data, err := json.Marshal(map[string]string{
 		"access_token": accessToken,
 	})
-	url := fmt.Sprintf("https://3scale-admin.%s/admin/api/users/%d/member.json", tsc.wildCardDomain, userID)
+	url := tsc.adminAPIURL(fmt.Sprintf("users/%d/member.json", userID))
 	req, err := http.NewRequest(
 		"PUT",
 		url,
@@ -283,7 +319,7 @@ func (tsc *threeScaleClient) UpdateUser(userID int, username string, email strin
 		"username":     username,
 		"email":        email,
 	})
-	url := fmt.Sprintf("https://3scale-admin.%s/admin/api/users/%d.json", tsc.wildCardDomain, userID)
+	url := tsc.adminAPIURL(fmt.Sprintf("users/%d.json", userID))
 	req, err := http.NewRequest(
 		"PUT",
 		url,
@@ -397,7 +433,7 @@ func (tsc *threeScaleClient) CreateService(accessToken, name, systemName string)
 		"services.xml",
 		withAccessToken(accessToken, map[string]interface{}{
 			"name":        name,
-			"system_name": name,
+			"system_name": systemName,
 		}),
 	)
 	if err != nil {
@@ -466,6 +502,123 @@ func (tsc *threeScaleClient) CreateApplication(accessToken, accountID, planID, n
 	return xmlFromResponse(res, "//application/user_key/text()")
 }
 
+func (tsc *threeScaleClient) CreateServiceMetric(accessToken, serviceID, friendlyName, unit string) (int, error) {
+	res, err := tsc.makeRequest(
+		"POST",
+		fmt.Sprintf("services/%s/metrics.json", serviceID),
+		withAccessToken(accessToken, map[string]interface{}{
+			"friendly_name": friendlyName,
+			"unit":          unit,
+		}),
+	)
+	if err != nil {
+		return 0, err
+	}
+	if err := assertStatusCode(http.StatusCreated, res); err != nil {
+		return 0, err
+	}
+
+	responseBody := &struct {
+		Metric struct {
+			ID int `json:"id"`
+		} `json:"metric"`
+	}{}
+	if err := jsonFromResponse(res, responseBody); err != nil {
+		return 0, err
+	}
+
+	return responseBody.Metric.ID, nil
+}
+
+func (tsc *threeScaleClient) CreateServiceMappingRule(accessToken, serviceID string, metricID int, httpMethod, pattern string, delta int) error {
+	res, err := tsc.makeRequest(
+		"POST",
+		fmt.Sprintf("services/%s/proxy/mapping_rules.json", serviceID),
+		withAccessToken(accessToken, map[string]interface{}{
+			"http_method": httpMethod,
+			"pattern":     pattern,
+			"delta":       delta,
+			"metric_id":   metricID,
+		}),
+	)
+	if err != nil {
+		return err
+	}
+
+	return assertStatusCode(http.StatusCreated, res)
+}
+
+// CreateApplicationPlanLimit limits the usage of the metric metricID, of the service or of one of
+// its backends, by the applications of the plan planID
+func (tsc *threeScaleClient) CreateApplicationPlanLimit(accessToken, planID string, metricID int, period string, value int) error {
+	res, err := tsc.makeRequest(
+		"POST",
+		fmt.Sprintf("application_plans/%s/metrics/%d/limits.json", planID, metricID),
+		withAccessToken(accessToken, map[string]interface{}{
+			"period": period,
+			"value":  value,
+		}),
+	)
+	if err != nil {
+		return err
+	}
+
+	return assertStatusCode(http.StatusCreated, res)
+}
+
+// CreateApplicationWithCredentials creates application with its user key, or with its app ID and
+// appKey, so the clients of the application keep working with the credentials they have
+func (tsc *threeScaleClient) CreateApplicationWithCredentials(accessToken, accountID, planID string, application Application, appKey string) (int, error) {
+	data := map[string]interface{}{
+		"plan_id":     planID,
+		"name":        application.Name,
+		"description": application.Description,
+	}
+	if application.UserKey != "" {
+		data["user_key"] = application.UserKey
+	}
+	if application.AppID != "" {
+		data["application_id"] = application.AppID
+	}
+	if appKey != "" {
+		data["application_key"] = appKey
+	}
+
+	res, err := tsc.makeRequest("POST", fmt.Sprintf("accounts/%s/applications.json", accountID), withAccessToken(accessToken, data))
+	if err != nil {
+		return 0, err
+	}
+	if err := assertStatusCode(http.StatusCreated, res); err != nil {
+		return 0, err
+	}
+
+	responseBody := &struct {
+		Application struct {
+			ID int `json:"id"`
+		} `json:"application"`
+	}{}
+	if err := jsonFromResponse(res, responseBody); err != nil {
+		return 0, err
+	}
+
+	return responseBody.Application.ID, nil
+}
+
+func (tsc *threeScaleClient) CreateApplicationKey(accessToken, accountID string, applicationID int, key string) error {
+	res, err := tsc.makeRequest(
+		"POST",
+		fmt.Sprintf("accounts/%s/applications/%d/keys.json", accountID, applicationID),
+		withAccessToken(accessToken, map[string]interface{}{
+			"key": key,
+		}),
+	)
+	if err != nil {
+		return err
+	}
+
+	return assertStatusCode(http.StatusCreated, res)
+}
+
 func (tsc *threeScaleClient) DeployProxy(accessToken, serviceID string) error {
 	res, err := tsc.makeRequest(
 		"POST",
@@ -481,7 +634,7 @@ func (tsc *threeScaleClient) DeployProxy(accessToken, serviceID string) error {
 }
 
 func (tsc *threeScaleClient) PromoteProxy(accessToken, serviceID, env, to string) (string, error) {
-	res, err := tsc.httpc.Get(fmt.Sprintf("https://3scale-admin.%s/admin/api/services/%s/proxy/configs/%s/latest.json?access_token=%s", tsc.wildCardDomain, serviceID, env, accessToken))
+	res, err := tsc.httpc.Get(tsc.adminAPIURL(fmt.Sprintf("services/%s/proxy/configs/%s/latest.json?access_token=%s", serviceID, env, accessToken)))
 	if err != nil {
 		return "", err
 	}
@@ -525,6 +678,342 @@ func (tsc *threeScaleClient) PromoteProxy(accessToken, serviceID, env, to string
 	return proxyConfigResponse.ProxyConfig.Content.Proxy.Endpoint, nil
 }
 
+// ListServices returns a page of the services of the tenant, pages have listPageSize services
+func (tsc *threeScaleClient) ListServices(accessToken string, page int) ([]Service, error) {
+	res, err := tsc.makeRequest("GET", "services.json", withAccessToken(accessToken, pageParameters(page)))
+	if err != nil {
+		return nil, err
+	}
+	if err := assertStatusCode(http.StatusOK, res); err != nil {
+		return nil, err
+	}
+
+	responseBody := &struct {
+		Services []struct {
+			Service Service `json:"service"`
+		} `json:"services"`
+	}{}
+	if err := jsonFromResponse(res, responseBody); err != nil {
+		return nil, err
+	}
+
+	services := make([]Service, 0, len(responseBody.Services))
+	for _, s := range responseBody.Services {
+		services = append(services, s.Service)
+	}
+	return services, nil
+}
+
+// ListBackends returns a page of the backends of the tenant, pages have listPageSize backends
+func (tsc *threeScaleClient) ListBackends(accessToken string, page int) ([]Backend, error) {
+	res, err := tsc.makeRequest("GET", "backend_apis.json", withAccessToken(accessToken, pageParameters(page)))
+	if err != nil {
+		return nil, err
+	}
+	if err := assertStatusCode(http.StatusOK, res); err != nil {
+		return nil, err
+	}
+
+	responseBody := &struct {
+		BackendAPIs []struct {
+			BackendAPI Backend `json:"backend_api"`
+		} `json:"backend_apis"`
+	}{}
+	if err := jsonFromResponse(res, responseBody); err != nil {
+		return nil, err
+	}
+
+	backends := make([]Backend, 0, len(responseBody.BackendAPIs))
+	for _, b := range responseBody.BackendAPIs {
+		backends = append(backends, b.BackendAPI)
+	}
+	return backends, nil
+}
+
+func (tsc *threeScaleClient) ListBackendMetrics(accessToken string, backendID int) ([]Metric, error) {
+	res, err := tsc.makeRequest(
+		"GET",
+		fmt.Sprintf("backend_apis/%d/metrics.json", backendID),
+		onlyAccessToken(accessToken),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := assertStatusCode(http.StatusOK, res); err != nil {
+		return nil, err
+	}
+
+	responseBody := &struct {
+		Metrics []struct {
+			Metric Metric `json:"metric"`
+		} `json:"metrics"`
+	}{}
+	if err := jsonFromResponse(res, responseBody); err != nil {
+		return nil, err
+	}
+
+	metrics := make([]Metric, 0, len(responseBody.Metrics))
+	for _, m := range responseBody.Metrics {
+		metrics = append(metrics, m.Metric)
+	}
+	return metrics, nil
+}
+
+func (tsc *threeScaleClient) ListBackendMappingRules(accessToken string, backendID int) ([]MappingRule, error) {
+	res, err := tsc.makeRequest(
+		"GET",
+		fmt.Sprintf("backend_apis/%d/mapping_rules.json", backendID),
+		onlyAccessToken(accessToken),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := assertStatusCode(http.StatusOK, res); err != nil {
+		return nil, err
+	}
+
+	responseBody := &struct {
+		MappingRules []struct {
+			MappingRule MappingRule `json:"mapping_rule"`
+		} `json:"mapping_rules"`
+	}{}
+	if err := jsonFromResponse(res, responseBody); err != nil {
+		return nil, err
+	}
+
+	rules := make([]MappingRule, 0, len(responseBody.MappingRules))
+	for _, r := range responseBody.MappingRules {
+		rules = append(rules, r.MappingRule)
+	}
+	return rules, nil
+}
+
+func (tsc *threeScaleClient) ListBackendUsages(accessToken, serviceID string) ([]BackendUsage, error) {
+	res, err := tsc.makeRequest(
+		"GET",
+		fmt.Sprintf("services/%s/backend_usages.json", serviceID),
+		onlyAccessToken(accessToken),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := assertStatusCode(http.StatusOK, res); err != nil {
+		return nil, err
+	}
+
+	responseBody := []struct {
+		BackendUsage BackendUsage `json:"backend_usage"`
+	}{}
+	if err := jsonFromResponse(res, &responseBody); err != nil {
+		return nil, err
+	}
+
+	usages := make([]BackendUsage, 0, len(responseBody))
+	for _, u := range responseBody {
+		usages = append(usages, u.BackendUsage)
+	}
+	return usages, nil
+}
+
+func (tsc *threeScaleClient) ListApplicationPlans(accessToken, serviceID string) ([]ApplicationPlan, error) {
+	res, err := tsc.makeRequest(
+		"GET",
+		fmt.Sprintf("services/%s/application_plans.json", serviceID),
+		onlyAccessToken(accessToken),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := assertStatusCode(http.StatusOK, res); err != nil {
+		return nil, err
+	}
+
+	responseBody := &struct {
+		Plans []struct {
+			ApplicationPlan ApplicationPlan `json:"application_plan"`
+		} `json:"plans"`
+	}{}
+	if err := jsonFromResponse(res, responseBody); err != nil {
+		return nil, err
+	}
+
+	plans := make([]ApplicationPlan, 0, len(responseBody.Plans))
+	for _, p := range responseBody.Plans {
+		plans = append(plans, p.ApplicationPlan)
+	}
+	return plans, nil
+}
+
+// ListAccounts returns a page of the developer accounts of the tenant, pages have listPageSize
+// accounts
+func (tsc *threeScaleClient) ListAccounts(accessToken string, page int) ([]AccountDetail, error) {
+	res, err := tsc.makeRequest("GET", "accounts.xml", withAccessToken(accessToken, pageParameters(page)))
+	if err != nil {
+		return nil, err
+	}
+	if err := assertStatusCode(http.StatusOK, res); err != nil {
+		return nil, err
+	}
+
+	accountList := XMLAccountList{}
+	if err := responseFromXML(res, &accountList); err != nil {
+		return nil, err
+	}
+
+	return accountList.Accounts, nil
+}
+
+// ListAccountApplications returns a page of the applications of the account accountID, pages have
+// listPageSize applications
+func (tsc *threeScaleClient) ListAccountApplications(accessToken, accountID string, page int) ([]Application, error) {
+	res, err := tsc.makeRequest(
+		"GET",
+		fmt.Sprintf("accounts/%s/applications.json", accountID),
+		withAccessToken(accessToken, pageParameters(page)),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := assertStatusCode(http.StatusOK, res); err != nil {
+		return nil, err
+	}
+
+	responseBody := &struct {
+		Applications []struct {
+			Application Application `json:"application"`
+		} `json:"applications"`
+	}{}
+	if err := jsonFromResponse(res, responseBody); err != nil {
+		return nil, err
+	}
+
+	applications := make([]Application, 0, len(responseBody.Applications))
+	for _, a := range responseBody.Applications {
+		applications = append(applications, a.Application)
+	}
+	return applications, nil
+}
+
+func (tsc *threeScaleClient) ListServiceMetrics(accessToken, serviceID string) ([]Metric, error) {
+	res, err := tsc.makeRequest(
+		"GET",
+		fmt.Sprintf("services/%s/metrics.json", serviceID),
+		onlyAccessToken(accessToken),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := assertStatusCode(http.StatusOK, res); err != nil {
+		return nil, err
+	}
+
+	responseBody := &struct {
+		Metrics []struct {
+			Metric Metric `json:"metric"`
+		} `json:"metrics"`
+	}{}
+	if err := jsonFromResponse(res, responseBody); err != nil {
+		return nil, err
+	}
+
+	metrics := make([]Metric, 0, len(responseBody.Metrics))
+	for _, m := range responseBody.Metrics {
+		metrics = append(metrics, m.Metric)
+	}
+	return metrics, nil
+}
+
+func (tsc *threeScaleClient) ListServiceMappingRules(accessToken, serviceID string) ([]MappingRule, error) {
+	res, err := tsc.makeRequest(
+		"GET",
+		fmt.Sprintf("services/%s/proxy/mapping_rules.json", serviceID),
+		onlyAccessToken(accessToken),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := assertStatusCode(http.StatusOK, res); err != nil {
+		return nil, err
+	}
+
+	responseBody := &struct {
+		MappingRules []struct {
+			MappingRule MappingRule `json:"mapping_rule"`
+		} `json:"mapping_rules"`
+	}{}
+	if err := jsonFromResponse(res, responseBody); err != nil {
+		return nil, err
+	}
+
+	rules := make([]MappingRule, 0, len(responseBody.MappingRules))
+	for _, r := range responseBody.MappingRules {
+		rules = append(rules, r.MappingRule)
+	}
+	return rules, nil
+}
+
+func (tsc *threeScaleClient) ListApplicationPlanLimits(accessToken, planID string) ([]Limit, error) {
+	res, err := tsc.makeRequest(
+		"GET",
+		fmt.Sprintf("application_plans/%s/limits.json", planID),
+		onlyAccessToken(accessToken),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := assertStatusCode(http.StatusOK, res); err != nil {
+		return nil, err
+	}
+
+	responseBody := &struct {
+		Limits []struct {
+			Limit Limit `json:"limit"`
+		} `json:"limits"`
+	}{}
+	if err := jsonFromResponse(res, responseBody); err != nil {
+		return nil, err
+	}
+
+	limits := make([]Limit, 0, len(responseBody.Limits))
+	for _, l := range responseBody.Limits {
+		limits = append(limits, l.Limit)
+	}
+	return limits, nil
+}
+
+// ListApplicationKeys returns the app keys of an application of a service authenticated with an
+// app ID and app keys
+func (tsc *threeScaleClient) ListApplicationKeys(accessToken, accountID string, applicationID int) ([]string, error) {
+	res, err := tsc.makeRequest(
+		"GET",
+		fmt.Sprintf("accounts/%s/applications/%d/keys.json", accountID, applicationID),
+		onlyAccessToken(accessToken),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := assertStatusCode(http.StatusOK, res); err != nil {
+		return nil, err
+	}
+
+	responseBody := &struct {
+		Keys []struct {
+			Key struct {
+				Value string `json:"value"`
+			} `json:"key"`
+		} `json:"keys"`
+	}{}
+	if err := jsonFromResponse(res, responseBody); err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(responseBody.Keys))
+	for _, k := range responseBody.Keys {
+		keys = append(keys, k.Key.Value)
+	}
+	return keys, nil
+}
+
 func (tsc *threeScaleClient) DeleteService(accessToken, serviceID string) error {
 	res, err := tsc.makeRequest(
 		"DELETE",
@@ -627,7 +1116,7 @@ func (tsc *threeScaleClient) CreateTenant(accessToken string, account AccountDet
 func (tsc *threeScaleClient) GetTenantAccount(accessToken string, id int) (*SignUpAccount, error) {
 	res, err := tsc.makeRequestToMaster(
 		"GET",
-		fmt.Sprintf("master/api/providers/{%v}.xml", id),
+		fmt.Sprintf("master/api/providers/%v.xml", id),
 		onlyAccessToken(accessToken),
 	)
 	if err != nil {
@@ -749,6 +1238,36 @@ func (tsc *threeScaleClient) DeleteTenant(accessToken string, accountId int) err
 	return nil
 }
 
+// SuspendTenant suspends the tenant account with id, the tenant and its data are kept but its
+// users can't log in and its APIs are not served
+func (tsc *threeScaleClient) SuspendTenant(accessToken string, id int) error {
+	return tsc.updateTenantState(accessToken, id, "suspend")
+}
+
+// ResumeTenant resumes the suspended tenant account with id
+func (tsc *threeScaleClient) ResumeTenant(accessToken string, id int) error {
+	return tsc.updateTenantState(accessToken, id, "resume")
+}
+
+func (tsc *threeScaleClient) updateTenantState(accessToken string, id int, stateEvent string) error {
+	res, err := tsc.makeRequestToMaster(
+		"PUT",
+		fmt.Sprintf("master/api/providers/%d.xml", id),
+		withAccessToken(accessToken, map[string]interface{}{
+			"state_event": stateEvent,
+		}),
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := assertStatusCode(http.StatusOK, res); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func makeRequest(url, method string, parameters map[string]interface{}, tsc *threeScaleClient) (*http.Response, error) {
 	dataJSON, err := json.Marshal(parameters)
 	if err != nil {
@@ -769,8 +1288,15 @@ func makeRequest(url, method string, parameters map[string]interface{}, tsc *thr
 }
 
 func (tsc *threeScaleClient) makeRequest(method, path string, parameters map[string]interface{}) (*http.Response, error) {
-	url := fmt.Sprintf("https://3scale-admin.%s/admin/api/%s", tsc.wildCardDomain, path)
-	return makeRequest(url, method, parameters, tsc)
+	return makeRequest(tsc.adminAPIURL(path), method, parameters, tsc)
+}
+
+// adminAPIURL returns the URL of path in the admin API of the tenant of the client
+func (tsc *threeScaleClient) adminAPIURL(path string) string {
+	if tsc.adminBaseURL != "" {
+		return fmt.Sprintf("%s/admin/api/%s", strings.TrimSuffix(tsc.adminBaseURL, "/"), path)
+	}
+	return fmt.Sprintf("https://3scale-admin.%s/admin/api/%s", tsc.wildCardDomain, path)
 }
 
 func (tsc *threeScaleClient) makeRequestToMaster(method, path string, parameters map[string]interface{}) (*http.Response, error) {
@@ -807,6 +1333,13 @@ func onlyAccessToken(accessToken string) map[string]interface{} {
 	}
 }
 
+func pageParameters(page int) map[string]interface{} {
+	return map[string]interface{}{
+		"page":     page,
+		"per_page": listPageSize,
+	}
+}
+
 func withAccessToken(accessToken string, data map[string]interface{}) map[string]interface{} {
 	data["access_token"] = accessToken
 	return data
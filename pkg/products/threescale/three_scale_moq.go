@@ -36,9 +36,18 @@ var _ ThreeScaleInterface = &ThreeScaleInterfaceMock{}
 // 			CreateApplicationFunc: func(accessToken string, accountID string, planID string, name string, description string) (string, error) {
 // 				panic("mock out the CreateApplication method")
 // 			},
+// 			CreateApplicationKeyFunc: func(accessToken string, accountID string, applicationID int, key string) error {
+// 				panic("mock out the CreateApplicationKey method")
+// 			},
 // 			CreateApplicationPlanFunc: func(accessToken string, serviceID string, name string) (string, error) {
 // 				panic("mock out the CreateApplicationPlan method")
 // 			},
+// 			CreateApplicationPlanLimitFunc: func(accessToken string, planID string, metricID int, period string, value int) error {
+// 				panic("mock out the CreateApplicationPlanLimit method")
+// 			},
+// 			CreateApplicationWithCredentialsFunc: func(accessToken string, accountID string, planID string, application Application, appKey string) (int, error) {
+// 				panic("mock out the CreateApplicationWithCredentials method")
+// 			},
 // 			CreateBackendFunc: func(accessToken string, name string, privateEndpoint string) (int, error) {
 // 				panic("mock out the CreateBackend method")
 // 			},
@@ -54,6 +63,12 @@ var _ ThreeScaleInterface = &ThreeScaleInterfaceMock{}
 // 			CreateServiceFunc: func(accessToken string, name string, systemName string) (string, error) {
 // 				panic("mock out the CreateService method")
 // 			},
+// 			CreateServiceMappingRuleFunc: func(accessToken string, serviceID string, metricID int, httpMethod string, pattern string, delta int) error {
+// 				panic("mock out the CreateServiceMappingRule method")
+// 			},
+// 			CreateServiceMetricFunc: func(accessToken string, serviceID string, friendlyName string, unit string) (int, error) {
+// 				panic("mock out the CreateServiceMetric method")
+// 			},
 // 			CreateTenantFunc: func(accessToken string, account AccountDetail, password string, email string) (*SignUpAccount, error) {
 // 				panic("mock out the CreateTenant method")
 // 			},
@@ -96,12 +111,51 @@ var _ ThreeScaleInterface = &ThreeScaleInterfaceMock{}
 // 			IsAuthProviderAddedFunc: func(accessToken string, authProviderName string, account AccountDetail) (bool, error) {
 // 				panic("mock out the IsAuthProviderAdded method")
 // 			},
+// 			ListAccountApplicationsFunc: func(accessToken string, accountID string, page int) ([]Application, error) {
+// 				panic("mock out the ListAccountApplications method")
+// 			},
+// 			ListAccountsFunc: func(accessToken string, page int) ([]AccountDetail, error) {
+// 				panic("mock out the ListAccounts method")
+// 			},
+// 			ListApplicationKeysFunc: func(accessToken string, accountID string, applicationID int) ([]string, error) {
+// 				panic("mock out the ListApplicationKeys method")
+// 			},
+// 			ListApplicationPlanLimitsFunc: func(accessToken string, planID string) ([]Limit, error) {
+// 				panic("mock out the ListApplicationPlanLimits method")
+// 			},
+// 			ListApplicationPlansFunc: func(accessToken string, serviceID string) ([]ApplicationPlan, error) {
+// 				panic("mock out the ListApplicationPlans method")
+// 			},
+// 			ListBackendMappingRulesFunc: func(accessToken string, backendID int) ([]MappingRule, error) {
+// 				panic("mock out the ListBackendMappingRules method")
+// 			},
+// 			ListBackendMetricsFunc: func(accessToken string, backendID int) ([]Metric, error) {
+// 				panic("mock out the ListBackendMetrics method")
+// 			},
+// 			ListBackendUsagesFunc: func(accessToken string, serviceID string) ([]BackendUsage, error) {
+// 				panic("mock out the ListBackendUsages method")
+// 			},
+// 			ListBackendsFunc: func(accessToken string, page int) ([]Backend, error) {
+// 				panic("mock out the ListBackends method")
+// 			},
+// 			ListServiceMappingRulesFunc: func(accessToken string, serviceID string) ([]MappingRule, error) {
+// 				panic("mock out the ListServiceMappingRules method")
+// 			},
+// 			ListServiceMetricsFunc: func(accessToken string, serviceID string) ([]Metric, error) {
+// 				panic("mock out the ListServiceMetrics method")
+// 			},
+// 			ListServicesFunc: func(accessToken string, page int) ([]Service, error) {
+// 				panic("mock out the ListServices method")
+// 			},
 // 			ListTenantAccountsFunc: func(accessToken string, page int) ([]AccountDetail, error) {
 // 				panic("mock out the ListTenantAccounts method")
 // 			},
 // 			PromoteProxyFunc: func(accessToken string, serviceID string, env string, to string) (string, error) {
 // 				panic("mock out the PromoteProxy method")
 // 			},
+// 			ResumeTenantFunc: func(accessToken string, id int) error {
+// 				panic("mock out the ResumeTenant method")
+// 			},
 // 			SetFromEmailAddressFunc: func(emailAddress string, accessToken string) (*http.Response, error) {
 // 				panic("mock out the SetFromEmailAddress method")
 // 			},
@@ -114,6 +168,9 @@ var _ ThreeScaleInterface = &ThreeScaleInterfaceMock{}
 // 			SetUserAsMemberFunc: func(userID int, accessToken string) (*http.Response, error) {
 // 				panic("mock out the SetUserAsMember method")
 // 			},
+// 			SuspendTenantFunc: func(accessToken string, id int) error {
+// 				panic("mock out the SuspendTenant method")
+// 			},
 // 			UpdateUserFunc: func(userID int, username string, email string, accessToken string) (*http.Response, error) {
 // 				panic("mock out the UpdateUser method")
 // 			},
@@ -142,9 +199,18 @@ type ThreeScaleInterfaceMock struct {
 	// CreateApplicationFunc mocks the CreateApplication method.
 	CreateApplicationFunc func(accessToken string, accountID string, planID string, name string, description string) (string, error)
 
+	// CreateApplicationKeyFunc mocks the CreateApplicationKey method.
+	CreateApplicationKeyFunc func(accessToken string, accountID string, applicationID int, key string) error
+
 	// CreateApplicationPlanFunc mocks the CreateApplicationPlan method.
 	CreateApplicationPlanFunc func(accessToken string, serviceID string, name string) (string, error)
 
+	// CreateApplicationPlanLimitFunc mocks the CreateApplicationPlanLimit method.
+	CreateApplicationPlanLimitFunc func(accessToken string, planID string, metricID int, period string, value int) error
+
+	// CreateApplicationWithCredentialsFunc mocks the CreateApplicationWithCredentials method.
+	CreateApplicationWithCredentialsFunc func(accessToken string, accountID string, planID string, application Application, appKey string) (int, error)
+
 	// CreateBackendFunc mocks the CreateBackend method.
 	CreateBackendFunc func(accessToken string, name string, privateEndpoint string) (int, error)
 
@@ -160,6 +226,12 @@ type ThreeScaleInterfaceMock struct {
 	// CreateServiceFunc mocks the CreateService method.
 	CreateServiceFunc func(accessToken string, name string, systemName string) (string, error)
 
+	// CreateServiceMappingRuleFunc mocks the CreateServiceMappingRule method.
+	CreateServiceMappingRuleFunc func(accessToken string, serviceID string, metricID int, httpMethod string, pattern string, delta int) error
+
+	// CreateServiceMetricFunc mocks the CreateServiceMetric method.
+	CreateServiceMetricFunc func(accessToken string, serviceID string, friendlyName string, unit string) (int, error)
+
 	// CreateTenantFunc mocks the CreateTenant method.
 	CreateTenantFunc func(accessToken string, account AccountDetail, password string, email string) (*SignUpAccount, error)
 
@@ -202,12 +274,51 @@ type ThreeScaleInterfaceMock struct {
 	// IsAuthProviderAddedFunc mocks the IsAuthProviderAdded method.
 	IsAuthProviderAddedFunc func(accessToken string, authProviderName string, account AccountDetail) (bool, error)
 
+	// ListAccountApplicationsFunc mocks the ListAccountApplications method.
+	ListAccountApplicationsFunc func(accessToken string, accountID string, page int) ([]Application, error)
+
+	// ListAccountsFunc mocks the ListAccounts method.
+	ListAccountsFunc func(accessToken string, page int) ([]AccountDetail, error)
+
+	// ListApplicationKeysFunc mocks the ListApplicationKeys method.
+	ListApplicationKeysFunc func(accessToken string, accountID string, applicationID int) ([]string, error)
+
+	// ListApplicationPlanLimitsFunc mocks the ListApplicationPlanLimits method.
+	ListApplicationPlanLimitsFunc func(accessToken string, planID string) ([]Limit, error)
+
+	// ListApplicationPlansFunc mocks the ListApplicationPlans method.
+	ListApplicationPlansFunc func(accessToken string, serviceID string) ([]ApplicationPlan, error)
+
+	// ListBackendMappingRulesFunc mocks the ListBackendMappingRules method.
+	ListBackendMappingRulesFunc func(accessToken string, backendID int) ([]MappingRule, error)
+
+	// ListBackendMetricsFunc mocks the ListBackendMetrics method.
+	ListBackendMetricsFunc func(accessToken string, backendID int) ([]Metric, error)
+
+	// ListBackendUsagesFunc mocks the ListBackendUsages method.
+	ListBackendUsagesFunc func(accessToken string, serviceID string) ([]BackendUsage, error)
+
+	// ListBackendsFunc mocks the ListBackends method.
+	ListBackendsFunc func(accessToken string, page int) ([]Backend, error)
+
+	// ListServiceMappingRulesFunc mocks the ListServiceMappingRules method.
+	ListServiceMappingRulesFunc func(accessToken string, serviceID string) ([]MappingRule, error)
+
+	// ListServiceMetricsFunc mocks the ListServiceMetrics method.
+	ListServiceMetricsFunc func(accessToken string, serviceID string) ([]Metric, error)
+
+	// ListServicesFunc mocks the ListServices method.
+	ListServicesFunc func(accessToken string, page int) ([]Service, error)
+
 	// ListTenantAccountsFunc mocks the ListTenantAccounts method.
 	ListTenantAccountsFunc func(accessToken string, page int) ([]AccountDetail, error)
 
 	// PromoteProxyFunc mocks the PromoteProxy method.
 	PromoteProxyFunc func(accessToken string, serviceID string, env string, to string) (string, error)
 
+	// ResumeTenantFunc mocks the ResumeTenant method.
+	ResumeTenantFunc func(accessToken string, id int) error
+
 	// SetFromEmailAddressFunc mocks the SetFromEmailAddress method.
 	SetFromEmailAddressFunc func(emailAddress string, accessToken string) (*http.Response, error)
 
@@ -220,6 +331,9 @@ type ThreeScaleInterfaceMock struct {
 	// SetUserAsMemberFunc mocks the SetUserAsMember method.
 	SetUserAsMemberFunc func(userID int, accessToken string) (*http.Response, error)
 
+	// SuspendTenantFunc mocks the SuspendTenant method.
+	SuspendTenantFunc func(accessToken string, id int) error
+
 	// UpdateUserFunc mocks the UpdateUser method.
 	UpdateUserFunc func(userID int, username string, email string, accessToken string) (*http.Response, error)
 
@@ -283,6 +397,17 @@ type ThreeScaleInterfaceMock struct {
 			// Description is the description argument value.
 			Description string
 		}
+		// CreateApplicationKey holds details about calls to the CreateApplicationKey method.
+		CreateApplicationKey []struct {
+			// AccessToken is the accessToken argument value.
+			AccessToken string
+			// AccountID is the accountID argument value.
+			AccountID string
+			// ApplicationID is the applicationID argument value.
+			ApplicationID int
+			// Key is the key argument value.
+			Key string
+		}
 		// CreateApplicationPlan holds details about calls to the CreateApplicationPlan method.
 		CreateApplicationPlan []struct {
 			// AccessToken is the accessToken argument value.
@@ -292,6 +417,32 @@ type ThreeScaleInterfaceMock struct {
 			// Name is the name argument value.
 			Name string
 		}
+		// CreateApplicationPlanLimit holds details about calls to the CreateApplicationPlanLimit method.
+		CreateApplicationPlanLimit []struct {
+			// AccessToken is the accessToken argument value.
+			AccessToken string
+			// PlanID is the planID argument value.
+			PlanID string
+			// MetricID is the metricID argument value.
+			MetricID int
+			// Period is the period argument value.
+			Period string
+			// Value is the value argument value.
+			Value int
+		}
+		// CreateApplicationWithCredentials holds details about calls to the CreateApplicationWithCredentials method.
+		CreateApplicationWithCredentials []struct {
+			// AccessToken is the accessToken argument value.
+			AccessToken string
+			// AccountID is the accountID argument value.
+			AccountID string
+			// PlanID is the planID argument value.
+			PlanID string
+			// Application is the application argument value.
+			Application Application
+			// AppKey is the appKey argument value.
+			AppKey string
+		}
 		// CreateBackend holds details about calls to the CreateBackend method.
 		CreateBackend []struct {
 			// AccessToken is the accessToken argument value.
@@ -347,6 +498,32 @@ type ThreeScaleInterfaceMock struct {
 			// SystemName is the systemName argument value.
 			SystemName string
 		}
+		// CreateServiceMappingRule holds details about calls to the CreateServiceMappingRule method.
+		CreateServiceMappingRule []struct {
+			// AccessToken is the accessToken argument value.
+			AccessToken string
+			// ServiceID is the serviceID argument value.
+			ServiceID string
+			// MetricID is the metricID argument value.
+			MetricID int
+			// HttpMethod is the httpMethod argument value.
+			HttpMethod string
+			// Pattern is the pattern argument value.
+			Pattern string
+			// Delta is the delta argument value.
+			Delta int
+		}
+		// CreateServiceMetric holds details about calls to the CreateServiceMetric method.
+		CreateServiceMetric []struct {
+			// AccessToken is the accessToken argument value.
+			AccessToken string
+			// ServiceID is the serviceID argument value.
+			ServiceID string
+			// FriendlyName is the friendlyName argument value.
+			FriendlyName string
+			// Unit is the unit argument value.
+			Unit string
+		}
 		// CreateTenant holds details about calls to the CreateTenant method.
 		CreateTenant []struct {
 			// AccessToken is the accessToken argument value.
@@ -447,6 +624,94 @@ type ThreeScaleInterfaceMock struct {
 			// Account is the account argument value.
 			Account AccountDetail
 		}
+		// ListAccountApplications holds details about calls to the ListAccountApplications method.
+		ListAccountApplications []struct {
+			// AccessToken is the accessToken argument value.
+			AccessToken string
+			// AccountID is the accountID argument value.
+			AccountID string
+			// Page is the page argument value.
+			Page int
+		}
+		// ListAccounts holds details about calls to the ListAccounts method.
+		ListAccounts []struct {
+			// AccessToken is the accessToken argument value.
+			AccessToken string
+			// Page is the page argument value.
+			Page int
+		}
+		// ListApplicationKeys holds details about calls to the ListApplicationKeys method.
+		ListApplicationKeys []struct {
+			// AccessToken is the accessToken argument value.
+			AccessToken string
+			// AccountID is the accountID argument value.
+			AccountID string
+			// ApplicationID is the applicationID argument value.
+			ApplicationID int
+		}
+		// ListApplicationPlanLimits holds details about calls to the ListApplicationPlanLimits method.
+		ListApplicationPlanLimits []struct {
+			// AccessToken is the accessToken argument value.
+			AccessToken string
+			// PlanID is the planID argument value.
+			PlanID string
+		}
+		// ListApplicationPlans holds details about calls to the ListApplicationPlans method.
+		ListApplicationPlans []struct {
+			// AccessToken is the accessToken argument value.
+			AccessToken string
+			// ServiceID is the serviceID argument value.
+			ServiceID string
+		}
+		// ListBackendMappingRules holds details about calls to the ListBackendMappingRules method.
+		ListBackendMappingRules []struct {
+			// AccessToken is the accessToken argument value.
+			AccessToken string
+			// BackendID is the backendID argument value.
+			BackendID int
+		}
+		// ListBackendMetrics holds details about calls to the ListBackendMetrics method.
+		ListBackendMetrics []struct {
+			// AccessToken is the accessToken argument value.
+			AccessToken string
+			// BackendID is the backendID argument value.
+			BackendID int
+		}
+		// ListBackendUsages holds details about calls to the ListBackendUsages method.
+		ListBackendUsages []struct {
+			// AccessToken is the accessToken argument value.
+			AccessToken string
+			// ServiceID is the serviceID argument value.
+			ServiceID string
+		}
+		// ListBackends holds details about calls to the ListBackends method.
+		ListBackends []struct {
+			// AccessToken is the accessToken argument value.
+			AccessToken string
+			// Page is the page argument value.
+			Page int
+		}
+		// ListServiceMappingRules holds details about calls to the ListServiceMappingRules method.
+		ListServiceMappingRules []struct {
+			// AccessToken is the accessToken argument value.
+			AccessToken string
+			// ServiceID is the serviceID argument value.
+			ServiceID string
+		}
+		// ListServiceMetrics holds details about calls to the ListServiceMetrics method.
+		ListServiceMetrics []struct {
+			// AccessToken is the accessToken argument value.
+			AccessToken string
+			// ServiceID is the serviceID argument value.
+			ServiceID string
+		}
+		// ListServices holds details about calls to the ListServices method.
+		ListServices []struct {
+			// AccessToken is the accessToken argument value.
+			AccessToken string
+			// Page is the page argument value.
+			Page int
+		}
 		// ListTenantAccounts holds details about calls to the ListTenantAccounts method.
 		ListTenantAccounts []struct {
 			// AccessToken is the accessToken argument value.
@@ -465,6 +730,13 @@ type ThreeScaleInterfaceMock struct {
 			// To is the to argument value.
 			To string
 		}
+		// ResumeTenant holds details about calls to the ResumeTenant method.
+		ResumeTenant []struct {
+			// AccessToken is the accessToken argument value.
+			AccessToken string
+			// ID is the id argument value.
+			ID int
+		}
 		// SetFromEmailAddress holds details about calls to the SetFromEmailAddress method.
 		SetFromEmailAddress []struct {
 			// EmailAddress is the emailAddress argument value.
@@ -491,6 +763,13 @@ type ThreeScaleInterfaceMock struct {
 			// AccessToken is the accessToken argument value.
 			AccessToken string
 		}
+		// SuspendTenant holds details about calls to the SuspendTenant method.
+		SuspendTenant []struct {
+			// AccessToken is the accessToken argument value.
+			AccessToken string
+			// ID is the id argument value.
+			ID int
+		}
 		// UpdateUser holds details about calls to the UpdateUser method.
 		UpdateUser []struct {
 			// UserID is the userID argument value.
@@ -503,39 +782,58 @@ type ThreeScaleInterfaceMock struct {
 			AccessToken string
 		}
 	}
-	lockActivateUser                    sync.RWMutex
-	lockAddAuthProviderToAccount        sync.RWMutex
-	lockAddAuthenticationProvider       sync.RWMutex
-	lockAddUser                         sync.RWMutex
-	lockCreateAccount                   sync.RWMutex
-	lockCreateApplication               sync.RWMutex
-	lockCreateApplicationPlan           sync.RWMutex
-	lockCreateBackend                   sync.RWMutex
-	lockCreateBackendMappingRule        sync.RWMutex
-	lockCreateBackendUsage              sync.RWMutex
-	lockCreateMetric                    sync.RWMutex
-	lockCreateService                   sync.RWMutex
-	lockCreateTenant                    sync.RWMutex
-	lockDeleteAccount                   sync.RWMutex
-	lockDeleteBackend                   sync.RWMutex
-	lockDeleteService                   sync.RWMutex
-	lockDeleteTenant                    sync.RWMutex
-	lockDeleteTenants                   sync.RWMutex
-	lockDeleteUser                      sync.RWMutex
-	lockDeployProxy                     sync.RWMutex
-	lockGetAuthenticationProviderByName sync.RWMutex
-	lockGetAuthenticationProviders      sync.RWMutex
-	lockGetTenantAccount                sync.RWMutex
-	lockGetUser                         sync.RWMutex
-	lockGetUsers                        sync.RWMutex
-	lockIsAuthProviderAdded             sync.RWMutex
-	lockListTenantAccounts              sync.RWMutex
-	lockPromoteProxy                    sync.RWMutex
-	lockSetFromEmailAddress             sync.RWMutex
-	lockSetNamespace                    sync.RWMutex
-	lockSetUserAsAdmin                  sync.RWMutex
-	lockSetUserAsMember                 sync.RWMutex
-	lockUpdateUser                      sync.RWMutex
+	lockActivateUser                     sync.RWMutex
+	lockAddAuthProviderToAccount         sync.RWMutex
+	lockAddAuthenticationProvider        sync.RWMutex
+	lockAddUser                          sync.RWMutex
+	lockCreateAccount                    sync.RWMutex
+	lockCreateApplication                sync.RWMutex
+	lockCreateApplicationKey             sync.RWMutex
+	lockCreateApplicationPlan            sync.RWMutex
+	lockCreateApplicationPlanLimit       sync.RWMutex
+	lockCreateApplicationWithCredentials sync.RWMutex
+	lockCreateBackend                    sync.RWMutex
+	lockCreateBackendMappingRule         sync.RWMutex
+	lockCreateBackendUsage               sync.RWMutex
+	lockCreateMetric                     sync.RWMutex
+	lockCreateService                    sync.RWMutex
+	lockCreateServiceMappingRule         sync.RWMutex
+	lockCreateServiceMetric              sync.RWMutex
+	lockCreateTenant                     sync.RWMutex
+	lockDeleteAccount                    sync.RWMutex
+	lockDeleteBackend                    sync.RWMutex
+	lockDeleteService                    sync.RWMutex
+	lockDeleteTenant                     sync.RWMutex
+	lockDeleteTenants                    sync.RWMutex
+	lockDeleteUser                       sync.RWMutex
+	lockDeployProxy                      sync.RWMutex
+	lockGetAuthenticationProviderByName  sync.RWMutex
+	lockGetAuthenticationProviders       sync.RWMutex
+	lockGetTenantAccount                 sync.RWMutex
+	lockGetUser                          sync.RWMutex
+	lockGetUsers                         sync.RWMutex
+	lockIsAuthProviderAdded              sync.RWMutex
+	lockListAccountApplications          sync.RWMutex
+	lockListAccounts                     sync.RWMutex
+	lockListApplicationKeys              sync.RWMutex
+	lockListApplicationPlanLimits        sync.RWMutex
+	lockListApplicationPlans             sync.RWMutex
+	lockListBackendMappingRules          sync.RWMutex
+	lockListBackendMetrics               sync.RWMutex
+	lockListBackendUsages                sync.RWMutex
+	lockListBackends                     sync.RWMutex
+	lockListServiceMappingRules          sync.RWMutex
+	lockListServiceMetrics               sync.RWMutex
+	lockListServices                     sync.RWMutex
+	lockListTenantAccounts               sync.RWMutex
+	lockPromoteProxy                     sync.RWMutex
+	lockResumeTenant                     sync.RWMutex
+	lockSetFromEmailAddress              sync.RWMutex
+	lockSetNamespace                     sync.RWMutex
+	lockSetUserAsAdmin                   sync.RWMutex
+	lockSetUserAsMember                  sync.RWMutex
+	lockSuspendTenant                    sync.RWMutex
+	lockUpdateUser                       sync.RWMutex
 }
 
 // ActivateUser calls ActivateUserFunc.
@@ -780,6 +1078,49 @@ func (mock *ThreeScaleInterfaceMock) CreateApplicationCalls() []struct {
 	return calls
 }
 
+// CreateApplicationKey calls CreateApplicationKeyFunc.
+func (mock *ThreeScaleInterfaceMock) CreateApplicationKey(accessToken string, accountID string, applicationID int, key string) error {
+	if mock.CreateApplicationKeyFunc == nil {
+		panic("ThreeScaleInterfaceMock.CreateApplicationKeyFunc: method is nil but ThreeScaleInterface.CreateApplicationKey was just called")
+	}
+	callInfo := struct {
+		AccessToken   string
+		AccountID     string
+		ApplicationID int
+		Key           string
+	}{
+		AccessToken:   accessToken,
+		AccountID:     accountID,
+		ApplicationID: applicationID,
+		Key:           key,
+	}
+	mock.lockCreateApplicationKey.Lock()
+	mock.calls.CreateApplicationKey = append(mock.calls.CreateApplicationKey, callInfo)
+	mock.lockCreateApplicationKey.Unlock()
+	return mock.CreateApplicationKeyFunc(accessToken, accountID, applicationID, key)
+}
+
+// CreateApplicationKeyCalls gets all the calls that were made to CreateApplicationKey.
+// Check the length with:
+//     len(mockedThreeScaleInterface.CreateApplicationKeyCalls())
+func (mock *ThreeScaleInterfaceMock) CreateApplicationKeyCalls() []struct {
+	AccessToken   string
+	AccountID     string
+	ApplicationID int
+	Key           string
+} {
+	var calls []struct {
+		AccessToken   string
+		AccountID     string
+		ApplicationID int
+		Key           string
+	}
+	mock.lockCreateApplicationKey.RLock()
+	calls = mock.calls.CreateApplicationKey
+	mock.lockCreateApplicationKey.RUnlock()
+	return calls
+}
+
 // CreateApplicationPlan calls CreateApplicationPlanFunc.
 func (mock *ThreeScaleInterfaceMock) CreateApplicationPlan(accessToken string, serviceID string, name string) (string, error) {
 	if mock.CreateApplicationPlanFunc == nil {
@@ -819,6 +1160,100 @@ func (mock *ThreeScaleInterfaceMock) CreateApplicationPlanCalls() []struct {
 	return calls
 }
 
+// CreateApplicationPlanLimit calls CreateApplicationPlanLimitFunc.
+func (mock *ThreeScaleInterfaceMock) CreateApplicationPlanLimit(accessToken string, planID string, metricID int, period string, value int) error {
+	if mock.CreateApplicationPlanLimitFunc == nil {
+		panic("ThreeScaleInterfaceMock.CreateApplicationPlanLimitFunc: method is nil but ThreeScaleInterface.CreateApplicationPlanLimit was just called")
+	}
+	callInfo := struct {
+		AccessToken string
+		PlanID      string
+		MetricID    int
+		Period      string
+		Value       int
+	}{
+		AccessToken: accessToken,
+		PlanID:      planID,
+		MetricID:    metricID,
+		Period:      period,
+		Value:       value,
+	}
+	mock.lockCreateApplicationPlanLimit.Lock()
+	mock.calls.CreateApplicationPlanLimit = append(mock.calls.CreateApplicationPlanLimit, callInfo)
+	mock.lockCreateApplicationPlanLimit.Unlock()
+	return mock.CreateApplicationPlanLimitFunc(accessToken, planID, metricID, period, value)
+}
+
+// CreateApplicationPlanLimitCalls gets all the calls that were made to CreateApplicationPlanLimit.
+// Check the length with:
+//     len(mockedThreeScaleInterface.CreateApplicationPlanLimitCalls())
+func (mock *ThreeScaleInterfaceMock) CreateApplicationPlanLimitCalls() []struct {
+	AccessToken string
+	PlanID      string
+	MetricID    int
+	Period      string
+	Value       int
+} {
+	var calls []struct {
+		AccessToken string
+		PlanID      string
+		MetricID    int
+		Period      string
+		Value       int
+	}
+	mock.lockCreateApplicationPlanLimit.RLock()
+	calls = mock.calls.CreateApplicationPlanLimit
+	mock.lockCreateApplicationPlanLimit.RUnlock()
+	return calls
+}
+
+// CreateApplicationWithCredentials calls CreateApplicationWithCredentialsFunc.
+func (mock *ThreeScaleInterfaceMock) CreateApplicationWithCredentials(accessToken string, accountID string, planID string, application Application, appKey string) (int, error) {
+	if mock.CreateApplicationWithCredentialsFunc == nil {
+		panic("ThreeScaleInterfaceMock.CreateApplicationWithCredentialsFunc: method is nil but ThreeScaleInterface.CreateApplicationWithCredentials was just called")
+	}
+	callInfo := struct {
+		AccessToken string
+		AccountID   string
+		PlanID      string
+		Application Application
+		AppKey      string
+	}{
+		AccessToken: accessToken,
+		AccountID:   accountID,
+		PlanID:      planID,
+		Application: application,
+		AppKey:      appKey,
+	}
+	mock.lockCreateApplicationWithCredentials.Lock()
+	mock.calls.CreateApplicationWithCredentials = append(mock.calls.CreateApplicationWithCredentials, callInfo)
+	mock.lockCreateApplicationWithCredentials.Unlock()
+	return mock.CreateApplicationWithCredentialsFunc(accessToken, accountID, planID, application, appKey)
+}
+
+// CreateApplicationWithCredentialsCalls gets all the calls that were made to CreateApplicationWithCredentials.
+// Check the length with:
+//     len(mockedThreeScaleInterface.CreateApplicationWithCredentialsCalls())
+func (mock *ThreeScaleInterfaceMock) CreateApplicationWithCredentialsCalls() []struct {
+	AccessToken string
+	AccountID   string
+	PlanID      string
+	Application Application
+	AppKey      string
+} {
+	var calls []struct {
+		AccessToken string
+		AccountID   string
+		PlanID      string
+		Application Application
+		AppKey      string
+	}
+	mock.lockCreateApplicationWithCredentials.RLock()
+	calls = mock.calls.CreateApplicationWithCredentials
+	mock.lockCreateApplicationWithCredentials.RUnlock()
+	return calls
+}
+
 // CreateBackend calls CreateBackendFunc.
 func (mock *ThreeScaleInterfaceMock) CreateBackend(accessToken string, name string, privateEndpoint string) (int, error) {
 	if mock.CreateBackendFunc == nil {
@@ -1034,42 +1469,136 @@ func (mock *ThreeScaleInterfaceMock) CreateServiceCalls() []struct {
 	return calls
 }
 
-// CreateTenant calls CreateTenantFunc.
-func (mock *ThreeScaleInterfaceMock) CreateTenant(accessToken string, account AccountDetail, password string, email string) (*SignUpAccount, error) {
-	if mock.CreateTenantFunc == nil {
-		panic("ThreeScaleInterfaceMock.CreateTenantFunc: method is nil but ThreeScaleInterface.CreateTenant was just called")
+// CreateServiceMappingRule calls CreateServiceMappingRuleFunc.
+func (mock *ThreeScaleInterfaceMock) CreateServiceMappingRule(accessToken string, serviceID string, metricID int, httpMethod string, pattern string, delta int) error {
+	if mock.CreateServiceMappingRuleFunc == nil {
+		panic("ThreeScaleInterfaceMock.CreateServiceMappingRuleFunc: method is nil but ThreeScaleInterface.CreateServiceMappingRule was just called")
 	}
 	callInfo := struct {
 		AccessToken string
-		Account     AccountDetail
-		Password    string
-		Email       string
+		ServiceID   string
+		MetricID    int
+		HttpMethod  string
+		Pattern     string
+		Delta       int
 	}{
 		AccessToken: accessToken,
-		Account:     account,
-		Password:    password,
-		Email:       email,
+		ServiceID:   serviceID,
+		MetricID:    metricID,
+		HttpMethod:  httpMethod,
+		Pattern:     pattern,
+		Delta:       delta,
 	}
-	mock.lockCreateTenant.Lock()
-	mock.calls.CreateTenant = append(mock.calls.CreateTenant, callInfo)
-	mock.lockCreateTenant.Unlock()
-	return mock.CreateTenantFunc(accessToken, account, password, email)
+	mock.lockCreateServiceMappingRule.Lock()
+	mock.calls.CreateServiceMappingRule = append(mock.calls.CreateServiceMappingRule, callInfo)
+	mock.lockCreateServiceMappingRule.Unlock()
+	return mock.CreateServiceMappingRuleFunc(accessToken, serviceID, metricID, httpMethod, pattern, delta)
 }
 
-// CreateTenantCalls gets all the calls that were made to CreateTenant.
+// CreateServiceMappingRuleCalls gets all the calls that were made to CreateServiceMappingRule.
 // Check the length with:
-//     len(mockedThreeScaleInterface.CreateTenantCalls())
-func (mock *ThreeScaleInterfaceMock) CreateTenantCalls() []struct {
+//     len(mockedThreeScaleInterface.CreateServiceMappingRuleCalls())
+func (mock *ThreeScaleInterfaceMock) CreateServiceMappingRuleCalls() []struct {
 	AccessToken string
-	Account     AccountDetail
-	Password    string
-	Email       string
+	ServiceID   string
+	MetricID    int
+	HttpMethod  string
+	Pattern     string
+	Delta       int
 } {
 	var calls []struct {
 		AccessToken string
-		Account     AccountDetail
-		Password    string
-		Email       string
+		ServiceID   string
+		MetricID    int
+		HttpMethod  string
+		Pattern     string
+		Delta       int
+	}
+	mock.lockCreateServiceMappingRule.RLock()
+	calls = mock.calls.CreateServiceMappingRule
+	mock.lockCreateServiceMappingRule.RUnlock()
+	return calls
+}
+
+// CreateServiceMetric calls CreateServiceMetricFunc.
+func (mock *ThreeScaleInterfaceMock) CreateServiceMetric(accessToken string, serviceID string, friendlyName string, unit string) (int, error) {
+	if mock.CreateServiceMetricFunc == nil {
+		panic("ThreeScaleInterfaceMock.CreateServiceMetricFunc: method is nil but ThreeScaleInterface.CreateServiceMetric was just called")
+	}
+	callInfo := struct {
+		AccessToken  string
+		ServiceID    string
+		FriendlyName string
+		Unit         string
+	}{
+		AccessToken:  accessToken,
+		ServiceID:    serviceID,
+		FriendlyName: friendlyName,
+		Unit:         unit,
+	}
+	mock.lockCreateServiceMetric.Lock()
+	mock.calls.CreateServiceMetric = append(mock.calls.CreateServiceMetric, callInfo)
+	mock.lockCreateServiceMetric.Unlock()
+	return mock.CreateServiceMetricFunc(accessToken, serviceID, friendlyName, unit)
+}
+
+// CreateServiceMetricCalls gets all the calls that were made to CreateServiceMetric.
+// Check the length with:
+//     len(mockedThreeScaleInterface.CreateServiceMetricCalls())
+func (mock *ThreeScaleInterfaceMock) CreateServiceMetricCalls() []struct {
+	AccessToken  string
+	ServiceID    string
+	FriendlyName string
+	Unit         string
+} {
+	var calls []struct {
+		AccessToken  string
+		ServiceID    string
+		FriendlyName string
+		Unit         string
+	}
+	mock.lockCreateServiceMetric.RLock()
+	calls = mock.calls.CreateServiceMetric
+	mock.lockCreateServiceMetric.RUnlock()
+	return calls
+}
+
+// CreateTenant calls CreateTenantFunc.
+func (mock *ThreeScaleInterfaceMock) CreateTenant(accessToken string, account AccountDetail, password string, email string) (*SignUpAccount, error) {
+	if mock.CreateTenantFunc == nil {
+		panic("ThreeScaleInterfaceMock.CreateTenantFunc: method is nil but ThreeScaleInterface.CreateTenant was just called")
+	}
+	callInfo := struct {
+		AccessToken string
+		Account     AccountDetail
+		Password    string
+		Email       string
+	}{
+		AccessToken: accessToken,
+		Account:     account,
+		Password:    password,
+		Email:       email,
+	}
+	mock.lockCreateTenant.Lock()
+	mock.calls.CreateTenant = append(mock.calls.CreateTenant, callInfo)
+	mock.lockCreateTenant.Unlock()
+	return mock.CreateTenantFunc(accessToken, account, password, email)
+}
+
+// CreateTenantCalls gets all the calls that were made to CreateTenant.
+// Check the length with:
+//     len(mockedThreeScaleInterface.CreateTenantCalls())
+func (mock *ThreeScaleInterfaceMock) CreateTenantCalls() []struct {
+	AccessToken string
+	Account     AccountDetail
+	Password    string
+	Email       string
+} {
+	var calls []struct {
+		AccessToken string
+		Account     AccountDetail
+		Password    string
+		Email       string
 	}
 	mock.lockCreateTenant.RLock()
 	calls = mock.calls.CreateTenant
@@ -1528,6 +2057,434 @@ func (mock *ThreeScaleInterfaceMock) IsAuthProviderAddedCalls() []struct {
 	return calls
 }
 
+// ListAccountApplications calls ListAccountApplicationsFunc.
+func (mock *ThreeScaleInterfaceMock) ListAccountApplications(accessToken string, accountID string, page int) ([]Application, error) {
+	if mock.ListAccountApplicationsFunc == nil {
+		panic("ThreeScaleInterfaceMock.ListAccountApplicationsFunc: method is nil but ThreeScaleInterface.ListAccountApplications was just called")
+	}
+	callInfo := struct {
+		AccessToken string
+		AccountID   string
+		Page        int
+	}{
+		AccessToken: accessToken,
+		AccountID:   accountID,
+		Page:        page,
+	}
+	mock.lockListAccountApplications.Lock()
+	mock.calls.ListAccountApplications = append(mock.calls.ListAccountApplications, callInfo)
+	mock.lockListAccountApplications.Unlock()
+	return mock.ListAccountApplicationsFunc(accessToken, accountID, page)
+}
+
+// ListAccountApplicationsCalls gets all the calls that were made to ListAccountApplications.
+// Check the length with:
+//     len(mockedThreeScaleInterface.ListAccountApplicationsCalls())
+func (mock *ThreeScaleInterfaceMock) ListAccountApplicationsCalls() []struct {
+	AccessToken string
+	AccountID   string
+	Page        int
+} {
+	var calls []struct {
+		AccessToken string
+		AccountID   string
+		Page        int
+	}
+	mock.lockListAccountApplications.RLock()
+	calls = mock.calls.ListAccountApplications
+	mock.lockListAccountApplications.RUnlock()
+	return calls
+}
+
+// ListAccounts calls ListAccountsFunc.
+func (mock *ThreeScaleInterfaceMock) ListAccounts(accessToken string, page int) ([]AccountDetail, error) {
+	if mock.ListAccountsFunc == nil {
+		panic("ThreeScaleInterfaceMock.ListAccountsFunc: method is nil but ThreeScaleInterface.ListAccounts was just called")
+	}
+	callInfo := struct {
+		AccessToken string
+		Page        int
+	}{
+		AccessToken: accessToken,
+		Page:        page,
+	}
+	mock.lockListAccounts.Lock()
+	mock.calls.ListAccounts = append(mock.calls.ListAccounts, callInfo)
+	mock.lockListAccounts.Unlock()
+	return mock.ListAccountsFunc(accessToken, page)
+}
+
+// ListAccountsCalls gets all the calls that were made to ListAccounts.
+// Check the length with:
+//     len(mockedThreeScaleInterface.ListAccountsCalls())
+func (mock *ThreeScaleInterfaceMock) ListAccountsCalls() []struct {
+	AccessToken string
+	Page        int
+} {
+	var calls []struct {
+		AccessToken string
+		Page        int
+	}
+	mock.lockListAccounts.RLock()
+	calls = mock.calls.ListAccounts
+	mock.lockListAccounts.RUnlock()
+	return calls
+}
+
+// ListApplicationKeys calls ListApplicationKeysFunc.
+func (mock *ThreeScaleInterfaceMock) ListApplicationKeys(accessToken string, accountID string, applicationID int) ([]string, error) {
+	if mock.ListApplicationKeysFunc == nil {
+		panic("ThreeScaleInterfaceMock.ListApplicationKeysFunc: method is nil but ThreeScaleInterface.ListApplicationKeys was just called")
+	}
+	callInfo := struct {
+		AccessToken   string
+		AccountID     string
+		ApplicationID int
+	}{
+		AccessToken:   accessToken,
+		AccountID:     accountID,
+		ApplicationID: applicationID,
+	}
+	mock.lockListApplicationKeys.Lock()
+	mock.calls.ListApplicationKeys = append(mock.calls.ListApplicationKeys, callInfo)
+	mock.lockListApplicationKeys.Unlock()
+	return mock.ListApplicationKeysFunc(accessToken, accountID, applicationID)
+}
+
+// ListApplicationKeysCalls gets all the calls that were made to ListApplicationKeys.
+// Check the length with:
+//     len(mockedThreeScaleInterface.ListApplicationKeysCalls())
+func (mock *ThreeScaleInterfaceMock) ListApplicationKeysCalls() []struct {
+	AccessToken   string
+	AccountID     string
+	ApplicationID int
+} {
+	var calls []struct {
+		AccessToken   string
+		AccountID     string
+		ApplicationID int
+	}
+	mock.lockListApplicationKeys.RLock()
+	calls = mock.calls.ListApplicationKeys
+	mock.lockListApplicationKeys.RUnlock()
+	return calls
+}
+
+// ListApplicationPlanLimits calls ListApplicationPlanLimitsFunc.
+func (mock *ThreeScaleInterfaceMock) ListApplicationPlanLimits(accessToken string, planID string) ([]Limit, error) {
+	if mock.ListApplicationPlanLimitsFunc == nil {
+		panic("ThreeScaleInterfaceMock.ListApplicationPlanLimitsFunc: method is nil but ThreeScaleInterface.ListApplicationPlanLimits was just called")
+	}
+	callInfo := struct {
+		AccessToken string
+		PlanID      string
+	}{
+		AccessToken: accessToken,
+		PlanID:      planID,
+	}
+	mock.lockListApplicationPlanLimits.Lock()
+	mock.calls.ListApplicationPlanLimits = append(mock.calls.ListApplicationPlanLimits, callInfo)
+	mock.lockListApplicationPlanLimits.Unlock()
+	return mock.ListApplicationPlanLimitsFunc(accessToken, planID)
+}
+
+// ListApplicationPlanLimitsCalls gets all the calls that were made to ListApplicationPlanLimits.
+// Check the length with:
+//     len(mockedThreeScaleInterface.ListApplicationPlanLimitsCalls())
+func (mock *ThreeScaleInterfaceMock) ListApplicationPlanLimitsCalls() []struct {
+	AccessToken string
+	PlanID      string
+} {
+	var calls []struct {
+		AccessToken string
+		PlanID      string
+	}
+	mock.lockListApplicationPlanLimits.RLock()
+	calls = mock.calls.ListApplicationPlanLimits
+	mock.lockListApplicationPlanLimits.RUnlock()
+	return calls
+}
+
+// ListApplicationPlans calls ListApplicationPlansFunc.
+func (mock *ThreeScaleInterfaceMock) ListApplicationPlans(accessToken string, serviceID string) ([]ApplicationPlan, error) {
+	if mock.ListApplicationPlansFunc == nil {
+		panic("ThreeScaleInterfaceMock.ListApplicationPlansFunc: method is nil but ThreeScaleInterface.ListApplicationPlans was just called")
+	}
+	callInfo := struct {
+		AccessToken string
+		ServiceID   string
+	}{
+		AccessToken: accessToken,
+		ServiceID:   serviceID,
+	}
+	mock.lockListApplicationPlans.Lock()
+	mock.calls.ListApplicationPlans = append(mock.calls.ListApplicationPlans, callInfo)
+	mock.lockListApplicationPlans.Unlock()
+	return mock.ListApplicationPlansFunc(accessToken, serviceID)
+}
+
+// ListApplicationPlansCalls gets all the calls that were made to ListApplicationPlans.
+// Check the length with:
+//     len(mockedThreeScaleInterface.ListApplicationPlansCalls())
+func (mock *ThreeScaleInterfaceMock) ListApplicationPlansCalls() []struct {
+	AccessToken string
+	ServiceID   string
+} {
+	var calls []struct {
+		AccessToken string
+		ServiceID   string
+	}
+	mock.lockListApplicationPlans.RLock()
+	calls = mock.calls.ListApplicationPlans
+	mock.lockListApplicationPlans.RUnlock()
+	return calls
+}
+
+// ListBackendMappingRules calls ListBackendMappingRulesFunc.
+func (mock *ThreeScaleInterfaceMock) ListBackendMappingRules(accessToken string, backendID int) ([]MappingRule, error) {
+	if mock.ListBackendMappingRulesFunc == nil {
+		panic("ThreeScaleInterfaceMock.ListBackendMappingRulesFunc: method is nil but ThreeScaleInterface.ListBackendMappingRules was just called")
+	}
+	callInfo := struct {
+		AccessToken string
+		BackendID   int
+	}{
+		AccessToken: accessToken,
+		BackendID:   backendID,
+	}
+	mock.lockListBackendMappingRules.Lock()
+	mock.calls.ListBackendMappingRules = append(mock.calls.ListBackendMappingRules, callInfo)
+	mock.lockListBackendMappingRules.Unlock()
+	return mock.ListBackendMappingRulesFunc(accessToken, backendID)
+}
+
+// ListBackendMappingRulesCalls gets all the calls that were made to ListBackendMappingRules.
+// Check the length with:
+//     len(mockedThreeScaleInterface.ListBackendMappingRulesCalls())
+func (mock *ThreeScaleInterfaceMock) ListBackendMappingRulesCalls() []struct {
+	AccessToken string
+	BackendID   int
+} {
+	var calls []struct {
+		AccessToken string
+		BackendID   int
+	}
+	mock.lockListBackendMappingRules.RLock()
+	calls = mock.calls.ListBackendMappingRules
+	mock.lockListBackendMappingRules.RUnlock()
+	return calls
+}
+
+// ListBackendMetrics calls ListBackendMetricsFunc.
+func (mock *ThreeScaleInterfaceMock) ListBackendMetrics(accessToken string, backendID int) ([]Metric, error) {
+	if mock.ListBackendMetricsFunc == nil {
+		panic("ThreeScaleInterfaceMock.ListBackendMetricsFunc: method is nil but ThreeScaleInterface.ListBackendMetrics was just called")
+	}
+	callInfo := struct {
+		AccessToken string
+		BackendID   int
+	}{
+		AccessToken: accessToken,
+		BackendID:   backendID,
+	}
+	mock.lockListBackendMetrics.Lock()
+	mock.calls.ListBackendMetrics = append(mock.calls.ListBackendMetrics, callInfo)
+	mock.lockListBackendMetrics.Unlock()
+	return mock.ListBackendMetricsFunc(accessToken, backendID)
+}
+
+// ListBackendMetricsCalls gets all the calls that were made to ListBackendMetrics.
+// Check the length with:
+//     len(mockedThreeScaleInterface.ListBackendMetricsCalls())
+func (mock *ThreeScaleInterfaceMock) ListBackendMetricsCalls() []struct {
+	AccessToken string
+	BackendID   int
+} {
+	var calls []struct {
+		AccessToken string
+		BackendID   int
+	}
+	mock.lockListBackendMetrics.RLock()
+	calls = mock.calls.ListBackendMetrics
+	mock.lockListBackendMetrics.RUnlock()
+	return calls
+}
+
+// ListBackendUsages calls ListBackendUsagesFunc.
+func (mock *ThreeScaleInterfaceMock) ListBackendUsages(accessToken string, serviceID string) ([]BackendUsage, error) {
+	if mock.ListBackendUsagesFunc == nil {
+		panic("ThreeScaleInterfaceMock.ListBackendUsagesFunc: method is nil but ThreeScaleInterface.ListBackendUsages was just called")
+	}
+	callInfo := struct {
+		AccessToken string
+		ServiceID   string
+	}{
+		AccessToken: accessToken,
+		ServiceID:   serviceID,
+	}
+	mock.lockListBackendUsages.Lock()
+	mock.calls.ListBackendUsages = append(mock.calls.ListBackendUsages, callInfo)
+	mock.lockListBackendUsages.Unlock()
+	return mock.ListBackendUsagesFunc(accessToken, serviceID)
+}
+
+// ListBackendUsagesCalls gets all the calls that were made to ListBackendUsages.
+// Check the length with:
+//     len(mockedThreeScaleInterface.ListBackendUsagesCalls())
+func (mock *ThreeScaleInterfaceMock) ListBackendUsagesCalls() []struct {
+	AccessToken string
+	ServiceID   string
+} {
+	var calls []struct {
+		AccessToken string
+		ServiceID   string
+	}
+	mock.lockListBackendUsages.RLock()
+	calls = mock.calls.ListBackendUsages
+	mock.lockListBackendUsages.RUnlock()
+	return calls
+}
+
+// ListBackends calls ListBackendsFunc.
+func (mock *ThreeScaleInterfaceMock) ListBackends(accessToken string, page int) ([]Backend, error) {
+	if mock.ListBackendsFunc == nil {
+		panic("ThreeScaleInterfaceMock.ListBackendsFunc: method is nil but ThreeScaleInterface.ListBackends was just called")
+	}
+	callInfo := struct {
+		AccessToken string
+		Page        int
+	}{
+		AccessToken: accessToken,
+		Page:        page,
+	}
+	mock.lockListBackends.Lock()
+	mock.calls.ListBackends = append(mock.calls.ListBackends, callInfo)
+	mock.lockListBackends.Unlock()
+	return mock.ListBackendsFunc(accessToken, page)
+}
+
+// ListBackendsCalls gets all the calls that were made to ListBackends.
+// Check the length with:
+//     len(mockedThreeScaleInterface.ListBackendsCalls())
+func (mock *ThreeScaleInterfaceMock) ListBackendsCalls() []struct {
+	AccessToken string
+	Page        int
+} {
+	var calls []struct {
+		AccessToken string
+		Page        int
+	}
+	mock.lockListBackends.RLock()
+	calls = mock.calls.ListBackends
+	mock.lockListBackends.RUnlock()
+	return calls
+}
+
+// ListServiceMappingRules calls ListServiceMappingRulesFunc.
+func (mock *ThreeScaleInterfaceMock) ListServiceMappingRules(accessToken string, serviceID string) ([]MappingRule, error) {
+	if mock.ListServiceMappingRulesFunc == nil {
+		panic("ThreeScaleInterfaceMock.ListServiceMappingRulesFunc: method is nil but ThreeScaleInterface.ListServiceMappingRules was just called")
+	}
+	callInfo := struct {
+		AccessToken string
+		ServiceID   string
+	}{
+		AccessToken: accessToken,
+		ServiceID:   serviceID,
+	}
+	mock.lockListServiceMappingRules.Lock()
+	mock.calls.ListServiceMappingRules = append(mock.calls.ListServiceMappingRules, callInfo)
+	mock.lockListServiceMappingRules.Unlock()
+	return mock.ListServiceMappingRulesFunc(accessToken, serviceID)
+}
+
+// ListServiceMappingRulesCalls gets all the calls that were made to ListServiceMappingRules.
+// Check the length with:
+//     len(mockedThreeScaleInterface.ListServiceMappingRulesCalls())
+func (mock *ThreeScaleInterfaceMock) ListServiceMappingRulesCalls() []struct {
+	AccessToken string
+	ServiceID   string
+} {
+	var calls []struct {
+		AccessToken string
+		ServiceID   string
+	}
+	mock.lockListServiceMappingRules.RLock()
+	calls = mock.calls.ListServiceMappingRules
+	mock.lockListServiceMappingRules.RUnlock()
+	return calls
+}
+
+// ListServiceMetrics calls ListServiceMetricsFunc.
+func (mock *ThreeScaleInterfaceMock) ListServiceMetrics(accessToken string, serviceID string) ([]Metric, error) {
+	if mock.ListServiceMetricsFunc == nil {
+		panic("ThreeScaleInterfaceMock.ListServiceMetricsFunc: method is nil but ThreeScaleInterface.ListServiceMetrics was just called")
+	}
+	callInfo := struct {
+		AccessToken string
+		ServiceID   string
+	}{
+		AccessToken: accessToken,
+		ServiceID:   serviceID,
+	}
+	mock.lockListServiceMetrics.Lock()
+	mock.calls.ListServiceMetrics = append(mock.calls.ListServiceMetrics, callInfo)
+	mock.lockListServiceMetrics.Unlock()
+	return mock.ListServiceMetricsFunc(accessToken, serviceID)
+}
+
+// ListServiceMetricsCalls gets all the calls that were made to ListServiceMetrics.
+// Check the length with:
+//     len(mockedThreeScaleInterface.ListServiceMetricsCalls())
+func (mock *ThreeScaleInterfaceMock) ListServiceMetricsCalls() []struct {
+	AccessToken string
+	ServiceID   string
+} {
+	var calls []struct {
+		AccessToken string
+		ServiceID   string
+	}
+	mock.lockListServiceMetrics.RLock()
+	calls = mock.calls.ListServiceMetrics
+	mock.lockListServiceMetrics.RUnlock()
+	return calls
+}
+
+// ListServices calls ListServicesFunc.
+func (mock *ThreeScaleInterfaceMock) ListServices(accessToken string, page int) ([]Service, error) {
+	if mock.ListServicesFunc == nil {
+		panic("ThreeScaleInterfaceMock.ListServicesFunc: method is nil but ThreeScaleInterface.ListServices was just called")
+	}
+	callInfo := struct {
+		AccessToken string
+		Page        int
+	}{
+		AccessToken: accessToken,
+		Page:        page,
+	}
+	mock.lockListServices.Lock()
+	mock.calls.ListServices = append(mock.calls.ListServices, callInfo)
+	mock.lockListServices.Unlock()
+	return mock.ListServicesFunc(accessToken, page)
+}
+
+// ListServicesCalls gets all the calls that were made to ListServices.
+// Check the length with:
+//     len(mockedThreeScaleInterface.ListServicesCalls())
+func (mock *ThreeScaleInterfaceMock) ListServicesCalls() []struct {
+	AccessToken string
+	Page        int
+} {
+	var calls []struct {
+		AccessToken string
+		Page        int
+	}
+	mock.lockListServices.RLock()
+	calls = mock.calls.ListServices
+	mock.lockListServices.RUnlock()
+	return calls
+}
+
 // ListTenantAccounts calls ListTenantAccountsFunc.
 func (mock *ThreeScaleInterfaceMock) ListTenantAccounts(accessToken string, page int) ([]AccountDetail, error) {
 	if mock.ListTenantAccountsFunc == nil {
@@ -1606,6 +2563,41 @@ func (mock *ThreeScaleInterfaceMock) PromoteProxyCalls() []struct {
 	return calls
 }
 
+// ResumeTenant calls ResumeTenantFunc.
+func (mock *ThreeScaleInterfaceMock) ResumeTenant(accessToken string, id int) error {
+	if mock.ResumeTenantFunc == nil {
+		panic("ThreeScaleInterfaceMock.ResumeTenantFunc: method is nil but ThreeScaleInterface.ResumeTenant was just called")
+	}
+	callInfo := struct {
+		AccessToken string
+		ID          int
+	}{
+		AccessToken: accessToken,
+		ID:          id,
+	}
+	mock.lockResumeTenant.Lock()
+	mock.calls.ResumeTenant = append(mock.calls.ResumeTenant, callInfo)
+	mock.lockResumeTenant.Unlock()
+	return mock.ResumeTenantFunc(accessToken, id)
+}
+
+// ResumeTenantCalls gets all the calls that were made to ResumeTenant.
+// Check the length with:
+//     len(mockedThreeScaleInterface.ResumeTenantCalls())
+func (mock *ThreeScaleInterfaceMock) ResumeTenantCalls() []struct {
+	AccessToken string
+	ID          int
+} {
+	var calls []struct {
+		AccessToken string
+		ID          int
+	}
+	mock.lockResumeTenant.RLock()
+	calls = mock.calls.ResumeTenant
+	mock.lockResumeTenant.RUnlock()
+	return calls
+}
+
 // SetFromEmailAddress calls SetFromEmailAddressFunc.
 func (mock *ThreeScaleInterfaceMock) SetFromEmailAddress(emailAddress string, accessToken string) (*http.Response, error) {
 	if mock.SetFromEmailAddressFunc == nil {
@@ -1742,6 +2734,41 @@ func (mock *ThreeScaleInterfaceMock) SetUserAsMemberCalls() []struct {
 	return calls
 }
 
+// SuspendTenant calls SuspendTenantFunc.
+func (mock *ThreeScaleInterfaceMock) SuspendTenant(accessToken string, id int) error {
+	if mock.SuspendTenantFunc == nil {
+		panic("ThreeScaleInterfaceMock.SuspendTenantFunc: method is nil but ThreeScaleInterface.SuspendTenant was just called")
+	}
+	callInfo := struct {
+		AccessToken string
+		ID          int
+	}{
+		AccessToken: accessToken,
+		ID:          id,
+	}
+	mock.lockSuspendTenant.Lock()
+	mock.calls.SuspendTenant = append(mock.calls.SuspendTenant, callInfo)
+	mock.lockSuspendTenant.Unlock()
+	return mock.SuspendTenantFunc(accessToken, id)
+}
+
+// SuspendTenantCalls gets all the calls that were made to SuspendTenant.
+// Check the length with:
+//     len(mockedThreeScaleInterface.SuspendTenantCalls())
+func (mock *ThreeScaleInterfaceMock) SuspendTenantCalls() []struct {
+	AccessToken string
+	ID          int
+} {
+	var calls []struct {
+		AccessToken string
+		ID          int
+	}
+	mock.lockSuspendTenant.RLock()
+	calls = mock.calls.SuspendTenant
+	mock.lockSuspendTenant.RUnlock()
+	return calls
+}
+
 // UpdateUser calls UpdateUserFunc.
 func (mock *ThreeScaleInterfaceMock) UpdateUser(userID int, username string, email string, accessToken string) (*http.Response, error) {
 	if mock.UpdateUserFunc == nil {
@@ -107,3 +107,61 @@ func tsIsNotFoundError(e error) bool {
 
 	return false
 }
+
+type Service struct {
+	ID         int    `json:"id"`
+	Name       string `json:"name"`
+	SystemName string `json:"system_name"`
+}
+
+type Backend struct {
+	ID              int    `json:"id"`
+	Name            string `json:"name"`
+	SystemName      string `json:"system_name"`
+	PrivateEndpoint string `json:"private_endpoint"`
+}
+
+type Metric struct {
+	ID           int    `json:"id"`
+	FriendlyName string `json:"friendly_name"`
+	SystemName   string `json:"system_name"`
+	Unit         string `json:"unit"`
+}
+
+type MappingRule struct {
+	ID         int    `json:"id"`
+	MetricID   int    `json:"metric_id"`
+	HTTPMethod string `json:"http_method"`
+	Pattern    string `json:"pattern"`
+	Delta      int    `json:"delta"`
+}
+
+type BackendUsage struct {
+	ID        int    `json:"id"`
+	BackendID int    `json:"backend_id"`
+	Path      string `json:"path"`
+}
+
+type ApplicationPlan struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+type Limit struct {
+	ID       int    `json:"id"`
+	MetricID int    `json:"metric_id"`
+	Period   string `json:"period"`
+	Value    int    `json:"value"`
+}
+
+type Application struct {
+	ID          int    `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	PlanID      int    `json:"plan_id"`
+	ServiceID   int    `json:"service_id"`
+	// UserKey is the credential of applications of services authenticated with a user key
+	UserKey string `json:"user_key,omitempty"`
+	// AppID identifies applications of services authenticated with an app ID and app keys
+	AppID string `json:"application_id,omitempty"`
+}
@@ -0,0 +1,134 @@
+package archive
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"path"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	corev1 "k8s.io/api/core/v1"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// S3Scheme is the scheme of locations in an S3 compatible bucket, s3://<bucket>/<key>
+	S3Scheme = "s3://"
+
+	// S3CredentialsSecretName is the secret in the operator namespace with the credentials of the
+	// S3 bucket. The secret has the keys AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and AWS_REGION,
+	// and optionally AWS_S3_ENDPOINT for buckets that are not in AWS
+	S3CredentialsSecretName = "tenant-archive-s3-credentials"
+)
+
+// Store reads and writes the archive at a location
+type Store interface {
+	Write(ctx context.Context, data []byte) error
+	Read(ctx context.Context) ([]byte, error)
+}
+
+// NewStore returns the store of location, which is s3://<bucket>/<key>. The key is relative to
+// prefix in the bucket, so callers sharing the bucket can't read or overwrite the archives under
+// another prefix. The credentials of the bucket are read from a secret in namespace. There is no
+// store for a PVC, the operator pod mounts no volume to write the archives to
+func NewStore(ctx context.Context, serverClient k8sclient.Client, namespace, prefix, location string) (Store, error) {
+	if !strings.HasPrefix(location, S3Scheme) {
+		return nil, fmt.Errorf("unsupported archive location %s, expected %s<bucket>/<key>", location, S3Scheme)
+	}
+	bucket, key, err := parseS3Location(location)
+	if err != nil {
+		return nil, err
+	}
+	key, err = prefixKey(prefix, key)
+	if err != nil {
+		return nil, err
+	}
+	secret := &corev1.Secret{}
+	if err := serverClient.Get(ctx, k8sclient.ObjectKey{Name: S3CredentialsSecretName, Namespace: namespace}, secret); err != nil {
+		return nil, fmt.Errorf("failed to get S3 credentials secret %s: %w", S3CredentialsSecretName, err)
+	}
+	return newS3Store(secret.Data, bucket, key)
+}
+
+type s3Store struct {
+	api    *s3.S3
+	bucket string
+	key    string
+}
+
+func newS3Store(credentialsData map[string][]byte, bucket, key string) (*s3Store, error) {
+	accessKeyID := string(credentialsData["AWS_ACCESS_KEY_ID"])
+	secretAccessKey := string(credentialsData["AWS_SECRET_ACCESS_KEY"])
+	if accessKeyID == "" || secretAccessKey == "" {
+		return nil, fmt.Errorf("S3 credentials secret %s has no access key", S3CredentialsSecretName)
+	}
+
+	cfg := aws.NewConfig().
+		WithCredentials(credentials.NewStaticCredentials(accessKeyID, secretAccessKey, "")).
+		WithRegion(string(credentialsData["AWS_REGION"]))
+	if endpoint := string(credentialsData["AWS_S3_ENDPOINT"]); endpoint != "" {
+		// S3 compatible stores are usually only reachable with path style requests
+		cfg = cfg.WithEndpoint(endpoint).WithS3ForcePathStyle(true)
+	}
+	sess, err := session.NewSession(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create S3 session: %w", err)
+	}
+
+	return &s3Store{api: s3.New(sess), bucket: bucket, key: key}, nil
+}
+
+func (s *s3Store) Write(ctx context.Context, data []byte) error {
+	_, err := s.api.PutObjectWithContext(ctx, &s3.PutObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.key),
+		Body:   bytes.NewReader(data),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to upload archive to bucket %s: %w", s.bucket, err)
+	}
+	return nil
+}
+
+func (s *s3Store) Read(ctx context.Context) ([]byte, error) {
+	out, err := s.api.GetObjectWithContext(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.key),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to download archive from bucket %s: %w", s.bucket, err)
+	}
+	defer out.Body.Close()
+
+	data, err := ioutil.ReadAll(out.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download archive from bucket %s: %w", s.bucket, err)
+	}
+	return data, nil
+}
+
+// prefixKey returns key under prefix, keys that resolve to an object outside of prefix are rejected
+func prefixKey(prefix, key string) (string, error) {
+	prefix = strings.Trim(path.Clean("/"+prefix), "/")
+	if prefix == "" {
+		return "", fmt.Errorf("archive prefix is empty")
+	}
+	prefixedKey := path.Join(prefix, key)
+	if !strings.HasPrefix(prefixedKey, prefix+"/") {
+		return "", fmt.Errorf("archive key %s is outside of %s", key, prefix)
+	}
+	return prefixedKey, nil
+}
+
+func parseS3Location(location string) (string, string, error) {
+	parts := strings.SplitN(strings.TrimPrefix(location, S3Scheme), "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("invalid S3 archive location %s, expected %s<bucket>/<key>", location, S3Scheme)
+	}
+	return parts[0], parts[1], nil
+}
@@ -0,0 +1,223 @@
+package archive
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const (
+	testNamespace = "test-namespace"
+	testPrefix    = "tenant-dev/tenant"
+)
+
+// fakeBucketServer stores the objects uploaded with path style S3 requests
+type fakeBucketServer struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+func (f *fakeBucketServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch r.Method {
+	case http.MethodPut:
+		data, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		f.objects[r.URL.Path] = data
+	case http.MethodGet:
+		data, ok := f.objects[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte("<Error><Code>NoSuchKey</Code></Error>"))
+			return
+		}
+		_, _ = w.Write(data)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func TestNewStore(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	credentials := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: S3CredentialsSecretName, Namespace: testNamespace},
+		Data: map[string][]byte{
+			"AWS_ACCESS_KEY_ID":     []byte("key-id"),
+			"AWS_SECRET_ACCESS_KEY": []byte("secret"),
+			"AWS_REGION":            []byte("us-east-1"),
+		},
+	}
+
+	cases := []struct {
+		Name          string
+		Location      string
+		Objects       []runtime.Object
+		ExpectedError string
+	}{
+		{
+			Name:     "s3 location",
+			Location: "s3://bucket/tenants/tenant.json.gz",
+			Objects:  []runtime.Object{credentials},
+		},
+		{
+			Name:          "pvc location",
+			Location:      "pvc://tenants/tenant.json.gz",
+			ExpectedError: "unsupported archive location",
+		},
+		{
+			Name:          "s3 location without key",
+			Location:      "s3://bucket",
+			Objects:       []runtime.Object{credentials},
+			ExpectedError: "invalid S3 archive location",
+		},
+		{
+			Name:          "s3 location outside of the prefix",
+			Location:      "s3://bucket/../other-tenant/tenant.json.gz",
+			Objects:       []runtime.Object{credentials},
+			ExpectedError: "is outside of",
+		},
+		{
+			Name:          "s3 location without credentials",
+			Location:      "s3://bucket/tenant.json.gz",
+			ExpectedError: "failed to get S3 credentials secret",
+		},
+		{
+			Name:          "unsupported location",
+			Location:      "https://example.com/tenant.json.gz",
+			ExpectedError: "unsupported archive location",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			serverClient := fake.NewFakeClientWithScheme(scheme, tc.Objects...)
+
+			store, err := NewStore(context.TODO(), serverClient, testNamespace, testPrefix, tc.Location)
+			if tc.ExpectedError != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.ExpectedError) {
+					t.Fatalf("expected error %q, got %v", tc.ExpectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if store == nil {
+				t.Fatal("expected store, got nil")
+			}
+		})
+	}
+}
+
+func TestPrefixKey(t *testing.T) {
+	cases := []struct {
+		Name          string
+		Prefix        string
+		Key           string
+		ExpectedKey   string
+		ExpectedError string
+	}{
+		{
+			Name:        "key in the prefix",
+			Prefix:      testPrefix,
+			Key:         "archives/tenant.json.gz",
+			ExpectedKey: "tenant-dev/tenant/archives/tenant.json.gz",
+		},
+		{
+			Name:        "key with the prefix of another tenant",
+			Prefix:      testPrefix,
+			Key:         "other-dev/other/tenant.json.gz",
+			ExpectedKey: "tenant-dev/tenant/other-dev/other/tenant.json.gz",
+		},
+		{
+			Name:          "key resolving outside of the prefix",
+			Prefix:        testPrefix,
+			Key:           "../../other-dev/other/tenant.json.gz",
+			ExpectedError: "is outside of",
+		},
+		{
+			Name:          "key resolving to the prefix",
+			Prefix:        testPrefix,
+			Key:           "archives/..",
+			ExpectedError: "is outside of",
+		},
+		{
+			Name:          "empty prefix",
+			Prefix:        "/",
+			Key:           "tenant.json.gz",
+			ExpectedError: "archive prefix is empty",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			key, err := prefixKey(tc.Prefix, tc.Key)
+			if tc.ExpectedError != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.ExpectedError) {
+					t.Fatalf("expected error %q, got %v", tc.ExpectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if key != tc.ExpectedKey {
+				t.Fatalf("expected key %s, got %s", tc.ExpectedKey, key)
+			}
+		})
+	}
+}
+
+func TestS3Store(t *testing.T) {
+	bucket := &fakeBucketServer{objects: map[string][]byte{}}
+	server := httptest.NewServer(bucket)
+	defer server.Close()
+
+	store, err := newS3Store(map[string][]byte{
+		"AWS_ACCESS_KEY_ID":     []byte("key-id"),
+		"AWS_SECRET_ACCESS_KEY": []byte("secret"),
+		"AWS_REGION":            []byte("us-east-1"),
+		"AWS_S3_ENDPOINT":       []byte(server.URL),
+	}, "bucket", "tenants/tenant.json.gz")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := store.Read(context.TODO()); err == nil {
+		t.Fatal("expected error reading missing archive")
+	}
+	if err := store.Write(context.TODO(), []byte("archive")); err != nil {
+		t.Fatalf("unexpected error writing archive: %v", err)
+	}
+	if _, ok := bucket.objects["/bucket/tenants/tenant.json.gz"]; !ok {
+		t.Fatalf("expected archive to be uploaded with a path style request, got objects %v", bucket.objects)
+	}
+	data, err := store.Read(context.TODO())
+	if err != nil {
+		t.Fatalf("unexpected error reading archive: %v", err)
+	}
+	if string(data) != "archive" {
+		t.Fatalf("expected archive content %q, got %q", "archive", data)
+	}
+
+	if _, err := newS3Store(map[string][]byte{}, "bucket", "tenant.json.gz"); err == nil || !strings.Contains(err.Error(), "has no access key") {
+		t.Fatalf("expected missing access key error, got %v", err)
+	}
+}
@@ -1,22 +1,17 @@
 package resources
 
 import (
-	"crypto/tls"
-	"crypto/x509"
 	"encoding/json"
 	"fmt"
-	k8s "github.com/integr8ly/integreatly-operator/pkg/resources/k8s"
+	integreatlyclient "github.com/integr8ly/integreatly-operator/pkg/client"
 	l "github.com/integr8ly/integreatly-operator/pkg/resources/logger"
 	"io"
-	"io/ioutil"
 	"net/http"
-	"os"
 	"time"
 )
 
 const oauthServerDetails = "%s/.well-known/oauth-authorization-server"
 const defaultHost = "https://openshift.default.svc"
-const rootCAFile = "/var/run/secrets/kubernetes.io/serviceaccount/ca.crt"
 
 type OauthResolver struct {
 	client *http.Client
@@ -24,10 +19,12 @@ type OauthResolver struct {
 	Log    l.Logger
 }
 
-func NewOauthResolver(client *http.Client, log l.Logger) *OauthResolver {
-	client.Timeout = time.Second * 10
+// NewOauthResolver returns a resolver that calls the API server with a client from httpClients,
+// which trusts the CA of the API server when the operator runs in a pod. An operator running
+// locally needs the CA of the cluster in the trusted CA bundle of the installation
+func NewOauthResolver(httpClients *integreatlyclient.HTTPClientFactory, log l.Logger) *OauthResolver {
 	return &OauthResolver{
-		client: client,
+		client: httpClients.Client(time.Second * 10),
 		Host:   defaultHost,
 		Log:    log,
 	}
@@ -36,32 +33,6 @@ func NewOauthResolver(client *http.Client, log l.Logger) *OauthResolver {
 func (or *OauthResolver) GetOauthEndPoint() (*OauthServerConfig, error) {
 	url := fmt.Sprintf(oauthServerDetails, or.Host)
 
-	caCert, err := ioutil.ReadFile(rootCAFile)
-	// if running locally, CA certificate isn't available in expected path
-	if os.IsNotExist(err) && k8s.IsRunLocally() {
-		or.Log.Warning("GetOauthEndPoint() will skip certificate verification - this is acceptable only if operator is running locally")
-		/* #nosec */
-		tr := &http.Transport{
-			TLSClientConfig: &tls.Config{InsecureSkipVerify: true}, // gosec G402 skipped as only ran when operator is running locally (used in rhmi only)
-		}
-		or.client.Transport = tr
-		or.client.Timeout = time.Second * 10
-	} else {
-		if err != nil {
-			return nil, fmt.Errorf("failed to read k8s root CA file: %w", err)
-		}
-		caCertPool := x509.NewCertPool()
-		caCertPool.AppendCertsFromPEM(caCert)
-
-		/* #nosec */
-		tlsConfig := &tls.Config{
-			RootCAs: caCertPool, // gosec G402 skipped as only used in rhmi
-		}
-		tlsConfig.BuildNameToCertificate()
-		transport := &http.Transport{TLSClientConfig: tlsConfig, DisableKeepAlives: true}
-		or.client.Transport = transport
-	}
-
 	resp, err := or.client.Get(url)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get oauth server config from well known endpoint %s: %w", url, err)
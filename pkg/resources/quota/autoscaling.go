@@ -0,0 +1,129 @@
+package quota
+
+import (
+	"context"
+	"fmt"
+
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
+	corev1 "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// RequestsPerSecondMetricName is the pods metric of the custom metrics API the autoscalers with a
+// requests per second target scale on, it has to be served by a custom metrics adapter. The operator
+// does not install one, so the requests per second target is only accepted along with a cpu
+// utilization target the autoscaler can still scale on without the adapter
+const RequestsPerSecondMetricName = "requests_per_second"
+
+// AutoscalingConfig configures a horizontal pod autoscaler for a component instead of fixed
+// replicas, the replicas of the component are then kept between the min and max replicas
+type AutoscalingConfig struct {
+	MinReplicas int32 `json:"min_replicas"`
+	MaxReplicas int32 `json:"max_replicas"`
+	// TargetCPUUtilization is the average cpu usage of the pods as a percentage of their cpu requests
+	TargetCPUUtilization *int32 `json:"target_cpu_utilization,omitempty"`
+	// TargetRequestsPerSecond is the average requests per second of the pods, it requires
+	// TargetCPUUtilization to be set too
+	TargetRequestsPerSecond *int32 `json:"target_requests_per_second,omitempty"`
+}
+
+// GetAutoscaling returns the autoscaling of the component, or nil if it has fixed replicas
+func (p QuotaProductConfig) GetAutoscaling(ddcssName string) *AutoscalingConfig {
+	return p.resourceConfigs[ddcssName].Autoscaling
+}
+
+// GetReplicas returns the replicas the autoscaler keeps the current replicas at
+func (a *AutoscalingConfig) GetReplicas(current int32) int32 {
+	if current < a.MinReplicas {
+		return a.MinReplicas
+	}
+	if current > a.MaxReplicas {
+		return a.MaxReplicas
+	}
+	return current
+}
+
+func (a *AutoscalingConfig) validate() error {
+	if a.MinReplicas < 1 {
+		return fmt.Errorf("the min replicas must be at least 1")
+	}
+	if a.MaxReplicas < a.MinReplicas {
+		return fmt.Errorf("the max replicas must be at least the min replicas")
+	}
+	if a.TargetCPUUtilization == nil {
+		return fmt.Errorf("a cpu utilization target is required, the requests per second target can only be added to it")
+	}
+	if a.TargetCPUUtilization != nil && *a.TargetCPUUtilization <= 0 {
+		return fmt.Errorf("the cpu utilization target must be greater than 0")
+	}
+	if a.TargetRequestsPerSecond != nil && *a.TargetRequestsPerSecond <= 0 {
+		return fmt.Errorf("the requests per second target must be greater than 0")
+	}
+	return nil
+}
+
+func (a *AutoscalingConfig) getMetrics() []autoscalingv2beta2.MetricSpec {
+	metrics := []autoscalingv2beta2.MetricSpec{}
+	if a.TargetCPUUtilization != nil {
+		utilization := *a.TargetCPUUtilization
+		metrics = append(metrics, autoscalingv2beta2.MetricSpec{
+			Type: autoscalingv2beta2.ResourceMetricSourceType,
+			Resource: &autoscalingv2beta2.ResourceMetricSource{
+				Name: corev1.ResourceCPU,
+				Target: autoscalingv2beta2.MetricTarget{
+					Type:               autoscalingv2beta2.UtilizationMetricType,
+					AverageUtilization: &utilization,
+				},
+			},
+		})
+	}
+	if a.TargetRequestsPerSecond != nil {
+		metrics = append(metrics, autoscalingv2beta2.MetricSpec{
+			Type: autoscalingv2beta2.PodsMetricSourceType,
+			Pods: &autoscalingv2beta2.PodsMetricSource{
+				Metric: autoscalingv2beta2.MetricIdentifier{Name: RequestsPerSecondMetricName},
+				Target: autoscalingv2beta2.MetricTarget{
+					Type:         autoscalingv2beta2.AverageValueMetricType,
+					AverageValue: resource.NewQuantity(int64(*a.TargetRequestsPerSecond), resource.DecimalSI),
+				},
+			},
+		})
+	}
+	return metrics
+}
+
+// ReconcileAutoscaler creates or updates the horizontal pod autoscaler of the component, named
+// after the target it scales, if the quota autoscales the component and deletes it otherwise
+func ReconcileAutoscaler(ctx context.Context, client k8sclient.Client, productConfig ProductConfig, ddcssName, namespace string, target autoscalingv2beta2.CrossVersionObjectReference) error {
+	hpa := &autoscalingv2beta2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      target.Name,
+			Namespace: namespace,
+		},
+	}
+
+	autoscaling := productConfig.GetAutoscaling(ddcssName)
+	if autoscaling == nil {
+		if err := client.Delete(ctx, hpa); err != nil && !k8serr.IsNotFound(err) {
+			return fmt.Errorf("failed to delete horizontal pod autoscaler of %s: %w", ddcssName, err)
+		}
+		return nil
+	}
+
+	_, err := controllerutil.CreateOrUpdate(ctx, client, hpa, func() error {
+		minReplicas := autoscaling.MinReplicas
+		hpa.Spec.ScaleTargetRef = target
+		hpa.Spec.MinReplicas = &minReplicas
+		hpa.Spec.MaxReplicas = autoscaling.MaxReplicas
+		hpa.Spec.Metrics = autoscaling.getMetrics()
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to reconcile horizontal pod autoscaler of %s: %w", ddcssName, err)
+	}
+	return nil
+}
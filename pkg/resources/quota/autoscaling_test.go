@@ -0,0 +1,151 @@
+package quota
+
+import (
+	"context"
+	"testing"
+
+	threescalev1 "github.com/3scale/3scale-operator/apis/apps/v1alpha1"
+	"github.com/integr8ly/integreatly-operator/apis/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func getAutoscaledProductConfig(productName v1alpha1.ProductName, autoscaling map[string]*AutoscalingConfig) QuotaProductConfig {
+	productConfig := QuotaProductConfig{
+		productName:     productName,
+		resourceConfigs: map[string]ResourceConfig{},
+		quota:           &Quota{name: "autoscaled", isUpdated: true},
+	}
+	for name, config := range autoscaling {
+		productConfig.resourceConfigs[name] = ResourceConfig{Replicas: 2, Autoscaling: config}
+	}
+	return productConfig
+}
+
+func TestQuotaProductConfig_ConfigureAutoscaled(t *testing.T) {
+	cpu := int32(80)
+	autoscaling := &AutoscalingConfig{MinReplicas: 2, MaxReplicas: 6, TargetCPUUtilization: &cpu}
+
+	tests := []struct {
+		name    string
+		current int32
+		want    int32
+	}{
+		{
+			name:    "test replicas scaled by the autoscaler are kept",
+			current: 4,
+			want:    4,
+		},
+		{
+			name:    "test replicas below the min replicas are raised",
+			current: 0,
+			want:    2,
+		},
+		{
+			name:    "test replicas above the max replicas are lowered",
+			current: 10,
+			want:    6,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			productConfig := getAutoscaledProductConfig(v1alpha1.ProductMarin3r, map[string]*AutoscalingConfig{RateLimitName: autoscaling})
+			replicas := tt.current
+			deployment := &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Name: RateLimitName},
+				Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+			}
+			if err := productConfig.Configure(deployment); err != nil {
+				t.Fatal(err)
+			}
+			if *deployment.Spec.Replicas != tt.want {
+				t.Errorf("expected deployment replicas %d, got %d", tt.want, *deployment.Spec.Replicas)
+			}
+
+			productConfig = getAutoscaledProductConfig(v1alpha1.Product3Scale, map[string]*AutoscalingConfig{BackendListenerName: autoscaling})
+			apiManagerReplicas := int64(tt.current)
+			apim := &threescalev1.APIManager{
+				Spec: threescalev1.APIManagerSpec{
+					Backend: &threescalev1.BackendSpec{
+						ListenerSpec: &threescalev1.BackendListenerSpec{Replicas: &apiManagerReplicas},
+					},
+				},
+			}
+			if err := productConfig.Configure(apim); err != nil {
+				t.Fatal(err)
+			}
+			if *apim.Spec.Backend.ListenerSpec.Replicas != int64(tt.want) {
+				t.Errorf("expected api manager replicas %d, got %d", tt.want, *apim.Spec.Backend.ListenerSpec.Replicas)
+			}
+			if productConfig.GetReplicas(BackendListenerName) != autoscaling.MinReplicas {
+				t.Errorf("expected the replicas of an autoscaled component to be the min replicas")
+			}
+		})
+	}
+}
+
+func TestReconcileAutoscaler(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := autoscalingv2beta2.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	cpu, rps := int32(80), int32(100)
+	target := autoscalingv2beta2.CrossVersionObjectReference{APIVersion: "apps/v1", Kind: "Deployment", Name: RateLimitName}
+	existing := &autoscalingv2beta2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{Name: RateLimitName, Namespace: "marin3r"},
+	}
+
+	tests := []struct {
+		name        string
+		autoscaling *AutoscalingConfig
+		initObjs    []runtime.Object
+		verify      func(t *testing.T, hpa *autoscalingv2beta2.HorizontalPodAutoscaler, err error)
+	}{
+		{
+			name:        "test autoscaler is created for an autoscaled component",
+			autoscaling: &AutoscalingConfig{MinReplicas: 2, MaxReplicas: 6, TargetCPUUtilization: &cpu, TargetRequestsPerSecond: &rps},
+			verify: func(t *testing.T, hpa *autoscalingv2beta2.HorizontalPodAutoscaler, err error) {
+				if err != nil {
+					t.Fatal(err)
+				}
+				if hpa.Spec.ScaleTargetRef != target || *hpa.Spec.MinReplicas != 2 || hpa.Spec.MaxReplicas != 6 {
+					t.Errorf("unexpected autoscaler spec %+v", hpa.Spec)
+				}
+				if len(hpa.Spec.Metrics) != 2 || *hpa.Spec.Metrics[0].Resource.Target.AverageUtilization != cpu ||
+					hpa.Spec.Metrics[1].Pods.Metric.Name != RequestsPerSecondMetricName || hpa.Spec.Metrics[1].Pods.Target.AverageValue.Value() != 100 {
+					t.Errorf("unexpected autoscaler metrics %+v", hpa.Spec.Metrics)
+				}
+			},
+		},
+		{
+			name:     "test autoscaler is deleted for a component with fixed replicas",
+			initObjs: []runtime.Object{existing},
+			verify: func(t *testing.T, _ *autoscalingv2beta2.HorizontalPodAutoscaler, err error) {
+				if !k8serr.IsNotFound(err) {
+					t.Errorf("expected the autoscaler to be deleted, got %v", err)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := fake.NewFakeClientWithScheme(scheme, tt.initObjs...)
+			productConfig := getAutoscaledProductConfig(v1alpha1.ProductMarin3r, map[string]*AutoscalingConfig{RateLimitName: tt.autoscaling})
+			if tt.autoscaling == nil {
+				productConfig.resourceConfigs[RateLimitName] = ResourceConfig{Replicas: 2}
+			}
+
+			if err := ReconcileAutoscaler(context.TODO(), client, productConfig, RateLimitName, "marin3r", target); err != nil {
+				t.Fatal(err)
+			}
+			hpa := &autoscalingv2beta2.HorizontalPodAutoscaler{}
+			err := client.Get(context.TODO(), k8sclient.ObjectKey{Name: RateLimitName, Namespace: "marin3r"}, hpa)
+			tt.verify(t, hpa, err)
+		})
+	}
+}
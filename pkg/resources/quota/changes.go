@@ -0,0 +1,89 @@
+package quota
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/integr8ly/integreatly-operator/apis/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// GetChanges returns the components with different replicas or resources in the quotas, sorted by
+// product and component name
+func GetChanges(from, to *Quota) []v1alpha1.QuotaComponentChange {
+	changes := []v1alpha1.QuotaComponentChange{}
+	for productName, toConfig := range to.productConfigs {
+		fromConfig := from.productConfigs[productName]
+		for name, toResourceConfig := range toConfig.resourceConfigs {
+			fromResourceConfig := fromConfig.resourceConfigs[name]
+			if fromResourceConfig.Replicas == toResourceConfig.Replicas && equalResources(fromResourceConfig.Resources, toResourceConfig.Resources) &&
+				reflect.DeepEqual(fromResourceConfig.Autoscaling, toResourceConfig.Autoscaling) {
+				continue
+			}
+			changes = append(changes, v1alpha1.QuotaComponentChange{
+				Product:       productName,
+				Component:     name,
+				FromReplicas:  fromConfig.GetReplicas(name),
+				ToReplicas:    toConfig.GetReplicas(name),
+				FromResources: formatResources(fromResourceConfig.Resources),
+				ToResources:   formatResources(toResourceConfig.Resources),
+			})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].Product != changes[j].Product {
+			return changes[i].Product < changes[j].Product
+		}
+		return changes[i].Component < changes[j].Component
+	})
+	return changes
+}
+
+// GetChangedProducts returns the products of the changes in the order of the changes
+func GetChangedProducts(changes []v1alpha1.QuotaComponentChange) []v1alpha1.ProductName {
+	products := []v1alpha1.ProductName{}
+	for _, change := range changes {
+		if len(products) == 0 || products[len(products)-1] != change.Product {
+			products = append(products, change.Product)
+		}
+	}
+	return products
+}
+
+// equalResources compares the quantities of the resources, so "1" and "1000m" cpu are equal
+func equalResources(a, b corev1.ResourceRequirements) bool {
+	return equalResourceList(a.Requests, b.Requests) && equalResourceList(a.Limits, b.Limits)
+}
+
+func equalResourceList(a, b corev1.ResourceList) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for name, quantity := range a {
+		other, ok := b[name]
+		if !ok || quantity.Cmp(other) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// formatResources returns the requests and limits as "requests: cpu=250m,memory=450Mi limits: cpu=300m,memory=500Mi"
+func formatResources(resources corev1.ResourceRequirements) string {
+	if reflect.DeepEqual(resources, corev1.ResourceRequirements{}) {
+		return ""
+	}
+	return fmt.Sprintf("requests: %s limits: %s", formatResourceList(resources.Requests), formatResourceList(resources.Limits))
+}
+
+func formatResourceList(resources corev1.ResourceList) string {
+	values := make([]string, 0, len(resources))
+	for name, quantity := range resources {
+		values = append(values, fmt.Sprintf("%s=%s", name, quantity.String()))
+	}
+	sort.Strings(values)
+	return strings.Join(values, ",")
+}
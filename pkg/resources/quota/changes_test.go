@@ -0,0 +1,106 @@
+package quota
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/integr8ly/integreatly-operator/apis/v1alpha1"
+)
+
+func TestGetChanges(t *testing.T) {
+	RegisterProductComponents(v1alpha1.Product3Scale, []string{BackendListenerName, BackendWorkerName, ApicastProductionName, ApicastStagingName})
+	RegisterProductComponents(v1alpha1.ProductRHSSOUser, []string{KeycloakName})
+	RegisterProductComponents(v1alpha1.ProductMarin3r, []string{RateLimitName})
+	RegisterProductComponents(v1alpha1.ProductGrafana, []string{GrafanaName})
+
+	dev, twentyMillion := &Quota{}, &Quota{}
+	if err := GetQuota(DEVQUOTAPARAM, getQuotaConfig(nil), dev); err != nil {
+		t.Fatal(err)
+	}
+	if err := GetQuota(TWENTYMILLIONQUOTAPARAM, getQuotaConfig(nil), twentyMillion); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name         string
+		from         *Quota
+		to           *Quota
+		want         []v1alpha1.QuotaComponentChange
+		wantProducts []v1alpha1.ProductName
+	}{
+		{
+			name:         "test no changes to the same quota",
+			from:         dev,
+			to:           dev,
+			want:         []v1alpha1.QuotaComponentChange{},
+			wantProducts: []v1alpha1.ProductName{},
+		},
+		{
+			name: "test changes of replicas and resources",
+			from: dev,
+			to:   twentyMillion,
+			want: []v1alpha1.QuotaComponentChange{
+				{
+					Product:       v1alpha1.Product3Scale,
+					Component:     ApicastProductionName,
+					FromReplicas:  1,
+					ToReplicas:    0,
+					FromResources: "requests: cpu=50m,memory=50Mi limits: cpu=150m,memory=100Mi",
+				},
+				{
+					Product:      v1alpha1.Product3Scale,
+					Component:    BackendListenerName,
+					FromReplicas: 0,
+					ToReplicas:   3,
+					ToResources:  "requests: cpu=250m,memory=450 limits: cpu=300m,memory=500",
+				},
+			},
+			wantProducts: []v1alpha1.ProductName{v1alpha1.Product3Scale},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := GetChanges(tt.from, tt.to)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetChanges() = %+v, want %+v", got, tt.want)
+			}
+			if products := GetChangedProducts(got); !reflect.DeepEqual(products, tt.wantProducts) {
+				t.Errorf("GetChangedProducts() = %v, want %v", products, tt.wantProducts)
+			}
+		})
+	}
+}
+
+func TestQuota_SetRollout(t *testing.T) {
+	RegisterProductComponents(v1alpha1.Product3Scale, []string{BackendListenerName, BackendWorkerName, ApicastProductionName, ApicastStagingName})
+	RegisterProductComponents(v1alpha1.ProductMarin3r, []string{RateLimitName})
+
+	dev, twentyMillion := &Quota{}, &Quota{}
+	if err := GetQuota(DEVQUOTAPARAM, getQuotaConfig(nil), dev); err != nil {
+		t.Fatal(err)
+	}
+	if err := GetQuota(TWENTYMILLIONQUOTAPARAM, getQuotaConfig(nil), twentyMillion); err != nil {
+		t.Fatal(err)
+	}
+	twentyMillion.SetRollout(dev, []v1alpha1.ProductName{v1alpha1.Product3Scale})
+
+	threescale := twentyMillion.GetProduct(v1alpha1.Product3Scale)
+	if threescale.GetActiveQuota() != TWENTYMILLIONQUOTACONFIGNAME || threescale.GetReplicas(BackendListenerName) != 3 || !threescale.isUpdated() {
+		t.Errorf("expected the quota to be rolled out to 3scale as an updated quota")
+	}
+	marin3r := twentyMillion.GetProduct(v1alpha1.ProductMarin3r)
+	if marin3r.GetActiveQuota() != DEVQUOTACONFIGNAME || marin3r.isUpdated() {
+		t.Errorf("expected marin3r to keep the previous quota")
+	}
+	if twentyMillion.IsUpdated() {
+		t.Errorf("expected the quota not to be updated until it is rolled out to every product")
+	}
+
+	// getting the quota again resets the rollout
+	if err := GetQuota(TWENTYMILLIONQUOTAPARAM, getQuotaConfig(nil), twentyMillion); err != nil {
+		t.Fatal(err)
+	}
+	if twentyMillion.GetProduct(v1alpha1.ProductMarin3r).GetActiveQuota() != TWENTYMILLIONQUOTACONFIGNAME {
+		t.Errorf("expected the quota to be applied to every product after getting it again")
+	}
+}
@@ -0,0 +1,222 @@
+package quota
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	marin3rconfig "github.com/integr8ly/integreatly-operator/pkg/products/marin3r/config"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// CustomConfigMapName is the config map in the operator namespace the cluster admin can create to
+// add quotas, or override the quotas with the same param, with the same format as the quota config
+const CustomConfigMapName = "quota-config-custom"
+
+var (
+	// the most replicas and resources a custom quota can set for a component
+	maxCustomReplicas int32 = 15
+	maxCustomCPU            = resource.MustParse("4")
+	maxCustomMemory         = resource.MustParse("8Gi")
+)
+
+// CustomQuotaError is returned by GetQuota when the quota param selects a custom quota that was
+// rejected
+type CustomQuotaError struct {
+	Param string
+	Err   error
+}
+
+func (e *CustomQuotaError) Error() string {
+	return fmt.Sprintf("custom quota for the '%s' quota parameter was rejected: %v", e.Param, e.Err)
+}
+
+// customQuotaConfig is the schema of a custom quota, the same as the quota config with the alert
+// limits the shipped quotas define under the rate limiting
+type customQuotaConfig struct {
+	Name      string                    `json:"name"`
+	Param     string                    `json:"param"`
+	RateLimit customRateLimitConfig     `json:"rate-limiting"`
+	Resources map[string]ResourceConfig `json:"resources,omitempty"`
+}
+
+type customRateLimitConfig struct {
+	marin3rconfig.RateLimitConfig
+	AlertLimits json.RawMessage `json:"alert_limits,omitempty"`
+}
+
+// AddCustomQuotas adds the valid quotas of the custom config to the quota config, a custom quota
+// replaces the quota with the same param. The names of the accepted custom quotas are returned,
+// and why each invalid one was rejected by its param
+func AddCustomQuotas(quotaConfig, customConfig *corev1.ConfigMap) (accepted []string, rejected map[string]error, err error) {
+	rawQuotas := []json.RawMessage{}
+	if err := json.Unmarshal([]byte(quotaConfig.Data[ConfigMapData]), &rawQuotas); err != nil {
+		return nil, nil, err
+	}
+	allQuotas := []quotaConfigReceiver{}
+	if err := json.Unmarshal([]byte(quotaConfig.Data[ConfigMapData]), &allQuotas); err != nil {
+		return nil, nil, err
+	}
+
+	customQuotas := []json.RawMessage{}
+	if err := json.Unmarshal([]byte(customConfig.Data[ConfigMapData]), &customQuotas); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse the %s key of config map %s: %w", ConfigMapData, CustomConfigMapName, err)
+	}
+
+	rejected = map[string]error{}
+	params := map[string]bool{}
+	names := map[string]bool{}
+	for i, raw := range customQuotas {
+		// the name and param identify the quota in the errors, even if it doesn't match the schema
+		quota := quotaConfigReceiver{}
+		if err := json.Unmarshal(raw, &quota); err != nil {
+			rejected[fmt.Sprintf("#%d", i)] = fmt.Errorf("invalid custom quota %d: %w", i, err)
+			continue
+		}
+		if err := validateCustomQuota(raw, quota, allQuotas); err != nil {
+			rejected[quota.Param] = fmt.Errorf("invalid custom quota %q: %w", quota.Name, err)
+			continue
+		}
+		if params[quota.Param] || names[quota.Name] {
+			rejected[quota.Param] = fmt.Errorf("invalid custom quota %q: another custom quota has the same name or param", quota.Name)
+			continue
+		}
+		params[quota.Param] = true
+		names[quota.Name] = true
+
+		replaced := false
+		for j := range allQuotas {
+			if allQuotas[j].Param == quota.Param {
+				rawQuotas[j] = raw
+				replaced = true
+			}
+		}
+		if !replaced {
+			rawQuotas = append(rawQuotas, raw)
+		}
+		accepted = append(accepted, quota.Name)
+	}
+
+	data, err := json.Marshal(rawQuotas)
+	if err != nil {
+		return nil, nil, err
+	}
+	quotaConfig.Data[ConfigMapData] = string(data)
+	return accepted, rejected, nil
+}
+
+// validateCustomQuota returns an error when the quota can't be applied, or when its name is
+// already used by a quota with a different param
+func validateCustomQuota(raw json.RawMessage, quota quotaConfigReceiver, allQuotas []quotaConfigReceiver) error {
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&customQuotaConfig{}); err != nil {
+		return err
+	}
+	if quota.Name == "" || quota.Param == "" {
+		return fmt.Errorf("the name and param are required")
+	}
+	for _, other := range allQuotas {
+		if other.Name == quota.Name && other.Param != quota.Param {
+			return fmt.Errorf("the name is used by the quota with param %q", other.Param)
+		}
+	}
+
+	if err := quota.RateLimit.Validate(); err != nil {
+		return fmt.Errorf("invalid rate-limiting: %w", err)
+	}
+	if _, err := marin3rconfig.ConvertRate(quota.RateLimit.Unit, "second", 1); err != nil {
+		return fmt.Errorf("invalid rate-limiting: unsupported unit %q", quota.RateLimit.Unit)
+	}
+	if quota.RateLimit.RequestsPerUnit == 0 {
+		return fmt.Errorf("invalid rate-limiting: the requests per unit must be greater than 0")
+	}
+
+	components := map[string]bool{}
+	productsLock.RLock()
+	for _, ddcssNames := range products {
+		for _, name := range ddcssNames {
+			components[name] = true
+		}
+	}
+	productsLock.RUnlock()
+
+	names := make([]string, 0, len(quota.Resources))
+	for name := range quota.Resources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if !components[name] {
+			return fmt.Errorf("unknown component %q", name)
+		}
+		if err := validateResourceConfig(quota.Resources[name]); err != nil {
+			return fmt.Errorf("invalid resources of %s: %w", name, err)
+		}
+	}
+
+	// a component without resources in the quota would be scaled to 0 replicas with no resources
+	missing := []string{}
+	for name := range components {
+		if _, ok := quota.Resources[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return fmt.Errorf("the resources of every component are required, missing %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+func validateResourceConfig(config ResourceConfig) error {
+	// the replicas of an autoscaled component are set by its autoscaler
+	var minReplicas int32 = 1
+	if config.Autoscaling != nil {
+		minReplicas = 0
+	}
+	if config.Replicas < minReplicas || config.Replicas > maxCustomReplicas {
+		return fmt.Errorf("replicas must be between %d and %d", minReplicas, maxCustomReplicas)
+	}
+	if config.Autoscaling != nil {
+		if err := config.Autoscaling.validate(); err != nil {
+			return fmt.Errorf("invalid autoscaling: %w", err)
+		}
+		if config.Autoscaling.MaxReplicas > maxCustomReplicas {
+			return fmt.Errorf("invalid autoscaling: the max replicas must be at most %d", maxCustomReplicas)
+		}
+	}
+	for _, name := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+		if _, ok := config.Resources.Requests[name]; !ok {
+			return fmt.Errorf("a %s request is required", name)
+		}
+		if _, ok := config.Resources.Limits[name]; !ok {
+			return fmt.Errorf("a %s limit is required", name)
+		}
+	}
+	for _, resources := range []corev1.ResourceList{config.Resources.Requests, config.Resources.Limits} {
+		for name, quantity := range resources {
+			var max resource.Quantity
+			switch name {
+			case corev1.ResourceCPU:
+				max = maxCustomCPU
+			case corev1.ResourceMemory:
+				max = maxCustomMemory
+			default:
+				return fmt.Errorf("unsupported resource %s, only cpu and memory can be set", name)
+			}
+			if quantity.Sign() <= 0 || quantity.Cmp(max) > 0 {
+				return fmt.Errorf("%s must be greater than 0 and at most %s", name, max.String())
+			}
+		}
+	}
+	for name, request := range config.Resources.Requests {
+		if limit, ok := config.Resources.Limits[name]; ok && request.Cmp(limit) > 0 {
+			return fmt.Errorf("the %s request is greater than the limit", name)
+		}
+	}
+	return nil
+}
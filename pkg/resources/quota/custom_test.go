@@ -0,0 +1,216 @@
+package quota
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/integr8ly/integreatly-operator/apis/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func getCustomQuotaConfig(data string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: CustomConfigMapName},
+		Data:       map[string]string{ConfigMapData: data},
+	}
+}
+
+// setProductComponents replaces the registered components of the products for the test
+func setProductComponents(t *testing.T, components map[v1alpha1.ProductName][]string) {
+	productsLock.Lock()
+	previous := products
+	products = components
+	productsLock.Unlock()
+	t.Cleanup(func() {
+		productsLock.Lock()
+		products = previous
+		productsLock.Unlock()
+	})
+}
+
+// customQuota returns a custom quota with the resources of every component in
+// customQuotaComponents, resources replaces the resources of a component or removes them when empty
+func customQuota(name, param string, resources map[string]string) string {
+	components := []string{}
+	for _, component := range customQuotaComponents {
+		config, ok := resources[component]
+		if !ok {
+			config = `{"replicas": 1, "resources": {"requests": {"cpu": "100m", "memory": "100Mi"}, "limits": {"cpu": "200m", "memory": "200Mi"}}}`
+		}
+		if config != "" {
+			components = append(components, fmt.Sprintf(`%q: %s`, component, config))
+		}
+	}
+	for component, config := range resources {
+		if !contains(customQuotaComponents, component) {
+			components = append(components, fmt.Sprintf(`%q: %s`, component, config))
+		}
+	}
+	return fmt.Sprintf(`{"name": %q, "param": %q, "rate-limiting": {"unit": "minute", "requests_per_unit": 10}, "resources": {%s}}`, name, param, strings.Join(components, ", "))
+}
+
+var customQuotaComponents = []string{BackendListenerName, BackendWorkerName, ApicastProductionName, ApicastStagingName, RateLimitName}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+func TestAddCustomQuotas(t *testing.T) {
+	setProductComponents(t, map[v1alpha1.ProductName][]string{
+		v1alpha1.Product3Scale:  {BackendListenerName, BackendWorkerName, ApicastProductionName, ApicastStagingName},
+		v1alpha1.ProductMarin3r: {RateLimitName},
+	})
+
+	tests := []struct {
+		name         string
+		customData   string
+		wantErr      bool
+		wantAccepted []string
+		wantRejected map[string]string
+		verify       func(t *testing.T, quotaConfig *corev1.ConfigMap)
+	}{
+		{
+			name: "test custom quota is added",
+			customData: `[` + customQuota("5 Million", "50", map[string]string{
+				ApicastProductionName: `{"replicas": 4, "resources": {"requests": {"cpu": "500m", "memory": "250Mi"}, "limits": {"cpu": "1", "memory": "500Mi"}}}`,
+			}) + `]`,
+			wantAccepted: []string{"5 Million"},
+			wantRejected: map[string]string{},
+			verify: func(t *testing.T, quotaConfig *corev1.ConfigMap) {
+				quota := &Quota{}
+				if err := GetQuota("50", quotaConfig, quota); err != nil {
+					t.Fatal(err)
+				}
+				threescale := quota.GetProduct(v1alpha1.Product3Scale)
+				if threescale.GetReplicas(ApicastProductionName) != 4 || threescale.GetReplicas(BackendWorkerName) != 1 {
+					t.Errorf("expected the replicas of the custom quota")
+				}
+				if err := GetQuota(DEVQUOTAPARAM, quotaConfig, quota); err != nil {
+					t.Errorf("expected the shipped quotas to be kept: %v", err)
+				}
+			},
+		},
+		{
+			name: "test autoscaled custom quota is added",
+			customData: `[` + customQuota("Bursty", "60", map[string]string{
+				BackendListenerName: `{"resources": {"requests": {"cpu": "250m", "memory": "250Mi"}, "limits": {"cpu": "500m", "memory": "500Mi"}}, "autoscaling": {"min_replicas": 2, "max_replicas": 8, "target_cpu_utilization": 75}}`,
+			}) + `]`,
+			wantAccepted: []string{"Bursty"},
+			wantRejected: map[string]string{},
+			verify: func(t *testing.T, quotaConfig *corev1.ConfigMap) {
+				quota := &Quota{}
+				if err := GetQuota("60", quotaConfig, quota); err != nil {
+					t.Fatal(err)
+				}
+				autoscaling := quota.GetProduct(v1alpha1.Product3Scale).GetAutoscaling(BackendListenerName)
+				if autoscaling == nil || autoscaling.MaxReplicas != 8 || *autoscaling.TargetCPUUtilization != 75 {
+					t.Errorf("expected the autoscaling of the custom quota, got %+v", autoscaling)
+				}
+			},
+		},
+		{
+			name: "test custom quota overrides the quota with the same param",
+			customData: `[` + customQuota(DEVQUOTACONFIGNAME, DEVQUOTAPARAM, map[string]string{
+				ApicastProductionName: `{"replicas": 2, "resources": {"requests": {"cpu": "100m", "memory": "100Mi"}, "limits": {"cpu": "200m", "memory": "200Mi"}}}`,
+			}) + `]`,
+			wantAccepted: []string{DEVQUOTACONFIGNAME},
+			wantRejected: map[string]string{},
+			verify: func(t *testing.T, quotaConfig *corev1.ConfigMap) {
+				quota := &Quota{}
+				if err := GetQuota(DEVQUOTAPARAM, quotaConfig, quota); err != nil {
+					t.Fatal(err)
+				}
+				if quota.GetProduct(v1alpha1.Product3Scale).GetReplicas(ApicastProductionName) != 2 || quota.GetRateLimitConfig().RequestsPerUnit != 10 {
+					t.Errorf("expected the custom quota to override the shipped quota")
+				}
+			},
+		},
+		{
+			name: "test invalid custom quotas are rejected",
+			customData: `[` + strings.Join([]string{
+				customQuota("typo", "1", map[string]string{ApicastProductionName: `{"replica": 2}`}),
+				customQuota("too many replicas", "2", map[string]string{ApicastProductionName: `{"replicas": 16}`}),
+				customQuota("too much memory", "3", map[string]string{ApicastProductionName: `{"replicas": 1, "resources": {"requests": {"cpu": "1", "memory": "1Gi"}, "limits": {"cpu": "1", "memory": "9Gi"}}}`}),
+				customQuota("request over limit", "4", map[string]string{ApicastProductionName: `{"replicas": 1, "resources": {"requests": {"cpu": "2", "memory": "1Gi"}, "limits": {"cpu": "1", "memory": "1Gi"}}}`}),
+				customQuota("unknown component", "5", map[string]string{"apicast": `{"replicas": 1}`}),
+				`{"name": "unknown unit", "param": "6", "rate-limiting": {"unit": "week", "requests_per_unit": 10}}`,
+				customQuota(DEVQUOTACONFIGNAME, "7", nil),
+				customQuota("duplicate", "8", nil),
+				customQuota("duplicate", "9", nil),
+				`"not a quota"`,
+				customQuota("autoscaling over max", "10", map[string]string{BackendListenerName: `{"autoscaling": {"min_replicas": 2, "max_replicas": 20, "target_cpu_utilization": 80}}`}),
+				customQuota("no requests", "11", map[string]string{BackendListenerName: `{"replicas": 1, "resources": {"limits": {"cpu": "1", "memory": "1Gi"}}}`}),
+				customQuota("autoscaling without target", "12", map[string]string{BackendListenerName: `{"autoscaling": {"min_replicas": 2, "max_replicas": 6}}`}),
+				customQuota("no replicas", "13", map[string]string{BackendWorkerName: `{"resources": {"requests": {"cpu": "100m", "memory": "100Mi"}, "limits": {"cpu": "200m", "memory": "200Mi"}}}`}),
+				customQuota("no memory limit", "14", map[string]string{BackendWorkerName: `{"replicas": 1, "resources": {"requests": {"cpu": "100m", "memory": "100Mi"}, "limits": {"cpu": "200m"}}}`}),
+				customQuota("partial", "15", map[string]string{RateLimitName: "", ApicastStagingName: ""}),
+				customQuota("requests per second only", "16", map[string]string{BackendListenerName: `{"resources": {"requests": {"cpu": "100m", "memory": "100Mi"}, "limits": {"cpu": "200m", "memory": "200Mi"}}, "autoscaling": {"min_replicas": 2, "max_replicas": 6, "target_requests_per_second": 100}}`}),
+			}, ",") + `]`,
+			wantAccepted: []string{"duplicate"},
+			wantRejected: map[string]string{
+				"1":  "unknown field \"replica\"",
+				"2":  "replicas must be between 1 and 15",
+				"3":  "memory must be greater than 0 and at most 8Gi",
+				"4":  "the cpu request is greater than the limit",
+				"5":  "unknown component \"apicast\"",
+				"6":  "invalid rate-limiting",
+				"7":  "the name is used by the quota with param",
+				"9":  "another custom quota has the same name or param",
+				"#9": "invalid custom quota 9",
+				"10": "the max replicas must be at most 15",
+				"11": "a cpu request is required",
+				"12": "a cpu utilization target is required",
+				"13": "replicas must be between 1 and 15",
+				"14": "a memory limit is required",
+				"15": "the resources of every component are required, missing apicast_staging, ratelimit",
+				"16": "a cpu utilization target is required, the requests per second target can only be added to it",
+			},
+			verify: func(t *testing.T, quotaConfig *corev1.ConfigMap) {
+				for _, param := range []string{"2", "15"} {
+					if err := GetQuota(param, quotaConfig, &Quota{}); err == nil {
+						t.Errorf("expected the rejected quota %s not to be added", param)
+					}
+				}
+			},
+		},
+		{
+			name:       "test custom config that is not a list",
+			customData: `{"name": "5 Million"}`,
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			quotaConfig := getQuotaConfig(nil)
+			accepted, rejected, err := AddCustomQuotas(quotaConfig, getCustomQuotaConfig(tt.customData))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("AddCustomQuotas() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !reflect.DeepEqual(accepted, tt.wantAccepted) {
+				t.Errorf("AddCustomQuotas() accepted = %v, want %v", accepted, tt.wantAccepted)
+			}
+			if len(rejected) != len(tt.wantRejected) {
+				t.Errorf("AddCustomQuotas() rejected = %v, want %v", rejected, tt.wantRejected)
+			}
+			for param, message := range tt.wantRejected {
+				if err, ok := rejected[param]; !ok || !strings.Contains(err.Error(), message) {
+					t.Errorf("AddCustomQuotas() rejected[%s] = %v, want it to contain %q", param, err, message)
+				}
+			}
+			if tt.verify != nil {
+				tt.verify(t, quotaConfig)
+			}
+		})
+	}
+}
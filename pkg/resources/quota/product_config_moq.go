@@ -26,6 +26,9 @@ var _ ProductConfig = &ProductConfigMock{}
 // 			GetActiveQuotaFunc: func() string {
 // 				panic("mock out the GetActiveQuota method")
 // 			},
+// 			GetAutoscalingFunc: func(ddcssName string) *AutoscalingConfig {
+// 				panic("mock out the GetAutoscaling method")
+// 			},
 // 			GetRateLimitConfigFunc: func() marin3rconfig.RateLimitConfig {
 // 				panic("mock out the GetRateLimitConfig method")
 // 			},
@@ -48,6 +51,9 @@ type ProductConfigMock struct {
 	// GetActiveQuotaFunc mocks the GetActiveQuota method.
 	GetActiveQuotaFunc func() string
 
+	// GetAutoscalingFunc mocks the GetAutoscaling method.
+	GetAutoscalingFunc func(ddcssName string) *AutoscalingConfig
+
 	// GetRateLimitConfigFunc mocks the GetRateLimitConfig method.
 	GetRateLimitConfigFunc func() marin3rconfig.RateLimitConfig
 
@@ -67,6 +73,11 @@ type ProductConfigMock struct {
 		// GetActiveQuota holds details about calls to the GetActiveQuota method.
 		GetActiveQuota []struct {
 		}
+		// GetAutoscaling holds details about calls to the GetAutoscaling method.
+		GetAutoscaling []struct {
+			// DdcssName is the ddcssName argument value.
+			DdcssName string
+		}
 		// GetRateLimitConfig holds details about calls to the GetRateLimitConfig method.
 		GetRateLimitConfig []struct {
 		}
@@ -83,6 +94,7 @@ type ProductConfigMock struct {
 	}
 	lockConfigure          sync.RWMutex
 	lockGetActiveQuota     sync.RWMutex
+	lockGetAutoscaling     sync.RWMutex
 	lockGetRateLimitConfig sync.RWMutex
 	lockGetReplicas        sync.RWMutex
 	lockGetResourceConfig  sync.RWMutex
@@ -145,6 +157,37 @@ func (mock *ProductConfigMock) GetActiveQuotaCalls() []struct {
 	return calls
 }
 
+// GetAutoscaling calls GetAutoscalingFunc.
+func (mock *ProductConfigMock) GetAutoscaling(ddcssName string) *AutoscalingConfig {
+	if mock.GetAutoscalingFunc == nil {
+		panic("ProductConfigMock.GetAutoscalingFunc: method is nil but ProductConfig.GetAutoscaling was just called")
+	}
+	callInfo := struct {
+		DdcssName string
+	}{
+		DdcssName: ddcssName,
+	}
+	mock.lockGetAutoscaling.Lock()
+	mock.calls.GetAutoscaling = append(mock.calls.GetAutoscaling, callInfo)
+	mock.lockGetAutoscaling.Unlock()
+	return mock.GetAutoscalingFunc(ddcssName)
+}
+
+// GetAutoscalingCalls gets all the calls that were made to GetAutoscaling.
+// Check the length with:
+//     len(mockedProductConfig.GetAutoscalingCalls())
+func (mock *ProductConfigMock) GetAutoscalingCalls() []struct {
+	DdcssName string
+} {
+	var calls []struct {
+		DdcssName string
+	}
+	mock.lockGetAutoscaling.RLock()
+	calls = mock.calls.GetAutoscaling
+	mock.lockGetAutoscaling.RUnlock()
+	return calls
+}
+
 // GetRateLimitConfig calls GetRateLimitConfigFunc.
 func (mock *ProductConfigMock) GetRateLimitConfig() marin3rconfig.RateLimitConfig {
 	if mock.GetRateLimitConfigFunc == nil {
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"sync"
 
 	threescalev1 "github.com/3scale/3scale-operator/apis/apps/v1alpha1"
 	"github.com/integr8ly/integreatly-operator/apis/v1alpha1"
@@ -30,31 +31,29 @@ const (
 )
 
 var (
-	// map of products iterate over that to build the return map
-	products = map[v1alpha1.ProductName][]string{
-		v1alpha1.Product3Scale: {
-			BackendListenerName,
-			BackendWorkerName,
-			ApicastProductionName,
-			ApicastStagingName,
-		},
-		v1alpha1.ProductRHSSOUser: {
-			KeycloakName,
-		},
-		v1alpha1.ProductMarin3r: {
-			RateLimitName,
-		},
-		v1alpha1.ProductGrafana: {
-			GrafanaName,
-		},
-	}
+	// map of products iterate over that to build the return map, products add their components
+	// with RegisterProductComponents
+	products     = map[v1alpha1.ProductName][]string{}
+	productsLock sync.RWMutex
 )
 
+// RegisterProductComponents sets the names of the deployments, deployment configs and stateful sets
+// of the product that are sized by the quota
+func RegisterProductComponents(product v1alpha1.ProductName, ddcssNames []string) {
+	productsLock.Lock()
+	defer productsLock.Unlock()
+	products[product] = ddcssNames
+}
+
 type Quota struct {
 	name            string
 	productConfigs  map[v1alpha1.ProductName]QuotaProductConfig
 	isUpdated       bool
 	rateLimitConfig marin3rconfig.RateLimitConfig
+	// previous is the quota of the products the quota is not rolled out to yet, rolledOut are the
+	// products the quota is rolled out to
+	previous  *Quota
+	rolledOut map[v1alpha1.ProductName]bool
 }
 
 //go:generate moq -out product_config_moq.go . ProductConfig
@@ -62,6 +61,7 @@ type ProductConfig interface {
 	Configure(obj metav1.Object) error
 	GetResourceConfig(ddcssName string) (corev1.ResourceRequirements, bool)
 	GetReplicas(ddcssName string) int32
+	GetAutoscaling(ddcssName string) *AutoscalingConfig
 	GetRateLimitConfig() marin3rconfig.RateLimitConfig
 	GetActiveQuota() string
 }
@@ -77,6 +77,8 @@ type QuotaProductConfig struct {
 type ResourceConfig struct {
 	Replicas  int32                       `json:"replicas,omitempty"`
 	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
+	// Autoscaling replaces the fixed replicas with a horizontal pod autoscaler
+	Autoscaling *AutoscalingConfig `json:"autoscaling,omitempty"`
 }
 
 type quotaConfigReceiver struct {
@@ -87,27 +89,58 @@ type quotaConfigReceiver struct {
 }
 
 func GetQuota(quotaParam string, QuotaConfig *corev1.ConfigMap, retQuota *Quota) error {
-	allQuotas := &[]quotaConfigReceiver{}
-	err := json.Unmarshal([]byte(QuotaConfig.Data[ConfigMapData]), allQuotas)
+	quotaReceiver, err := findQuota(QuotaConfig, func(quota quotaConfigReceiver) bool { return quota.Param == quotaParam })
 	if err != nil {
 		return err
 	}
-	quotaReceiver := quotaConfigReceiver{}
-
-	for _, quota := range *allQuotas {
-		if quota.Param == quotaParam {
-			quotaReceiver = quota
-			break
-		}
-	}
 	// if the quota receiver is empty at this point we haven't found a quota which matches the config
 	// return in progress
 	if quotaReceiver.Name == "" {
 		return errors.New(fmt.Sprintf("wasn't able to find a quota in the quota config which matches the '%s' quota parameter", quotaParam))
 	}
 
+	setQuota(quotaReceiver, retQuota)
+	return nil
+}
+
+// GetQuotaByName gets the quota with the name, such as the name of the quota in the status of the
+// installation, from the quota config
+func GetQuotaByName(quotaName string, QuotaConfig *corev1.ConfigMap, retQuota *Quota) error {
+	quotaReceiver, err := findQuota(QuotaConfig, func(quota quotaConfigReceiver) bool { return quota.Name == quotaName })
+	if err != nil {
+		return err
+	}
+	if quotaReceiver.Name == "" {
+		return fmt.Errorf("wasn't able to find a quota in the quota config named '%s'", quotaName)
+	}
+
+	setQuota(quotaReceiver, retQuota)
+	return nil
+}
+
+func findQuota(QuotaConfig *corev1.ConfigMap, matches func(quotaConfigReceiver) bool) (quotaConfigReceiver, error) {
+	allQuotas := &[]quotaConfigReceiver{}
+	err := json.Unmarshal([]byte(QuotaConfig.Data[ConfigMapData]), allQuotas)
+	if err != nil {
+		return quotaConfigReceiver{}, err
+	}
+
+	for _, quota := range *allQuotas {
+		if matches(quota) {
+			return quota, nil
+		}
+	}
+	return quotaConfigReceiver{}, nil
+}
+
+func setQuota(quotaReceiver quotaConfigReceiver, retQuota *Quota) {
 	retQuota.name = quotaReceiver.Name
 	retQuota.productConfigs = map[v1alpha1.ProductName]QuotaProductConfig{}
+	retQuota.previous = nil
+	retQuota.rolledOut = nil
+
+	productsLock.RLock()
+	defer productsLock.RUnlock()
 
 	// loop through array of ddcss (deployment deploymentConfig StatefulSets)
 	for product, ddcssNames := range products {
@@ -124,14 +157,26 @@ func GetQuota(quotaParam string, QuotaConfig *corev1.ConfigMap, retQuota *Quota)
 
 	//populate rate limit configuration
 	retQuota.rateLimitConfig = quotaReceiver.RateLimit
-	return nil
 }
 
 func (s *Quota) GetProduct(productName v1alpha1.ProductName) QuotaProductConfig {
+	if s.previous != nil && !s.rolledOut[productName] {
+		return s.previous.GetProduct(productName)
+	}
 	// handle product not found e.g. return nil?
 	return s.productConfigs[productName]
 }
 
+// SetRollout rolls the quota out to the products only, the other products keep the previous
+// quota. The quota is applied to the products it is rolled out to as if it was updated
+func (s *Quota) SetRollout(previous *Quota, products []v1alpha1.ProductName) {
+	s.previous = previous
+	s.rolledOut = map[v1alpha1.ProductName]bool{}
+	for _, product := range products {
+		s.rolledOut[product] = true
+	}
+}
+
 func (s *Quota) GetName() string {
 	return s.name
 }
@@ -163,7 +208,17 @@ func (p QuotaProductConfig) GetActiveQuota() string {
 	return p.quota.name
 }
 
+// isUpdated returns true when the quota of the product changed, the replicas and resources are
+// then set to the ones of the quota even if they are lower
+func (p QuotaProductConfig) isUpdated() bool {
+	return p.quota.isUpdated || p.quota.rolledOut[p.productName]
+}
+
+// GetReplicas returns the replicas of the component, or the min replicas if it is autoscaled
 func (p QuotaProductConfig) GetReplicas(ddcssName string) int32 {
+	if autoscaling := p.resourceConfigs[ddcssName].Autoscaling; autoscaling != nil {
+		return autoscaling.MinReplicas
+	}
 	return p.resourceConfigs[ddcssName].Replicas
 }
 
@@ -188,7 +243,9 @@ func (p QuotaProductConfig) Configure(obj metav1.Object) error {
 		break
 	case *keycloak.Keycloak:
 		configReplicas := p.resourceConfigs[name].Replicas
-		if p.quota.isUpdated || t.Spec.Instances < int(configReplicas) {
+		if autoscaling := p.resourceConfigs[name].Autoscaling; autoscaling != nil {
+			t.Spec.Instances = int(autoscaling.GetReplicas(int32(t.Spec.Instances)))
+		} else if p.isUpdated() || t.Spec.Instances < int(configReplicas) {
 			t.Spec.Instances = int(configReplicas)
 		}
 		resources := p.resourceConfigs[KeycloakName].Resources
@@ -240,9 +297,14 @@ func checkStatefulSetReplicas(deployment *appsv12.StatefulSet) {
 }
 
 func (p QuotaProductConfig) mutateAPIManagerReplicas(replicas *int64, name string) {
+	// the replicas of an autoscaled component are set by its autoscaler, they are only kept in its range
+	if autoscaling := p.resourceConfigs[name].Autoscaling; autoscaling != nil {
+		*replicas = int64(autoscaling.GetReplicas(int32(*replicas)))
+		return
+	}
 	configReplicas := p.resourceConfigs[name].Replicas
 	value := int64(configReplicas)
-	if p.quota.isUpdated || *replicas < value || *replicas == 0 {
+	if p.isUpdated() || *replicas < value || *replicas == 0 {
 		*replicas = value
 	}
 }
@@ -254,8 +316,13 @@ func (p QuotaProductConfig) mutatePodTemplate(template *corev1.PodTemplateSpec,
 }
 
 func (p QuotaProductConfig) mutateReplicas(replicas *int32, name string) {
+	// the replicas of an autoscaled component are set by its autoscaler, they are only kept in its range
+	if autoscaling := p.resourceConfigs[name].Autoscaling; autoscaling != nil {
+		*replicas = autoscaling.GetReplicas(*replicas)
+		return
+	}
 	configReplicas := p.resourceConfigs[name].Replicas
-	if p.quota.isUpdated || *replicas < configReplicas || *replicas == 0 {
+	if p.isUpdated() || *replicas < configReplicas || *replicas == 0 {
 		*replicas = configReplicas
 	}
 }
@@ -275,13 +342,13 @@ func (p QuotaProductConfig) mutateResourcesRequirement(resourceRequirements *cor
 func (p QuotaProductConfig) mutateResources(pod, cfg corev1.ResourceList) {
 	podcpu := pod[corev1.ResourceCPU]
 	//Cmp returns -1 if the quantity is less than y (passed value) so if podcpu is less than cfg cpu
-	if p.quota.isUpdated || podcpu.Cmp(cfg[corev1.ResourceCPU]) == -1 || podcpu.IsZero() {
+	if p.isUpdated() || podcpu.Cmp(cfg[corev1.ResourceCPU]) == -1 || podcpu.IsZero() {
 		quantity := cfg[corev1.ResourceCPU]
 		pod[corev1.ResourceCPU] = resource.MustParse(quantity.String())
 	}
 	podmem := pod[corev1.ResourceMemory]
 	//Cmp returns -1 if the quantity is less than y (passed value) so if podmem is less than cfg memory
-	if p.quota.isUpdated || podmem.Cmp(cfg[corev1.ResourceMemory]) == -1 || podmem.IsZero() {
+	if p.isUpdated() || podmem.Cmp(cfg[corev1.ResourceMemory]) == -1 || podmem.IsZero() {
 		quantity := cfg[corev1.ResourceMemory]
 		pod[corev1.ResourceMemory] = resource.MustParse(quantity.String())
 	}
@@ -22,6 +22,11 @@ const (
 )
 
 func TestGetQuota(t *testing.T) {
+	// the quota components are registered by the product packages, which this package can not import
+	RegisterProductComponents(v1alpha1.Product3Scale, []string{BackendListenerName, BackendWorkerName, ApicastProductionName, ApicastStagingName})
+	RegisterProductComponents(v1alpha1.ProductRHSSOUser, []string{KeycloakName})
+	RegisterProductComponents(v1alpha1.ProductMarin3r, []string{RateLimitName})
+	RegisterProductComponents(v1alpha1.ProductGrafana, []string{GrafanaName})
 
 	pointerToQuota := &Quota{}
 
@@ -74,30 +79,30 @@ func TestGetQuota(t *testing.T) {
 									},
 								},
 							}
-							rcs[ApicastStagingName] = ResourceConfig{0, corev1.ResourceRequirements{}}
-							rcs[BackendListenerName] = ResourceConfig{0, corev1.ResourceRequirements{}}
-							rcs[BackendWorkerName] = ResourceConfig{0, corev1.ResourceRequirements{}}
+							rcs[ApicastStagingName] = ResourceConfig{0, corev1.ResourceRequirements{}, nil}
+							rcs[BackendListenerName] = ResourceConfig{0, corev1.ResourceRequirements{}, nil}
+							rcs[BackendWorkerName] = ResourceConfig{0, corev1.ResourceRequirements{}, nil}
 						}),
 						quota: pointerToQuota,
 					},
 					v1alpha1.ProductGrafana: {
 						v1alpha1.ProductGrafana,
 						getResourceConfig(func(rcs map[string]ResourceConfig) {
-							rcs[GrafanaName] = ResourceConfig{0, corev1.ResourceRequirements{}}
+							rcs[GrafanaName] = ResourceConfig{0, corev1.ResourceRequirements{}, nil}
 						}),
 						pointerToQuota,
 					},
 					v1alpha1.ProductMarin3r: {
 						v1alpha1.ProductMarin3r,
 						getResourceConfig(func(rcs map[string]ResourceConfig) {
-							rcs[RateLimitName] = ResourceConfig{0, corev1.ResourceRequirements{}}
+							rcs[RateLimitName] = ResourceConfig{0, corev1.ResourceRequirements{}, nil}
 						}),
 						pointerToQuota,
 					},
 					v1alpha1.ProductRHSSOUser: {
 						v1alpha1.ProductRHSSOUser,
 						getResourceConfig(func(rcs map[string]ResourceConfig) {
-							rcs[KeycloakName] = ResourceConfig{0, corev1.ResourceRequirements{}}
+							rcs[KeycloakName] = ResourceConfig{0, corev1.ResourceRequirements{}, nil}
 						}),
 						pointerToQuota,
 					},
@@ -148,30 +153,30 @@ func TestGetQuota(t *testing.T) {
 									},
 								},
 							}
-							rcs[ApicastStagingName] = ResourceConfig{0, corev1.ResourceRequirements{}}
-							rcs[ApicastProductionName] = ResourceConfig{0, corev1.ResourceRequirements{}}
-							rcs[BackendWorkerName] = ResourceConfig{0, corev1.ResourceRequirements{}}
+							rcs[ApicastStagingName] = ResourceConfig{0, corev1.ResourceRequirements{}, nil}
+							rcs[ApicastProductionName] = ResourceConfig{0, corev1.ResourceRequirements{}, nil}
+							rcs[BackendWorkerName] = ResourceConfig{0, corev1.ResourceRequirements{}, nil}
 						}),
 						quota: pointerToQuota,
 					},
 					v1alpha1.ProductGrafana: {
 						v1alpha1.ProductGrafana,
 						getResourceConfig(func(rcs map[string]ResourceConfig) {
-							rcs[GrafanaName] = ResourceConfig{0, corev1.ResourceRequirements{}}
+							rcs[GrafanaName] = ResourceConfig{0, corev1.ResourceRequirements{}, nil}
 						}),
 						pointerToQuota,
 					},
 					v1alpha1.ProductMarin3r: {
 						productName: v1alpha1.ProductMarin3r,
 						resourceConfigs: map[string]ResourceConfig{
-							RateLimitName: {0, corev1.ResourceRequirements{}},
+							RateLimitName: {0, corev1.ResourceRequirements{}, nil},
 						},
 						quota: pointerToQuota,
 					},
 					v1alpha1.ProductRHSSOUser: {
 						productName: v1alpha1.ProductRHSSOUser,
 						resourceConfigs: map[string]ResourceConfig{
-							KeycloakName: {0, corev1.ResourceRequirements{}},
+							KeycloakName: {0, corev1.ResourceRequirements{}, nil},
 						},
 						quota: pointerToQuota,
 					},
@@ -28,6 +28,14 @@ const (
 	RateLimitClusterName     = "ratelimit"
 	RateLimitDomain          = "apicast-ratelimit"
 	RateLimitDescriptorValue = "slowpath"
+	// RouteDescriptorKey is the descriptor key of the limits by route, its value is the name of the
+	// route limit in the rate limit config
+	RouteDescriptorKey = "route"
+	// RateLimitExemptDomain is the domain of the requests exempt from the rate limit, they are
+	// counted in it by the name of their exemption and never limited
+	RateLimitExemptDomain  = "apicast-ratelimit-exempt"
+	ExemptDescriptorValue  = "exempt"
+	ExemptionDescriptorKey = "exemption"
 )
 
 func DeleteEnvoyConfigsInNamespaces(ctx context.Context, client k8sclient.Client, namespaces ...string) (integreatlyv1alpha1.StatusPhase, error) {
@@ -0,0 +1,60 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/apis/v1alpha1"
+	"github.com/integr8ly/integreatly-operator/pkg/resources/user"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// TenantOverrideDescriptorValue is sent instead of the per tenant descriptor for the requests of
+	// tenants that override their limit, so the limit shared by the other tenants is not applied to them
+	TenantOverrideDescriptorValue = "per-tenant-limit"
+
+	// suspendedTenantUnit is the unit of the limit of suspended tenants without an override, their
+	// limit allows no requests so the unit only sets how long their counters are kept
+	suspendedTenantUnit = "minute"
+)
+
+// TenantRateLimitOverride is the limit of a tenant that overrides the limit per tenant
+type TenantRateLimitOverride struct {
+	TenantName string
+	integreatlyv1alpha1.TenantRateLimit
+}
+
+// GetTenantRateLimitOverrides returns the limit overrides in the spec of the APIManagementTenant
+// CRs, sorted by tenant name. CRs that will not be provisioned are left out. Suspended tenants are
+// overridden with a limit of 0 requests so all of their requests are rejected
+func GetTenantRateLimitOverrides(ctx context.Context, client k8sclient.Client) ([]TenantRateLimitOverride, error) {
+	tenants := &integreatlyv1alpha1.APIManagementTenantList{}
+	if err := client.List(ctx, tenants); err != nil {
+		return nil, fmt.Errorf("failed to list APIManagementTenant CRs: %w", err)
+	}
+
+	var overrides []TenantRateLimitOverride
+	for _, tenant := range tenants.Items {
+		if (tenant.Spec.RateLimit == nil && !tenant.Spec.Suspended) || tenant.Status.ProvisioningStatus == integreatlyv1alpha1.WontProvisionTenant {
+			continue
+		}
+		override := TenantRateLimitOverride{TenantName: user.GetTenantName(&tenant)}
+		if tenant.Spec.RateLimit != nil {
+			override.TenantRateLimit = *tenant.Spec.RateLimit
+		}
+		if tenant.Spec.Suspended {
+			if override.Unit == "" {
+				override.Unit = suspendedTenantUnit
+			}
+			override.RequestsPerUnit = 0
+		}
+		overrides = append(overrides, override)
+	}
+	sort.Slice(overrides, func(i, j int) bool {
+		return overrides[i].TenantName < overrides[j].TenantName
+	})
+
+	return overrides, nil
+}
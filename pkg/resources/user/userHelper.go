@@ -9,6 +9,7 @@ import (
 	"regexp"
 	"strings"
 
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	keycloak "github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
@@ -278,9 +279,56 @@ func GetMultiTenantUsers(ctx context.Context, serverClient k8sclient.Client, ins
 		}
 	}
 
+	// Tenants with an owner are provisioned without an OpenShift user
+	tenants := &integreatlyv1alpha1.APIManagementTenantList{}
+	err = serverClient.List(ctx, tenants)
+	if err != nil {
+		return nil, fmt.Errorf("Error getting APIManagementTenant list")
+	}
+	for i := range tenants.Items {
+		tenant := &tenants.Items[i]
+		if tenant.Spec.Owner != nil && tenant.Status.ProvisioningStatus != integreatlyv1alpha1.WontProvisionTenant {
+			users = append(users, GetTenantOwnerUser(tenant))
+		}
+	}
+
 	return users, nil
 }
 
+// GetTenantOwnerUser returns the tenant details of the owner in the spec of tenant
+func GetTenantOwnerUser(tenant *integreatlyv1alpha1.APIManagementTenant) MultiTenantUser {
+	tenantName := GetTenantName(tenant)
+	return MultiTenantUser{
+		Username:   tenantName,
+		TenantName: tenantName,
+		Email:      tenant.Spec.Owner.Email,
+		UID:        string(tenant.UID),
+	}
+}
+
+// GetMultiTenantUser returns the tenant details of user, the email is read from the identities of
+// the user instead of the identities of every user in the cluster
+func GetMultiTenantUser(ctx context.Context, serverClient k8sclient.Client, user *usersv1.User) (MultiTenantUser, error) {
+	identities := &usersv1.IdentityList{}
+	for _, name := range user.Identities {
+		identity := &usersv1.Identity{}
+		if err := serverClient.Get(ctx, k8sclient.ObjectKey{Name: name}, identity); err != nil {
+			if k8serr.IsNotFound(err) {
+				continue
+			}
+			return MultiTenantUser{}, fmt.Errorf("error getting identity %s of user %s: %w", name, user.Name, err)
+		}
+		identities.Items = append(identities.Items, *identity)
+	}
+
+	return MultiTenantUser{
+		Username:   user.Name,
+		TenantName: SanitiseTenantUserName(user.Name),
+		Email:      getUserEmail(user, identities),
+		UID:        string(user.UID),
+	}, nil
+}
+
 func isUserHasTenantAnnotation(user *usersv1.User, installation *integreatlyv1alpha1.RHMI) bool {
 	if user.Annotations == nil {
 		return false
@@ -325,6 +373,23 @@ func SanitiseTenantUserName(username string) string {
 	return strings.TrimSuffix(processedString, invalidCharacterReplacement)
 }
 
+// GetTenantNameFromNamespace returns the name of the 3scale tenant of the user whose
+// APIManagementTenant is in namespace. The CRs are created in {USERNAME}-dev or {USERNAME}-stage
+func GetTenantNameFromNamespace(namespace string) string {
+	username := strings.TrimSuffix(namespace, "-dev")
+	username = strings.TrimSuffix(username, "-stage")
+	return SanitiseTenantUserName(username)
+}
+
+// GetTenantName returns the name of the 3scale tenant of tenant, the organization name of its owner
+// or, for tenants without an owner, the name of the user whose namespace it is in
+func GetTenantName(tenant *integreatlyv1alpha1.APIManagementTenant) string {
+	if tenant.Spec.Owner != nil {
+		return SanitiseTenantUserName(tenant.Spec.Owner.OrganizationName)
+	}
+	return GetTenantNameFromNamespace(tenant.Namespace)
+}
+
 func SetUserNameAsEmail(userName string) string {
 	// If username is a valid email address
 	_, err := mail.ParseAddress(userName)
@@ -596,6 +596,7 @@ func assertThatUsersAreReturnedCorrectly(usersList userv1.UserList) error {
 func TestGetMultitenantUsers(t *testing.T) {
 	scheme := runtime.NewScheme()
 	_ = userv1.AddToScheme(scheme)
+	_ = integreatlyv1alpha1.AddToScheme(scheme)
 
 	tests := []struct {
 		Name           string
@@ -710,6 +711,40 @@ func TestGetMultitenantUsers(t *testing.T) {
 			},
 			Assertion: confirmThatUsersHaveCorrectEmailAddressesSet,
 		},
+		{
+			Name: "Test that tenants with an owner are returned",
+			FakeClient: fake.NewFakeClientWithScheme(scheme,
+				&userv1.User{
+					ObjectMeta: v1.ObjectMeta{
+						Name:        "test-1",
+						UID:         types.UID("test-1"),
+						Annotations: map[string]string{"tenant": "yes"},
+					},
+				},
+				&integreatlyv1alpha1.APIManagementTenant{
+					ObjectMeta: v1.ObjectMeta{Name: "example", Namespace: "automation", UID: types.UID("tenant-1")},
+					Spec: integreatlyv1alpha1.APIManagementTenantSpec{
+						Owner: &integreatlyv1alpha1.TenantOwner{
+							Email:            "admin@example.com",
+							OrganizationName: "Example Org",
+							IdentityProvider: "example-idp",
+						},
+					},
+				},
+				&integreatlyv1alpha1.APIManagementTenant{
+					ObjectMeta: v1.ObjectMeta{Name: "rejected", Namespace: "automation", UID: types.UID("tenant-2")},
+					Spec: integreatlyv1alpha1.APIManagementTenantSpec{
+						Owner: &integreatlyv1alpha1.TenantOwner{OrganizationName: "rejected"},
+					},
+					Status: integreatlyv1alpha1.APIManagementTenantStatus{ProvisioningStatus: integreatlyv1alpha1.WontProvisionTenant},
+				},
+				&integreatlyv1alpha1.APIManagementTenant{
+					ObjectMeta: v1.ObjectMeta{Name: "example", Namespace: "test-1-dev", UID: types.UID("tenant-3")},
+				},
+			),
+			InstallationCR: &integreatlyv1alpha1.RHMI{},
+			Assertion:      confirmThatTenantOwnersAreReturned,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.Name, func(t *testing.T) {
@@ -764,6 +799,78 @@ func confirmThatUsersHaveCorrectEmailAddressesSet(users []MultiTenantUser) error
 	return nil
 }
 
+func confirmThatTenantOwnersAreReturned(users []MultiTenantUser) error {
+	if len(users) != 2 {
+		return fmt.Errorf("incorrect number of users returned, expected 2, got %v", len(users))
+	}
+
+	want := MultiTenantUser{Username: "example-org", TenantName: "example-org", Email: "admin@example.com", UID: "tenant-1"}
+	if users[1] != want {
+		return fmt.Errorf("expected tenant owner %+v but got %+v", want, users[1])
+	}
+
+	return nil
+}
+
+func TestGetTenantName(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Tenant   *integreatlyv1alpha1.APIManagementTenant
+		Expected string
+	}{
+		{
+			Name: "Test that the tenant name is the user of the namespace",
+			Tenant: &integreatlyv1alpha1.APIManagementTenant{
+				ObjectMeta: v1.ObjectMeta{Namespace: "Test.User-stage"},
+			},
+			Expected: "test-user",
+		},
+		{
+			Name: "Test that the tenant name is the organization of the owner",
+			Tenant: &integreatlyv1alpha1.APIManagementTenant{
+				ObjectMeta: v1.ObjectMeta{Namespace: "test-user-dev"},
+				Spec: integreatlyv1alpha1.APIManagementTenantSpec{
+					Owner: &integreatlyv1alpha1.TenantOwner{OrganizationName: "Example Org"},
+				},
+			},
+			Expected: "example-org",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			if got := GetTenantName(tt.Tenant); got != tt.Expected {
+				t.Errorf("expected tenant name %s but got %s", tt.Expected, got)
+			}
+		})
+	}
+}
+
 func getLogger() l.Logger {
 	return l.NewLoggerWithContext(l.Fields{l.ProductLogContext: integreatlyv1alpha1.ProductRHSSO})
 }
+
+func TestGetMultiTenantUser(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = userv1.AddToScheme(scheme)
+
+	user := &userv1.User{
+		ObjectMeta: v1.ObjectMeta{Name: "Test.User", UID: types.UID("test-user")},
+		Identities: []string{"testIdp:test-user", "removedIdp:test-user"},
+	}
+	fakeClient := fake.NewFakeClientWithScheme(scheme,
+		&userv1.Identity{
+			ObjectMeta: v1.ObjectMeta{Name: "testIdp:test-user"},
+			User:       corev1.ObjectReference{Name: "Test.User"},
+			Extra:      map[string]string{"email": "test.user@email.com"},
+		},
+	)
+
+	got, err := GetMultiTenantUser(context.TODO(), fakeClient, user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := MultiTenantUser{Username: "Test.User", TenantName: "test-user", Email: "test.user@email.com", UID: "test-user"}
+	if got != want {
+		t.Errorf("expected %+v but got %+v", want, got)
+	}
+}
@@ -0,0 +1,526 @@
+package tracing
+
+import (
+	"context"
+
+	keycloakCommon "github.com/integr8ly/keycloak-client/pkg/common"
+	keycloak "github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const keycloakMethodKey = attribute.Key("keycloak.method")
+
+type keycloakClientFactory struct {
+	factory keycloakCommon.KeycloakClientFactory
+	parent  context.Context
+}
+
+// NewKeycloakClientFactory traces the calls of the keycloak clients built by factory as children
+// of the span in parent. The keycloak client does not take a context, so each call is traced as a
+// span of the method called
+func NewKeycloakClientFactory(factory keycloakCommon.KeycloakClientFactory, parent context.Context) keycloakCommon.KeycloakClientFactory {
+	return &keycloakClientFactory{factory: factory, parent: parent}
+}
+
+func (f *keycloakClientFactory) AuthenticatedClient(kc keycloak.Keycloak) (keycloakCommon.KeycloakInterface, error) {
+	client, err := f.factory.AuthenticatedClient(kc)
+	if err != nil {
+		return nil, err
+	}
+	return &keycloakClient{client: client, parent: f.parent}, nil
+}
+
+type keycloakClient struct {
+	client keycloakCommon.KeycloakInterface
+	parent context.Context
+}
+
+var _ keycloakCommon.KeycloakInterface = &keycloakClient{}
+
+func (c *keycloakClient) start(method string) trace.Span {
+	_, span := otel.Tracer(tracerName).Start(parentContext(context.Background(), c.parent), "Keycloak "+method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(keycloakMethodKey.String(method)),
+	)
+	return span
+}
+
+func endKeycloakSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+func (c *keycloakClient) Ping() error {
+	span := c.start("Ping")
+	err := c.client.Ping()
+	endKeycloakSpan(span, err)
+	return err
+}
+
+func (c *keycloakClient) CreateRealm(realm *keycloak.KeycloakRealm) (string, error) {
+	span := c.start("CreateRealm")
+	result, err := c.client.CreateRealm(realm)
+	endKeycloakSpan(span, err)
+	return result, err
+}
+
+func (c *keycloakClient) GetRealm(realmName string) (*keycloak.KeycloakRealm, error) {
+	span := c.start("GetRealm")
+	result, err := c.client.GetRealm(realmName)
+	endKeycloakSpan(span, err)
+	return result, err
+}
+
+func (c *keycloakClient) UpdateRealm(specRealm *keycloak.KeycloakRealm) error {
+	span := c.start("UpdateRealm")
+	err := c.client.UpdateRealm(specRealm)
+	endKeycloakSpan(span, err)
+	return err
+}
+
+func (c *keycloakClient) DeleteRealm(realmName string) error {
+	span := c.start("DeleteRealm")
+	err := c.client.DeleteRealm(realmName)
+	endKeycloakSpan(span, err)
+	return err
+}
+
+func (c *keycloakClient) ListRealms() ([]*keycloak.KeycloakAPIRealm, error) {
+	span := c.start("ListRealms")
+	result, err := c.client.ListRealms()
+	endKeycloakSpan(span, err)
+	return result, err
+}
+
+func (c *keycloakClient) CreateClient(client *keycloak.KeycloakAPIClient, realmName string) (string, error) {
+	span := c.start("CreateClient")
+	result, err := c.client.CreateClient(client, realmName)
+	endKeycloakSpan(span, err)
+	return result, err
+}
+
+func (c *keycloakClient) GetClient(clientID, realmName string) (*keycloak.KeycloakAPIClient, error) {
+	span := c.start("GetClient")
+	result, err := c.client.GetClient(clientID, realmName)
+	endKeycloakSpan(span, err)
+	return result, err
+}
+
+func (c *keycloakClient) GetClientSecret(clientID, realmName string) (string, error) {
+	span := c.start("GetClientSecret")
+	result, err := c.client.GetClientSecret(clientID, realmName)
+	endKeycloakSpan(span, err)
+	return result, err
+}
+
+func (c *keycloakClient) GetClientInstall(clientID, realmName string) ([]byte, error) {
+	span := c.start("GetClientInstall")
+	result, err := c.client.GetClientInstall(clientID, realmName)
+	endKeycloakSpan(span, err)
+	return result, err
+}
+
+func (c *keycloakClient) UpdateClient(specClient *keycloak.KeycloakAPIClient, realmName string) error {
+	span := c.start("UpdateClient")
+	err := c.client.UpdateClient(specClient, realmName)
+	endKeycloakSpan(span, err)
+	return err
+}
+
+func (c *keycloakClient) DeleteClient(clientID, realmName string) error {
+	span := c.start("DeleteClient")
+	err := c.client.DeleteClient(clientID, realmName)
+	endKeycloakSpan(span, err)
+	return err
+}
+
+func (c *keycloakClient) ListClients(realmName string) ([]*keycloak.KeycloakAPIClient, error) {
+	span := c.start("ListClients")
+	result, err := c.client.ListClients(realmName)
+	endKeycloakSpan(span, err)
+	return result, err
+}
+
+func (c *keycloakClient) CreateUser(user *keycloak.KeycloakAPIUser, realmName string) (string, error) {
+	span := c.start("CreateUser")
+	result, err := c.client.CreateUser(user, realmName)
+	endKeycloakSpan(span, err)
+	return result, err
+}
+
+func (c *keycloakClient) CreateFederatedIdentity(fid keycloak.FederatedIdentity, userID string, realmName string) (string, error) {
+	span := c.start("CreateFederatedIdentity")
+	result, err := c.client.CreateFederatedIdentity(fid, userID, realmName)
+	endKeycloakSpan(span, err)
+	return result, err
+}
+
+func (c *keycloakClient) RemoveFederatedIdentity(fid keycloak.FederatedIdentity, userID string, realmName string) error {
+	span := c.start("RemoveFederatedIdentity")
+	err := c.client.RemoveFederatedIdentity(fid, userID, realmName)
+	endKeycloakSpan(span, err)
+	return err
+}
+
+func (c *keycloakClient) GetUserFederatedIdentities(userName string, realmName string) ([]keycloak.FederatedIdentity, error) {
+	span := c.start("GetUserFederatedIdentities")
+	result, err := c.client.GetUserFederatedIdentities(userName, realmName)
+	endKeycloakSpan(span, err)
+	return result, err
+}
+
+func (c *keycloakClient) UpdatePassword(user *keycloak.KeycloakAPIUser, realmName, newPass string) error {
+	span := c.start("UpdatePassword")
+	err := c.client.UpdatePassword(user, realmName, newPass)
+	endKeycloakSpan(span, err)
+	return err
+}
+
+func (c *keycloakClient) FindUserByEmail(email, realm string) (*keycloak.KeycloakAPIUser, error) {
+	span := c.start("FindUserByEmail")
+	result, err := c.client.FindUserByEmail(email, realm)
+	endKeycloakSpan(span, err)
+	return result, err
+}
+
+func (c *keycloakClient) FindUserByUsername(name, realm string) (*keycloak.KeycloakAPIUser, error) {
+	span := c.start("FindUserByUsername")
+	result, err := c.client.FindUserByUsername(name, realm)
+	endKeycloakSpan(span, err)
+	return result, err
+}
+
+func (c *keycloakClient) GetUser(userID, realmName string) (*keycloak.KeycloakAPIUser, error) {
+	span := c.start("GetUser")
+	result, err := c.client.GetUser(userID, realmName)
+	endKeycloakSpan(span, err)
+	return result, err
+}
+
+func (c *keycloakClient) UpdateUser(specUser *keycloak.KeycloakAPIUser, realmName string) error {
+	span := c.start("UpdateUser")
+	err := c.client.UpdateUser(specUser, realmName)
+	endKeycloakSpan(span, err)
+	return err
+}
+
+func (c *keycloakClient) DeleteUser(userID, realmName string) error {
+	span := c.start("DeleteUser")
+	err := c.client.DeleteUser(userID, realmName)
+	endKeycloakSpan(span, err)
+	return err
+}
+
+func (c *keycloakClient) ListUsers(realmName string) ([]*keycloak.KeycloakAPIUser, error) {
+	span := c.start("ListUsers")
+	result, err := c.client.ListUsers(realmName)
+	endKeycloakSpan(span, err)
+	return result, err
+}
+
+func (c *keycloakClient) ListUsersInGroup(realmName, groupID string) ([]*keycloak.KeycloakAPIUser, error) {
+	span := c.start("ListUsersInGroup")
+	result, err := c.client.ListUsersInGroup(realmName, groupID)
+	endKeycloakSpan(span, err)
+	return result, err
+}
+
+func (c *keycloakClient) AddUserToGroup(realmName, userID, groupID string) error {
+	span := c.start("AddUserToGroup")
+	err := c.client.AddUserToGroup(realmName, userID, groupID)
+	endKeycloakSpan(span, err)
+	return err
+}
+
+func (c *keycloakClient) DeleteUserFromGroup(realmName, userID, groupID string) error {
+	span := c.start("DeleteUserFromGroup")
+	err := c.client.DeleteUserFromGroup(realmName, userID, groupID)
+	endKeycloakSpan(span, err)
+	return err
+}
+
+func (c *keycloakClient) FindGroupByName(groupName string, realmName string) (*keycloakCommon.Group, error) {
+	span := c.start("FindGroupByName")
+	result, err := c.client.FindGroupByName(groupName, realmName)
+	endKeycloakSpan(span, err)
+	return result, err
+}
+
+func (c *keycloakClient) FindGroupByPath(groupPath, realmName string) (*keycloakCommon.Group, error) {
+	span := c.start("FindGroupByPath")
+	result, err := c.client.FindGroupByPath(groupPath, realmName)
+	endKeycloakSpan(span, err)
+	return result, err
+}
+
+func (c *keycloakClient) CreateGroup(group string, realmName string) (string, error) {
+	span := c.start("CreateGroup")
+	result, err := c.client.CreateGroup(group, realmName)
+	endKeycloakSpan(span, err)
+	return result, err
+}
+
+func (c *keycloakClient) MakeGroupDefault(groupID string, realmName string) error {
+	span := c.start("MakeGroupDefault")
+	err := c.client.MakeGroupDefault(groupID, realmName)
+	endKeycloakSpan(span, err)
+	return err
+}
+
+func (c *keycloakClient) ListDefaultGroups(realmName string) ([]*keycloakCommon.Group, error) {
+	span := c.start("ListDefaultGroups")
+	result, err := c.client.ListDefaultGroups(realmName)
+	endKeycloakSpan(span, err)
+	return result, err
+}
+
+func (c *keycloakClient) SetGroupChild(groupID, realmName string, childGroup *keycloakCommon.Group) error {
+	span := c.start("SetGroupChild")
+	err := c.client.SetGroupChild(groupID, realmName, childGroup)
+	endKeycloakSpan(span, err)
+	return err
+}
+
+func (c *keycloakClient) CreateGroupClientRole(role *keycloak.KeycloakUserRole, realmName, clientID, groupID string) (string, error) {
+	span := c.start("CreateGroupClientRole")
+	result, err := c.client.CreateGroupClientRole(role, realmName, clientID, groupID)
+	endKeycloakSpan(span, err)
+	return result, err
+}
+
+func (c *keycloakClient) ListGroupClientRoles(realmName, clientID, groupID string) ([]*keycloak.KeycloakUserRole, error) {
+	span := c.start("ListGroupClientRoles")
+	result, err := c.client.ListGroupClientRoles(realmName, clientID, groupID)
+	endKeycloakSpan(span, err)
+	return result, err
+}
+
+func (c *keycloakClient) FindGroupClientRole(realmName, clientID, groupID string, predicate func(*keycloak.KeycloakUserRole) bool) (*keycloak.KeycloakUserRole, error) {
+	span := c.start("FindGroupClientRole")
+	result, err := c.client.FindGroupClientRole(realmName, clientID, groupID, predicate)
+	endKeycloakSpan(span, err)
+	return result, err
+}
+
+func (c *keycloakClient) ListAvailableGroupClientRoles(realmName, clientID, groupID string) ([]*keycloak.KeycloakUserRole, error) {
+	span := c.start("ListAvailableGroupClientRoles")
+	result, err := c.client.ListAvailableGroupClientRoles(realmName, clientID, groupID)
+	endKeycloakSpan(span, err)
+	return result, err
+}
+
+func (c *keycloakClient) FindAvailableGroupClientRole(realmName, clientID, groupID string, predicate func(*keycloak.KeycloakUserRole) bool) (*keycloak.KeycloakUserRole, error) {
+	span := c.start("FindAvailableGroupClientRole")
+	result, err := c.client.FindAvailableGroupClientRole(realmName, clientID, groupID, predicate)
+	endKeycloakSpan(span, err)
+	return result, err
+}
+
+func (c *keycloakClient) CreateGroupRealmRole(role *keycloak.KeycloakUserRole, realmName, groupID string) (string, error) {
+	span := c.start("CreateGroupRealmRole")
+	result, err := c.client.CreateGroupRealmRole(role, realmName, groupID)
+	endKeycloakSpan(span, err)
+	return result, err
+}
+
+func (c *keycloakClient) ListGroupRealmRoles(realmName, groupID string) ([]*keycloak.KeycloakUserRole, error) {
+	span := c.start("ListGroupRealmRoles")
+	result, err := c.client.ListGroupRealmRoles(realmName, groupID)
+	endKeycloakSpan(span, err)
+	return result, err
+}
+
+func (c *keycloakClient) ListAvailableGroupRealmRoles(realmName, groupID string) ([]*keycloak.KeycloakUserRole, error) {
+	span := c.start("ListAvailableGroupRealmRoles")
+	result, err := c.client.ListAvailableGroupRealmRoles(realmName, groupID)
+	endKeycloakSpan(span, err)
+	return result, err
+}
+
+func (c *keycloakClient) CreateIdentityProvider(identityProvider *keycloak.KeycloakIdentityProvider, realmName string) (string, error) {
+	span := c.start("CreateIdentityProvider")
+	result, err := c.client.CreateIdentityProvider(identityProvider, realmName)
+	endKeycloakSpan(span, err)
+	return result, err
+}
+
+func (c *keycloakClient) GetIdentityProvider(alias, realmName string) (*keycloak.KeycloakIdentityProvider, error) {
+	span := c.start("GetIdentityProvider")
+	result, err := c.client.GetIdentityProvider(alias, realmName)
+	endKeycloakSpan(span, err)
+	return result, err
+}
+
+func (c *keycloakClient) UpdateIdentityProvider(specIdentityProvider *keycloak.KeycloakIdentityProvider, realmName string) error {
+	span := c.start("UpdateIdentityProvider")
+	err := c.client.UpdateIdentityProvider(specIdentityProvider, realmName)
+	endKeycloakSpan(span, err)
+	return err
+}
+
+func (c *keycloakClient) DeleteIdentityProvider(alias, realmName string) error {
+	span := c.start("DeleteIdentityProvider")
+	err := c.client.DeleteIdentityProvider(alias, realmName)
+	endKeycloakSpan(span, err)
+	return err
+}
+
+func (c *keycloakClient) ListIdentityProviders(realmName string) ([]*keycloak.KeycloakIdentityProvider, error) {
+	span := c.start("ListIdentityProviders")
+	result, err := c.client.ListIdentityProviders(realmName)
+	endKeycloakSpan(span, err)
+	return result, err
+}
+
+func (c *keycloakClient) CreateUserClientRole(role *keycloak.KeycloakUserRole, realmName, clientID, userID string) (string, error) {
+	span := c.start("CreateUserClientRole")
+	result, err := c.client.CreateUserClientRole(role, realmName, clientID, userID)
+	endKeycloakSpan(span, err)
+	return result, err
+}
+
+func (c *keycloakClient) ListUserClientRoles(realmName, clientID, userID string) ([]*keycloak.KeycloakUserRole, error) {
+	span := c.start("ListUserClientRoles")
+	result, err := c.client.ListUserClientRoles(realmName, clientID, userID)
+	endKeycloakSpan(span, err)
+	return result, err
+}
+
+func (c *keycloakClient) ListAvailableUserClientRoles(realmName, clientID, userID string) ([]*keycloak.KeycloakUserRole, error) {
+	span := c.start("ListAvailableUserClientRoles")
+	result, err := c.client.ListAvailableUserClientRoles(realmName, clientID, userID)
+	endKeycloakSpan(span, err)
+	return result, err
+}
+
+func (c *keycloakClient) DeleteUserClientRole(role *keycloak.KeycloakUserRole, realmName, clientID, userID string) error {
+	span := c.start("DeleteUserClientRole")
+	err := c.client.DeleteUserClientRole(role, realmName, clientID, userID)
+	endKeycloakSpan(span, err)
+	return err
+}
+
+func (c *keycloakClient) CreateUserRealmRole(role *keycloak.KeycloakUserRole, realmName, userID string) (string, error) {
+	span := c.start("CreateUserRealmRole")
+	result, err := c.client.CreateUserRealmRole(role, realmName, userID)
+	endKeycloakSpan(span, err)
+	return result, err
+}
+
+func (c *keycloakClient) ListUserRealmRoles(realmName, userID string) ([]*keycloak.KeycloakUserRole, error) {
+	span := c.start("ListUserRealmRoles")
+	result, err := c.client.ListUserRealmRoles(realmName, userID)
+	endKeycloakSpan(span, err)
+	return result, err
+}
+
+func (c *keycloakClient) ListAvailableUserRealmRoles(realmName, userID string) ([]*keycloak.KeycloakUserRole, error) {
+	span := c.start("ListAvailableUserRealmRoles")
+	result, err := c.client.ListAvailableUserRealmRoles(realmName, userID)
+	endKeycloakSpan(span, err)
+	return result, err
+}
+
+func (c *keycloakClient) DeleteUserRealmRole(role *keycloak.KeycloakUserRole, realmName, userID string) error {
+	span := c.start("DeleteUserRealmRole")
+	err := c.client.DeleteUserRealmRole(role, realmName, userID)
+	endKeycloakSpan(span, err)
+	return err
+}
+
+func (c *keycloakClient) CreateAuthenticationFlow(authFlow keycloakCommon.AuthenticationFlow, realmName string) (string, error) {
+	span := c.start("CreateAuthenticationFlow")
+	result, err := c.client.CreateAuthenticationFlow(authFlow, realmName)
+	endKeycloakSpan(span, err)
+	return result, err
+}
+
+func (c *keycloakClient) ListAuthenticationFlows(realmName string) ([]*keycloakCommon.AuthenticationFlow, error) {
+	span := c.start("ListAuthenticationFlows")
+	result, err := c.client.ListAuthenticationFlows(realmName)
+	endKeycloakSpan(span, err)
+	return result, err
+}
+
+func (c *keycloakClient) FindAuthenticationFlowByAlias(flowAlias, realmName string) (*keycloakCommon.AuthenticationFlow, error) {
+	span := c.start("FindAuthenticationFlowByAlias")
+	result, err := c.client.FindAuthenticationFlowByAlias(flowAlias, realmName)
+	endKeycloakSpan(span, err)
+	return result, err
+}
+
+func (c *keycloakClient) AddExecutionToAuthenticatonFlow(flowAlias, realmName string, providerID string, requirement keycloakCommon.Requirement) error {
+	span := c.start("AddExecutionToAuthenticatonFlow")
+	err := c.client.AddExecutionToAuthenticatonFlow(flowAlias, realmName, providerID, requirement)
+	endKeycloakSpan(span, err)
+	return err
+}
+
+func (c *keycloakClient) ListAuthenticationExecutionsForFlow(flowAlias, realmName string) ([]*keycloak.AuthenticationExecutionInfo, error) {
+	span := c.start("ListAuthenticationExecutionsForFlow")
+	result, err := c.client.ListAuthenticationExecutionsForFlow(flowAlias, realmName)
+	endKeycloakSpan(span, err)
+	return result, err
+}
+
+func (c *keycloakClient) FindAuthenticationExecutionForFlow(flowAlias, realmName string, predicate func(*keycloak.AuthenticationExecutionInfo) bool) (*keycloak.AuthenticationExecutionInfo, error) {
+	span := c.start("FindAuthenticationExecutionForFlow")
+	result, err := c.client.FindAuthenticationExecutionForFlow(flowAlias, realmName, predicate)
+	endKeycloakSpan(span, err)
+	return result, err
+}
+
+func (c *keycloakClient) UpdateAuthenticationExecutionForFlow(flowAlias, realmName string, execution *keycloak.AuthenticationExecutionInfo) error {
+	span := c.start("UpdateAuthenticationExecutionForFlow")
+	err := c.client.UpdateAuthenticationExecutionForFlow(flowAlias, realmName, execution)
+	endKeycloakSpan(span, err)
+	return err
+}
+
+func (c *keycloakClient) CreateAuthenticatorConfig(authenticatorConfig *keycloak.AuthenticatorConfig, realmName, executionID string) (string, error) {
+	span := c.start("CreateAuthenticatorConfig")
+	result, err := c.client.CreateAuthenticatorConfig(authenticatorConfig, realmName, executionID)
+	endKeycloakSpan(span, err)
+	return result, err
+}
+
+func (c *keycloakClient) GetAuthenticatorConfig(configID, realmName string) (*keycloak.AuthenticatorConfig, error) {
+	span := c.start("GetAuthenticatorConfig")
+	result, err := c.client.GetAuthenticatorConfig(configID, realmName)
+	endKeycloakSpan(span, err)
+	return result, err
+}
+
+func (c *keycloakClient) UpdateAuthenticatorConfig(authenticatorConfig *keycloak.AuthenticatorConfig, realmName string) error {
+	span := c.start("UpdateAuthenticatorConfig")
+	err := c.client.UpdateAuthenticatorConfig(authenticatorConfig, realmName)
+	endKeycloakSpan(span, err)
+	return err
+}
+
+func (c *keycloakClient) DeleteAuthenticatorConfig(configID, realmName string) error {
+	span := c.start("DeleteAuthenticatorConfig")
+	err := c.client.DeleteAuthenticatorConfig(configID, realmName)
+	endKeycloakSpan(span, err)
+	return err
+}
+
+func (c *keycloakClient) ListOfActivesUsersPerRealm(realmName, dateFrom string, max int) ([]keycloakCommon.Users, error) {
+	span := c.start("ListOfActivesUsersPerRealm")
+	result, err := c.client.ListOfActivesUsersPerRealm(realmName, dateFrom, max)
+	endKeycloakSpan(span, err)
+	return result, err
+}
+
+func (c *keycloakClient) UpdateEventsConfig(realmName string, enabledEventTypes, eventsListeners []string) error {
+	span := c.start("UpdateEventsConfig")
+	err := c.client.UpdateEventsConfig(realmName, enabledEventTypes, eventsListeners)
+	endKeycloakSpan(span, err)
+	return err
+}
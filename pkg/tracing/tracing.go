@@ -0,0 +1,106 @@
+// Package tracing traces the reconcile loop of the installation controller with OpenTelemetry. A
+// reconcile is traced as a span for the reconcile, a span for each stage, a span for each product
+// and a span for each call the products make to the product APIs
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/apis/v1alpha1"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// ExporterEnvName selects where spans are exported to. The OTLP exporter is configured with the
+	// standard OTEL_EXPORTER_OTLP_* environment variables, such as OTEL_EXPORTER_OTLP_ENDPOINT
+	ExporterEnvName = "OTEL_TRACES_EXPORTER"
+
+	ExporterNone   = "none"
+	ExporterOTLP   = "otlp"
+	ExporterStdout = "stdout"
+
+	tracerName  = "github.com/integr8ly/integreatly-operator"
+	serviceName = "rhmi-operator"
+)
+
+// Attributes set on the spans of the reconcile loop
+const (
+	InstallationTypeKey = attribute.Key("rhmi.installation.type")
+	StageKey            = attribute.Key("rhmi.stage")
+	ProductKey          = attribute.Key("rhmi.product")
+	PhaseKey            = attribute.Key("rhmi.phase")
+)
+
+// Setup installs the tracer provider that exports spans with the exporter, spans are not recorded
+// when exporter is empty or none. The returned function flushes the spans that have not been
+// exported yet and must be called before the operator exits
+func Setup(ctx context.Context, exporter string) (func(context.Context) error, error) {
+	spanExporter, err := newExporter(ctx, exporter, os.Stdout)
+	if err != nil {
+		return nil, err
+	}
+	if spanExporter == nil {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(spanExporter),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceNameKey.String(serviceName))),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	return provider.Shutdown, nil
+}
+
+func newExporter(ctx context.Context, exporter string, stdout io.Writer) (sdktrace.SpanExporter, error) {
+	switch exporter {
+	case "", ExporterNone:
+		return nil, nil
+	case ExporterOTLP:
+		return otlptracegrpc.New(ctx)
+	case ExporterStdout:
+		return stdouttrace.New(stdouttrace.WithWriter(stdout))
+	default:
+		return nil, fmt.Errorf("unsupported trace exporter %q, expected one of %s, %s or %s", exporter, ExporterNone, ExporterOTLP, ExporterStdout)
+	}
+}
+
+// StartSpan starts a span that is a child of the span in ctx
+func StartSpan(ctx context.Context, name string, attributes ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(attributes...))
+}
+
+// EndSpan records the phase and the error of the traced work and ends the span. The phase is left
+// out when it is empty
+func EndSpan(span trace.Span, phase integreatlyv1alpha1.StatusPhase, err error) {
+	if phase != integreatlyv1alpha1.PhaseNone {
+		span.SetAttributes(PhaseKey.String(string(phase)))
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// parentContext returns ctx when it holds a span, otherwise the span in parent is added to ctx so
+// calls made without a traced context are still traced as part of the reconcile
+func parentContext(ctx context.Context, parent context.Context) context.Context {
+	if parent == nil || trace.SpanContextFromContext(ctx).IsValid() {
+		return ctx
+	}
+	return trace.ContextWithSpan(ctx, trace.SpanFromContext(parent))
+}
@@ -0,0 +1,239 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/apis/v1alpha1"
+	keycloakCommon "github.com/integr8ly/keycloak-client/pkg/common"
+	keycloak "github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+)
+
+func setupTestProvider(t *testing.T) *tracetest.InMemoryExporter {
+	exporter := tracetest.NewInMemoryExporter()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+	return exporter
+}
+
+func attributeValue(attributes []attribute.KeyValue, key attribute.Key) (attribute.Value, bool) {
+	for _, kv := range attributes {
+		if kv.Key == key {
+			return kv.Value, true
+		}
+	}
+	return attribute.Value{}, false
+}
+
+func TestEndSpan(t *testing.T) {
+	tests := []struct {
+		name       string
+		phase      integreatlyv1alpha1.StatusPhase
+		err        error
+		wantPhase  bool
+		wantStatus codes.Code
+	}{
+		{
+			name:       "test completed phase is recorded",
+			phase:      integreatlyv1alpha1.PhaseCompleted,
+			wantPhase:  true,
+			wantStatus: codes.Unset,
+		},
+		{
+			name:       "test empty phase is left out",
+			phase:      integreatlyv1alpha1.PhaseNone,
+			wantStatus: codes.Unset,
+		},
+		{
+			name:       "test error sets the error status",
+			phase:      integreatlyv1alpha1.PhaseFailed,
+			err:        errors.New("failed"),
+			wantPhase:  true,
+			wantStatus: codes.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exporter := setupTestProvider(t)
+
+			_, span := StartSpan(context.TODO(), "stage", StageKey.String("products"))
+			EndSpan(span, tt.phase, tt.err)
+
+			spans := exporter.GetSpans()
+			if len(spans) != 1 {
+				t.Fatalf("expected 1 span but got %d", len(spans))
+			}
+			if value, ok := attributeValue(spans[0].Attributes, StageKey); !ok || value.AsString() != "products" {
+				t.Errorf("expected stage attribute products but got %v", value.AsString())
+			}
+			value, ok := attributeValue(spans[0].Attributes, PhaseKey)
+			if ok != tt.wantPhase {
+				t.Errorf("expected phase attribute %v but got %v", tt.wantPhase, ok)
+			}
+			if ok && value.AsString() != string(tt.phase) {
+				t.Errorf("expected phase %s but got %s", tt.phase, value.AsString())
+			}
+			if spans[0].Status.Code != tt.wantStatus {
+				t.Errorf("expected status %v but got %v", tt.wantStatus, spans[0].Status.Code)
+			}
+		})
+	}
+}
+
+func TestTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name       string
+		path       string
+		wantURL    string
+		wantCode   int
+		wantStatus codes.Code
+	}{
+		{
+			name:       "test query is left out of the span",
+			path:       "/admin/api/accounts.json?access_token=secret",
+			wantURL:    server.URL + "/admin/api/accounts.json",
+			wantCode:   http.StatusOK,
+			wantStatus: codes.Unset,
+		},
+		{
+			name:       "test error response sets the error status",
+			path:       "/missing",
+			wantURL:    server.URL + "/missing",
+			wantCode:   http.StatusNotFound,
+			wantStatus: codes.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exporter := setupTestProvider(t)
+
+			parent, parentSpan := StartSpan(context.TODO(), "product.Reconcile")
+			client := &http.Client{Transport: NewTransport(http.DefaultTransport, parent)}
+			resp, err := client.Get(server.URL + tt.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+			parentSpan.End()
+
+			spans := exporter.GetSpans()
+			if len(spans) != 2 {
+				t.Fatalf("expected 2 spans but got %d", len(spans))
+			}
+			span := spans[0]
+			if span.Name != "HTTP GET" {
+				t.Errorf("expected span HTTP GET but got %s", span.Name)
+			}
+			if span.Parent.SpanID() != parentSpan.SpanContext().SpanID() {
+				t.Errorf("expected the request to be traced as a child of the parent span")
+			}
+			if value, _ := attributeValue(span.Attributes, semconv.HTTPURLKey); value.AsString() != tt.wantURL {
+				t.Errorf("expected url %s but got %s", tt.wantURL, value.AsString())
+			}
+			if value, _ := attributeValue(span.Attributes, semconv.HTTPStatusCodeKey); value.AsInt64() != int64(tt.wantCode) {
+				t.Errorf("expected status code %d but got %d", tt.wantCode, value.AsInt64())
+			}
+			if span.Status.Code != tt.wantStatus {
+				t.Errorf("expected status %v but got %v", tt.wantStatus, span.Status.Code)
+			}
+		})
+	}
+}
+
+func TestKeycloakClientFactory(t *testing.T) {
+	exporter := setupTestProvider(t)
+
+	listErr := errors.New("unauthorized")
+	factory := &keycloakCommon.KeycloakClientFactoryMock{
+		AuthenticatedClientFunc: func(kc keycloak.Keycloak) (keycloakCommon.KeycloakInterface, error) {
+			return &keycloakCommon.KeycloakInterfaceMock{
+				ListRealmsFunc: func() ([]*keycloak.KeycloakAPIRealm, error) {
+					return nil, listErr
+				},
+			}, nil
+		},
+	}
+
+	parent, parentSpan := StartSpan(context.TODO(), "product.Reconcile")
+	client, err := NewKeycloakClientFactory(factory, parent).AuthenticatedClient(keycloak.Keycloak{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.ListRealms(); !errors.Is(err, listErr) {
+		t.Fatalf("expected the error of the client to be returned but got %v", err)
+	}
+	parentSpan.End()
+
+	spans := exporter.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans but got %d", len(spans))
+	}
+	if spans[0].Name != "Keycloak ListRealms" {
+		t.Errorf("expected span Keycloak ListRealms but got %s", spans[0].Name)
+	}
+	if spans[0].Parent.SpanID() != parentSpan.SpanContext().SpanID() {
+		t.Errorf("expected the call to be traced as a child of the parent span")
+	}
+	if spans[0].Status.Code != codes.Error {
+		t.Errorf("expected error status but got %v", spans[0].Status.Code)
+	}
+}
+
+func TestNewExporter(t *testing.T) {
+	tests := []struct {
+		name         string
+		exporter     string
+		wantExporter bool
+		wantErr      bool
+	}{
+		{
+			name:     "test tracing is disabled by default",
+			exporter: "",
+		},
+		{
+			name:     "test tracing is disabled with none",
+			exporter: ExporterNone,
+		},
+		{
+			name:         "test stdout exporter",
+			exporter:     ExporterStdout,
+			wantExporter: true,
+		},
+		{
+			name:     "test unknown exporter is an error",
+			exporter: "zipkin",
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := newExporter(context.TODO(), tt.exporter, &bytes.Buffer{})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("newExporter() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if (got != nil) != tt.wantExporter {
+				t.Errorf("expected exporter %v but got %v", tt.wantExporter, got)
+			}
+		})
+	}
+}
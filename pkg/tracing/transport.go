@@ -0,0 +1,54 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type transport struct {
+	next   http.RoundTripper
+	parent context.Context
+}
+
+// NewTransport traces the requests sent through next. Requests whose context has no span are
+// traced as children of the span in parent
+func NewTransport(next http.RoundTripper, parent context.Context) http.RoundTripper {
+	return &transport{next: next, parent: parent}
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// the query is left out of the span, product APIs such as 3scale take access tokens in it
+	url := *req.URL
+	url.RawQuery, url.User = "", nil
+
+	ctx, span := otel.Tracer(tracerName).Start(parentContext(req.Context(), t.parent), fmt.Sprintf("HTTP %s", req.Method),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.HTTPMethodKey.String(req.Method),
+			semconv.HTTPURLKey.String(url.String()),
+		),
+	)
+	defer span.End()
+
+	req = req.Clone(ctx)
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return resp, err
+	}
+	span.SetAttributes(semconv.HTTPStatusCodeKey.Int(resp.StatusCode))
+	if resp.StatusCode >= http.StatusBadRequest {
+		span.SetStatus(codes.Error, resp.Status)
+	}
+	return resp, nil
+}
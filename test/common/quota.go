@@ -20,6 +20,8 @@ import (
 	rhmiv1alpha1 "github.com/integr8ly/integreatly-operator/apis/v1alpha1"
 	"github.com/integr8ly/integreatly-operator/pkg/addon"
 	"github.com/integr8ly/integreatly-operator/pkg/products/marin3r"
+	// registers the quota components of every product
+	_ "github.com/integr8ly/integreatly-operator/pkg/products/builtin"
 	"github.com/integr8ly/integreatly-operator/pkg/resources/quota"
 	"github.com/pkg/errors"
 	v1 "k8s.io/api/core/v1"